@@ -9,7 +9,7 @@ import (
 )
 
 // Dump takes a database and dumps its content as SQL queries in the given writer.
-// If tables is provided, only selected tables will be outputted.
+// If tables is provided, only selected tables and their triggers will be outputted, without the views.
 func Dump(db *chai.DB, w io.Writer, tables ...string) error {
 	tx, err := db.Begin(false)
 	if err != nil {
@@ -33,6 +33,9 @@ func Dump(db *chai.DB, w io.Writer, tables ...string) error {
 
 		return dumpTable(tx, w, query, name)
 	})
+	if err == nil {
+		err = dumpViewsAndTriggers(tx, w, tables, i > 0)
+	}
 	if err != nil {
 		_, er := fmt.Fprintln(w, "ROLLBACK;")
 		return multierr.Append(err, er)
@@ -73,7 +76,7 @@ func dumpTable(tx *chai.Tx, w io.Writer, query, tableName string) error {
 }
 
 // DumpSchema takes a database and dumps its schema as SQL queries in the given writer.
-// If tables are provided, only selected tables will be outputted.
+// If tables are provided, only selected tables and their triggers will be outputted, without the views.
 func DumpSchema(db *chai.DB, w io.Writer, tables ...string) error {
 	tx, err := db.Begin(false)
 	if err != nil {
@@ -82,7 +85,7 @@ func DumpSchema(db *chai.DB, w io.Writer, tables ...string) error {
 	defer tx.Rollback()
 
 	i := 0
-	return QueryTables(tx, tables, func(name, query string) error {
+	err = QueryTables(tx, tables, func(name, query string) error {
 		// Blank separation between tables.
 		if i > 0 {
 			if _, err := fmt.Fprintln(w, ""); err != nil {
@@ -93,6 +96,40 @@ func DumpSchema(db *chai.DB, w io.Writer, tables ...string) error {
 
 		return dumpSchema(tx, w, query, name)
 	})
+	if err != nil {
+		return err
+	}
+
+	return dumpViewsAndTriggers(tx, w, tables, i > 0)
+}
+
+// dumpViewsAndTriggers displays the CREATE VIEW statements of all the views,
+// then the CREATE TRIGGER statements of the triggers of the given tables.
+// They are displayed after the tables they depend on, so that restoring
+// the content of the tables doesn't execute the triggers.
+// If tables are provided, the views are not displayed.
+// If sep is true, the statements are separated from the tables by a blank line.
+func dumpViewsAndTriggers(tx *chai.Tx, w io.Writer, tables []string, sep bool) error {
+	write := func(name, query string) error {
+		if sep {
+			if _, err := fmt.Fprintln(w, ""); err != nil {
+				return err
+			}
+			sep = false
+		}
+
+		_, err := fmt.Fprintf(w, "%s;\n", query)
+		return err
+	}
+
+	if len(tables) == 0 {
+		err := QueryViews(tx, write)
+		if err != nil {
+			return err
+		}
+	}
+
+	return QueryTriggers(tx, tables, write)
 }
 
 // dumpSchema displays the schema of the given table as SQL statements.
@@ -2,7 +2,9 @@ package dbutil
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"testing"
 
 	"github.com/chaisql/chai"
@@ -83,6 +85,26 @@ func TestDump(t *testing.T) {
 				assert.NoError(t, err)
 				writeToBuf(q + "\n")
 			}
+
+			// views are only dumped along with all the tables
+			q := "CREATE VIEW viewA AS SELECT a, b FROM tblA WHERE a > 1;"
+			err = db.Exec(q)
+			assert.NoError(t, err)
+			if len(tt.tables) == 0 {
+				want.WriteString("\n" + q + "\n")
+			}
+
+			// triggers are dumped along with their table
+			q = "CREATE TRIGGER trgA AFTER INSERT ON tblA FOR EACH ROW BEGIN DELETE FROM tblA WHERE a = NEW.a; END;"
+			err = db.Exec(q)
+			assert.NoError(t, err)
+			if len(tt.tables) == 0 {
+				want.WriteString(q + "\n")
+			} else {
+				// the selection contains tblA
+				want.WriteString("\n" + q + "\n")
+			}
+
 			want.WriteString("COMMIT;\n")
 
 			var got bytes.Buffer
@@ -93,3 +115,69 @@ func TestDump(t *testing.T) {
 		})
 	}
 }
+
+func TestDumpForeignKeys(t *testing.T) {
+	db, err := chai.Open(":memory:")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	err = db.Exec(`
+		CREATE TABLE zp (id INTEGER PRIMARY KEY);
+		CREATE TABLE ac (id INTEGER PRIMARY KEY, zp_id INTEGER REFERENCES zp);
+		CREATE TABLE mm (id INTEGER PRIMARY KEY, ac_id INTEGER REFERENCES ac);
+		INSERT INTO zp (id) VALUES (1);
+		INSERT INTO ac (id, zp_id) VALUES (1, 1);
+		INSERT INTO mm (id, ac_id) VALUES (1, 1);
+	`)
+	assert.NoError(t, err)
+
+	var got bytes.Buffer
+	err = Dump(db, &got)
+	assert.NoError(t, err)
+
+	// tables are dumped after the tables they reference
+	zp := bytes.Index(got.Bytes(), []byte("CREATE TABLE zp"))
+	ac := bytes.Index(got.Bytes(), []byte("CREATE TABLE ac"))
+	mm := bytes.Index(got.Bytes(), []byte("CREATE TABLE mm"))
+	require.True(t, zp < ac && ac < mm, got.String())
+
+	// the dump can be restored
+	restored, err := chai.Open(":memory:")
+	assert.NoError(t, err)
+	defer restored.Close()
+
+	err = ExecSQL(context.Background(), restored, &got, io.Discard)
+	assert.NoError(t, err)
+}
+
+func TestDumpViews(t *testing.T) {
+	db, err := chai.Open(":memory:")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	err = db.Exec(`
+		CREATE TABLE t (a INTEGER PRIMARY KEY);
+		CREATE VIEW zz AS SELECT a FROM t;
+		CREATE VIEW aa AS SELECT a FROM zz;
+		CREATE VIEW mm AS SELECT a FROM t WHERE a IN (SELECT a FROM aa);
+	`)
+	assert.NoError(t, err)
+
+	var got bytes.Buffer
+	err = Dump(db, &got)
+	assert.NoError(t, err)
+
+	// views are dumped after the views they read
+	zz := bytes.Index(got.Bytes(), []byte("CREATE VIEW zz"))
+	aa := bytes.Index(got.Bytes(), []byte("CREATE VIEW aa"))
+	mm := bytes.Index(got.Bytes(), []byte("CREATE VIEW mm"))
+	require.True(t, zz < aa && aa < mm, got.String())
+
+	// the dump can be restored
+	restored, err := chai.Open(":memory:")
+	assert.NoError(t, err)
+	defer restored.Close()
+
+	err = ExecSQL(context.Background(), restored, &got, io.Discard)
+	assert.NoError(t, err)
+}
@@ -6,6 +6,9 @@ import (
 	"github.com/chaisql/chai/internal/sql/parser"
 )
 
+// QueryTables calls fn with the name and the CREATE TABLE statement of each table.
+// Tables are returned after the tables referenced by their foreign keys.
+// If tables are provided, only the selected tables are returned.
 func QueryTables(tx *chai.Tx, tables []string, fn func(name, query string) error) error {
 	query := "SELECT name, sql FROM __chai_catalog WHERE type = 'table' AND name NOT LIKE '__chai_%'"
 	if len(tables) > 0 {
@@ -18,13 +21,140 @@ func QueryTables(tx *chai.Tx, tables []string, fn func(name, query string) error
 	}
 	defer res.Close()
 
-	return res.Iterate(func(r *chai.Row) error {
+	var names []string
+	queries := make(map[string]string)
+	deps := make(map[string][]string)
+	err = res.Iterate(func(r *chai.Row) error {
 		// Get table name.
 		var name, query string
 		if err := r.Scan(&name, &query); err != nil {
 			return err
 		}
 
+		q, err := parser.ParseQuery(query)
+		if err != nil {
+			return err
+		}
+
+		for _, tc := range q.Statements[0].(*statement.CreateTableStmt).Info.TableConstraints {
+			if tc.ForeignKey != nil {
+				deps[name] = append(deps[name], tc.ForeignKey.TableName)
+			}
+		}
+
+		names = append(names, name)
+		queries[name] = query
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, name := range orderByDependencies(names, deps) {
+		if err := fn(name, queries[name]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// orderByDependencies returns the names ordered so that each name comes
+// after the names it depends on. Otherwise, the original order is kept.
+// Dependencies that are not part of the names are ignored.
+func orderByDependencies(names []string, deps map[string][]string) []string {
+	ordered := make([]string, 0, len(names))
+
+	known := make(map[string]bool, len(names))
+	for _, name := range names {
+		known[name] = true
+	}
+
+	visited := make(map[string]bool, len(names))
+	var visit func(name string)
+	visit = func(name string) {
+		if visited[name] {
+			return
+		}
+		visited[name] = true
+
+		for _, dep := range deps[name] {
+			if known[dep] {
+				visit(dep)
+			}
+		}
+
+		ordered = append(ordered, name)
+	}
+
+	for _, name := range names {
+		visit(name)
+	}
+
+	return ordered
+}
+
+// QueryViews calls fn with the name and the CREATE VIEW statement of each view.
+// Views are returned after the views they read.
+func QueryViews(tx *chai.Tx, fn func(name, query string) error) error {
+	res, err := tx.Query("SELECT name, sql FROM __chai_catalog WHERE type = 'view'")
+	if err != nil {
+		return err
+	}
+	defer res.Close()
+
+	var names []string
+	queries := make(map[string]string)
+	deps := make(map[string][]string)
+	err = res.Iterate(func(r *chai.Row) error {
+		var name, query string
+		if err := r.Scan(&name, &query); err != nil {
+			return err
+		}
+
+		q, err := parser.ParseQuery(query)
+		if err != nil {
+			return err
+		}
+
+		names = append(names, name)
+		queries[name] = query
+		deps[name] = q.Statements[0].(*statement.CreateViewStmt).Info.Query.Sources()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, name := range orderByDependencies(names, deps) {
+		if err := fn(name, queries[name]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// QueryTriggers calls fn with the name and the CREATE TRIGGER statement of each trigger.
+// If tables are provided, only the triggers of the selected tables are returned.
+func QueryTriggers(tx *chai.Tx, tables []string, fn func(name, query string) error) error {
+	query := "SELECT name, sql FROM __chai_catalog WHERE type = 'trigger'"
+	if len(tables) > 0 {
+		query += " AND owner.table_name IN ?"
+	}
+
+	res, err := tx.Query(query, tables)
+	if err != nil {
+		return err
+	}
+	defer res.Close()
+
+	return res.Iterate(func(r *chai.Row) error {
+		var name, query string
+		if err := r.Scan(&name, &query); err != nil {
+			return err
+		}
+
 		return fn(name, query)
 	})
 }
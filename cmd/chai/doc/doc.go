@@ -23,13 +23,18 @@ func DocString(rawExpr string) (string, error) {
 		return "", ErrInvalid
 	}
 	s := scanner.NewScanner(strings.NewReader(rawExpr))
-	tok, _, _ := s.Scan()
+	tok, _, lit := s.Scan()
 	if tok == scanner.ILLEGAL {
 		return "", ErrInvalid
 	}
 	if tok == scanner.IDENT {
-		s.Unscan()
-		return scanFuncDocString(s)
+		// non-reserved keywords are scanned as identifiers
+		kw, ok := scanner.Keyword(lit)
+		if !ok {
+			s.Unscan()
+			return scanFuncDocString(s)
+		}
+		tok = kw
 	}
 	docstr, ok := tokenDocs[tok]
 	if ok {
@@ -40,6 +40,7 @@ var stringsDocs = functionDocs{
 	"trim":  "The trim function returns arg1 with leading and trailing characters removed. space by default or arg2",
 	"ltrim": "The ltrim function returns arg1 with leading characters removed. space by default or arg2",
 	"rtrim": "The rtrim function returns arg1 with trailing characters removed. space by default or arg2",
+	"rank":  "The rank function returns the relevance of the text arg1 for the full-text search query arg2, as a double. With no arguments, rank is the window function returning the rank of the row.",
 }
 
 var objectsDocs = functionDocs{
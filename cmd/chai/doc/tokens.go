@@ -14,4 +14,5 @@ func init() {
 
 	tokenDocs[scanner.BY] = "See GROUP BY, ORDER BY"
 	tokenDocs[scanner.FROM] = "FROM [TABLE] selects rows in the table named [TABLE]"
+	tokenDocs[scanner.MATCH] = "[TEXT] MATCH [QUERY], or [TEXT] @@ [QUERY], returns whether the text contains all the words of the full-text search query"
 }
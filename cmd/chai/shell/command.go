@@ -76,7 +76,7 @@ var commands = []command{
 		Name:        ".schema",
 		Options:     "[table_name]",
 		DisplayName: ".schema",
-		Description: "Show the CREATE statements of all tables or of the selected ones.",
+		Description: "Show the CREATE statements of all tables, views and triggers or of the selected tables and their triggers.",
 	},
 	{
 		Name:        ".import",
@@ -85,9 +85,12 @@ func TestOpen(t *testing.T) {
 		CREATE TABLE tableC (a INTEGER, b BOOL);
 		CREATE INDEX tableC_a_b_idx ON tableC(a, b);
 		CREATE SEQUENCE seqD INCREMENT BY 10 CYCLE MINVALUE 100 NO MAXVALUE START 500;
+		CREATE VIEW viewE AS SELECT a AS e FROM tableB WHERE a != 'foo';
 
 		INSERT INTO tableB (a) VALUES (1);
 		INSERT INTO tableC (a, b) VALUES (1, NEXT VALUE FOR seqD);
+
+		CREATE TRIGGER triggerF AFTER INSERT ON tableC BEGIN INSERT INTO tableB (a) VALUES (NEW.a); END;
 	`)
 	assert.NoError(t, err)
 
@@ -115,6 +118,8 @@ func TestOpen(t *testing.T) {
 		`{"name":"tableC", "rowid_sequence_name":"tableC_seq", "sql":"CREATE TABLE tableC (a INTEGER, b BOOLEAN)", "namespace":13, "type":"table"}`,
 		`{"name":"tableC_a_b_idx", "owner":{"table_name":"tableC"}, "sql":"CREATE INDEX tableC_a_b_idx ON tableC (a, b)", "namespace":14, "type":"index"}`,
 		`{"name":"tableC_seq", "owner":{"table_name":"tableC"}, "sql":"CREATE SEQUENCE tableC_seq CACHE 64", "type":"sequence"}`,
+		`{"name":"triggerF", "owner":{"table_name":"tableC"}, "sql":"CREATE TRIGGER triggerF AFTER INSERT ON tableC FOR EACH ROW BEGIN INSERT INTO tableB (a) VALUES (NEW.a); END", "type":"trigger"}`,
+		`{"name":"viewE", "sql":"CREATE VIEW viewE AS SELECT a AS e FROM tableB WHERE a != \"foo\"", "type":"view"}`,
 	}
 	err = res1.Iterate(func(r *chai.Row) error {
 		count++
@@ -131,6 +136,17 @@ func TestOpen(t *testing.T) {
 	assert.NoError(t, err)
 	testutil.RequireJSONEq(t, d, `{"a": "1"}`)
 
+	d, err = db.QueryRow("SELECT * FROM viewE")
+	assert.NoError(t, err)
+	testutil.RequireJSONEq(t, d, `{"e": "1"}`)
+
+	err = db.Exec("INSERT INTO tableC (a, b) VALUES (2, true)")
+	assert.NoError(t, err)
+
+	d, err = db.QueryRow("SELECT * FROM tableB WHERE a = '2'")
+	assert.NoError(t, err)
+	testutil.RequireJSONEq(t, d, `{"a": "2"}`)
+
 	d, err = db.QueryRow("SELECT * FROM __chai_sequence")
 	assert.NoError(t, err)
 	testutil.RequireJSONEq(t, d, `{"name":"__chai_store_seq", "seq":14}`)
@@ -18,6 +18,7 @@ import (
 const (
 	CatalogTableName  = InternalPrefix + "catalog"
 	SequenceTableName = InternalPrefix + "sequence"
+	StatsTableName    = InternalPrefix + "stats"
 )
 
 // Relation types
@@ -25,6 +26,8 @@ const (
 	RelationTableType    = "table"
 	RelationIndexType    = "index"
 	RelationSequenceType = "sequence"
+	RelationViewType     = "view"
+	RelationTriggerType  = "trigger"
 )
 
 // System sequences
@@ -37,11 +40,12 @@ const (
 	CatalogTableNamespace    tree.Namespace = 1
 	SequenceTableNamespace   tree.Namespace = 2
 	RollbackSegmentNamespace tree.Namespace = 3
+	StatsTableNamespace      tree.Namespace = 4
 	MinTransientNamespace    tree.Namespace = math.MaxInt64 - 1<<24
 	MaxTransientNamespace    tree.Namespace = math.MaxInt64
 )
 
-// Catalog manages all database objects such as tables, indexes and sequences.
+// Catalog manages all database objects such as tables, indexes, sequences, views and triggers.
 // It stores all these objects in memory for fast access. Any modification
 // is persisted into the __chai_catalog table.
 type Catalog struct {
@@ -144,6 +148,12 @@ func (c *Catalog) ListSequences() []string {
 	return c.Cache.ListObjects(RelationSequenceType)
 }
 
+// GetTableStats returns the statistics of the given table,
+// or nil if the table hasn't been analyzed.
+func (c *Catalog) GetTableStats(tableName string) *TableStats {
+	return c.Cache.stats[tableName]
+}
+
 // GetFreeTransientNamespace returns the next available transient namespace.
 // Transient namespaces start from math.MaxInt64 - (2 << 24) to math.MaxInt64 (around 16 M).
 // The transient namespaces counter is not persisted and resets when the database is restarted.
@@ -248,6 +258,11 @@ func (c *CatalogWriter) CreateTable(tx *Transaction, tableName string, info *Tab
 		return errors.WithStack(errs.AlreadyExistsError{Name: tableName})
 	}
 
+	err = c.checkForeignKeys(info)
+	if err != nil {
+		return err
+	}
+
 	if info.StoreNamespace == 0 {
 		info.StoreNamespace, err = c.generateStoreNamespace(tx)
 		if err != nil {
@@ -264,7 +279,9 @@ func (c *CatalogWriter) CreateTable(tx *Transaction, tableName string, info *Tab
 	return c.Catalog.Cache.Add(tx, &rel)
 }
 
-// DropTable deletes a table from the catalog
+// DropTable deletes a table from the catalog.
+// It returns an error if a foreign key of another table references it
+// or if a view reads it.
 func (c *CatalogWriter) DropTable(tx *Transaction, tableName string) error {
 	ti, err := c.GetTableInfo(tableName)
 	if err != nil {
@@ -275,6 +292,16 @@ func (c *CatalogWriter) DropTable(tx *Transaction, tableName string) error {
 		return errors.New("cannot write to read-only table")
 	}
 
+	for _, ref := range c.ListReferences(tableName) {
+		if ref.TableName != tableName {
+			return errors.Errorf("cannot drop table %q because constraint %q of table %q depends on it", tableName, ref.Constraint.Name, ref.TableName)
+		}
+	}
+
+	if views := c.ListDependentViews(tableName); len(views) > 0 {
+		return errors.Errorf("cannot drop table %q because view %q depends on it", tableName, views[0])
+	}
+
 	for _, idx := range c.Cache.GetTableIndexes(tableName) {
 		_, err = c.Cache.Delete(tx, RelationIndexType, idx.IndexName)
 		if err != nil {
@@ -287,6 +314,13 @@ func (c *CatalogWriter) DropTable(tx *Transaction, tableName string) error {
 		}
 	}
 
+	for _, t := range c.Cache.getTableTriggers(tableName) {
+		err = c.DropTrigger(tx, t.TriggerName)
+		if err != nil {
+			return err
+		}
+	}
+
 	_, err = c.Cache.Delete(tx, RelationTableType, tableName)
 	if err != nil {
 		return err
@@ -297,6 +331,11 @@ func (c *CatalogWriter) DropTable(tx *Transaction, tableName string) error {
 		return err
 	}
 
+	err = c.DeleteTableStats(tx, tableName)
+	if err != nil {
+		return err
+	}
+
 	return tree.New(tx.Session, ti.StoreNamespace, ti.PrimaryKeySortOrder()).Truncate()
 }
 
@@ -310,13 +349,44 @@ func (c *CatalogWriter) CreateIndex(tx *Transaction, info *IndexInfo) (*IndexInf
 	}
 
 	// check if the indexed fields exist
-	for _, p := range info.Paths {
+	for _, p := range info.UsedPaths() {
 		fc := ti.GetFieldConstraintForPath(p)
 		if fc == nil {
 			return nil, errors.Errorf("field %q does not exist for table %q", p, ti.TableName)
 		}
 	}
 
+	// the terms of full-text indexes are extracted from texts
+	if info.FullText {
+		fc := ti.GetFieldConstraintForPath(info.Paths[0])
+		if fc.Type != types.TypeAny && fc.Type != types.TypeText {
+			return nil, errors.Errorf("cannot create a full-text index on field %q of type %s", info.Paths[0], fc.Type)
+		}
+	}
+
+	// the elements of arrays are indexed separately
+	for _, e := range info.Exprs {
+		if e == nil || !e.Elements {
+			continue
+		}
+
+		fc := ti.GetFieldConstraintForPath(e.Paths[0])
+		if fc.Type != types.TypeAny && fc.Type != types.TypeArray {
+			return nil, errors.Errorf("cannot index the elements of field %q of type %s", e.Paths[0], fc.Type)
+		}
+	}
+
+	// check that included fields are not already stored in the index
+	stored := append([]object.Path(nil), info.Paths...)
+	for _, p := range info.Include {
+		for _, sp := range stored {
+			if sp.IsEqual(p) {
+				return nil, errors.Errorf("field %q is already stored in the index", p)
+			}
+		}
+		stored = append(stored, p)
+	}
+
 	info.StoreNamespace, err = c.generateStoreNamespace(tx)
 	if err != nil {
 		return nil, err
@@ -354,6 +424,19 @@ func (c *CatalogWriter) DropIndex(tx *Transaction, name string) error {
 		return err
 	}
 
+	// remove the statistics of the index, so that they are not
+	// used by another index created later with the same name
+	if stats := c.GetTableStats(info.Owner.TableName); stats != nil {
+		if _, ok := stats.Indexes[name]; ok {
+			clone := stats.Clone()
+			delete(clone.Indexes, name)
+			err = c.SetTableStats(tx, clone)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
 	return c.dropIndex(tx, info)
 }
 
@@ -389,6 +472,11 @@ func (c *CatalogWriter) AddFieldConstraint(tx *Transaction, tableName string, fc
 		}
 	}
 
+	err = c.checkForeignKeys(clone)
+	if err != nil {
+		return err
+	}
+
 	cloneRel := &TableInfoRelation{Info: clone}
 	err = c.Cache.Replace(tx, cloneRel)
 	if err != nil {
@@ -398,6 +486,209 @@ func (c *CatalogWriter) AddFieldConstraint(tx *Transaction, tableName string, fc
 	return c.CatalogTable.Replace(tx, tableName, cloneRel)
 }
 
+// DropFieldConstraint removes a field constraint from a table.
+// It returns an error if a table constraint or an index depends on the field.
+func (c *CatalogWriter) DropFieldConstraint(tx *Transaction, tableName string, field string) error {
+	r, err := c.Cache.Get(RelationTableType, tableName)
+	if err != nil {
+		return err
+	}
+	ti := r.(*TableInfoRelation).Info
+
+	for _, tc := range ti.TableConstraints {
+		if pathsUseField(tc.Paths, field) {
+			return errors.Errorf("cannot drop field %q because constraint %q depends on it", field, tc.Name)
+		}
+	}
+
+	for _, idx := range c.Cache.GetTableIndexes(tableName) {
+		if pathsUseField(idx.UsedPaths(), field) {
+			return errors.Errorf("cannot drop field %q because index %q depends on it", field, idx.IndexName)
+		}
+	}
+
+	clone := ti.Clone()
+	err = clone.FieldConstraints.Remove(field)
+	if err != nil {
+		return err
+	}
+
+	err = c.deleteFieldStats(tx, tableName, field)
+	if err != nil {
+		return err
+	}
+
+	return c.replaceTableInfo(tx, clone)
+}
+
+// deleteFieldStats removes the statistics of the field and of the indexes using it,
+// which don't describe its values anymore once it is dropped or converted.
+func (c *CatalogWriter) deleteFieldStats(tx *Transaction, tableName string, field string) error {
+	stats := c.GetTableStats(tableName)
+	if stats == nil {
+		return nil
+	}
+
+	clone := stats.Clone()
+	_, changed := clone.Columns[field]
+	delete(clone.Columns, field)
+
+	for _, idx := range c.Cache.GetTableIndexes(tableName) {
+		if _, ok := clone.Indexes[idx.IndexName]; ok && pathsUseField(idx.UsedPaths(), field) {
+			delete(clone.Indexes, idx.IndexName)
+			changed = true
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+
+	return c.SetTableStats(tx, clone)
+}
+
+// DropTableConstraint removes a table constraint from a table and returns it.
+// If the constraint is a unique constraint, its index is dropped.
+// If it is the primary key, a sequence is created to generate the new keys of the table
+// if the table doesn't already have one.
+func (c *CatalogWriter) DropTableConstraint(tx *Transaction, tableName string, name string) (*TableConstraint, error) {
+	r, err := c.Cache.Get(RelationTableType, tableName)
+	if err != nil {
+		return nil, err
+	}
+	ti := r.(*TableInfoRelation).Info
+
+	if ti.ReadOnly {
+		return nil, errors.New("cannot write to read-only table")
+	}
+
+	clone := ti.Clone()
+
+	var tc *TableConstraint
+	clone.TableConstraints = clone.TableConstraints[:0]
+	for _, t := range ti.TableConstraints {
+		if t.Name == name {
+			tc = t
+			continue
+		}
+		clone.TableConstraints = append(clone.TableConstraints, t)
+	}
+	if tc == nil {
+		return nil, errors.Errorf("constraint %q does not exist for table %q", name, tableName)
+	}
+
+	if tc.PrimaryKey || tc.Unique {
+		for _, ref := range c.ListReferences(tableName) {
+			if ref.Constraint.ForeignKey.Paths.IsEqual(tc.Paths) {
+				return nil, errors.Errorf("cannot drop constraint %q because constraint %q of table %q depends on it", name, ref.Constraint.Name, ref.TableName)
+			}
+		}
+	}
+
+	switch {
+	case tc.PrimaryKey:
+		clone.PrimaryKey = nil
+		if clone.RowidSequenceName == "" {
+			seq := NewRowidSequenceInfo(tableName)
+			err = c.CreateSequence(tx, seq)
+			if err != nil {
+				return nil, err
+			}
+			clone.RowidSequenceName = seq.Name
+		}
+	case tc.Unique:
+		for _, idx := range c.Cache.GetTableIndexes(tableName) {
+			if !idx.Owner.Paths.IsEqual(tc.Paths) {
+				continue
+			}
+
+			_, err = c.Cache.Delete(tx, RelationIndexType, idx.IndexName)
+			if err != nil {
+				return nil, err
+			}
+
+			err = c.dropIndex(tx, idx)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	err = c.replaceTableInfo(tx, clone)
+	if err != nil {
+		return nil, err
+	}
+
+	return tc, nil
+}
+
+// ReplaceFieldConstraint replaces the constraint of a field of a table.
+func (c *CatalogWriter) ReplaceFieldConstraint(tx *Transaction, tableName string, fc *FieldConstraint) error {
+	r, err := c.Cache.Get(RelationTableType, tableName)
+	if err != nil {
+		return err
+	}
+	ti := r.(*TableInfoRelation).Info
+
+	clone := ti.Clone()
+	err = clone.FieldConstraints.Replace(fc)
+	if err != nil {
+		return err
+	}
+	clone.PrimaryKey = nil
+	clone.BuildPrimaryKey()
+
+	// the type of the field may not match the foreign keys anymore
+	err = c.checkForeignKeys(clone)
+	if err != nil {
+		return err
+	}
+	for _, ref := range c.ListReferences(tableName) {
+		if ref.TableName == tableName {
+			continue
+		}
+
+		rti, err := c.GetTableInfo(ref.TableName)
+		if err != nil {
+			return err
+		}
+		err = checkForeignKeyTypes(rti, ref.Constraint, clone)
+		if err != nil {
+			return err
+		}
+	}
+
+	if old := ti.GetFieldConstraintForPath(object.NewPath(fc.Field)); old != nil && old.Type != fc.Type {
+		err = c.deleteFieldStats(tx, tableName, fc.Field)
+		if err != nil {
+			return err
+		}
+	}
+
+	return c.replaceTableInfo(tx, clone)
+}
+
+func (c *CatalogWriter) replaceTableInfo(tx *Transaction, ti *TableInfo) error {
+	rel := &TableInfoRelation{Info: ti}
+	err := c.Cache.Replace(tx, rel)
+	if err != nil {
+		return err
+	}
+
+	return c.CatalogTable.Replace(tx, ti.TableName, rel)
+}
+
+// pathsUseField returns true if any of the paths starts with the given field.
+func pathsUseField(paths []object.Path, field string) bool {
+	for _, p := range paths {
+		if len(p) > 0 && p[0].FieldName == field {
+			return true
+		}
+	}
+
+	return false
+}
+
 // RenameTable renames a table.
 // If it doesn't exist, it returns errs.ErrTableNotFound.
 func (c *CatalogWriter) RenameTable(tx *Transaction, oldName, newName string) error {
@@ -419,6 +710,7 @@ func (c *CatalogWriter) RenameTable(tx *Transaction, oldName, newName string) er
 
 	clone := ti.Clone()
 	clone.TableName = newName
+	clone.TableConstraints = renameReferences(clone.TableConstraints, oldName, newName)
 
 	cloneRel := &TableInfoRelation{
 		Info: clone,
@@ -455,6 +747,55 @@ func (c *CatalogWriter) RenameTable(tx *Transaction, oldName, newName string) er
 		}
 	}
 
+	for _, t := range c.Cache.getTableTriggers(oldName) {
+		tClone := *t
+		tClone.TableName = newName
+
+		cloneRel := &TriggerInfoRelation{Info: &tClone}
+		err = c.Cache.Replace(tx, cloneRel)
+		if err != nil {
+			return err
+		}
+
+		err = c.CatalogTable.Replace(tx, t.TriggerName, cloneRel)
+		if err != nil {
+			return err
+		}
+	}
+
+	if stats := c.GetTableStats(oldName); stats != nil {
+		err = c.DeleteTableStats(tx, oldName)
+		if err != nil {
+			return err
+		}
+
+		clone := stats.Clone()
+		clone.TableName = newName
+		err = c.SetTableStats(tx, clone)
+		if err != nil {
+			return err
+		}
+	}
+
+	// update the foreign keys of the other tables referencing it
+	for _, ref := range c.ListReferences(oldName) {
+		if ref.TableName == newName {
+			continue
+		}
+
+		ti, err := c.GetTableInfo(ref.TableName)
+		if err != nil {
+			return err
+		}
+
+		refClone := ti.Clone()
+		refClone.TableConstraints = renameReferences(refClone.TableConstraints, oldName, newName)
+		err = c.replaceTableInfo(tx, refClone)
+		if err != nil {
+			return err
+		}
+	}
+
 	for _, seqName := range c.ListSequences() {
 		seq, err := c.GetSequence(seqName)
 		if err != nil {
@@ -486,6 +827,96 @@ func (c *CatalogWriter) RenameTable(tx *Transaction, oldName, newName string) er
 	return nil
 }
 
+// SetTableStats stores the statistics of a table,
+// replacing the previous ones.
+func (c *CatalogWriter) SetTableStats(tx *Transaction, stats *TableStats) error {
+	err := c.DeleteTableStats(tx, stats.TableName)
+	if err != nil {
+		return err
+	}
+
+	tb, err := c.getOrCreateStatsTable(tx)
+	if err != nil {
+		return err
+	}
+
+	for _, o := range tableStatsToObjects(stats) {
+		_, _, err = tb.Insert(o)
+		if err != nil {
+			return err
+		}
+	}
+
+	c.Cache.setStats(tx, stats.TableName, stats)
+	return nil
+}
+
+// DeleteTableStats deletes the statistics of a table, if any.
+func (c *CatalogWriter) DeleteTableStats(tx *Transaction, tableName string) error {
+	if c.GetTableStats(tableName) == nil {
+		return nil
+	}
+
+	tb, err := c.GetTable(tx, StatsTableName)
+	if err != nil {
+		return err
+	}
+
+	var keys []*tree.Key
+	rng := Range{Min: Pivot{types.NewTextValue(tableName)}, Exact: true}
+	err = tb.IterateOnRange(&rng, false, func(key *tree.Key, _ Row) error {
+		keys = append(keys, tree.NewEncodedKey(append([]byte{}, key.Encoded...)))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, k := range keys {
+		err = tb.Delete(k)
+		if err != nil {
+			return err
+		}
+	}
+
+	c.Cache.setStats(tx, tableName, nil)
+	return nil
+}
+
+func (c *CatalogWriter) getOrCreateStatsTable(tx *Transaction) (*Table, error) {
+	tb, err := c.GetTable(tx, StatsTableName)
+	if err == nil || !errs.IsNotFoundError(err) {
+		return tb, err
+	}
+
+	err = c.CreateTable(tx, StatsTableName, statsTableInfo.Clone())
+	if err != nil {
+		return nil, err
+	}
+
+	return c.GetTable(tx, StatsTableName)
+}
+
+// renameReferences returns a copy of the constraints where the foreign keys
+// referencing oldName reference newName instead.
+func renameReferences(tcs TableConstraints, oldName, newName string) TableConstraints {
+	cp := make(TableConstraints, len(tcs))
+	for i, tc := range tcs {
+		cp[i] = tc
+		if tc.ForeignKey == nil || tc.ForeignKey.TableName != oldName {
+			continue
+		}
+
+		tcCopy := *tc
+		fk := *tc.ForeignKey
+		fk.TableName = newName
+		tcCopy.ForeignKey = &fk
+		cp[i] = &tcCopy
+	}
+
+	return cp
+}
+
 // CreateSequence creates a sequence with the given name.
 func (c *CatalogWriter) CreateSequence(tx *Transaction, info *SequenceInfo) error {
 	if info == nil {
@@ -580,7 +1011,7 @@ func (r *IndexInfoRelation) SetName(name string) {
 }
 
 func (r *IndexInfoRelation) GenerateBaseName() string {
-	return fmt.Sprintf("%s_%s_idx", r.Info.Owner.TableName, pathsToIndexName(r.Info.Paths))
+	return fmt.Sprintf("%s_%s_idx", r.Info.Owner.TableName, pathsToIndexName(r.Info.KeyPaths()))
 }
 
 func (r *IndexInfoRelation) Clone() Relation {
@@ -607,6 +1038,11 @@ type catalogCache struct {
 	tables    map[string]Relation
 	indexes   map[string]Relation
 	sequences map[string]Relation
+	views     map[string]Relation
+	triggers  map[string]Relation
+
+	// statistics of the analyzed tables
+	stats map[string]*TableStats
 }
 
 func newCatalogCache() *catalogCache {
@@ -614,10 +1050,13 @@ func newCatalogCache() *catalogCache {
 		tables:    make(map[string]Relation),
 		indexes:   make(map[string]Relation),
 		sequences: make(map[string]Relation),
+		views:     make(map[string]Relation),
+		triggers:  make(map[string]Relation),
+		stats:     make(map[string]*TableStats),
 	}
 }
 
-func (c *catalogCache) Load(tables []TableInfo, indexes []IndexInfo, sequences []Sequence) {
+func (c *catalogCache) Load(tables []TableInfo, indexes []IndexInfo, sequences []Sequence, views []ViewInfo, triggers []TriggerInfo) {
 	for i := range tables {
 		c.tables[tables[i].TableName] = &TableInfoRelation{Info: &tables[i]}
 	}
@@ -629,6 +1068,14 @@ func (c *catalogCache) Load(tables []TableInfo, indexes []IndexInfo, sequences [
 	for i := range sequences {
 		c.sequences[sequences[i].Info.Name] = &sequences[i]
 	}
+
+	for i := range views {
+		c.views[views[i].ViewName] = &ViewInfoRelation{Info: &views[i]}
+	}
+
+	for i := range triggers {
+		c.triggers[triggers[i].TriggerName] = &TriggerInfoRelation{Info: &triggers[i]}
+	}
 }
 
 func (c *catalogCache) Clone() *catalogCache {
@@ -643,10 +1090,46 @@ func (c *catalogCache) Clone() *catalogCache {
 	for k, v := range c.sequences {
 		clone.sequences[k] = v
 	}
+	for k, v := range c.views {
+		clone.views[k] = v
+	}
+	for k, v := range c.triggers {
+		clone.triggers[k] = v
+	}
+	for k, v := range c.stats {
+		clone.stats[k] = v
+	}
 
 	return clone
 }
 
+// LoadStats loads the statistics of the analyzed tables.
+func (c *catalogCache) LoadStats(stats []*TableStats) {
+	for _, s := range stats {
+		c.stats[s.TableName] = s
+	}
+}
+
+// setStats replaces the statistics of a table.
+// If stats is nil, the statistics are removed.
+func (c *catalogCache) setStats(tx *Transaction, tableName string, stats *TableStats) {
+	old, ok := c.stats[tableName]
+
+	if stats == nil {
+		delete(c.stats, tableName)
+	} else {
+		c.stats[tableName] = stats
+	}
+
+	tx.OnRollbackHooks = append(tx.OnRollbackHooks, func() {
+		if ok {
+			c.stats[tableName] = old
+		} else {
+			delete(c.stats, tableName)
+		}
+	})
+}
+
 func (c *catalogCache) objectExists(name string) bool {
 	// checking if table exists with the same name
 	if _, ok := c.tables[name]; ok {
@@ -663,6 +1146,16 @@ func (c *catalogCache) objectExists(name string) bool {
 		return true
 	}
 
+	// checking if view exists with the same name
+	if _, ok := c.views[name]; ok {
+		return true
+	}
+
+	// checking if trigger exists with the same name
+	if _, ok := c.triggers[name]; ok {
+		return true
+	}
+
 	return false
 }
 
@@ -689,6 +1182,10 @@ func (c *catalogCache) getMapByType(tp string) map[string]Relation {
 		return c.indexes
 	case RelationSequenceType:
 		return c.sequences
+	case RelationViewType:
+		return c.views
+	case RelationTriggerType:
+		return c.triggers
 	}
 
 	panic(fmt.Sprintf("unknown catalog object type %q", tp))
@@ -911,6 +1408,10 @@ func relationToObject(r Relation) types.Object {
 		return indexInfoToObject(t.Info)
 	case *Sequence:
 		return sequenceInfoToObject(t.Info)
+	case *ViewInfoRelation:
+		return viewInfoToObject(t.Info)
+	case *TriggerInfoRelation:
+		return triggerInfoToObject(t.Info)
 	}
 
 	panic(fmt.Sprintf("relationToObject: unknown type %q", r.Type()))
@@ -955,6 +1456,25 @@ func sequenceInfoToObject(seq *SequenceInfo) types.Object {
 	return buf
 }
 
+func viewInfoToObject(v *ViewInfo) types.Object {
+	buf := object.NewFieldBuffer()
+	buf.Add("name", types.NewTextValue(v.ViewName))
+	buf.Add("type", types.NewTextValue(RelationViewType))
+	buf.Add("sql", types.NewTextValue(v.String()))
+
+	return buf
+}
+
+func triggerInfoToObject(t *TriggerInfo) types.Object {
+	buf := object.NewFieldBuffer()
+	buf.Add("name", types.NewTextValue(t.TriggerName))
+	buf.Add("type", types.NewTextValue(RelationTriggerType))
+	buf.Add("sql", types.NewTextValue(t.String()))
+	buf.Add("owner", types.NewObjectValue(ownerToObject(&Owner{TableName: t.TableName})))
+
+	return buf
+}
+
 func ownerToObject(owner *Owner) types.Object {
 	buf := object.NewFieldBuffer().Add("table_name", types.NewTextValue(owner.TableName))
 	if owner.Paths != nil {
@@ -42,6 +42,9 @@ func updateCatalog(t testing.TB, db *database.Database, fn func(tx *database.Tra
 // - DropTable
 // - RenameTable
 // - AddFieldConstraint
+// - DropFieldConstraint
+// - ReplaceFieldConstraint
+// - DropTableConstraint
 func TestCatalogTable(t *testing.T) {
 	t.Run("Get", func(t *testing.T) {
 		db := testutil.NewTestDB(t)
@@ -238,6 +241,149 @@ func TestCatalogTable(t *testing.T) {
 
 		require.Equal(t, clone, db.Catalog())
 	})
+
+	t.Run("Drop field constraint", func(t *testing.T) {
+		db := testutil.NewTestDB(t)
+
+		ti := &database.TableInfo{FieldConstraints: database.MustNewFieldConstraints(
+			&database.FieldConstraint{Field: "name", Type: types.TypeText},
+			&database.FieldConstraint{Field: "age", Type: types.TypeInteger},
+			&database.FieldConstraint{Field: "city", Type: types.TypeText},
+		), TableConstraints: []*database.TableConstraint{
+			{Paths: []object.Path{testutil.ParseObjectPath(t, "age")}, PrimaryKey: true},
+		}}
+
+		updateCatalog(t, db, func(tx *database.Transaction, catalog *database.CatalogWriter) error {
+			return catalog.CreateTable(tx, "foo", ti)
+		})
+
+		clone := db.Catalog().Clone()
+
+		updateCatalog(t, db, func(tx *database.Transaction, catalog *database.CatalogWriter) error {
+			err := catalog.DropFieldConstraint(tx, "foo", "name")
+			assert.NoError(t, err)
+
+			tb, err := catalog.GetTable(tx, "foo")
+			assert.NoError(t, err)
+
+			require.Len(t, tb.Info.FieldConstraints.Ordered, 2)
+			require.Nil(t, tb.Info.FieldConstraints.ByField["name"])
+			require.Equal(t, 1, tb.Info.FieldConstraints.ByField["city"].Position)
+
+			// the previous table info must not be modified
+			require.Equal(t, 2, ti.FieldConstraints.ByField["city"].Position)
+
+			// Dropping a field used by a constraint should return an error
+			err = catalog.DropFieldConstraint(tx, "foo", "age")
+			assert.Error(t, err)
+
+			// Dropping a non existing field should return an error
+			err = catalog.DropFieldConstraint(tx, "foo", "unknown")
+			assert.Error(t, err)
+
+			return errDontCommit
+		})
+
+		require.Equal(t, clone, db.Catalog())
+	})
+
+	t.Run("Replace field constraint", func(t *testing.T) {
+		db := testutil.NewTestDB(t)
+
+		ti := &database.TableInfo{FieldConstraints: database.MustNewFieldConstraints(
+			&database.FieldConstraint{Field: "name", Type: types.TypeText},
+			&database.FieldConstraint{Field: "age", Type: types.TypeInteger},
+		)}
+
+		updateCatalog(t, db, func(tx *database.Transaction, catalog *database.CatalogWriter) error {
+			return catalog.CreateTable(tx, "foo", ti)
+		})
+
+		clone := db.Catalog().Clone()
+
+		updateCatalog(t, db, func(tx *database.Transaction, catalog *database.CatalogWriter) error {
+			err := catalog.ReplaceFieldConstraint(tx, "foo", &database.FieldConstraint{Field: "age", Type: types.TypeDouble, IsNotNull: true})
+			assert.NoError(t, err)
+
+			tb, err := catalog.GetTable(tx, "foo")
+			assert.NoError(t, err)
+
+			fc := tb.Info.FieldConstraints.ByField["age"]
+			require.Equal(t, types.TypeDouble, fc.Type)
+			require.True(t, fc.IsNotNull)
+			require.Equal(t, 1, fc.Position)
+
+			// Replacing a non existing field should return an error
+			err = catalog.ReplaceFieldConstraint(tx, "foo", &database.FieldConstraint{Field: "unknown"})
+			assert.Error(t, err)
+
+			return errDontCommit
+		})
+
+		require.Equal(t, clone, db.Catalog())
+	})
+
+	t.Run("Drop table constraint", func(t *testing.T) {
+		db := testutil.NewTestDB(t)
+
+		ti := &database.TableInfo{FieldConstraints: database.MustNewFieldConstraints(
+			&database.FieldConstraint{Field: "name", Type: types.TypeText},
+			&database.FieldConstraint{Field: "age", Type: types.TypeInteger},
+		), TableConstraints: []*database.TableConstraint{
+			{Name: "foo_pk", Paths: []object.Path{testutil.ParseObjectPath(t, "age")}, PrimaryKey: true},
+			{Name: "foo_name_unique", Paths: []object.Path{testutil.ParseObjectPath(t, "name")}, Unique: true},
+		}}
+		ti.BuildPrimaryKey()
+
+		updateCatalog(t, db, func(tx *database.Transaction, catalog *database.CatalogWriter) error {
+			err := catalog.CreateTable(tx, "foo", ti)
+			if err != nil {
+				return err
+			}
+
+			_, err = catalog.CreateIndex(tx, &database.IndexInfo{
+				Paths:  []object.Path{testutil.ParseObjectPath(t, "name")},
+				Unique: true,
+				Owner: database.Owner{
+					TableName: "foo",
+					Paths:     []object.Path{testutil.ParseObjectPath(t, "name")},
+				},
+			})
+			return err
+		})
+
+		clone := db.Catalog().Clone()
+
+		updateCatalog(t, db, func(tx *database.Transaction, catalog *database.CatalogWriter) error {
+			tc, err := catalog.DropTableConstraint(tx, "foo", "foo_name_unique")
+			assert.NoError(t, err)
+			require.True(t, tc.Unique)
+
+			// the index of the constraint must be dropped
+			require.Empty(t, catalog.ListIndexes("foo"))
+
+			tc, err = catalog.DropTableConstraint(tx, "foo", "foo_pk")
+			assert.NoError(t, err)
+			require.True(t, tc.PrimaryKey)
+
+			tb, err := catalog.GetTable(tx, "foo")
+			assert.NoError(t, err)
+			require.Empty(t, tb.Info.TableConstraints)
+			require.Nil(t, tb.Info.PrimaryKey)
+
+			// a sequence must be created to generate the keys
+			_, err = catalog.GetSequence(tb.Info.RowidSequenceName)
+			assert.NoError(t, err)
+
+			// Dropping a non existing constraint should return an error
+			_, err = catalog.DropTableConstraint(tx, "foo", "unknown")
+			assert.Error(t, err)
+
+			return errDontCommit
+		})
+
+		require.Equal(t, clone, db.Catalog())
+	})
 }
 
 func TestCatalogCreateTable(t *testing.T) {
@@ -632,3 +778,202 @@ func TestCatalogConcurrency(t *testing.T) {
 	assert.NoError(t, err)
 	require.Equal(t, "CREATE TABLE test (a INTEGER)", s)
 }
+
+type viewQuery string
+
+func (q viewQuery) String() string {
+	return string(q)
+}
+
+func (q viewQuery) Sources() []string {
+	return nil
+}
+
+func TestCatalogCreateView(t *testing.T) {
+	t.Run("Should create a view and add it to the catalog table", func(t *testing.T) {
+		db := testutil.NewTestDB(t)
+
+		updateCatalog(t, db, func(tx *database.Transaction, catalog *database.CatalogWriter) error {
+			return catalog.CreateView(tx, &database.ViewInfo{ViewName: "v", Query: viewQuery("SELECT 1")})
+		})
+
+		info, err := db.Catalog().GetViewInfo("v")
+		assert.NoError(t, err)
+		require.Equal(t, "CREATE VIEW v AS SELECT 1", info.String())
+		require.Equal(t, []string{"v"}, db.Catalog().ListViews())
+
+		tx, err := db.Begin(false)
+		assert.NoError(t, err)
+		defer tx.Rollback()
+
+		r, err := db.Catalog().CatalogTable.Table(tx).GetRow(tree.NewKey(types.NewTextValue("v")))
+		assert.NoError(t, err)
+		testutil.RequireJSONEq(t, r, `{"name": "v", "type": "view", "sql": "CREATE VIEW v AS SELECT 1"}`)
+	})
+
+	t.Run("Should fail if a relation with the same name exists", func(t *testing.T) {
+		db := testutil.NewTestDB(t)
+
+		updateCatalog(t, db, func(tx *database.Transaction, catalog *database.CatalogWriter) error {
+			err := catalog.CreateTable(tx, "test", nil)
+			assert.NoError(t, err)
+
+			err = catalog.CreateView(tx, &database.ViewInfo{ViewName: "test", Query: viewQuery("SELECT 1")})
+			assert.ErrorIs(t, err, errs.AlreadyExistsError{Name: "test"})
+
+			err = catalog.CreateView(tx, &database.ViewInfo{ViewName: "v", Query: viewQuery("SELECT 1")})
+			assert.NoError(t, err)
+
+			err = catalog.CreateTable(tx, "v", nil)
+			assert.ErrorIs(t, err, errs.AlreadyExistsError{Name: "v"})
+
+			return errDontCommit
+		})
+	})
+
+	t.Run("Should drop a view", func(t *testing.T) {
+		db := testutil.NewTestDB(t)
+
+		updateCatalog(t, db, func(tx *database.Transaction, catalog *database.CatalogWriter) error {
+			return catalog.CreateView(tx, &database.ViewInfo{ViewName: "v", Query: viewQuery("SELECT 1")})
+		})
+
+		updateCatalog(t, db, func(tx *database.Transaction, catalog *database.CatalogWriter) error {
+			return catalog.DropView(tx, "v")
+		})
+
+		_, err := db.Catalog().GetViewInfo("v")
+		require.True(t, errs.IsNotFoundError(err))
+		require.Empty(t, db.Catalog().ListViews())
+
+		updateCatalog(t, db, func(tx *database.Transaction, catalog *database.CatalogWriter) error {
+			err := catalog.DropView(tx, "v")
+			assert.ErrorIs(t, err, errs.NotFoundError{Name: "v"})
+			return errDontCommit
+		})
+	})
+}
+
+func TestCatalogCreateTrigger(t *testing.T) {
+	newTrigger := func(name, tableName string) *database.TriggerInfo {
+		return &database.TriggerInfo{
+			TriggerName: name,
+			TableName:   tableName,
+			Timing:      database.TriggerAfter,
+			Event:       database.TriggerInsert,
+			Action:      viewQuery("BEGIN SELECT 1; END"),
+		}
+	}
+
+	t.Run("Should create a trigger and add it to the catalog table", func(t *testing.T) {
+		db := testutil.NewTestDB(t)
+
+		updateCatalog(t, db, func(tx *database.Transaction, catalog *database.CatalogWriter) error {
+			err := catalog.CreateTable(tx, "test", nil)
+			assert.NoError(t, err)
+
+			return catalog.CreateTrigger(tx, newTrigger("trg", "test"))
+		})
+
+		info, err := db.Catalog().GetTriggerInfo("trg")
+		assert.NoError(t, err)
+		require.Equal(t, "CREATE TRIGGER trg AFTER INSERT ON test FOR EACH ROW BEGIN SELECT 1; END", info.String())
+		require.Equal(t, []*database.TriggerInfo{info}, db.Catalog().ListTriggers("test", database.TriggerInsert))
+		require.Empty(t, db.Catalog().ListTriggers("test", database.TriggerDelete))
+
+		tx, err := db.Begin(false)
+		assert.NoError(t, err)
+		defer tx.Rollback()
+
+		r, err := db.Catalog().CatalogTable.Table(tx).GetRow(tree.NewKey(types.NewTextValue("trg")))
+		assert.NoError(t, err)
+		testutil.RequireJSONEq(t, r, `{"name": "trg", "type": "trigger", "sql": "CREATE TRIGGER trg AFTER INSERT ON test FOR EACH ROW BEGIN SELECT 1; END", "owner": {"table_name": "test"}}`)
+	})
+
+	t.Run("Should fail if the table doesn't exist", func(t *testing.T) {
+		db := testutil.NewTestDB(t)
+
+		updateCatalog(t, db, func(tx *database.Transaction, catalog *database.CatalogWriter) error {
+			err := catalog.CreateTrigger(tx, newTrigger("trg", "test"))
+			require.True(t, errs.IsNotFoundError(err))
+			return errDontCommit
+		})
+	})
+
+	t.Run("Should fail if a relation with the same name exists", func(t *testing.T) {
+		db := testutil.NewTestDB(t)
+
+		updateCatalog(t, db, func(tx *database.Transaction, catalog *database.CatalogWriter) error {
+			err := catalog.CreateTable(tx, "test", nil)
+			assert.NoError(t, err)
+
+			err = catalog.CreateTrigger(tx, newTrigger("test", "test"))
+			assert.ErrorIs(t, err, errs.AlreadyExistsError{Name: "test"})
+
+			return errDontCommit
+		})
+	})
+
+	t.Run("Should drop the triggers of a dropped table", func(t *testing.T) {
+		db := testutil.NewTestDB(t)
+
+		updateCatalog(t, db, func(tx *database.Transaction, catalog *database.CatalogWriter) error {
+			err := catalog.CreateTable(tx, "test", nil)
+			assert.NoError(t, err)
+
+			return catalog.CreateTrigger(tx, newTrigger("trg", "test"))
+		})
+
+		updateCatalog(t, db, func(tx *database.Transaction, catalog *database.CatalogWriter) error {
+			return catalog.DropTable(tx, "test")
+		})
+
+		_, err := db.Catalog().GetTriggerInfo("trg")
+		require.True(t, errs.IsNotFoundError(err))
+	})
+
+	t.Run("Should update the triggers of a renamed table", func(t *testing.T) {
+		db := testutil.NewTestDB(t)
+
+		updateCatalog(t, db, func(tx *database.Transaction, catalog *database.CatalogWriter) error {
+			err := catalog.CreateTable(tx, "test", nil)
+			assert.NoError(t, err)
+
+			return catalog.CreateTrigger(tx, newTrigger("trg", "test"))
+		})
+
+		updateCatalog(t, db, func(tx *database.Transaction, catalog *database.CatalogWriter) error {
+			return catalog.RenameTable(tx, "test", "foo")
+		})
+
+		info, err := db.Catalog().GetTriggerInfo("trg")
+		assert.NoError(t, err)
+		require.Equal(t, "foo", info.TableName)
+		require.Empty(t, db.Catalog().ListTriggers("test", database.TriggerInsert))
+		require.Len(t, db.Catalog().ListTriggers("foo", database.TriggerInsert), 1)
+	})
+
+	t.Run("Should drop a trigger", func(t *testing.T) {
+		db := testutil.NewTestDB(t)
+
+		updateCatalog(t, db, func(tx *database.Transaction, catalog *database.CatalogWriter) error {
+			err := catalog.CreateTable(tx, "test", nil)
+			assert.NoError(t, err)
+
+			return catalog.CreateTrigger(tx, newTrigger("trg", "test"))
+		})
+
+		updateCatalog(t, db, func(tx *database.Transaction, catalog *database.CatalogWriter) error {
+			return catalog.DropTrigger(tx, "trg")
+		})
+
+		_, err := db.Catalog().GetTriggerInfo("trg")
+		require.True(t, errs.IsNotFoundError(err))
+
+		updateCatalog(t, db, func(tx *database.Transaction, catalog *database.CatalogWriter) error {
+			err := catalog.DropTrigger(tx, "trg")
+			assert.ErrorIs(t, err, errs.NotFoundError{Name: "trg"})
+			return errDontCommit
+		})
+	})
+}
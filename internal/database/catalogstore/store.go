@@ -19,7 +19,7 @@ func LoadCatalog(tx *database.Transaction) error {
 		return err
 	}
 
-	tables, indexes, sequences, err := loadCatalogStore(tx, tx.Catalog.CatalogTable)
+	tables, indexes, sequences, views, triggers, err := loadCatalogStore(tx, tx.Catalog.CatalogTable)
 	if err != nil {
 		return errors.Wrap(err, "failed to load catalog store")
 	}
@@ -31,8 +31,8 @@ func LoadCatalog(tx *database.Transaction) error {
 	ti.ReadOnly = true
 	tables = append(tables, *ti)
 
-	// load tables and indexes first
-	tx.Catalog.Cache.Load(tables, indexes, nil)
+	// load tables, indexes, views and triggers first
+	tx.Catalog.Cache.Load(tables, indexes, nil, views, triggers)
 
 	if len(sequences) > 0 {
 		var seqList []database.Sequence
@@ -41,7 +41,17 @@ func LoadCatalog(tx *database.Transaction) error {
 			return errors.Wrap(err, "failed to load sequences")
 		}
 
-		tx.Catalog.Cache.Load(nil, nil, seqList)
+		tx.Catalog.Cache.Load(nil, nil, seqList, nil, nil)
+	}
+
+	// load the statistics of the analyzed tables, if any
+	if _, err := tx.Catalog.GetTableInfo(database.StatsTableName); err == nil {
+		stats, err := database.LoadTableStats(tx)
+		if err != nil {
+			return errors.Wrap(err, "failed to load statistics")
+		}
+
+		tx.Catalog.Cache.LoadStats(stats)
 	}
 
 	return nil
@@ -78,7 +88,7 @@ func loadSequences(tx *database.Transaction, info []database.SequenceInfo) ([]da
 	return sequences, nil
 }
 
-func loadCatalogStore(tx *database.Transaction, s *database.CatalogStore) (tables []database.TableInfo, indexes []database.IndexInfo, sequences []database.SequenceInfo, err error) {
+func loadCatalogStore(tx *database.Transaction, s *database.CatalogStore) (tables []database.TableInfo, indexes []database.IndexInfo, sequences []database.SequenceInfo, views []database.ViewInfo, triggers []database.TriggerInfo, err error) {
 	tb := s.Table(tx)
 
 	err = tb.IterateOnRange(nil, false, func(key *tree.Key, r database.Row) error {
@@ -107,6 +117,18 @@ func loadCatalogStore(tx *database.Transaction, s *database.CatalogStore) (table
 				return errors.Wrap(err, "failed to decode sequence info")
 			}
 			sequences = append(sequences, *i)
+		case database.RelationViewType:
+			v, err := viewInfoFromRow(r)
+			if err != nil {
+				return errors.Wrap(err, "failed to decode view info")
+			}
+			views = append(views, *v)
+		case database.RelationTriggerType:
+			t, err := triggerInfoFromRow(r)
+			if err != nil {
+				return errors.Wrap(err, "failed to decode trigger info")
+			}
+			triggers = append(triggers, *t)
 		}
 
 		return nil
@@ -219,6 +241,36 @@ func sequenceInfoFromRow(r database.Row) (*database.SequenceInfo, error) {
 	return &i, nil
 }
 
+func viewInfoFromRow(r database.Row) (*database.ViewInfo, error) {
+	s, err := r.Get("sql")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get sql field")
+	}
+
+	stmt, err := parser.NewParser(strings.NewReader(types.AsString(s))).ParseStatement()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse sql")
+	}
+
+	i := stmt.(*statement.CreateViewStmt).Info
+	return &i, nil
+}
+
+func triggerInfoFromRow(r database.Row) (*database.TriggerInfo, error) {
+	s, err := r.Get("sql")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get sql field")
+	}
+
+	stmt, err := parser.NewParser(strings.NewReader(types.AsString(s))).ParseStatement()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse sql")
+	}
+
+	i := stmt.(*statement.CreateTriggerStmt).Info
+	return &i, nil
+}
+
 func ownerFromObject(o types.Object) (*database.Owner, error) {
 	var owner database.Owner
 
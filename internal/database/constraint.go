@@ -116,6 +116,57 @@ func (f *FieldConstraints) Add(newFc *FieldConstraint) error {
 	return nil
 }
 
+// Replace the constraint of the field with the same name.
+func (f *FieldConstraints) Replace(newFc *FieldConstraint) error {
+	if _, ok := f.ByField[newFc.Field]; !ok {
+		return errors.Errorf("field %q not found", newFc.Field)
+	}
+
+	return f.rebuild(func(fc *FieldConstraint) *FieldConstraint {
+		if fc.Field == newFc.Field {
+			return newFc
+		}
+		return fc
+	})
+}
+
+// Remove the constraint of the given field.
+func (f *FieldConstraints) Remove(field string) error {
+	if _, ok := f.ByField[field]; !ok {
+		return errors.Errorf("field %q not found", field)
+	}
+
+	return f.rebuild(func(fc *FieldConstraint) *FieldConstraint {
+		if fc.Field == field {
+			return nil
+		}
+		return fc
+	})
+}
+
+// rebuild the list with the constraints returned by fn, skipping nil ones.
+// Constraints are copied before being added as their position may change
+// and they may be shared with the list this one was cloned from.
+func (f *FieldConstraints) rebuild(fn func(fc *FieldConstraint) *FieldConstraint) error {
+	old := f.Ordered
+	f.Ordered = nil
+	f.ByField = make(map[string]*FieldConstraint)
+
+	for _, fc := range old {
+		fc = fn(fc)
+		if fc == nil {
+			continue
+		}
+
+		cp := *fc
+		if err := f.Add(&cp); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // ConversionFunc is called when the type of a value is different than the expected type
 // and the value needs to be converted.
 type ConversionFunc func(v types.Value, path object.Path, targetType types.Type) (types.Value, error)
@@ -229,6 +280,7 @@ type TableConstraint struct {
 	Check      TableExpression
 	Unique     bool
 	PrimaryKey bool
+	ForeignKey *ForeignKey
 	SortOrder  tree.SortOrder
 }
 
@@ -269,6 +321,16 @@ func (t *TableConstraint) String() string {
 			}
 		}
 		sb.WriteString(")")
+	case t.ForeignKey != nil:
+		sb.WriteString(" FOREIGN KEY (")
+		for i, pt := range t.Paths {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString(pt.String())
+		}
+		sb.WriteString(") ")
+		sb.WriteString(t.ForeignKey.String())
 	}
 
 	return sb.String()
@@ -350,10 +412,17 @@ func (an *AnonymousType) String() string {
 type ConstraintViolationError struct {
 	Constraint string
 	Paths      []object.Path
-	Key        *tree.Key
+	// Exprs, if set, are the expressions of the index whose constraint
+	// is violated, displayed instead of Paths.
+	Exprs []string
+	Key   *tree.Key
 }
 
 func (c ConstraintViolationError) Error() string {
+	if len(c.Exprs) > 0 {
+		return fmt.Sprintf("%s constraint error: %s", c.Constraint, c.Exprs)
+	}
+
 	return fmt.Sprintf("%s constraint error: %s", c.Constraint, c.Paths)
 }
 
@@ -12,8 +12,9 @@ import (
 // EncodeObject validates a row against all the constraints of the table
 // and encodes it.
 func (t *TableInfo) EncodeObject(tx *Transaction, dst []byte, o types.Object) ([]byte, error) {
-	if ed, ok := o.(*encoding.EncodedObject); ok {
-		return ed.Encoded, nil
+	// objects read from this table are already encoded using its schema
+	if eo, ok := o.(*EncodedObject); ok && eo.fieldConstraints == &t.FieldConstraints {
+		return append(dst, eo.encoded...), nil
 	}
 
 	return encodeObject(tx, dst, &t.FieldConstraints, o)
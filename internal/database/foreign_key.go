@@ -0,0 +1,626 @@
+package database
+
+import (
+	"bytes"
+	"strings"
+
+	errs "github.com/chaisql/chai/internal/errors"
+	"github.com/chaisql/chai/internal/object"
+	"github.com/chaisql/chai/internal/stringutil"
+	"github.com/chaisql/chai/internal/tree"
+	"github.com/chaisql/chai/internal/types"
+	"github.com/cockroachdb/errors"
+)
+
+// ForeignKeyAction is the action performed on the referencing rows
+// when the row they reference is deleted or updated.
+type ForeignKeyAction uint8
+
+const (
+	// ForeignKeyNoAction refuses to delete or update a referenced row.
+	// It is the default action.
+	ForeignKeyNoAction ForeignKeyAction = iota
+	// ForeignKeyRestrict refuses to delete or update a referenced row.
+	ForeignKeyRestrict
+	// ForeignKeyCascade deletes the referencing rows, or updates them
+	// with the new values of the referenced row.
+	ForeignKeyCascade
+	// ForeignKeySetNull sets the referencing fields to NULL.
+	ForeignKeySetNull
+)
+
+func (a ForeignKeyAction) String() string {
+	switch a {
+	case ForeignKeyRestrict:
+		return "RESTRICT"
+	case ForeignKeyCascade:
+		return "CASCADE"
+	case ForeignKeySetNull:
+		return "SET NULL"
+	}
+
+	return "NO ACTION"
+}
+
+// A ForeignKey describes the rows referenced by a table constraint.
+// The paths of the constraint must match either the primary key
+// or a unique constraint of the referenced table.
+type ForeignKey struct {
+	// name of the referenced table.
+	TableName string
+	// referenced paths. If empty, the primary key of the
+	// referenced table is used.
+	Paths    object.Paths
+	OnDelete ForeignKeyAction
+	OnUpdate ForeignKeyAction
+}
+
+func (f *ForeignKey) String() string {
+	var sb strings.Builder
+
+	sb.WriteString("REFERENCES ")
+	sb.WriteString(stringutil.NormalizeIdentifier(f.TableName, '`'))
+
+	if len(f.Paths) > 0 {
+		sb.WriteString(" (")
+		for i, p := range f.Paths {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString(p.String())
+		}
+		sb.WriteString(")")
+	}
+
+	if f.OnDelete != ForeignKeyNoAction {
+		sb.WriteString(" ON DELETE ")
+		sb.WriteString(f.OnDelete.String())
+	}
+	if f.OnUpdate != ForeignKeyNoAction {
+		sb.WriteString(" ON UPDATE ")
+		sb.WriteString(f.OnUpdate.String())
+	}
+
+	return sb.String()
+}
+
+// A Reference is a foreign key of a table referencing another table.
+type Reference struct {
+	// name of the referencing table.
+	TableName  string
+	Constraint *TableConstraint
+}
+
+// ListReferences returns the foreign keys referencing the given table,
+// including the ones of the table itself.
+func (c *Catalog) ListReferences(tableName string) []Reference {
+	var refs []Reference
+
+	for _, name := range c.Cache.ListObjects(RelationTableType) {
+		ti, err := c.GetTableInfo(name)
+		if err != nil {
+			continue
+		}
+
+		for _, tc := range ti.TableConstraints {
+			if tc.ForeignKey != nil && tc.ForeignKey.TableName == tableName {
+				refs = append(refs, Reference{TableName: name, Constraint: tc})
+			}
+		}
+	}
+
+	return refs
+}
+
+// checkForeignKeys ensures the foreign keys of the table reference
+// the primary key or a unique constraint of an existing table.
+// Foreign keys without referenced paths are set to reference the primary key.
+func (c *CatalogWriter) checkForeignKeys(ti *TableInfo) error {
+	for _, tc := range ti.TableConstraints {
+		fk := tc.ForeignKey
+		if fk == nil {
+			continue
+		}
+
+		ref := ti
+		if fk.TableName != ti.TableName {
+			var err error
+			ref, err = c.GetTableInfo(fk.TableName)
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(fk.Paths) == 0 {
+			if ref.PrimaryKey == nil {
+				return errors.Errorf("table %q referenced by constraint %q has no primary key", ref.TableName, tc.Name)
+			}
+			fk.Paths = ref.PrimaryKey.Paths
+		}
+
+		if len(fk.Paths) != len(tc.Paths) {
+			return errors.Errorf("constraint %q must reference as many fields as it has", tc.Name)
+		}
+
+		var found bool
+		for _, rtc := range ref.TableConstraints {
+			if (rtc.PrimaryKey || rtc.Unique) && rtc.Paths.IsEqual(fk.Paths) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return errors.Errorf("no primary key or unique constraint of table %q matches (%s)", ref.TableName, fk.Paths)
+		}
+
+		err := checkForeignKeyTypes(ti, tc, ref)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkForeignKeyTypes ensures the values of the fields of the foreign key
+// can be compared with the values of the fields of the referenced table.
+func checkForeignKeyTypes(ti *TableInfo, tc *TableConstraint, ref *TableInfo) error {
+	for i, p := range tc.Paths {
+		fc := ti.GetFieldConstraintForPath(p)
+		rfc := ref.GetFieldConstraintForPath(tc.ForeignKey.Paths[i])
+		if fc == nil || rfc == nil || fc.Type.IsAny() || rfc.Type.IsAny() {
+			continue
+		}
+
+		if !fc.Type.IsComparableWith(rfc.Type) {
+			return errors.Errorf("constraint %q: field %q of type %s cannot reference field %q of type %s", tc.Name, p, fc.Type, tc.ForeignKey.Paths[i], rfc.Type)
+		}
+	}
+
+	return nil
+}
+
+// ValidateForeignKeys ensures the rows referenced by the foreign keys
+// of the table exist. Foreign keys with a NULL field are ignored.
+// A row may reference itself.
+func (ti *TableInfo) ValidateForeignKeys(tx *Transaction, o types.Object) error {
+	for _, tc := range ti.TableConstraints {
+		fk := tc.ForeignKey
+		if fk == nil {
+			continue
+		}
+
+		vs, err := foreignKeyValues(tc.Paths, o)
+		if err != nil {
+			return err
+		}
+		if vs == nil {
+			continue
+		}
+
+		if fk.TableName == ti.TableName {
+			self, err := foreignKeyValues(fk.Paths, o)
+			if err != nil {
+				return err
+			}
+			if self != nil && valuesEqual(vs, self) {
+				continue
+			}
+		}
+
+		parent, err := tx.Catalog.GetTable(tx, fk.TableName)
+		if err != nil {
+			return err
+		}
+
+		ok, err := parent.hasRow(fk.Paths, vs)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errors.Errorf("insert or update on table %q violates foreign key constraint %q", ti.TableName, tc.Name)
+		}
+	}
+
+	return nil
+}
+
+// ApplyOnDelete performs the ON DELETE actions of the foreign keys
+// referencing the given row, which must have been deleted beforehand.
+// The triggers of the referencing tables are not executed.
+func (t *Table) ApplyOnDelete(r Row) error {
+	for _, ref := range t.Tx.Catalog.ListReferences(t.Info.TableName) {
+		fk := ref.Constraint.ForeignKey
+
+		vs, err := foreignKeyValues(fk.Paths, r.Object())
+		if err != nil {
+			return err
+		}
+		if vs == nil {
+			continue
+		}
+
+		child, err := t.Tx.Catalog.GetTable(t.Tx, ref.TableName)
+		if err != nil {
+			return err
+		}
+
+		keys, err := child.referencingKeys(ref.Constraint.Paths, vs)
+		if err != nil {
+			return err
+		}
+
+		for _, k := range keys {
+			cr, err := child.GetRow(k)
+			if errs.IsNotFoundError(err) {
+				// already deleted by another action
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			switch fk.OnDelete {
+			case ForeignKeyCascade:
+				err = child.deleteRow(cr)
+				if err == nil {
+					err = child.ApplyOnDelete(cr)
+				}
+			case ForeignKeySetNull:
+				err = child.setReferences(cr, ref.Constraint.Paths, nil)
+			default:
+				err = errors.Errorf("update or delete on table %q violates foreign key constraint %q on table %q", t.Info.TableName, ref.Constraint.Name, ref.TableName)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// ApplyOnUpdate performs the ON UPDATE actions of the foreign keys
+// referencing the old version of a row, which must have been replaced
+// beforehand by o.
+// The triggers of the referencing tables are not executed.
+func (t *Table) ApplyOnUpdate(old Row, o types.Object) error {
+	for _, ref := range t.Tx.Catalog.ListReferences(t.Info.TableName) {
+		fk := ref.Constraint.ForeignKey
+
+		vs, err := foreignKeyValues(fk.Paths, old.Object())
+		if err != nil {
+			return err
+		}
+		if vs == nil {
+			continue
+		}
+
+		newVs, err := foreignKeyValues(fk.Paths, o)
+		if err != nil {
+			return err
+		}
+		if newVs != nil && valuesEqual(vs, newVs) {
+			continue
+		}
+
+		child, err := t.Tx.Catalog.GetTable(t.Tx, ref.TableName)
+		if err != nil {
+			return err
+		}
+
+		keys, err := child.referencingKeys(ref.Constraint.Paths, vs)
+		if err != nil {
+			return err
+		}
+
+		for _, k := range keys {
+			cr, err := child.GetRow(k)
+			if errs.IsNotFoundError(err) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			switch fk.OnUpdate {
+			case ForeignKeyCascade:
+				err = child.setReferences(cr, ref.Constraint.Paths, newVs)
+			case ForeignKeySetNull:
+				err = child.setReferences(cr, ref.Constraint.Paths, nil)
+			default:
+				err = errors.Errorf("update or delete on table %q violates foreign key constraint %q on table %q", t.Info.TableName, ref.Constraint.Name, ref.TableName)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// hasRow returns whether a row has the given values for the paths
+// of its primary key or of one of its unique constraints.
+func (t *Table) hasRow(paths object.Paths, vs []types.Value) (bool, error) {
+	vs, err := t.convertValues(paths, vs)
+	if err != nil {
+		return false, err
+	}
+
+	if pk := t.Info.PrimaryKey; pk != nil && pk.Paths.IsEqual(paths) {
+		return t.Tree.Exists(tree.NewKey(vs...))
+	}
+
+	for _, info := range t.Tx.Catalog.Cache.GetTableIndexes(t.Info.TableName) {
+		if !info.Unique || info.Predicate != nil || !object.Paths(info.Paths).IsEqual(paths) {
+			continue
+		}
+
+		idx, err := t.Tx.Catalog.GetIndex(t.Tx, info.IndexName)
+		if err != nil {
+			return false, err
+		}
+
+		ok, _, err := idx.Exists(vs)
+		return ok, err
+	}
+
+	return false, errors.Errorf("no primary key or unique index of table %q matches (%s)", t.Info.TableName, paths)
+}
+
+// referencingKeys returns the keys of the rows having the given values
+// at the given paths. If an index starts with the paths, it is used
+// instead of scanning the table.
+func (t *Table) referencingKeys(paths object.Paths, vs []types.Value) ([]*tree.Key, error) {
+	vs, err := t.convertValues(paths, vs)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []*tree.Key
+
+	for _, info := range t.Tx.Catalog.Cache.GetTableIndexes(t.Info.TableName) {
+		if info.Predicate != nil || info.FullText || len(info.Paths) < len(paths) || !object.Paths(info.Paths[:len(paths)]).IsEqual(paths) {
+			continue
+		}
+
+		idx, err := t.Tx.Catalog.GetIndex(t.Tx, info.IndexName)
+		if err != nil {
+			return nil, err
+		}
+
+		k := tree.NewKey(vs...)
+		err = idx.IterateOnRange(&tree.Range{Min: k, Max: k}, false, func(key *tree.Key) error {
+			keys = append(keys, tree.NewEncodedKey(bytes.Clone(key.Encoded)))
+			return nil
+		})
+		return keys, err
+	}
+
+	err = t.IterateOnRange(nil, false, func(key *tree.Key, r Row) error {
+		rvs, err := foreignKeyValues(paths, r.Object())
+		if err != nil || rvs == nil || !valuesEqual(vs, rvs) {
+			return err
+		}
+
+		keys = append(keys, tree.NewEncodedKey(bytes.Clone(key.Encoded)))
+		return nil
+	})
+	return keys, err
+}
+
+// setReferences replaces the values of the row at the given paths
+// and performs the ON UPDATE actions of the foreign keys referencing it.
+// If vs is nil, the paths are set to NULL.
+// The new row must satisfy the constraints of the table.
+func (t *Table) setReferences(r Row, paths object.Paths, vs []types.Value) error {
+	fb := object.NewFieldBuffer()
+	err := fb.Copy(r.Object())
+	if err != nil {
+		return err
+	}
+
+	for i, p := range paths {
+		var v types.Value = types.NewNullValue()
+		if vs != nil {
+			v = vs[i]
+		}
+
+		err = fb.Set(p, v)
+		if err != nil {
+			return err
+		}
+	}
+
+	// validate the new row like any row written to the table
+	buf, err := t.Info.EncodeObject(t.Tx, nil, fb)
+	if err != nil {
+		return err
+	}
+	eo := NewEncodedObject(&t.Info.FieldConstraints, buf)
+
+	err = t.Info.TableConstraints.ValidateRow(t.Tx, &BasicRow{tableName: t.Info.TableName, key: r.Key(), obj: eo})
+	if err != nil {
+		return err
+	}
+
+	err = t.Info.ValidateForeignKeys(t.Tx, eo)
+	if err != nil {
+		return err
+	}
+
+	err = t.deleteFromIndexes(r)
+	if err != nil {
+		return err
+	}
+
+	nr, err := t.Put(r.Key(), eo)
+	if err != nil {
+		return err
+	}
+
+	err = t.insertIntoIndexes(nr)
+	if err != nil {
+		return err
+	}
+
+	return t.ApplyOnUpdate(r, nr.Object())
+}
+
+// deleteRow deletes a row and its index entries.
+func (t *Table) deleteRow(r Row) error {
+	err := t.deleteFromIndexes(r)
+	if err != nil {
+		return err
+	}
+
+	return t.Delete(r.Key())
+}
+
+func (t *Table) deleteFromIndexes(r Row) error {
+	key, err := t.Info.EncodeKey(r.Key())
+	if err != nil {
+		return err
+	}
+
+	for _, info := range t.Tx.Catalog.Cache.GetTableIndexes(t.Info.TableName) {
+		ok, err := info.Includes(t.Tx, r.Object())
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+
+		idx, err := t.Tx.Catalog.GetIndex(t.Tx, info.IndexName)
+		if err != nil {
+			return err
+		}
+
+		entries, err := info.Entries(t.Tx, r.Object())
+		if err != nil {
+			return err
+		}
+
+		for _, vs := range entries {
+			err = idx.Delete(vs, key)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (t *Table) insertIntoIndexes(r Row) error {
+	key, err := t.Info.EncodeKey(r.Key())
+	if err != nil {
+		return err
+	}
+
+	for _, info := range t.Tx.Catalog.Cache.GetTableIndexes(t.Info.TableName) {
+		ok, err := info.Includes(t.Tx, r.Object())
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+
+		idx, err := t.Tx.Catalog.GetIndex(t.Tx, info.IndexName)
+		if err != nil {
+			return err
+		}
+
+		entries, err := info.Entries(t.Tx, r.Object())
+		if err != nil {
+			return err
+		}
+
+		// multi-valued indexes can't be unique
+		if info.Unique {
+			vs := entries[0]
+			hasNull := false
+			for _, v := range vs {
+				if v.Type() == types.TypeNull {
+					hasNull = true
+					break
+				}
+			}
+
+			if !hasNull {
+				duplicate, dk, err := idx.Exists(vs)
+				if err != nil {
+					return err
+				}
+				if duplicate {
+					return &ConstraintViolationError{
+						Constraint: "UNIQUE",
+						Paths:      info.Paths,
+						Exprs:      info.KeyExprs(),
+						Key:        dk,
+					}
+				}
+			}
+		}
+
+		included := info.IncludedValues(r.Object())
+		for _, vs := range entries {
+			err = idx.SetWithIncluded(vs, included, key)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// convertValues converts the values to the types of the fields of the table.
+func (t *Table) convertValues(paths object.Paths, vs []types.Value) ([]types.Value, error) {
+	converted := make([]types.Value, len(vs))
+	for i, p := range paths {
+		v, err := t.Info.FieldConstraints.ConvertValueAtPath(p, vs[i], CastConversion)
+		if err != nil {
+			return nil, err
+		}
+		converted[i] = v
+	}
+
+	return converted, nil
+}
+
+// foreignKeyValues returns the values of the object at the given paths.
+// If one of them is missing or NULL, it returns nil.
+func foreignKeyValues(paths object.Paths, o types.Object) ([]types.Value, error) {
+	vs := make([]types.Value, 0, len(paths))
+	for _, p := range paths {
+		v, err := p.GetValueFromObject(o)
+		if errors.Is(err, types.ErrFieldNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if v.Type() == types.TypeNull {
+			return nil, nil
+		}
+
+		vs = append(vs, v)
+	}
+
+	return vs, nil
+}
+
+func valuesEqual(a, b []types.Value) bool {
+	for i := range a {
+		ok, err := a[i].EQ(b[i])
+		if err != nil || !ok {
+			return false
+		}
+	}
+
+	return true
+}
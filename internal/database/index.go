@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 
+	"github.com/chaisql/chai/internal/encoding"
 	"github.com/chaisql/chai/internal/engine"
 	"github.com/chaisql/chai/internal/tree"
 	"github.com/chaisql/chai/internal/types"
@@ -24,14 +25,18 @@ type Index struct {
 	// How many values the index is operating on.
 	// For example, an index created with `CREATE INDEX idx_a_b ON foo (a, b)` has an arity of 2.
 	Arity int
-	Tree  *tree.Tree
+	// How many values are stored alongside the entries of the index, without being indexed.
+	// For example, an index created with `CREATE INDEX idx_a ON foo (a) INCLUDE (b, c)` stores 2 values.
+	IncludeArity int
+	Tree         *tree.Tree
 }
 
 // NewIndex creates an index that associates values with a list of keys.
 func NewIndex(tr *tree.Tree, opts IndexInfo) *Index {
 	return &Index{
-		Tree:  tr,
-		Arity: len(opts.Paths),
+		Tree:         tr,
+		Arity:        len(opts.Paths),
+		IncludeArity: len(opts.Include),
 	}
 }
 
@@ -47,6 +52,16 @@ var errStop = errors.New("stop")
 //	k: <encoded values><primary key>
 //	v: length of the encoded value, as an unsigned varint
 func (idx *Index) Set(vs []types.Value, key []byte) error {
+	return idx.SetWithIncluded(vs, nil, key)
+}
+
+// SetWithIncluded is like Set but also stores the included values in the entry,
+// i.e. the values of the INCLUDE columns, so that they can be read
+// without fetching the row.
+//
+//	k: <encoded values><primary key>
+//	v: <encoded included values>
+func (idx *Index) SetWithIncluded(vs []types.Value, included []types.Value, key []byte) error {
 	if key == nil {
 		return errors.New("cannot index value without a key")
 	}
@@ -59,13 +74,26 @@ func (idx *Index) Set(vs []types.Value, key []byte) error {
 		return fmt.Errorf("cannot index %d values on an index of arity %d", len(vs), idx.Arity)
 	}
 
+	if len(included) != idx.IncludeArity {
+		return fmt.Errorf("cannot include %d values on an index including %d values", len(included), idx.IncludeArity)
+	}
+
 	// append the key to the values
 	values := append(vs, types.NewBlobValue(key))
 
 	// create the key for the tree
 	treeKey := tree.NewKey(values...)
 
-	return idx.Tree.Put(treeKey, nil)
+	var data []byte
+	for _, v := range included {
+		var err error
+		data, err = encoding.EncodeValue(data, v, false)
+		if err != nil {
+			return err
+		}
+	}
+
+	return idx.Tree.Put(treeKey, data)
 }
 
 // Exists iterates over the index and check if the value exists
@@ -131,6 +159,31 @@ func (idx *Index) IterateOnRange(rng *tree.Range, reverse bool, fn func(key *tre
 	})
 }
 
+// IterateEntriesOnRange is like IterateOnRange but also returns the indexed values
+// and the included values of each entry.
+func (idx *Index) IterateEntriesOnRange(rng *tree.Range, reverse bool, fn func(values, included []types.Value, key *tree.Key) error) error {
+	return idx.Tree.IterateOnRange(rng, reverse, func(k *tree.Key, d []byte) error {
+		values, err := k.Decode()
+		if err != nil {
+			return err
+		}
+
+		pk := tree.NewEncodedKey(types.AsByteSlice(values[len(values)-1]))
+
+		var included []types.Value
+		if idx.IncludeArity > 0 {
+			included = make([]types.Value, 0, idx.IncludeArity)
+			for len(d) > 0 {
+				v, n := encoding.DecodeValue(d, false /* intAsDouble */)
+				d = d[n:]
+				included = append(included, v)
+			}
+		}
+
+		return fn(values[:len(values)-1], included, pk)
+	})
+}
+
 func (idx *Index) iterateOnRange(rng *tree.Range, reverse bool, fn func(itmKey *tree.Key, key *tree.Key) error) error {
 	return idx.Tree.IterateOnRange(rng, reverse, idx.iterator(fn))
 }
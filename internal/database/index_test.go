@@ -156,6 +156,35 @@ func TestIndexExists(t *testing.T) {
 	require.False(t, ok)
 }
 
+func TestIndexSetWithIncluded(t *testing.T) {
+	idx := getIndex(t, 1)
+	idx.IncludeArity = 2
+
+	assert.Error(t, idx.SetWithIncluded(values(types.NewIntegerValue(10)), values(types.NewTextValue("a")), []byte("key1")))
+	assert.NoError(t, idx.SetWithIncluded(values(types.NewIntegerValue(10)), values(types.NewTextValue("a"), types.NewNullValue()), []byte("key1")))
+	assert.NoError(t, idx.SetWithIncluded(values(types.NewIntegerValue(11)), values(types.NewTextValue("b"), types.NewDoubleValue(1.5)), []byte("key2")))
+
+	var entries [][]types.Value
+	var keys []string
+	err := idx.IterateEntriesOnRange(nil, false, func(vs, included []types.Value, key *tree.Key) error {
+		entries = append(entries, append(vs, included...))
+		keys = append(keys, string(key.Encoded))
+		return nil
+	})
+	assert.NoError(t, err)
+	require.Equal(t, [][]types.Value{
+		values(types.NewIntegerValue(10), types.NewTextValue("a"), types.NewNullValue()),
+		values(types.NewIntegerValue(11), types.NewTextValue("b"), types.NewDoubleValue(1.5)),
+	}, entries)
+	require.Equal(t, []string{"key1", "key2"}, keys)
+
+	// included values don't prevent deleting the entry
+	assert.NoError(t, idx.Delete(values(types.NewIntegerValue(10)), []byte("key1")))
+	ok, _, err := idx.Exists(values(types.NewIntegerValue(10)))
+	assert.NoError(t, err)
+	require.False(t, ok)
+}
+
 // BenchmarkIndexSet benchmarks the Set method with 1, 10, 1000 and 10000 successive insertions.
 func BenchmarkIndexSet(b *testing.B) {
 	for size := 10; size <= 10000; size *= 10 {
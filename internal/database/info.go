@@ -6,6 +6,8 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/chaisql/chai/internal/encoding"
+	"github.com/chaisql/chai/internal/fulltext"
 	"github.com/chaisql/chai/internal/object"
 	"github.com/chaisql/chai/internal/stringutil"
 	"github.com/chaisql/chai/internal/tree"
@@ -67,6 +69,22 @@ func (ti *TableInfo) AddTableConstraint(newTc *TableConstraint) error {
 		// add NOT NULL constraint to paths
 		for _, p := range newTc.Paths {
 			fc := ti.GetFieldConstraintForPath(p)
+			if fc.IsNotNull {
+				continue
+			}
+
+			// top level constraints may be shared with the table info
+			// this one was cloned from, replace them with a copy
+			if len(p) == 1 {
+				cp := *fc
+				cp.IsNotNull = true
+				err := ti.FieldConstraints.Replace(&cp)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+
 			fc.IsNotNull = true
 		}
 
@@ -103,6 +121,15 @@ func (ti *TableInfo) AddTableConstraint(newTc *TableConstraint) error {
 		if newTc.Name == "" {
 			newTc.Name = fmt.Sprintf("%s_%s_unique", ti.TableName, pathsToIndexName(newTc.Paths))
 		}
+	case newTc.ForeignKey != nil:
+		if len(newTc.ForeignKey.Paths) > 0 && len(newTc.ForeignKey.Paths) != len(newTc.Paths) {
+			return errors.Errorf("foreign key on %q must reference %d fields", newTc.Paths, len(newTc.Paths))
+		}
+
+		// generate name if not provided
+		if newTc.Name == "" {
+			newTc.Name = fmt.Sprintf("%s_%s_fkey", ti.TableName, pathsToIndexName(newTc.Paths))
+		}
 	default:
 		return errors.New("invalid table constraint")
 	}
@@ -246,6 +273,248 @@ type IndexInfo struct {
 	// i.e CREATE TABLE tbl(a INT UNIQUE)
 	// The path refers to the path this index is related to.
 	Owner Owner
+
+	// If set, Exprs[i] is the expression computing the i-th indexed value,
+	// in which case Paths[i] is empty. The other values are read from Paths.
+	// i.e CREATE INDEX ON tbl(lower(a))
+	// or CREATE INDEX ON tbl(tags[*])
+	Exprs []*IndexExpr
+
+	// Columns whose values are stored in the index without being indexed,
+	// allowing to read them without fetching the row.
+	// i.e CREATE INDEX ON tbl(a) INCLUDE (b, c)
+	Include object.Paths
+
+	// If set, only the rows matching the predicate are indexed,
+	// i.e CREATE INDEX ON tbl(a) WHERE b IS NULL
+	Predicate *IndexExpr
+
+	// If set to true, the index is an inverted index
+	// associating every term of the text column with the rows containing it,
+	// i.e CREATE FULLTEXT INDEX ON tbl(a)
+	FullText bool
+}
+
+// An IndexExpr is an expression evaluated for every indexed row,
+// either to compute an indexed value or as the predicate of a partial index.
+type IndexExpr struct {
+	Expr TableExpression
+	// Paths used by the expression.
+	Paths object.Paths
+	// If set, the expression is a path to an array
+	// and each of its elements is indexed separately.
+	Elements bool
+}
+
+// String returns a SQL representation.
+func (e *IndexExpr) String() string {
+	if e.Elements {
+		return e.Paths[0].String() + "[*]"
+	}
+
+	return e.Expr.String()
+}
+
+// exprAt returns the expression computing the i-th indexed value, if any.
+func (idx *IndexInfo) exprAt(i int) *IndexExpr {
+	if i < len(idx.Exprs) {
+		return idx.Exprs[i]
+	}
+
+	return nil
+}
+
+// KeyPaths returns the paths used to compute the indexed values.
+func (idx *IndexInfo) KeyPaths() object.Paths {
+	paths := make(object.Paths, 0, len(idx.Paths))
+	for i, p := range idx.Paths {
+		if e := idx.exprAt(i); e != nil {
+			paths = append(paths, e.Paths...)
+			continue
+		}
+
+		paths = append(paths, p)
+	}
+
+	return paths
+}
+
+// UsedPaths returns all the paths the index depends on,
+// including the included columns and the ones used by its predicate.
+func (idx *IndexInfo) UsedPaths() object.Paths {
+	paths := append(idx.KeyPaths(), idx.Include...)
+	if idx.Predicate != nil {
+		paths = append(paths, idx.Predicate.Paths...)
+	}
+
+	return paths
+}
+
+// Includes returns whether the object must be indexed,
+// i.e. if the index has no predicate or if the object matches it.
+func (idx *IndexInfo) Includes(tx *Transaction, o types.Object) (bool, error) {
+	if idx.Predicate == nil {
+		return true, nil
+	}
+
+	v, err := idx.Predicate.Expr.Eval(tx, o)
+	if err != nil {
+		return false, err
+	}
+
+	return types.IsTruthy(v)
+}
+
+// elementsPos returns the position of the indexed value
+// whose array elements are indexed, or -1.
+func (idx *IndexInfo) elementsPos() int {
+	for i, e := range idx.Exprs {
+		if e != nil && e.Elements {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Values returns the values of the object indexed by the index.
+// Missing values are replaced by NULL.
+// Values computed by expressions are converted to the type they
+// would be stored as in a column without type.
+// For multi-valued indexes, the whole array is returned,
+// use Entries to get the indexed values.
+func (idx *IndexInfo) Values(tx *Transaction, o types.Object) ([]types.Value, error) {
+	vs := make([]types.Value, 0, len(idx.Paths))
+	for i, path := range idx.Paths {
+		if e := idx.exprAt(i); e != nil {
+			v, err := e.Expr.Eval(tx, o)
+			if err != nil {
+				return nil, err
+			}
+
+			v, err = encoding.ConvertAsIndexType(v, types.TypeAny)
+			if err != nil {
+				return nil, err
+			}
+
+			vs = append(vs, v)
+			continue
+		}
+
+		v, err := path.GetValueFromObject(o)
+		if err != nil {
+			v = types.NewNullValue()
+		}
+		vs = append(vs, v)
+	}
+
+	return vs, nil
+}
+
+// Entries returns the list of values indexed for the object, one per index entry.
+// Multi-valued indexes have one entry per distinct element of the array,
+// and none if the value is not an array. Full-text indexes have one entry
+// per distinct term of the text, and none if the value is not a text.
+// Other indexes always have one entry.
+func (idx *IndexInfo) Entries(tx *Transaction, o types.Object) ([][]types.Value, error) {
+	vs, err := idx.Values(tx, o)
+	if err != nil {
+		return nil, err
+	}
+
+	if idx.FullText {
+		if vs[0].Type() != types.TypeText {
+			return nil, nil
+		}
+
+		terms := fulltext.Terms(types.AsString(vs[0]))
+		entries := make([][]types.Value, len(terms))
+		for i, t := range terms {
+			entries[i] = []types.Value{types.NewTextValue(t)}
+		}
+
+		return entries, nil
+	}
+
+	pos := idx.elementsPos()
+	if pos == -1 {
+		return [][]types.Value{vs}, nil
+	}
+
+	if vs[pos].Type() != types.TypeArray {
+		return nil, nil
+	}
+
+	var entries [][]types.Value
+	err = types.AsArray(vs[pos]).Iterate(func(_ int, v types.Value) error {
+		v, err := encoding.ConvertAsIndexType(v, types.TypeAny)
+		if err != nil {
+			return err
+		}
+
+		// an entry is created for every distinct element
+		for _, entry := range entries {
+			if entry[pos].Type() != v.Type() {
+				continue
+			}
+			if v.Type() == types.TypeNull {
+				return nil
+			}
+
+			ok, err := entry[pos].EQ(v)
+			if err != nil {
+				return err
+			}
+			if ok {
+				return nil
+			}
+		}
+
+		entry := make([]types.Value, len(vs))
+		copy(entry, vs)
+		entry[pos] = v
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// IncludedValues returns the values of the included columns of the object.
+// Missing values are replaced by NULL.
+func (idx *IndexInfo) IncludedValues(o types.Object) []types.Value {
+	if len(idx.Include) == 0 {
+		return nil
+	}
+
+	vs := make([]types.Value, 0, len(idx.Include))
+	for _, path := range idx.Include {
+		v, err := path.GetValueFromObject(o)
+		if err != nil {
+			v = types.NewNullValue()
+		}
+		vs = append(vs, v)
+	}
+
+	return vs
+}
+
+// KeyExprs returns the SQL representation of each indexed value,
+// i.e. the indexed path or the expression computing it.
+func (idx *IndexInfo) KeyExprs() []string {
+	exprs := make([]string, len(idx.Paths))
+	for i, p := range idx.Paths {
+		if e := idx.exprAt(i); e != nil {
+			exprs[i] = e.String()
+		} else {
+			exprs[i] = p.String()
+		}
+	}
+
+	return exprs
 }
 
 // String returns a SQL representation.
@@ -256,16 +525,18 @@ func (idx *IndexInfo) String() string {
 	if idx.Unique {
 		s.WriteString("UNIQUE ")
 	}
+	if idx.FullText {
+		s.WriteString("FULLTEXT ")
+	}
 
 	fmt.Fprintf(&s, "INDEX %s ON %s (", stringutil.NormalizeIdentifier(idx.IndexName, '`'), stringutil.NormalizeIdentifier(idx.Owner.TableName, '`'))
 
-	for i, p := range idx.Paths {
+	for i, e := range idx.KeyExprs() {
 		if i > 0 {
 			s.WriteString(", ")
 		}
 
-		// Path
-		s.WriteString(p.String())
+		s.WriteString(e)
 
 		if idx.KeySortOrder.IsDesc(i) {
 			s.WriteString(" DESC")
@@ -274,6 +545,22 @@ func (idx *IndexInfo) String() string {
 
 	s.WriteString(")")
 
+	if len(idx.Include) > 0 {
+		s.WriteString(" INCLUDE (")
+		for i, p := range idx.Include {
+			if i > 0 {
+				s.WriteString(", ")
+			}
+			s.WriteString(p.String())
+		}
+		s.WriteString(")")
+	}
+
+	if idx.Predicate != nil {
+		s.WriteString(" WHERE ")
+		s.WriteString(idx.Predicate.Expr.String())
+	}
+
 	return s.String()
 }
 
@@ -286,6 +573,17 @@ func (i IndexInfo) Clone() *IndexInfo {
 		c.Paths[i] = p.Clone()
 	}
 
+	if i.Exprs != nil {
+		c.Exprs = append([]*IndexExpr(nil), i.Exprs...)
+	}
+
+	if i.Include != nil {
+		c.Include = make(object.Paths, len(i.Include))
+		for j, p := range i.Include {
+			c.Include[j] = p.Clone()
+		}
+	}
+
 	return &c
 }
 
@@ -300,6 +598,20 @@ type SequenceInfo struct {
 	Owner       Owner
 }
 
+// NewRowidSequenceInfo returns the configuration of the sequence
+// generating the keys of a table without primary key.
+func NewRowidSequenceInfo(tableName string) *SequenceInfo {
+	return &SequenceInfo{
+		IncrementBy: 1,
+		Min:         1, Max: math.MaxInt64,
+		Start: 1,
+		Cache: 64,
+		Owner: Owner{
+			TableName: tableName,
+		},
+	}
+}
+
 // String returns a SQL representation.
 func (s *SequenceInfo) String() string {
 	var b strings.Builder
@@ -3,6 +3,7 @@ package database
 import (
 	"github.com/chaisql/chai/internal/tree"
 	"github.com/chaisql/chai/internal/types"
+	"github.com/cockroachdb/errors"
 )
 
 type Row interface {
@@ -102,6 +103,37 @@ func (r *LazyRow) Object() types.Object {
 	return r.row.Object()
 }
 
+var _ Row = (*CoveredRow)(nil)
+
+// CoveredRow holds the values of a row stored in an index and only loads the row
+// from the table if another column is requested.
+type CoveredRow struct {
+	LazyRow
+	stored types.Object
+}
+
+func (r *CoveredRow) ResetWith(table *Table, key *tree.Key, stored types.Object) {
+	r.LazyRow.ResetWith(table, key)
+	r.stored = stored
+}
+
+func (r *CoveredRow) Get(name string) (types.Value, error) {
+	return r.GetByField(name)
+}
+
+func (r *CoveredRow) GetByField(field string) (types.Value, error) {
+	v, err := r.stored.GetByField(field)
+	if !errors.Is(err, types.ErrFieldNotFound) {
+		return v, err
+	}
+
+	return r.LazyRow.GetByField(field)
+}
+
+func (r *CoveredRow) Object() types.Object {
+	return r
+}
+
 var _ Row = (*BasicRow)(nil)
 
 type BasicRow struct {
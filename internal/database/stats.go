@@ -0,0 +1,597 @@
+package database
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+
+	"github.com/chaisql/chai/internal/encoding"
+	"github.com/chaisql/chai/internal/object"
+	"github.com/chaisql/chai/internal/tree"
+	"github.com/chaisql/chai/internal/types"
+	"github.com/cockroachdb/errors"
+)
+
+// Kinds of statistics stored in the __chai_stats table.
+const (
+	StatsTableType  = "table"
+	StatsColumnType = "column"
+	StatsIndexType  = "index"
+)
+
+const (
+	// statsSampleSize is the maximum number of values per column
+	// used to build histograms and estimate the number of distinct values.
+	statsSampleSize = 10_000
+	// statsHistogramBuckets is the maximum number of buckets of a histogram.
+	statsHistogramBuckets = 100
+)
+
+var statsTableInfo = func() *TableInfo {
+	info := &TableInfo{
+		TableName:      StatsTableName,
+		StoreNamespace: StatsTableNamespace,
+		FieldConstraints: MustNewFieldConstraints(
+			&FieldConstraint{
+				Position:  0,
+				Field:     "table_name",
+				Type:      types.TypeText,
+				IsNotNull: true,
+			},
+			&FieldConstraint{
+				Position:  1,
+				Field:     "type",
+				Type:      types.TypeText,
+				IsNotNull: true,
+			},
+			&FieldConstraint{
+				Position:  2,
+				Field:     "name",
+				Type:      types.TypeText,
+				IsNotNull: true,
+			},
+			&FieldConstraint{
+				Position:  3,
+				Field:     "row_count",
+				Type:      types.TypeInteger,
+				IsNotNull: true,
+			},
+			&FieldConstraint{
+				Position: 4,
+				Field:    "null_count",
+				Type:     types.TypeInteger,
+			},
+			&FieldConstraint{
+				Position: 5,
+				Field:    "distinct_count",
+				Type:     types.TypeInteger,
+			},
+			&FieldConstraint{
+				Position: 6,
+				Field:    "histogram",
+				Type:     types.TypeArray,
+			},
+		),
+		TableConstraints: []*TableConstraint{
+			{
+				Name: StatsTableName + "_pk",
+				Paths: []object.Path{
+					object.NewPath("table_name"),
+					object.NewPath("type"),
+					object.NewPath("name"),
+				},
+				PrimaryKey: true,
+			},
+		},
+	}
+	info.BuildPrimaryKey()
+
+	return info
+}()
+
+// TableStats holds the statistics of a table and of its indexes,
+// as computed by the ANALYZE statement.
+type TableStats struct {
+	TableName string
+	// RowCount is the number of rows of the table.
+	RowCount int64
+	// Columns contains the statistics of each column of the table.
+	Columns map[string]*ColumnStats
+	// Indexes contains the statistics of the first value
+	// of the entries of each index of the table.
+	Indexes map[string]*ColumnStats
+}
+
+// Clone returns a copy of the statistics.
+// Column and index statistics are never modified and are shared.
+func (s *TableStats) Clone() *TableStats {
+	clone := TableStats{
+		TableName: s.TableName,
+		RowCount:  s.RowCount,
+		Columns:   make(map[string]*ColumnStats, len(s.Columns)),
+		Indexes:   make(map[string]*ColumnStats, len(s.Indexes)),
+	}
+
+	for k, v := range s.Columns {
+		clone.Columns[k] = v
+	}
+	for k, v := range s.Indexes {
+		clone.Indexes[k] = v
+	}
+
+	return &clone
+}
+
+// ColumnStats describes the distribution of a set of values,
+// i.e. the values of a column or the values of the first indexed
+// column of an index.
+type ColumnStats struct {
+	// Count is the number of values, including NULL,
+	// i.e. the number of rows for a column or the number
+	// of entries for an index.
+	Count int64
+	// NullCount is the number of NULL values.
+	NullCount int64
+	// DistinctCount is the estimated number of distinct non-NULL values.
+	DistinctCount int64
+	// Histogram contains the bounds of equi-depth buckets:
+	// every bucket contains roughly the same number of non-NULL values.
+	// The first bound is the smallest value and the last bound
+	// is the largest one.
+	Histogram []types.Value
+}
+
+// EqualSelectivity returns the estimated fraction of values equal to v.
+// If v is nil, the value is considered unknown and the average
+// selectivity of a value is returned.
+func (s *ColumnStats) EqualSelectivity(v types.Value) float64 {
+	if s.Count == 0 || s.DistinctCount == 0 {
+		return 0
+	}
+	if v != nil && v.Type() == types.TypeNull {
+		return 0
+	}
+
+	return s.eq(v) * s.nonNullFraction()
+}
+
+// RangeSelectivity returns the estimated fraction of values between min and max.
+// A nil boundary is unbounded. If exclusive is true, the boundaries are excluded.
+func (s *ColumnStats) RangeSelectivity(min, max types.Value, exclusive bool) float64 {
+	if s.Count == 0 || len(s.Histogram) == 0 {
+		return 0
+	}
+
+	lo, hi := 0.0, 1.0
+	if min != nil {
+		lo = s.lt(min)
+		if exclusive {
+			lo += s.eq(min)
+		}
+	}
+	if max != nil {
+		hi = s.lt(max)
+		if !exclusive {
+			hi += s.eq(max)
+		}
+	}
+
+	return math.Max(0, math.Min(1, hi-lo)) * s.nonNullFraction()
+}
+
+func (s *ColumnStats) nonNullFraction() float64 {
+	return float64(s.Count-s.NullCount) / float64(s.Count)
+}
+
+// eq returns the estimated fraction of non-NULL values equal to v.
+func (s *ColumnStats) eq(v types.Value) float64 {
+	if s.DistinctCount == 0 {
+		return 0
+	}
+
+	sel := 1 / float64(s.DistinctCount)
+	if v == nil || len(s.Histogram) < 2 {
+		return sel
+	}
+
+	if compareValues(v, s.Histogram[0]) < 0 || compareValues(v, s.Histogram[len(s.Histogram)-1]) > 0 {
+		return 0
+	}
+
+	// a value appearing in n bounds fills at least n - 1 buckets.
+	// These frequent values are excluded when estimating
+	// the selectivity of the other values.
+	buckets := float64(len(s.Histogram) - 1)
+	var frequent, frequentSel float64
+	for i := 0; i < len(s.Histogram); {
+		j := i + 1
+		for j < len(s.Histogram) && compareValues(s.Histogram[i], s.Histogram[j]) == 0 {
+			j++
+		}
+
+		if j-i > 1 {
+			f := float64(j-i-1) / buckets
+			if compareValues(v, s.Histogram[i]) == 0 {
+				return math.Max(sel, f)
+			}
+
+			frequent++
+			frequentSel += f
+		}
+		i = j
+	}
+
+	if frequent > 0 && float64(s.DistinctCount) > frequent {
+		sel = math.Max(0, 1-frequentSel) / (float64(s.DistinctCount) - frequent)
+	}
+
+	return math.Min(sel, 1)
+}
+
+// lt returns the estimated fraction of non-NULL values lower than v.
+func (s *ColumnStats) lt(v types.Value) float64 {
+	h := s.Histogram
+	i := sort.Search(len(h), func(i int) bool {
+		return compareValues(h[i], v) >= 0
+	})
+	if i == 0 {
+		return 0
+	}
+	if i == len(h) {
+		return 1
+	}
+
+	// v is in the bucket between h[i-1] and h[i]
+	pos := 0.5
+	if h[i-1].Type().IsNumber() && h[i].Type().IsNumber() && v.Type().IsNumber() {
+		lo, hi := asFloat64(h[i-1]), asFloat64(h[i])
+		if hi > lo {
+			pos = (asFloat64(v) - lo) / (hi - lo)
+		}
+	}
+
+	return (float64(i-1) + pos) / float64(len(h)-1)
+}
+
+// compareValues compares values in the order of the keys of the trees.
+// Numbers are compared regardless of their type.
+func compareValues(a, b types.Value) int {
+	if a.Type().IsNumber() && b.Type().IsNumber() {
+		x, y := asFloat64(a), asFloat64(b)
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+		return 0
+	}
+
+	ea, err := encoding.EncodeValue(nil, a, false)
+	if err != nil {
+		return 0
+	}
+	eb, err := encoding.EncodeValue(nil, b, false)
+	if err != nil {
+		return 0
+	}
+
+	return encoding.Compare(ea, eb)
+}
+
+func asFloat64(v types.Value) float64 {
+	if v.Type() == types.TypeInteger {
+		return float64(types.AsInt64(v))
+	}
+
+	return types.AsFloat64(v)
+}
+
+// A statsCollector computes the statistics of a set of values.
+// It keeps a random sample of the values, using reservoir sampling,
+// to build the histogram and estimate the number of distinct values.
+type statsCollector struct {
+	count  int64
+	nulls  int64
+	seen   int64
+	sample []types.Value
+	rnd    *rand.Rand
+}
+
+func newStatsCollector() *statsCollector {
+	// a fixed seed makes ANALYZE deterministic
+	return &statsCollector{
+		rnd: rand.New(rand.NewSource(1)),
+	}
+}
+
+func (c *statsCollector) add(v types.Value) error {
+	c.count++
+	if v.Type() == types.TypeNull {
+		c.nulls++
+		return nil
+	}
+
+	c.seen++
+	i := c.seen - 1
+	if len(c.sample) >= statsSampleSize {
+		i = c.rnd.Int63n(c.seen)
+		if i >= statsSampleSize {
+			return nil
+		}
+	}
+
+	v, err := object.CloneValue(v)
+	if err != nil {
+		return err
+	}
+
+	if i < int64(len(c.sample)) {
+		c.sample[i] = v
+	} else {
+		c.sample = append(c.sample, v)
+	}
+
+	return nil
+}
+
+// stats returns the statistics of the collected values.
+// count is the total number of values, values that were
+// not added are considered NULL.
+func (c *statsCollector) stats(count int64) *ColumnStats {
+	cs := ColumnStats{
+		Count:     count,
+		NullCount: c.nulls + count - c.count,
+	}
+
+	n := len(c.sample)
+	if n == 0 {
+		return &cs
+	}
+
+	sort.Slice(c.sample, func(i, j int) bool {
+		return compareValues(c.sample[i], c.sample[j]) < 0
+	})
+
+	// count the distinct values of the sample
+	// and the values appearing only once
+	var distinct, once int
+	for i := 0; i < n; {
+		j := i + 1
+		for j < n && compareValues(c.sample[i], c.sample[j]) == 0 {
+			j++
+		}
+		distinct++
+		if j == i+1 {
+			once++
+		}
+		i = j
+	}
+
+	if int64(n) == c.seen {
+		cs.DistinctCount = int64(distinct)
+	} else {
+		// Haas and Stokes' Duj1 estimator
+		total := float64(c.seen)
+		d := float64(n) * float64(distinct) / (float64(n) - float64(once) + float64(once)*float64(n)/total)
+		cs.DistinctCount = int64(math.Min(math.Max(d, float64(distinct)), total))
+	}
+
+	buckets := statsHistogramBuckets
+	if n-1 < buckets {
+		buckets = n - 1
+	}
+	if buckets == 0 {
+		cs.Histogram = []types.Value{c.sample[0]}
+		return &cs
+	}
+
+	cs.Histogram = make([]types.Value, 0, buckets+1)
+	for i := 0; i <= buckets; i++ {
+		cs.Histogram = append(cs.Histogram, c.sample[i*(n-1)/buckets])
+	}
+
+	return &cs
+}
+
+// ComputeTableStats reads a table and its indexes
+// and computes their statistics.
+func ComputeTableStats(tx *Transaction, tableName string) (*TableStats, error) {
+	tb, err := tx.Catalog.GetTable(tx, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := TableStats{
+		TableName: tableName,
+		Columns:   make(map[string]*ColumnStats),
+		Indexes:   make(map[string]*ColumnStats),
+	}
+
+	collectors := make(map[string]*statsCollector)
+	err = tb.IterateOnRange(nil, false, func(key *tree.Key, r Row) error {
+		stats.RowCount++
+
+		return r.Iterate(func(column string, v types.Value) error {
+			c, ok := collectors[column]
+			if !ok {
+				c = newStatsCollector()
+				collectors[column] = c
+			}
+
+			return c.add(v)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for column, c := range collectors {
+		stats.Columns[column] = c.stats(stats.RowCount)
+	}
+
+	for _, name := range tx.Catalog.ListIndexes(tableName) {
+		idx, err := tx.Catalog.GetIndex(tx, name)
+		if err != nil {
+			return nil, err
+		}
+
+		c := newStatsCollector()
+		err = idx.IterateEntriesOnRange(nil, false, func(values, _ []types.Value, _ *tree.Key) error {
+			return c.add(values[0])
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		stats.Indexes[name] = c.stats(c.count)
+	}
+
+	return &stats, nil
+}
+
+// tableStatsToObjects returns the rows of the __chai_stats table
+// describing the statistics.
+func tableStatsToObjects(s *TableStats) []types.Object {
+	objs := []types.Object{
+		object.NewFieldBuffer().
+			Add("table_name", types.NewTextValue(s.TableName)).
+			Add("type", types.NewTextValue(StatsTableType)).
+			Add("name", types.NewTextValue(s.TableName)).
+			Add("row_count", types.NewIntegerValue(s.RowCount)),
+	}
+
+	add := func(tp string, m map[string]*ColumnStats) {
+		names := make([]string, 0, len(m))
+		for name := range m {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			cs := m[name]
+			fb := object.NewFieldBuffer().
+				Add("table_name", types.NewTextValue(s.TableName)).
+				Add("type", types.NewTextValue(tp)).
+				Add("name", types.NewTextValue(name)).
+				Add("row_count", types.NewIntegerValue(cs.Count)).
+				Add("null_count", types.NewIntegerValue(cs.NullCount)).
+				Add("distinct_count", types.NewIntegerValue(cs.DistinctCount))
+			if len(cs.Histogram) > 0 {
+				fb.Add("histogram", types.NewArrayValue(object.NewValueBuffer(cs.Histogram...)))
+			}
+			objs = append(objs, fb)
+		}
+	}
+
+	add(StatsColumnType, s.Columns)
+	add(StatsIndexType, s.Indexes)
+
+	return objs
+}
+
+// LoadTableStats reads the statistics stored in the __chai_stats table.
+func LoadTableStats(tx *Transaction) ([]*TableStats, error) {
+	tb, err := tx.Catalog.GetTable(tx, StatsTableName)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []*TableStats
+	var cur *TableStats
+	err = tb.IterateOnRange(nil, false, func(key *tree.Key, r Row) error {
+		tableName, err := getStatsText(r, "table_name")
+		if err != nil {
+			return err
+		}
+		// rows are sorted by table name
+		if cur == nil || cur.TableName != tableName {
+			cur = &TableStats{
+				TableName: tableName,
+				Columns:   make(map[string]*ColumnStats),
+				Indexes:   make(map[string]*ColumnStats),
+			}
+			list = append(list, cur)
+		}
+
+		tp, err := getStatsText(r, "type")
+		if err != nil {
+			return err
+		}
+		name, err := getStatsText(r, "name")
+		if err != nil {
+			return err
+		}
+
+		var cs ColumnStats
+		cs.Count, err = getStatsInt(r, "row_count")
+		if err != nil {
+			return err
+		}
+
+		if tp == StatsTableType {
+			cur.RowCount = cs.Count
+			return nil
+		}
+
+		cs.NullCount, err = getStatsInt(r, "null_count")
+		if err != nil {
+			return err
+		}
+		cs.DistinctCount, err = getStatsInt(r, "distinct_count")
+		if err != nil {
+			return err
+		}
+
+		v, err := r.Get("histogram")
+		if err != nil && !errors.Is(err, types.ErrFieldNotFound) {
+			return err
+		}
+		if err == nil && v.Type() == types.TypeArray {
+			err = types.AsArray(v).Iterate(func(i int, value types.Value) error {
+				value, err := object.CloneValue(value)
+				cs.Histogram = append(cs.Histogram, value)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		switch tp {
+		case StatsColumnType:
+			cur.Columns[name] = &cs
+		case StatsIndexType:
+			cur.Indexes[name] = &cs
+		}
+
+		return nil
+	})
+
+	return list, err
+}
+
+func getStatsText(r Row, column string) (string, error) {
+	v, err := r.Get(column)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Clone(types.AsString(v)), nil
+}
+
+func getStatsInt(r Row, column string) (int64, error) {
+	v, err := r.Get(column)
+	if err != nil {
+		if errors.Is(err, types.ErrFieldNotFound) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	if v.Type() == types.TypeNull {
+		return 0, nil
+	}
+
+	return types.AsInt64(v), nil
+}
@@ -0,0 +1,149 @@
+package database_test
+
+import (
+	"testing"
+
+	"github.com/chaisql/chai/internal/database"
+	"github.com/chaisql/chai/internal/database/catalogstore"
+	"github.com/chaisql/chai/internal/testutil"
+	"github.com/chaisql/chai/internal/testutil/assert"
+	"github.com/chaisql/chai/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColumnStatsSelectivity(t *testing.T) {
+	// 100 values between 0 and 40, 10 of them being NULL
+	cs := database.ColumnStats{
+		Count:         100,
+		NullCount:     10,
+		DistinctCount: 30,
+		Histogram: []types.Value{
+			types.NewIntegerValue(0),
+			types.NewIntegerValue(10),
+			types.NewIntegerValue(20),
+			types.NewIntegerValue(20),
+			types.NewIntegerValue(40),
+		},
+	}
+
+	t.Run("Equal", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			v        types.Value
+			expected float64
+		}{
+			{"unknown", nil, 0.9 / 30},
+			{"frequent", types.NewIntegerValue(20), 0.9 * 0.25},
+			{"other", types.NewIntegerValue(5), 0.9 * 0.75 / 29},
+			{"double", types.NewDoubleValue(5), 0.9 * 0.75 / 29},
+			{"out of bounds", types.NewIntegerValue(50), 0},
+			{"null", types.NewNullValue(), 0},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				require.InDelta(t, test.expected, cs.EqualSelectivity(test.v), 0.0001)
+			})
+		}
+	})
+
+	t.Run("Range", func(t *testing.T) {
+		tests := []struct {
+			name      string
+			min, max  types.Value
+			exclusive bool
+			expected  float64
+		}{
+			{"unbounded", nil, nil, false, 0.9},
+			{"lower than min", nil, types.NewIntegerValue(0), true, 0},
+			{"greater than max", types.NewIntegerValue(40), nil, true, 0},
+			{"first bucket", nil, types.NewIntegerValue(5), true, 0.9 * 0.125},
+			{"last bucket", types.NewIntegerValue(30), nil, true, 0.9 * (0.125 - 0.75/29)},
+			{"between", types.NewIntegerValue(10), types.NewIntegerValue(30), false, 0.9 * (0.625 + 0.75/29)},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				require.InDelta(t, test.expected, cs.RangeSelectivity(test.min, test.max, test.exclusive), 0.0001)
+			})
+		}
+	})
+}
+
+func TestComputeTableStats(t *testing.T) {
+	db, tx, cleanup := testutil.NewTestTx(t)
+	defer cleanup()
+
+	testutil.MustExec(t, db, tx, `
+		CREATE TABLE test(a int, b text);
+		CREATE INDEX test_b_idx ON test(b);
+		INSERT INTO test (a, b) VALUES (1, 'x'), (2, 'y'), (3, 'y'), (4, NULL);
+	`)
+
+	stats, err := database.ComputeTableStats(tx, "test")
+	assert.NoError(t, err)
+
+	require.Equal(t, int64(4), stats.RowCount)
+	require.Equal(t, &database.ColumnStats{
+		Count:         4,
+		DistinctCount: 4,
+		Histogram: []types.Value{
+			types.NewIntegerValue(1),
+			types.NewIntegerValue(2),
+			types.NewIntegerValue(3),
+			types.NewIntegerValue(4),
+		},
+	}, stats.Columns["a"])
+	require.Equal(t, &database.ColumnStats{
+		Count:         4,
+		NullCount:     1,
+		DistinctCount: 2,
+		Histogram: []types.Value{
+			types.NewTextValue("x"),
+			types.NewTextValue("y"),
+			types.NewTextValue("y"),
+		},
+	}, stats.Columns["b"])
+	require.Equal(t, stats.Columns["b"], stats.Indexes["test_b_idx"])
+}
+
+func TestTableStatsPersistence(t *testing.T) {
+	path := t.TempDir()
+
+	open := func() *database.Database {
+		db, err := database.Open(path, &database.Options{
+			CatalogLoader: catalogstore.LoadCatalog,
+		})
+		assert.NoError(t, err)
+		return db
+	}
+
+	db := open()
+	update(t, db, func(tx *database.Transaction) error {
+		testutil.MustExec(t, db, tx, `
+			CREATE TABLE test(a int);
+			INSERT INTO test (a) VALUES (1), (2), (3);
+			ANALYZE test;
+		`)
+		return nil
+	})
+	assert.NoError(t, db.Close())
+
+	db = open()
+	defer db.Close()
+
+	stats := db.Catalog().GetTableStats("test")
+	require.NotNil(t, stats)
+	require.Equal(t, int64(3), stats.RowCount)
+	require.Equal(t, int64(3), stats.Columns["a"].DistinctCount)
+	require.Len(t, stats.Columns["a"].Histogram, 3)
+
+	// statistics are discarded on rollback
+	tx, err := db.Begin(true)
+	assert.NoError(t, err)
+	testutil.MustExec(t, db, tx, `DROP TABLE test`)
+	require.Nil(t, tx.Catalog.GetTableStats("test"))
+	assert.NoError(t, tx.Rollback())
+
+	require.NotNil(t, db.Catalog().GetTableStats("test"))
+}
@@ -0,0 +1,188 @@
+package database
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/chaisql/chai/internal/stringutil"
+	"github.com/cockroachdb/errors"
+)
+
+// TriggerTiming defines when a trigger is executed,
+// relative to the modification of the row.
+type TriggerTiming uint8
+
+const (
+	TriggerBefore TriggerTiming = iota + 1
+	TriggerAfter
+)
+
+func (t TriggerTiming) String() string {
+	switch t {
+	case TriggerBefore:
+		return "BEFORE"
+	case TriggerAfter:
+		return "AFTER"
+	}
+
+	return ""
+}
+
+// TriggerEvent is the kind of modification executing a trigger.
+type TriggerEvent uint8
+
+const (
+	TriggerInsert TriggerEvent = iota + 1
+	TriggerUpdate
+	TriggerDelete
+)
+
+func (e TriggerEvent) String() string {
+	switch e {
+	case TriggerInsert:
+		return "INSERT"
+	case TriggerUpdate:
+		return "UPDATE"
+	case TriggerDelete:
+		return "DELETE"
+	}
+
+	return ""
+}
+
+// A TriggerAction is the optional WHEN condition and the list of statements
+// of a trigger, i.e. "WHEN cond BEGIN ... END".
+type TriggerAction interface {
+	String() string
+}
+
+// TriggerInfo contains information about a trigger:
+// an action executed for every row inserted, updated or deleted in a table.
+type TriggerInfo struct {
+	TriggerName string
+	TableName   string
+	Timing      TriggerTiming
+	Event       TriggerEvent
+	Action      TriggerAction
+}
+
+// String returns a SQL representation.
+func (t *TriggerInfo) String() string {
+	var s strings.Builder
+
+	s.WriteString("CREATE TRIGGER ")
+	s.WriteString(stringutil.NormalizeIdentifier(t.TriggerName, '`'))
+	s.WriteRune(' ')
+	s.WriteString(t.Timing.String())
+	s.WriteRune(' ')
+	s.WriteString(t.Event.String())
+	s.WriteString(" ON ")
+	s.WriteString(stringutil.NormalizeIdentifier(t.TableName, '`'))
+	s.WriteString(" FOR EACH ROW ")
+	s.WriteString(t.Action.String())
+
+	return s.String()
+}
+
+// GetTriggerInfo returns the trigger info for the given trigger name.
+func (c *Catalog) GetTriggerInfo(name string) (*TriggerInfo, error) {
+	r, err := c.Cache.Get(RelationTriggerType, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.(*TriggerInfoRelation).Info, nil
+}
+
+// ListTriggers returns the triggers of the given table executed by the given event,
+// sorted by name.
+func (c *Catalog) ListTriggers(tableName string, event TriggerEvent) []*TriggerInfo {
+	var triggers []*TriggerInfo
+	for _, t := range c.Cache.getTableTriggers(tableName) {
+		if t.Event == event {
+			triggers = append(triggers, t)
+		}
+	}
+
+	return triggers
+}
+
+// CreateTrigger creates a trigger.
+// If an object with the same name already exists, returns errs.AlreadyExistsError.
+func (c *CatalogWriter) CreateTrigger(tx *Transaction, info *TriggerInfo) error {
+	if info.TriggerName == "" {
+		return errors.New("trigger name required")
+	}
+
+	ti, err := c.Catalog.GetTableInfo(info.TableName)
+	if err != nil {
+		return err
+	}
+
+	if ti.ReadOnly {
+		return errors.New("cannot create a trigger on a read-only table")
+	}
+
+	rel := TriggerInfoRelation{Info: info}
+	err = c.Catalog.CatalogTable.Insert(tx, &rel)
+	if err != nil {
+		return err
+	}
+
+	return c.Catalog.Cache.Add(tx, &rel)
+}
+
+// DropTrigger deletes a trigger from the catalog.
+func (c *CatalogWriter) DropTrigger(tx *Transaction, name string) error {
+	_, err := c.Cache.Delete(tx, RelationTriggerType, name)
+	if err != nil {
+		return err
+	}
+
+	return c.CatalogTable.Delete(tx, name)
+}
+
+// getTableTriggers returns the triggers of the given table, sorted by name.
+func (c *catalogCache) getTableTriggers(tableName string) []*TriggerInfo {
+	var triggers []*TriggerInfo
+	for _, o := range c.triggers {
+		t := o.(*TriggerInfoRelation).Info
+		if t.TableName != tableName {
+			continue
+		}
+		triggers = append(triggers, t)
+	}
+
+	sort.Slice(triggers, func(i, j int) bool {
+		return triggers[i].TriggerName < triggers[j].TriggerName
+	})
+
+	return triggers
+}
+
+type TriggerInfoRelation struct {
+	Info *TriggerInfo
+}
+
+func (r *TriggerInfoRelation) Type() string {
+	return RelationTriggerType
+}
+
+func (r *TriggerInfoRelation) Name() string {
+	return r.Info.TriggerName
+}
+
+func (r *TriggerInfoRelation) SetName(name string) {
+	r.Info.TriggerName = name
+}
+
+func (r *TriggerInfoRelation) GenerateBaseName() string {
+	return r.Info.TableName + "_trigger"
+}
+
+func (r *TriggerInfoRelation) Clone() Relation {
+	clone := *r
+	info := *r.Info
+	clone.Info = &info
+	return &clone
+}
@@ -0,0 +1,132 @@
+package database
+
+import (
+	"strings"
+
+	"github.com/chaisql/chai/internal/stringutil"
+	"github.com/cockroachdb/errors"
+)
+
+// A ViewQuery is the SELECT statement of a view.
+type ViewQuery interface {
+	String() string
+	// Sources returns the names of the tables and views read by the query.
+	Sources() []string
+}
+
+// ViewInfo contains information about a view:
+// a named SELECT statement that can be queried like a table.
+type ViewInfo struct {
+	ViewName string
+	Query    ViewQuery
+}
+
+// String returns a SQL representation.
+func (v *ViewInfo) String() string {
+	var s strings.Builder
+
+	s.WriteString("CREATE VIEW ")
+	s.WriteString(stringutil.NormalizeIdentifier(v.ViewName, '`'))
+	s.WriteString(" AS ")
+	s.WriteString(v.Query.String())
+
+	return s.String()
+}
+
+// GetViewInfo returns the view info for the given view name.
+func (c *Catalog) GetViewInfo(viewName string) (*ViewInfo, error) {
+	r, err := c.Cache.Get(RelationViewType, viewName)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.(*ViewInfoRelation).Info, nil
+}
+
+// ListViews returns all view names sorted lexicographically.
+func (c *Catalog) ListViews() []string {
+	return c.Cache.ListObjects(RelationViewType)
+}
+
+// ListDependentViews returns the names of the views reading
+// the given table or view, sorted lexicographically.
+func (c *Catalog) ListDependentViews(name string) []string {
+	var views []string
+
+	for _, viewName := range c.ListViews() {
+		info, err := c.GetViewInfo(viewName)
+		if err != nil {
+			continue
+		}
+
+		for _, src := range info.Query.Sources() {
+			if src == name {
+				views = append(views, viewName)
+				break
+			}
+		}
+	}
+
+	return views
+}
+
+// CreateView creates a view.
+// If an object with the same name already exists, returns errs.AlreadyExistsError.
+func (c *CatalogWriter) CreateView(tx *Transaction, info *ViewInfo) error {
+	if info.ViewName == "" {
+		return errors.New("view name required")
+	}
+
+	rel := ViewInfoRelation{Info: info}
+	err := c.Catalog.CatalogTable.Insert(tx, &rel)
+	if err != nil {
+		return err
+	}
+
+	return c.Catalog.Cache.Add(tx, &rel)
+}
+
+// DropView deletes a view from the catalog.
+// It returns an error if another view reads it.
+func (c *CatalogWriter) DropView(tx *Transaction, viewName string) error {
+	// unknown views are reported by the deletion
+	if _, err := c.GetViewInfo(viewName); err == nil {
+		if views := c.ListDependentViews(viewName); len(views) > 0 {
+			return errors.Errorf("cannot drop view %q because view %q depends on it", viewName, views[0])
+		}
+	}
+
+	_, err := c.Cache.Delete(tx, RelationViewType, viewName)
+	if err != nil {
+		return err
+	}
+
+	return c.CatalogTable.Delete(tx, viewName)
+}
+
+type ViewInfoRelation struct {
+	Info *ViewInfo
+}
+
+func (r *ViewInfoRelation) Type() string {
+	return RelationViewType
+}
+
+func (r *ViewInfoRelation) Name() string {
+	return r.Info.ViewName
+}
+
+func (r *ViewInfoRelation) SetName(name string) {
+	r.Info.ViewName = name
+}
+
+func (r *ViewInfoRelation) GenerateBaseName() string {
+	return r.Info.ViewName
+}
+
+func (r *ViewInfoRelation) Clone() Relation {
+	clone := *r
+	info := *r.Info
+	clone.Info = &info
+	return &clone
+}
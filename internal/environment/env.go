@@ -5,6 +5,7 @@ import (
 
 	"github.com/chaisql/chai/internal/database"
 	"github.com/chaisql/chai/internal/object"
+	"github.com/chaisql/chai/internal/tree"
 	"github.com/chaisql/chai/internal/types"
 )
 
@@ -27,6 +28,12 @@ type Environment struct {
 	Tx     *database.Transaction
 
 	baseRow database.BasicRow
+	// temporary tables bound to this environment, by name.
+	tables map[string]*tree.Tree
+	// temporary tables materialized during the execution of the statement,
+	// stored in the outermost environment, and the functions removing them.
+	materialized map[any]*tree.Tree
+	cleanups     []func() error
 
 	Outer *Environment
 }
@@ -92,6 +99,74 @@ func (e *Environment) SetRowFromObject(o types.Object) {
 	e.Row = &e.baseRow
 }
 
+// SetTable binds a temporary table to the environment.
+// It is visible to every environment using this one as outer environment.
+func (e *Environment) SetTable(name string, t *tree.Tree) {
+	if e.tables == nil {
+		e.tables = make(map[string]*tree.Tree)
+	}
+
+	e.tables[name] = t
+}
+
+// GetTable returns the temporary table bound to the given name.
+func (e *Environment) GetTable(name string) (*tree.Tree, bool) {
+	if t, ok := e.tables[name]; ok {
+		return t, true
+	}
+
+	if e.Outer != nil {
+		return e.Outer.GetTable(name)
+	}
+
+	return nil, false
+}
+
+// GetMaterialized returns the temporary table stored under the given key
+// by SetMaterialized.
+func (e *Environment) GetMaterialized(key any) (*tree.Tree, bool) {
+	root := e.root()
+	t, ok := root.materialized[key]
+	return t, ok
+}
+
+// SetMaterialized stores a temporary table in the outermost environment,
+// for it to be shared by the whole execution of the statement.
+// The table is removed by calling Close on the outermost environment.
+func (e *Environment) SetMaterialized(key any, t *tree.Tree, cleanup func() error) {
+	root := e.root()
+	if root.materialized == nil {
+		root.materialized = make(map[any]*tree.Tree)
+	}
+
+	root.materialized[key] = t
+	root.cleanups = append(root.cleanups, cleanup)
+}
+
+// Close removes the temporary tables stored by SetMaterialized.
+// It must be called on the outermost environment once the statement is executed.
+func (e *Environment) Close() error {
+	var err error
+	for _, cleanup := range e.cleanups {
+		if cerr := cleanup(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+
+	e.materialized = nil
+	e.cleanups = nil
+	return err
+}
+
+// root returns the outermost environment.
+func (e *Environment) root() *Environment {
+	for e.Outer != nil {
+		e = e.Outer
+	}
+
+	return e
+}
+
 func (e *Environment) SetParams(params []Param) {
 	e.Params = params
 }
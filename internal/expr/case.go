@@ -0,0 +1,129 @@
+package expr
+
+import (
+	"strings"
+
+	"github.com/chaisql/chai/internal/environment"
+	"github.com/chaisql/chai/internal/types"
+)
+
+// A WhenClause is a condition of a CASE expression
+// associated with the result returned when it matches.
+type WhenClause struct {
+	Cond Expr
+	Then Expr
+}
+
+// A Case expression returns the result of the first WHEN clause that matches.
+// In its simple form, "CASE x WHEN v THEN r ... END", a clause matches if its expression equals the operand.
+// In its searched form, "CASE WHEN cond THEN r ... END", a clause matches if its condition is truthy.
+// If no clause matches, it returns the result of the ELSE clause or NULL.
+type Case struct {
+	// Operand is nil for searched CASE expressions.
+	Operand Expr
+	Whens   []WhenClause
+	// Else is nil if there is no ELSE clause.
+	Else Expr
+}
+
+// Eval evaluates the WHEN clauses in order and returns
+// the result of the first one that matches.
+func (c *Case) Eval(env *environment.Environment) (types.Value, error) {
+	var operand types.Value
+	if c.Operand != nil {
+		var err error
+		operand, err = c.Operand.Eval(env)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, w := range c.Whens {
+		ok, err := c.matches(env, operand, w.Cond)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return w.Then.Eval(env)
+		}
+	}
+
+	if c.Else != nil {
+		return c.Else.Eval(env)
+	}
+
+	return NullLiteral, nil
+}
+
+func (c *Case) matches(env *environment.Environment, operand types.Value, cond Expr) (bool, error) {
+	v, err := cond.Eval(env)
+	if err != nil {
+		return false, err
+	}
+
+	if c.Operand == nil {
+		return types.IsTruthy(v)
+	}
+
+	// NULL never matches, even another NULL
+	if operand.Type() == types.TypeNull || v.Type() == types.TypeNull {
+		return false, nil
+	}
+
+	return operand.EQ(v)
+}
+
+// IsEqual compares this expression with the other expression and returns
+// true if they are equal.
+func (c *Case) IsEqual(other Expr) bool {
+	if other == nil {
+		return false
+	}
+
+	o, ok := other.(*Case)
+	if !ok {
+		return false
+	}
+
+	if !Equal(c.Operand, o.Operand) || !Equal(c.Else, o.Else) {
+		return false
+	}
+
+	if len(c.Whens) != len(o.Whens) {
+		return false
+	}
+
+	for i := range c.Whens {
+		if !Equal(c.Whens[i].Cond, o.Whens[i].Cond) || !Equal(c.Whens[i].Then, o.Whens[i].Then) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (c *Case) String() string {
+	var sb strings.Builder
+
+	sb.WriteString("CASE")
+	if c.Operand != nil {
+		sb.WriteString(" ")
+		sb.WriteString(c.Operand.String())
+	}
+
+	for _, w := range c.Whens {
+		sb.WriteString(" WHEN ")
+		sb.WriteString(w.Cond.String())
+		sb.WriteString(" THEN ")
+		sb.WriteString(w.Then.String())
+	}
+
+	if c.Else != nil {
+		sb.WriteString(" ELSE ")
+		sb.WriteString(c.Else.String())
+	}
+
+	sb.WriteString(" END")
+
+	return sb.String()
+}
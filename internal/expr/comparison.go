@@ -132,10 +132,10 @@ func (op *BetweenOperator) String() string {
 }
 
 // IsComparisonOperator returns true if e is one of
-// =, !=, >, >=, <, <=, IS, IS NOT, IN, or NOT IN operators.
+// =, !=, >, >=, <, <=, IS, IS NOT, IN, NOT IN, LIKE, NOT LIKE, BETWEEN or MATCH operators.
 func IsComparisonOperator(op Operator) bool {
 	switch op.(type) {
-	case *cmpOp, *IsOperator, *IsNotOperator, *InOperator, *NotInOperator, *LikeOperator, *NotLikeOperator, *BetweenOperator:
+	case *cmpOp, *IsOperator, *IsNotOperator, *InOperator, *NotInOperator, *LikeOperator, *NotLikeOperator, *BetweenOperator, *MatchOperator:
 		return true
 	}
 
@@ -169,6 +169,16 @@ func (op *InOperator) Eval(env *environment.Environment) (types.Value, error) {
 		if ok {
 			return TrueLiteral, nil
 		}
+
+		// if the array contains NULL, a might be equal to it
+		ok, err = object.ArrayContainsNull(types.AsArray(b))
+		if err != nil {
+			return NullLiteral, err
+		}
+		if ok {
+			return NullLiteral, nil
+		}
+
 		return FalseLiteral, nil
 	})
 }
@@ -196,7 +206,7 @@ type IsOperator struct {
 
 // Is creates an expression that evaluates to the result of a IS b.
 func Is(a, b Expr) Expr {
-	return &IsOperator{&simpleOperator{a, b, scanner.IN}}
+	return &IsOperator{&simpleOperator{a, b, scanner.IS}}
 }
 
 func (op *IsOperator) Eval(env *environment.Environment) (types.Value, error) {
@@ -213,6 +223,10 @@ func (op *IsOperator) Eval(env *environment.Environment) (types.Value, error) {
 	})
 }
 
+func (op *IsOperator) String() string {
+	return fmt.Sprintf("%v IS %v", op.a, op.b)
+}
+
 type IsNotOperator struct {
 	*simpleOperator
 }
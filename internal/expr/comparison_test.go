@@ -57,6 +57,8 @@ func TestComparisonINExpr(t *testing.T) {
 		{"[1, 2] IN 1", types.NewBooleanValue(false), false},
 		{"1 IN NULL", nullLiteral, false},
 		{"NULL IN [1, 2, NULL]", nullLiteral, false},
+		{"1 IN [1, NULL]", types.NewBooleanValue(true), false},
+		{"1 IN [2, NULL]", nullLiteral, false},
 	}
 
 	for _, test := range tests {
@@ -81,6 +83,8 @@ func TestComparisonNOTINExpr(t *testing.T) {
 		{"[1, 2] NOT IN 1", types.NewBooleanValue(true), false},
 		{"1 NOT IN NULL", nullLiteral, false},
 		{"NULL NOT IN [1, 2, NULL]", nullLiteral, false},
+		{"1 NOT IN [1, NULL]", types.NewBooleanValue(false), false},
+		{"1 NOT IN [2, NULL]", nullLiteral, false},
 	}
 
 	for _, test := range tests {
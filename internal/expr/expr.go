@@ -137,6 +137,18 @@ func Walk(e Expr, fn func(Expr) bool) bool {
 	}
 
 	switch t := e.(type) {
+	case Parentheses:
+		return Walk(t.E, fn)
+	case *BetweenOperator:
+		if !Walk(t.X, fn) {
+			return false
+		}
+		if !Walk(t.LeftHand(), fn) {
+			return false
+		}
+		if !Walk(t.RightHand(), fn) {
+			return false
+		}
 	case Operator:
 		if !Walk(t.LeftHand(), fn) {
 			return false
@@ -164,6 +176,32 @@ func Walk(e Expr, fn func(Expr) bool) bool {
 				return false
 			}
 		}
+	case *Case:
+		if !Walk(t.Operand, fn) {
+			return false
+		}
+		for _, w := range t.Whens {
+			if !Walk(w.Cond, fn) || !Walk(w.Then, fn) {
+				return false
+			}
+		}
+		if !Walk(t.Else, fn) {
+			return false
+		}
+	case *Over:
+		if !Walk(t.Fn, fn) {
+			return false
+		}
+		for _, e := range t.Window.PartitionBy {
+			if !Walk(e, fn) {
+				return false
+			}
+		}
+		for _, e := range t.Window.OrderBy {
+			if !Walk(e, fn) {
+				return false
+			}
+		}
 	}
 
 	return true
@@ -81,6 +81,44 @@ var builtinFunctions = Definitions{
 			return &Now{}, nil
 		},
 	},
+	"row_number": &definition{
+		name:  "row_number",
+		arity: 0,
+		constructorFn: func(args ...expr.Expr) (expr.Function, error) {
+			return &RowNumber{}, nil
+		},
+	},
+	"rank": overloadedDefinition{
+		{
+			name:  "rank",
+			arity: 0,
+			constructorFn: func(args ...expr.Expr) (expr.Function, error) {
+				return &Rank{}, nil
+			},
+		},
+		stringsFunctions["rank"].(*definition),
+	},
+	"dense_rank": &definition{
+		name:  "dense_rank",
+		arity: 0,
+		constructorFn: func(args ...expr.Expr) (expr.Function, error) {
+			return &Rank{Dense: true}, nil
+		},
+	},
+	"lag": &definition{
+		name:  "lag",
+		arity: variadicArity,
+		constructorFn: func(args ...expr.Expr) (expr.Function, error) {
+			return newLag(args...)
+		},
+	},
+	"lead": &definition{
+		name:  "lead",
+		arity: variadicArity,
+		constructorFn: func(args ...expr.Expr) (expr.Function, error) {
+			return newLead(args...)
+		},
+	},
 
 	// strings alias
 	"lower": stringsFunctions["lower"],
@@ -137,6 +175,9 @@ func (t *TypeOf) IsEqual(other expr.Expr) bool {
 
 func (t *TypeOf) Params() []expr.Expr { return []expr.Expr{t.Expr} }
 
+// SetParams replaces the parameters of the function.
+func (t *TypeOf) SetParams(params ...expr.Expr) { t.Expr = params[0] }
+
 func (t *TypeOf) String() string {
 	return fmt.Sprintf("typeof(%v)", t.Expr)
 }
@@ -746,6 +787,9 @@ func (s *Len) IsEqual(other expr.Expr) bool {
 
 func (s *Len) Params() []expr.Expr { return []expr.Expr{s.Expr} }
 
+// SetParams replaces the parameters of the function.
+func (s *Len) SetParams(params ...expr.Expr) { s.Expr = params[0] }
+
 // String returns the literal representation of len.
 func (s *Len) String() string {
 	return fmt.Sprintf("LEN(%v)", s.Expr)
@@ -776,6 +820,11 @@ func (c *Coalesce) Params() []expr.Expr {
 	return c.Exprs
 }
 
+// SetParams replaces the parameters of the function.
+func (c *Coalesce) SetParams(params ...expr.Expr) {
+	c.Exprs = params
+}
+
 type Now struct{}
 
 func (n *Now) Eval(env *environment.Environment) (types.Value, error) {
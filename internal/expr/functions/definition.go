@@ -85,3 +85,39 @@ func (fd *definition) String() string {
 func (fd *definition) Arity() int {
 	return fd.arity
 }
+
+// An overloadedDefinition groups definitions sharing the same name
+// and selects one of them depending on the number of arguments,
+// i.e. rank() is a window function while rank(text, query) is a scalar function.
+type overloadedDefinition []*definition
+
+func (o overloadedDefinition) Name() string {
+	return o[0].name
+}
+
+func (o overloadedDefinition) Function(args ...expr.Expr) (expr.Function, error) {
+	arities := make([]string, len(o))
+	for i, fd := range o {
+		if fd.arity == len(args) {
+			return fd.Function(args...)
+		}
+
+		arities[i] = fmt.Sprintf("%d", fd.arity)
+	}
+
+	return nil, fmt.Errorf("%s() takes %s argument(s), not %d", o.Name(), strings.Join(arities, " or "), len(args))
+}
+
+func (o overloadedDefinition) String() string {
+	ss := make([]string, len(o))
+	for i, fd := range o {
+		ss[i] = fd.String()
+	}
+
+	return strings.Join(ss, ", ")
+}
+
+// Arity returns the arity of the first definition.
+func (o overloadedDefinition) Arity() int {
+	return o[0].arity
+}
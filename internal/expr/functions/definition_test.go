@@ -30,6 +30,26 @@ func TestDefinitions(t *testing.T) {
 	})
 }
 
+func TestOverloadedDefinitions(t *testing.T) {
+	packages := functions.DefaultPackages()
+	def, err := packages.GetFunc("", "rank")
+	assert.NoError(t, err)
+
+	require.Equal(t, "rank", def.Name())
+	require.Equal(t, "rank(), rank(arg1, arg2)", def.String())
+
+	fexpr, err := def.Function()
+	assert.NoError(t, err)
+	require.IsType(t, &functions.Rank{}, fexpr)
+
+	fexpr, err = def.Function(expr.Path(object.NewPath("a")), expr.Path(object.NewPath("b")))
+	assert.NoError(t, err)
+	require.IsType(t, &functions.TextRank{}, fexpr)
+
+	_, err = def.Function(expr.Path(object.NewPath("a")))
+	require.EqualError(t, err, "rank() takes 0 or 2 argument(s), not 1")
+}
+
 func TestPackages(t *testing.T) {
 	table := functions.DefaultPackages()
 
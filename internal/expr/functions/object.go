@@ -68,6 +68,9 @@ func (s *ObjectFields) IsEqual(other expr.Expr) bool {
 
 func (s *ObjectFields) Params() []expr.Expr { return []expr.Expr{s.Expr} }
 
+// SetParams replaces the parameters of the function.
+func (s *ObjectFields) SetParams(params ...expr.Expr) { s.Expr = params[0] }
+
 func (s *ObjectFields) String() string {
 	return fmt.Sprintf("objects.fields(%v)", s.Expr)
 }
@@ -86,10 +86,60 @@ func (sf *ScalarFunction) evalParams(env *environment.Environment) ([]types.Valu
 
 // String returns a string represention of the function expression and its arguments.
 func (sf *ScalarFunction) String() string {
-	return fmt.Sprintf("%s(%v)", sf.def.name, sf.params)
+	params := make([]string, len(sf.params))
+	for i, p := range sf.params {
+		params[i] = p.String()
+	}
+
+	return fmt.Sprintf("%s(%s)", sf.def.name, strings.Join(params, ", "))
 }
 
 // Params return the function arguments.
 func (sf *ScalarFunction) Params() []expr.Expr {
 	return sf.params
 }
+
+// SetParams replaces the arguments of the function.
+func (sf *ScalarFunction) SetParams(params ...expr.Expr) {
+	sf.params = params
+}
+
+// IsEqual compares this expression with the other expression and returns
+// true if they are equal.
+func (sf *ScalarFunction) IsEqual(other expr.Expr) bool {
+	if other == nil {
+		return false
+	}
+
+	o, ok := other.(*ScalarFunction)
+	if !ok || sf.def != o.def || len(sf.params) != len(o.params) {
+		return false
+	}
+
+	for i := range sf.params {
+		if !expr.Equal(sf.params[i], o.params[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IsVolatile returns whether the expression calls a function that can return
+// different values for the same arguments, such as NOW() or RANDOM().
+func IsVolatile(e expr.Expr) bool {
+	var volatile bool
+
+	expr.Walk(e, func(e expr.Expr) bool {
+		switch t := e.(type) {
+		case *Now:
+			volatile = true
+		case *ScalarFunction:
+			volatile = t.def == random
+		}
+
+		return !volatile
+	})
+
+	return volatile
+}
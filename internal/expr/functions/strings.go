@@ -6,6 +6,7 @@ import (
 
 	"github.com/chaisql/chai/internal/environment"
 	"github.com/chaisql/chai/internal/expr"
+	"github.com/chaisql/chai/internal/fulltext"
 	"github.com/chaisql/chai/internal/types"
 )
 
@@ -45,6 +46,13 @@ var stringsFunctions = Definitions{
 			return &Trim{Expr: args, TrimFunc: strings.TrimRight, Name: "RTRIM"}, nil
 		},
 	},
+	"rank": &definition{
+		name:  "rank",
+		arity: 2,
+		constructorFn: func(args ...expr.Expr) (expr.Function, error) {
+			return &TextRank{Text: args[0], Query: args[1]}, nil
+		},
+	},
 }
 
 func StringsDefinitions() Definitions {
@@ -87,6 +95,9 @@ func (s *Lower) IsEqual(other expr.Expr) bool {
 
 func (s *Lower) Params() []expr.Expr { return []expr.Expr{s.Expr} }
 
+// SetParams replaces the parameters of the function.
+func (s *Lower) SetParams(params ...expr.Expr) { s.Expr = params[0] }
+
 func (s *Lower) String() string {
 	return fmt.Sprintf("LOWER(%v)", s.Expr)
 }
@@ -127,6 +138,9 @@ func (s *Upper) IsEqual(other expr.Expr) bool {
 
 func (s *Upper) Params() []expr.Expr { return []expr.Expr{s.Expr} }
 
+// SetParams replaces the parameters of the function.
+func (s *Upper) SetParams(params ...expr.Expr) { s.Expr = params[0] }
+
 func (s *Upper) String() string {
 	return fmt.Sprintf("UPPER(%v)", s.Expr)
 }
@@ -200,9 +214,63 @@ func (s *Trim) Params() []expr.Expr {
 	return s.Expr
 }
 
+// SetParams replaces the parameters of the function.
+func (s *Trim) SetParams(params ...expr.Expr) {
+	s.Expr = params
+}
+
 func (s *Trim) String() string {
 	if len(s.Expr) == 1 {
 		return fmt.Sprintf("%v(%v)", s.Name, s.Expr[0])
 	}
 	return fmt.Sprintf("%v(%v, %v)", s.Name, s.Expr[0], s.Expr[1])
 }
+
+// TextRank is the RANK(text, query) function.
+// It returns the relevance of the text for a full-text search query,
+// as a double. Texts matching more terms of the query, more often,
+// get a higher score.
+type TextRank struct {
+	Text  expr.Expr
+	Query expr.Expr
+}
+
+func (s *TextRank) Eval(env *environment.Environment) (types.Value, error) {
+	text, err := s.Text.Eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	query, err := s.Query.Eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	if text.Type() != types.TypeText || query.Type() != types.TypeText {
+		return types.NewNullValue(), nil
+	}
+
+	return types.NewDoubleValue(fulltext.Rank(types.AsString(text), types.AsString(query))), nil
+}
+
+func (s *TextRank) IsEqual(other expr.Expr) bool {
+	if other == nil {
+		return false
+	}
+
+	o, ok := other.(*TextRank)
+	if !ok {
+		return false
+	}
+
+	return expr.Equal(s.Text, o.Text) && expr.Equal(s.Query, o.Query)
+}
+
+func (s *TextRank) Params() []expr.Expr { return []expr.Expr{s.Text, s.Query} }
+
+// SetParams replaces the parameters of the function.
+func (s *TextRank) SetParams(params ...expr.Expr) { s.Text, s.Query = params[0], params[1] }
+
+func (s *TextRank) String() string {
+	return fmt.Sprintf("RANK(%v, %v)", s.Text, s.Query)
+}
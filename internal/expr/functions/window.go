@@ -0,0 +1,274 @@
+package functions
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/chaisql/chai/internal/environment"
+	"github.com/chaisql/chai/internal/expr"
+	"github.com/chaisql/chai/internal/object"
+	"github.com/chaisql/chai/internal/types"
+	"github.com/cockroachdb/errors"
+)
+
+var _ expr.WindowFunction = (*RowNumber)(nil)
+
+// RowNumber is the ROW_NUMBER() window function. It returns the position
+// of the current row within its partition, starting at 1.
+type RowNumber struct{}
+
+func (r *RowNumber) Eval(env *environment.Environment) (types.Value, error) {
+	return nil, errors.New("misuse of window function ROW_NUMBER()")
+}
+
+// EvalWindow returns the position of the current row in the window.
+func (r *RowNumber) EvalWindow(w *expr.Window) (types.Value, error) {
+	return types.NewIntegerValue(int64(w.Current + 1)), nil
+}
+
+// IsEqual compares this expression with the other expression and returns
+// true if they are equal.
+func (r *RowNumber) IsEqual(other expr.Expr) bool {
+	if other == nil {
+		return false
+	}
+
+	_, ok := other.(*RowNumber)
+	return ok
+}
+
+func (r *RowNumber) Params() []expr.Expr { return nil }
+
+func (r *RowNumber) String() string {
+	return "ROW_NUMBER()"
+}
+
+var _ expr.WindowFunction = (*Rank)(nil)
+
+// Rank is the RANK() and DENSE_RANK() window functions.
+// RANK() returns the position of the first peer of the current row,
+// leaving gaps between the ranks of rows with different values.
+// DENSE_RANK() returns the number of distinct values up to the current row, without gaps.
+type Rank struct {
+	Dense bool
+}
+
+func (r *Rank) Eval(env *environment.Environment) (types.Value, error) {
+	return nil, fmt.Errorf("misuse of window function %s", r)
+}
+
+// EvalWindow returns the rank of the current row in the window.
+func (r *Rank) EvalWindow(w *expr.Window) (types.Value, error) {
+	if r.Dense {
+		return types.NewIntegerValue(int64(w.PeerGroup + 1)), nil
+	}
+
+	return types.NewIntegerValue(int64(w.PeerStart + 1)), nil
+}
+
+// IsEqual compares this expression with the other expression and returns
+// true if they are equal.
+func (r *Rank) IsEqual(other expr.Expr) bool {
+	if other == nil {
+		return false
+	}
+
+	o, ok := other.(*Rank)
+	if !ok {
+		return false
+	}
+
+	return r.Dense == o.Dense
+}
+
+func (r *Rank) Params() []expr.Expr { return nil }
+
+func (r *Rank) String() string {
+	if r.Dense {
+		return "DENSE_RANK()"
+	}
+
+	return "RANK()"
+}
+
+// offsetFunction holds the parameters of the LAG() and LEAD() window functions.
+type offsetFunction struct {
+	Expr expr.Expr
+	// Offset is the number of rows separating the current row
+	// from the one the expression is evaluated against. Defaults to 1.
+	Offset expr.Expr
+	// Default is returned when there is no row at the given offset. Defaults to NULL.
+	Default expr.Expr
+}
+
+func newOffsetFunction(name string, args ...expr.Expr) (offsetFunction, error) {
+	if len(args) > 3 {
+		return offsetFunction{}, fmt.Errorf("%s() takes at most 3 arguments, not %d", name, len(args))
+	}
+
+	f := offsetFunction{Expr: args[0]}
+	if len(args) > 1 {
+		f.Offset = args[1]
+	}
+	if len(args) > 2 {
+		f.Default = args[2]
+	}
+
+	return f, nil
+}
+
+// eval evaluates the expression against the row found
+// at the given direction from the current row.
+func (f *offsetFunction) eval(w *expr.Window, direction int) (types.Value, error) {
+	cur := w.Rows[w.Current]
+
+	offset := int64(1)
+	if f.Offset != nil {
+		v, err := f.Offset.Eval(cur)
+		if err != nil {
+			return nil, err
+		}
+		if v.Type() == types.TypeNull {
+			return types.NewNullValue(), nil
+		}
+
+		v, err = object.CastAsInteger(v)
+		if err != nil {
+			return nil, err
+		}
+
+		offset = types.AsInt64(v)
+		if offset < 0 {
+			return nil, errors.New("window function offset cannot be negative")
+		}
+	}
+
+	pos := int64(w.Current) + int64(direction)*offset
+	if pos >= 0 && pos < int64(len(w.Rows)) {
+		return f.Expr.Eval(w.Rows[pos])
+	}
+
+	if f.Default == nil {
+		return types.NewNullValue(), nil
+	}
+
+	return f.Default.Eval(cur)
+}
+
+func (f *offsetFunction) isEqual(o *offsetFunction) bool {
+	return expr.Equal(f.Expr, o.Expr) &&
+		(f.Offset == nil) == (o.Offset == nil) && (f.Offset == nil || expr.Equal(f.Offset, o.Offset)) &&
+		(f.Default == nil) == (o.Default == nil) && (f.Default == nil || expr.Equal(f.Default, o.Default))
+}
+
+func (f *offsetFunction) Params() []expr.Expr {
+	params := []expr.Expr{f.Expr}
+	if f.Offset != nil {
+		params = append(params, f.Offset)
+	}
+	if f.Default != nil {
+		params = append(params, f.Default)
+	}
+
+	return params
+}
+
+func (f *offsetFunction) format(name string) string {
+	params := f.Params()
+	args := make([]string, len(params))
+	for i, p := range params {
+		args[i] = p.String()
+	}
+
+	return fmt.Sprintf("%s(%s)", name, strings.Join(args, ", "))
+}
+
+var _ expr.WindowFunction = (*Lag)(nil)
+
+// Lag is the LAG() window function. It returns the value of an expression
+// evaluated against a row preceding the current row.
+type Lag struct {
+	offsetFunction
+}
+
+func newLag(args ...expr.Expr) (expr.Function, error) {
+	f, err := newOffsetFunction("lag", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Lag{f}, nil
+}
+
+func (l *Lag) Eval(env *environment.Environment) (types.Value, error) {
+	return nil, errors.New("misuse of window function LAG()")
+}
+
+// EvalWindow evaluates the expression against a preceding row of the window.
+func (l *Lag) EvalWindow(w *expr.Window) (types.Value, error) {
+	return l.eval(w, -1)
+}
+
+// IsEqual compares this expression with the other expression and returns
+// true if they are equal.
+func (l *Lag) IsEqual(other expr.Expr) bool {
+	if other == nil {
+		return false
+	}
+
+	o, ok := other.(*Lag)
+	if !ok {
+		return false
+	}
+
+	return l.isEqual(&o.offsetFunction)
+}
+
+func (l *Lag) String() string {
+	return l.format("LAG")
+}
+
+var _ expr.WindowFunction = (*Lead)(nil)
+
+// Lead is the LEAD() window function. It returns the value of an expression
+// evaluated against a row following the current row.
+type Lead struct {
+	offsetFunction
+}
+
+func newLead(args ...expr.Expr) (expr.Function, error) {
+	f, err := newOffsetFunction("lead", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Lead{f}, nil
+}
+
+func (l *Lead) Eval(env *environment.Environment) (types.Value, error) {
+	return nil, errors.New("misuse of window function LEAD()")
+}
+
+// EvalWindow evaluates the expression against a following row of the window.
+func (l *Lead) EvalWindow(w *expr.Window) (types.Value, error) {
+	return l.eval(w, 1)
+}
+
+// IsEqual compares this expression with the other expression and returns
+// true if they are equal.
+func (l *Lead) IsEqual(other expr.Expr) bool {
+	if other == nil {
+		return false
+	}
+
+	o, ok := other.(*Lead)
+	if !ok {
+		return false
+	}
+
+	return l.isEqual(&o.offsetFunction)
+}
+
+func (l *Lead) String() string {
+	return l.format("LEAD")
+}
@@ -92,12 +92,16 @@ func Not(e Expr) Expr {
 	return &NotOp{&simpleOperator{a: e}}
 }
 
-// Eval implements the Expr interface. It evaluates e and returns true if b is falsy
+// Eval implements the Expr interface. It evaluates e and returns true if b is falsy.
+// It returns NULL if e evaluates to NULL.
 func (op *NotOp) Eval(env *environment.Environment) (types.Value, error) {
 	s, err := op.a.Eval(env)
 	if err != nil {
 		return FalseLiteral, err
 	}
+	if s.Type() == types.TypeNull {
+		return NullLiteral, nil
+	}
 
 	isTruthy, err := types.IsTruthy(s)
 	if err != nil {
@@ -0,0 +1,34 @@
+package expr
+
+import (
+	"github.com/chaisql/chai/internal/environment"
+	"github.com/chaisql/chai/internal/fulltext"
+	"github.com/chaisql/chai/internal/sql/scanner"
+	"github.com/chaisql/chai/internal/types"
+)
+
+// MatchOperator is the full-text search operator.
+// a MATCH b evaluates to true if the text a contains
+// every term of the query b, once both are analyzed by the fulltext package.
+type MatchOperator struct {
+	*simpleOperator
+}
+
+// Match creates an expression that evaluates to the result of a MATCH b.
+func Match(a, b Expr) Expr {
+	return &MatchOperator{&simpleOperator{a, b, scanner.MATCH}}
+}
+
+func (op *MatchOperator) Eval(env *environment.Environment) (types.Value, error) {
+	return op.simpleOperator.eval(env, func(a, b types.Value) (types.Value, error) {
+		if a.Type() != types.TypeText || b.Type() != types.TypeText {
+			return NullLiteral, nil
+		}
+
+		if fulltext.Match(types.AsString(a), types.AsString(b)) {
+			return TrueLiteral, nil
+		}
+
+		return FalseLiteral, nil
+	})
+}
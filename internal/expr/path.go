@@ -29,6 +29,10 @@ func (p Path) Eval(env *environment.Environment) (types.Value, error) {
 	}
 
 	v, err := dp.GetValueFromObject(r.Object())
+	// the column may be qualified by the table name of the row
+	if errors.Is(err, types.ErrFieldNotFound) && len(dp) > 1 && dp[0].FieldName == r.TableName() {
+		v, err = dp[1:].GetValueFromObject(r.Object())
+	}
 	if errors.Is(err, types.ErrFieldNotFound) {
 		return NullLiteral, nil
 	}
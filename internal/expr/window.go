@@ -0,0 +1,248 @@
+package expr
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/chaisql/chai/internal/environment"
+	"github.com/chaisql/chai/internal/tree"
+	"github.com/chaisql/chai/internal/types"
+	"github.com/cockroachdb/errors"
+)
+
+// A WindowFunction is a function that computes a value for a row
+// using the other rows of its partition, i.e. ROW_NUMBER() or LAG().
+// It can only be used with an OVER clause.
+type WindowFunction interface {
+	Function
+
+	// EvalWindow returns the value of the function for the current row of the window.
+	EvalWindow(w *Window) (types.Value, error)
+}
+
+// A Window is the list of rows of a partition, sorted by the ORDER BY clause
+// of the window definition, along with the position of the current row.
+type Window struct {
+	Rows []*environment.Environment
+	// Current is the position of the current row in Rows.
+	Current int
+	// PeerStart is the position of the first row having the same
+	// ORDER BY values as the current row.
+	PeerStart int
+	// PeerGroup is the number of distinct ORDER BY values
+	// that precede the ones of the current row.
+	PeerGroup int
+}
+
+// FrameBoundType is the type of a bound of a window frame.
+type FrameBoundType int
+
+// Types of frame bounds.
+const (
+	UnboundedPreceding FrameBoundType = iota
+	Preceding
+	CurrentRow
+	Following
+	UnboundedFollowing
+)
+
+// A FrameBound is the start or the end of a window frame.
+type FrameBound struct {
+	Type FrameBoundType
+	// Offset is the number of rows of a Preceding or Following bound.
+	Offset int64
+}
+
+func (b FrameBound) String() string {
+	switch b.Type {
+	case UnboundedPreceding:
+		return "UNBOUNDED PRECEDING"
+	case Preceding:
+		return fmt.Sprintf("%d PRECEDING", b.Offset)
+	case CurrentRow:
+		return "CURRENT ROW"
+	case Following:
+		return fmt.Sprintf("%d FOLLOWING", b.Offset)
+	}
+
+	return "UNBOUNDED FOLLOWING"
+}
+
+// A WindowFrame delimits, relatively to the current row,
+// the rows of a partition aggregated by an aggregate function.
+// Only ROWS frames are supported.
+type WindowFrame struct {
+	Start FrameBound
+	End   FrameBound
+}
+
+// Validate ensures the frame bounds are compatible.
+func (f *WindowFrame) Validate() error {
+	if f.Start.Type == UnboundedFollowing {
+		return errors.New("frame start cannot be UNBOUNDED FOLLOWING")
+	}
+	if f.End.Type == UnboundedPreceding {
+		return errors.New("frame end cannot be UNBOUNDED PRECEDING")
+	}
+	if f.End.Type < f.Start.Type {
+		return errors.New("frame end cannot precede frame start")
+	}
+
+	return nil
+}
+
+// Bounds returns the positions of the first row of the frame and of the row following the last one,
+// for the row at position cur of a partition of n rows.
+func (f *WindowFrame) Bounds(cur, n int) (int, int) {
+	lo := f.Start.position(cur, n)
+	hi := f.End.position(cur, n) + 1
+
+	lo = max(lo, 0)
+	hi = min(hi, n)
+	if hi < lo {
+		hi = lo
+	}
+
+	return lo, hi
+}
+
+func (b FrameBound) position(cur, n int) int {
+	switch b.Type {
+	case UnboundedPreceding:
+		return 0
+	case Preceding:
+		return cur - int(b.Offset)
+	case CurrentRow:
+		return cur
+	case Following:
+		return cur + int(b.Offset)
+	}
+
+	return n - 1
+}
+
+func (f *WindowFrame) String() string {
+	return fmt.Sprintf("ROWS BETWEEN %s AND %s", f.Start, f.End)
+}
+
+// A WindowDefinition describes how the rows are partitioned and sorted
+// before evaluating a window function.
+type WindowDefinition struct {
+	PartitionBy      []Expr
+	OrderBy          []Expr
+	OrderBySortOrder tree.SortOrder
+	// Frame is nil when using the default frame: every row of the partition
+	// up to the last peer of the current row if there is an ORDER BY clause,
+	// the whole partition otherwise.
+	Frame *WindowFrame
+}
+
+// IsEqual compares this window definition with the other one and returns
+// true if they are equal.
+func (w *WindowDefinition) IsEqual(other *WindowDefinition) bool {
+	if other == nil {
+		return false
+	}
+
+	if len(w.PartitionBy) != len(other.PartitionBy) || len(w.OrderBy) != len(other.OrderBy) {
+		return false
+	}
+
+	for i := range w.PartitionBy {
+		if !Equal(w.PartitionBy[i], other.PartitionBy[i]) {
+			return false
+		}
+	}
+
+	for i := range w.OrderBy {
+		if !Equal(w.OrderBy[i], other.OrderBy[i]) {
+			return false
+		}
+		if w.OrderBySortOrder.IsDesc(i) != other.OrderBySortOrder.IsDesc(i) {
+			return false
+		}
+	}
+
+	if w.Frame == nil || other.Frame == nil {
+		return w.Frame == other.Frame
+	}
+
+	return *w.Frame == *other.Frame
+}
+
+func (w *WindowDefinition) String() string {
+	var sb strings.Builder
+
+	if len(w.PartitionBy) > 0 {
+		sb.WriteString("PARTITION BY ")
+		for i, e := range w.PartitionBy {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString(e.String())
+		}
+	}
+
+	if len(w.OrderBy) > 0 {
+		if sb.Len() > 0 {
+			sb.WriteString(" ")
+		}
+		sb.WriteString("ORDER BY ")
+		for i, e := range w.OrderBy {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString(e.String())
+			if w.OrderBySortOrder.IsDesc(i) {
+				sb.WriteString(" DESC")
+			}
+		}
+	}
+
+	if w.Frame != nil {
+		if sb.Len() > 0 {
+			sb.WriteString(" ")
+		}
+		sb.WriteString(w.Frame.String())
+	}
+
+	return sb.String()
+}
+
+// Over is a window function or an aggregate function
+// evaluated over a window of rows.
+// Its value is computed by the window operator and stored in the row
+// under its string representation, like aggregate functions.
+type Over struct {
+	Fn     Expr
+	Window *WindowDefinition
+}
+
+// Eval returns the value computed by the window operator.
+func (o *Over) Eval(env *environment.Environment) (types.Value, error) {
+	r, ok := env.GetRow()
+	if !ok {
+		return nil, fmt.Errorf("misuse of window function %s", o.Fn)
+	}
+
+	return r.Get(o.String())
+}
+
+// IsEqual compares this expression with the other expression and returns
+// true if they are equal.
+func (o *Over) IsEqual(other Expr) bool {
+	if other == nil {
+		return false
+	}
+
+	oo, ok := other.(*Over)
+	if !ok {
+		return false
+	}
+
+	return Equal(o.Fn, oo.Fn) && o.Window.IsEqual(oo.Window)
+}
+
+func (o *Over) String() string {
+	return fmt.Sprintf("%s OVER (%s)", o.Fn, o.Window)
+}
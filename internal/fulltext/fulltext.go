@@ -0,0 +1,110 @@
+// Package fulltext implements the text analysis used by full-text indexes
+// and the MATCH operator: texts are split into words, lowercased,
+// stripped of common English words and reduced to their stem.
+package fulltext
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// stopWords are ignored when analyzing a text,
+// as they appear in almost every English text.
+var stopWords = map[string]struct{}{
+	"a": {}, "an": {}, "and": {}, "are": {}, "as": {}, "at": {}, "be": {}, "but": {}, "by": {},
+	"for": {}, "if": {}, "in": {}, "into": {}, "is": {}, "it": {}, "no": {}, "not": {}, "of": {},
+	"on": {}, "or": {}, "such": {}, "that": {}, "the": {}, "their": {}, "then": {}, "there": {},
+	"these": {}, "they": {}, "this": {}, "to": {}, "was": {}, "will": {}, "with": {},
+}
+
+// Tokenize splits the text into words and returns their terms, in order.
+// Words are sequences of letters and digits. They are lowercased and stemmed,
+// stop words are removed.
+func Tokenize(text string) []string {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	terms := words[:0]
+	for _, w := range words {
+		w = strings.ToLower(w)
+		if _, ok := stopWords[w]; ok {
+			continue
+		}
+
+		terms = append(terms, Stem(w))
+	}
+
+	return terms
+}
+
+// Terms returns the distinct terms of the text, in order of appearance.
+func Terms(text string) []string {
+	tokens := Tokenize(text)
+
+	seen := make(map[string]struct{}, len(tokens))
+	terms := tokens[:0]
+	for _, t := range tokens {
+		if _, ok := seen[t]; ok {
+			continue
+		}
+
+		seen[t] = struct{}{}
+		terms = append(terms, t)
+	}
+
+	return terms
+}
+
+// Match returns whether the text contains all the terms of the query.
+// A query without terms matches nothing.
+func Match(text, query string) bool {
+	q := Terms(query)
+	if len(q) == 0 {
+		return false
+	}
+
+	freqs := frequencies(Tokenize(text))
+	for _, t := range q {
+		if freqs[t] == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Rank returns the relevance of the text for the query.
+// Every term of the query found in the text adds 1 + ln(f) to the score,
+// f being the number of occurrences of the term.
+// The score is then divided by the square root of the number of terms of the text,
+// so that short texts rank higher than long texts mentioning the terms as often.
+// A text containing none of the terms has a score of 0.
+func Rank(text, query string) float64 {
+	tokens := Tokenize(text)
+	if len(tokens) == 0 {
+		return 0
+	}
+
+	freqs := frequencies(tokens)
+
+	var score float64
+	for _, t := range Terms(query) {
+		if f := freqs[t]; f > 0 {
+			score += 1 + math.Log(float64(f))
+		}
+	}
+
+	return score / math.Sqrt(float64(len(tokens)))
+}
+
+// frequencies returns the number of occurrences of each term.
+func frequencies(tokens []string) map[string]int {
+	freqs := make(map[string]int, len(tokens))
+	for _, t := range tokens {
+		freqs[t]++
+	}
+
+	return freqs
+}
@@ -0,0 +1,89 @@
+package fulltext
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"", []string{}},
+		{"The", []string{}},
+		{"Hello, World!", []string{"hello", "world"}},
+		{"the connections of the databases", []string{"connect", "databas"}},
+		{"Go1.22 is   out", []string{"go1", "22", "out"}},
+		{"Écoles et cafés", []string{"écoles", "et", "cafés"}},
+		{"running runs", []string{"run", "run"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			got := Tokenize(test.text)
+			if len(got) == 0 && len(test.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Tokenize(%q) = %q, want %q", test.text, got, test.want)
+			}
+		})
+	}
+}
+
+func TestTerms(t *testing.T) {
+	got := Terms("Running runs and the runner runs")
+	want := []string{"run", "runner"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Terms() = %q, want %q", got, want)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		text, query string
+		want        bool
+	}{
+		{"Fast embedded databases", "database", true},
+		{"Fast embedded databases", "DATABASES", true},
+		{"Fast embedded databases", "embedded database", true},
+		{"Fast embedded databases", "embedded search", false},
+		{"Fast embedded databases", "", false},
+		{"Fast embedded databases", "the", false},
+		{"", "database", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.text+"/"+test.query, func(t *testing.T) {
+			if got := Match(test.text, test.query); got != test.want {
+				t.Errorf("Match(%q, %q) = %v, want %v", test.text, test.query, got, test.want)
+			}
+		})
+	}
+}
+
+func TestRank(t *testing.T) {
+	if got := Rank("nothing relevant", "database"); got != 0 {
+		t.Errorf("expected 0, got %v", got)
+	}
+
+	if got := Rank("", "database"); got != 0 {
+		t.Errorf("expected 0, got %v", got)
+	}
+
+	// more terms of the query
+	if a, b := Rank("sql database engine", "sql database"), Rank("sql storage engine", "sql database"); a <= b {
+		t.Errorf("expected %v > %v", a, b)
+	}
+
+	// more occurrences
+	if a, b := Rank("database database engine", "database"), Rank("database storage engine", "database"); a <= b {
+		t.Errorf("expected %v > %v", a, b)
+	}
+
+	// shorter text
+	if a, b := Rank("database engine", "database"), Rank("database storage engine", "database"); a <= b {
+		t.Errorf("expected %v > %v", a, b)
+	}
+}
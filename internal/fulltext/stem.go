@@ -0,0 +1,325 @@
+package fulltext
+
+// Stem reduces an English word to its stem using the Porter stemming algorithm,
+// so that different forms of a word share the same term, i.e. "connected",
+// "connecting" and "connection" are all reduced to "connect".
+// The word must be lowercased. Words containing characters other
+// than ASCII letters, as well as words of less than 3 letters,
+// are returned as is.
+//
+// See https://tartarus.org/martin/PorterStemmer/
+func Stem(word string) string {
+	if len(word) <= 2 {
+		return word
+	}
+
+	for i := 0; i < len(word); i++ {
+		if word[i] < 'a' || word[i] > 'z' {
+			return word
+		}
+	}
+
+	s := stemmer{b: []byte(word), k: len(word) - 1}
+	s.step1ab()
+	if s.k > 0 {
+		s.step1c()
+		s.step2()
+		s.step3()
+		s.step4()
+		s.step5()
+	}
+
+	return string(s.b[:s.k+1])
+}
+
+// stemmer holds the word being stemmed.
+// b[:k+1] is the current stem and j is the end of
+// the stem without the suffix matched by the last call to ends.
+type stemmer struct {
+	b    []byte
+	k, j int
+}
+
+// cons returns whether b[i] is a consonant.
+func (s *stemmer) cons(i int) bool {
+	switch s.b[i] {
+	case 'a', 'e', 'i', 'o', 'u':
+		return false
+	case 'y':
+		if i == 0 {
+			return true
+		}
+		return !s.cons(i - 1)
+	}
+
+	return true
+}
+
+// m measures the number of consonant sequences in b[:j+1].
+// If c is a consonant sequence and v a vowel sequence,
+// and <..> indicates arbitrary presence:
+//
+//	<c><v>       gives 0
+//	<c>vc<v>     gives 1
+//	<c>vcvc<v>   gives 2
+//	<c>vcvcvc<v> gives 3
+func (s *stemmer) m() int {
+	n := 0
+	i := 0
+	for {
+		if i > s.j {
+			return n
+		}
+		if !s.cons(i) {
+			break
+		}
+		i++
+	}
+	i++
+
+	for {
+		for {
+			if i > s.j {
+				return n
+			}
+			if s.cons(i) {
+				break
+			}
+			i++
+		}
+		i++
+		n++
+
+		for {
+			if i > s.j {
+				return n
+			}
+			if !s.cons(i) {
+				break
+			}
+			i++
+		}
+		i++
+	}
+}
+
+// vowelInStem returns whether b[:j+1] contains a vowel.
+func (s *stemmer) vowelInStem() bool {
+	for i := 0; i <= s.j; i++ {
+		if !s.cons(i) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// doubleC returns whether b[j-1:j+1] is a double consonant.
+func (s *stemmer) doubleC(j int) bool {
+	if j < 1 || s.b[j] != s.b[j-1] {
+		return false
+	}
+
+	return s.cons(j)
+}
+
+// cvc returns whether b[i-2:i+1] has the form consonant - vowel - consonant
+// and the second consonant is not w, x or y. This is used when trying to
+// restore an e at the end of a short word, i.e.
+//
+//	cav(e), lov(e), hop(e), crim(e), but
+//	snow, box, tray.
+func (s *stemmer) cvc(i int) bool {
+	if i < 2 || !s.cons(i) || s.cons(i-1) || !s.cons(i-2) {
+		return false
+	}
+
+	switch s.b[i] {
+	case 'w', 'x', 'y':
+		return false
+	}
+
+	return true
+}
+
+// ends returns whether b[:k+1] ends with the suffix and sets j accordingly.
+func (s *stemmer) ends(suffix string) bool {
+	l := len(suffix)
+	if l > s.k+1 || string(s.b[s.k-l+1:s.k+1]) != suffix {
+		return false
+	}
+
+	s.j = s.k - l
+	return true
+}
+
+// setTo replaces b[j+1:k+1] by the given string and adjusts k.
+func (s *stemmer) setTo(str string) {
+	s.b = append(s.b[:s.j+1], str...)
+	s.k = s.j + len(str)
+}
+
+// r replaces the suffix if the stem has at least one consonant sequence.
+func (s *stemmer) r(str string) {
+	if s.m() > 0 {
+		s.setTo(str)
+	}
+}
+
+// step1ab removes plurals and -ed or -ing, i.e.
+//
+//	caresses  ->  caress
+//	ponies    ->  poni
+//	cats      ->  cat
+//	feed      ->  feed
+//	agreed    ->  agree
+//	plastered ->  plaster
+//	motoring  ->  motor
+//	sing      ->  sing
+//	hopping   ->  hop
+//	filing    ->  file
+func (s *stemmer) step1ab() {
+	if s.b[s.k] == 's' {
+		switch {
+		case s.ends("sses"):
+			s.k -= 2
+		case s.ends("ies"):
+			s.setTo("i")
+		case s.b[s.k-1] != 's':
+			s.k--
+		}
+	}
+
+	if s.ends("eed") {
+		if s.m() > 0 {
+			s.k--
+		}
+		return
+	}
+
+	if (s.ends("ed") || s.ends("ing")) && s.vowelInStem() {
+		s.k = s.j
+		switch {
+		case s.ends("at"):
+			s.setTo("ate")
+		case s.ends("bl"):
+			s.setTo("ble")
+		case s.ends("iz"):
+			s.setTo("ize")
+		case s.doubleC(s.k):
+			switch s.b[s.k] {
+			case 'l', 's', 'z':
+			default:
+				s.k--
+			}
+		default:
+			s.j = s.k
+			if s.m() == 1 && s.cvc(s.k) {
+				s.setTo("e")
+			}
+		}
+	}
+}
+
+// step1c turns a terminal y into i when there is another vowel in the stem.
+func (s *stemmer) step1c() {
+	if s.ends("y") && s.vowelInStem() {
+		s.b[s.k] = 'i'
+	}
+}
+
+// step2 maps double suffixes to single ones,
+// i.e. -ization (= -ize plus -ation) maps to -ize.
+func (s *stemmer) step2() {
+	if s.k < 1 {
+		return
+	}
+
+	for _, r := range step2Suffixes[s.b[s.k-1]] {
+		if s.ends(r[0]) {
+			s.r(r[1])
+			return
+		}
+	}
+}
+
+var step2Suffixes = map[byte][][2]string{
+	'a': {{"ational", "ate"}, {"tional", "tion"}},
+	'c': {{"enci", "ence"}, {"anci", "ance"}},
+	'e': {{"izer", "ize"}},
+	'l': {{"bli", "ble"}, {"alli", "al"}, {"entli", "ent"}, {"eli", "e"}, {"ousli", "ous"}},
+	'o': {{"ization", "ize"}, {"ation", "ate"}, {"ator", "ate"}},
+	's': {{"alism", "al"}, {"iveness", "ive"}, {"fulness", "ful"}, {"ousness", "ous"}},
+	't': {{"aliti", "al"}, {"iviti", "ive"}, {"biliti", "ble"}},
+	'g': {{"logi", "log"}},
+}
+
+// step3 deals with -ic-, -full, -ness etc.
+func (s *stemmer) step3() {
+	for _, r := range step3Suffixes[s.b[s.k]] {
+		if s.ends(r[0]) {
+			s.r(r[1])
+			return
+		}
+	}
+}
+
+var step3Suffixes = map[byte][][2]string{
+	'e': {{"icate", "ic"}, {"ative", ""}, {"alize", "al"}},
+	'i': {{"iciti", "ic"}},
+	'l': {{"ical", "ic"}, {"ful", ""}},
+	's': {{"ness", ""}},
+}
+
+// step4 removes -ant, -ence etc. in context <c>vcvc<v>.
+func (s *stemmer) step4() {
+	if s.k < 1 {
+		return
+	}
+
+	var found bool
+	for _, suffix := range step4Suffixes[s.b[s.k-1]] {
+		if s.ends(suffix) {
+			found = true
+			break
+		}
+	}
+
+	if !found && s.b[s.k-1] == 'o' {
+		// -ion is only removed after s or t
+		found = (s.ends("ion") && s.j >= 0 && (s.b[s.j] == 's' || s.b[s.j] == 't')) || s.ends("ou")
+	}
+
+	if found && s.m() > 1 {
+		s.k = s.j
+	}
+}
+
+var step4Suffixes = map[byte][]string{
+	'a': {"al"},
+	'c': {"ance", "ence"},
+	'e': {"er"},
+	'i': {"ic"},
+	'l': {"able", "ible"},
+	'n': {"ant", "ement", "ment", "ent"},
+	's': {"ism"},
+	't': {"ate", "iti"},
+	'u': {"ous"},
+	'v': {"ive"},
+	'z': {"ize"},
+}
+
+// step5 removes a final -e if m() > 1, and changes -ll to -l if m() > 1.
+func (s *stemmer) step5() {
+	s.j = s.k
+	if s.b[s.k] == 'e' {
+		a := s.m()
+		if a > 1 || (a == 1 && !s.cvc(s.k-1)) {
+			s.k--
+		}
+	}
+
+	if s.b[s.k] == 'l' && s.doubleC(s.k) && s.m() > 1 {
+		s.k--
+	}
+}
@@ -0,0 +1,81 @@
+package fulltext
+
+import (
+	"testing"
+)
+
+func TestStem(t *testing.T) {
+	tests := []struct {
+		word, want string
+	}{
+		// short and non ASCII words
+		{"a", "a"},
+		{"go", "go"},
+		{"café", "café"},
+		{"h2o", "h2o"},
+
+		// step 1
+		{"caresses", "caress"},
+		{"ponies", "poni"},
+		{"ties", "ti"},
+		{"caress", "caress"},
+		{"cats", "cat"},
+		{"feed", "feed"},
+		{"agreed", "agre"},
+		{"plastered", "plaster"},
+		{"bled", "bled"},
+		{"motoring", "motor"},
+		{"sing", "sing"},
+		{"conflated", "conflat"},
+		{"troubled", "troubl"},
+		{"sized", "size"},
+		{"hopping", "hop"},
+		{"tanned", "tan"},
+		{"falling", "fall"},
+		{"hissing", "hiss"},
+		{"fizzed", "fizz"},
+		{"failing", "fail"},
+		{"filing", "file"},
+		{"happy", "happi"},
+		{"sky", "sky"},
+
+		// steps 2 to 5
+		{"relational", "relat"},
+		{"conditional", "condit"},
+		{"rational", "ration"},
+		{"generalization", "gener"},
+		{"hopefulness", "hope"},
+		{"goodness", "good"},
+		{"electrical", "electr"},
+		{"adjustable", "adjust"},
+		{"adjustment", "adjust"},
+		{"dependent", "depend"},
+		{"adoption", "adopt"},
+		{"communism", "commun"},
+		{"effective", "effect"},
+		{"probate", "probat"},
+		{"rate", "rate"},
+		{"cease", "ceas"},
+		{"controlling", "control"},
+		{"roll", "roll"},
+
+		// same stems
+		{"connect", "connect"},
+		{"connected", "connect"},
+		{"connecting", "connect"},
+		{"connection", "connect"},
+		{"connections", "connect"},
+		{"database", "databas"},
+		{"databases", "databas"},
+		{"running", "run"},
+		{"runs", "run"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.word, func(t *testing.T) {
+			if got := Stem(test.word); got != test.want {
+				t.Errorf("Stem(%q) = %q, want %q", test.word, got, test.want)
+			}
+		})
+	}
+}
@@ -46,6 +46,26 @@ func ArrayContains(a types.Array, v types.Value) (bool, error) {
 	return found, nil
 }
 
+// ArrayContainsNull returns true if the array contains a NULL value.
+func ArrayContainsNull(a types.Array) (bool, error) {
+	var found bool
+
+	err := a.Iterate(func(i int, v types.Value) error {
+		if v.Type() == types.TypeNull {
+			found = true
+			return errStop
+		}
+
+		return nil
+	})
+
+	if err != nil && !errors.Is(err, errStop) {
+		return false, err
+	}
+
+	return found, nil
+}
+
 // ValueBuffer is an array that holds values in memory.
 type ValueBuffer struct {
 	Values []types.Value
@@ -0,0 +1,125 @@
+package planner
+
+import (
+	"github.com/chaisql/chai/internal/expr"
+	"github.com/chaisql/chai/internal/stream/index"
+	"github.com/chaisql/chai/internal/stream/rows"
+)
+
+// SelectCoveringIndex turns an index scan into a covering scan when the
+// following operators only use columns stored in the index, i.e. the indexed columns,
+// the included columns and the primary key, avoiding to fetch every row from the table.
+//
+// Given the following index:
+//
+//	CREATE INDEX foo_a_idx ON foo (a) INCLUDE (b)
+//
+// and this query:
+//
+//	SELECT a, b FROM foo WHERE a > 10
+//	index.Scan("foo_a_idx", [{"min": [10], "exclusive": true}]) | rows.Project(a, b)
+//
+// the scan becomes:
+//
+//	index.CoveringScan("foo_a_idx", [{"min": [10], "exclusive": true}]) | rows.Project(a, b)
+//
+// Only indexes with an INCLUDE clause, which are meant to answer queries on their own, are considered.
+// The following operators must be filters, projections without wildcard, sorts, aggregations,
+// LIMIT or OFFSET. Any other operator prevents the rule from applying.
+func SelectCoveringIndex(sctx *StreamContext) error {
+	scan, ok := sctx.Stream.First().(*index.ScanOperator)
+	if !ok {
+		return nil
+	}
+
+	info, err := sctx.Catalog.GetIndexInfo(scan.IndexName)
+	if err != nil {
+		return err
+	}
+	if len(info.Include) == 0 {
+		return nil
+	}
+
+	ti, err := sctx.Catalog.GetTableInfo(info.Owner.TableName)
+	if err != nil {
+		return err
+	}
+
+	stored := make(map[string]struct{})
+	for _, p := range info.Paths {
+		if len(p) == 1 {
+			stored[p[0].FieldName] = struct{}{}
+		}
+	}
+	for _, p := range info.Include {
+		stored[p[0].FieldName] = struct{}{}
+	}
+	if ti.PrimaryKey != nil {
+		for _, p := range ti.PrimaryKey.Paths {
+			if len(p) == 1 {
+				stored[p[0].FieldName] = struct{}{}
+			}
+		}
+	}
+
+	// without projection, the whole rows are returned
+	var projected bool
+
+	for n := scan.GetNext(); n != nil; n = n.GetNext() {
+		var exprs []expr.Expr
+
+		switch t := n.(type) {
+		case *rows.FilterOperator:
+			exprs = []expr.Expr{t.Expr}
+		case *rows.ProjectOperator:
+			for _, e := range t.Exprs {
+				if _, ok := e.(expr.Wildcard); ok {
+					return nil
+				}
+			}
+			exprs = t.Exprs
+			projected = true
+		case *rows.TempTreeSortOperator:
+			exprs = t.Exprs
+		case *rows.GroupAggregateOperator:
+			exprs = t.Exprs
+			for _, b := range t.Builders {
+				exprs = append(exprs, b)
+			}
+		case *rows.SkipOperator:
+			exprs = []expr.Expr{t.E}
+		case *rows.TakeOperator:
+			exprs = []expr.Expr{t.E}
+		default:
+			return nil
+		}
+
+		for _, e := range exprs {
+			if !exprUsesColumns(e, stored) {
+				return nil
+			}
+		}
+	}
+
+	scan.Covering = projected
+	return nil
+}
+
+// exprUsesColumns returns whether the evaluation of e only depends
+// on the given columns of the row.
+func exprUsesColumns(e expr.Expr, columns map[string]struct{}) bool {
+	ok := true
+
+	expr.Walk(e, func(e expr.Expr) bool {
+		switch t := e.(type) {
+		case expr.Path:
+			_, ok = columns[t[0].FieldName]
+		case correlatedExpr:
+			ok = !t.IsCorrelated()
+		}
+
+		return ok
+	})
+
+	return ok
+}
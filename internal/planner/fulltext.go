@@ -0,0 +1,89 @@
+package planner
+
+import (
+	"github.com/chaisql/chai/internal/expr"
+	"github.com/chaisql/chai/internal/object"
+	"github.com/chaisql/chai/internal/stream"
+	"github.com/chaisql/chai/internal/stream/index"
+	"github.com/chaisql/chai/internal/stream/join"
+	"github.com/chaisql/chai/internal/stream/table"
+)
+
+// SelectFullTextIndex replaces a sequential scan by a full-text index scan
+// if one of the filters is a MATCH operator on a column with a full-text index.
+// It only applies if no other index was selected by the SelectIndex rule.
+//
+// Given the following index:
+//
+//	CREATE FULLTEXT INDEX posts_body_idx ON posts (body)
+//
+// and this query:
+//
+//	SELECT id FROM posts WHERE body MATCH 'embedded database'
+//	table.Scan('posts') | rows.Filter(body MATCH "embedded database") | rows.Project(id)
+//
+// the stream becomes:
+//
+//	index.FullTextScan("posts_body_idx", "embedded database") | rows.Project(id)
+//
+// As the scan only returns the rows containing all the terms of the query, the filter is removed.
+func SelectFullTextIndex(sctx *StreamContext) error {
+	seq, ok := sctx.Stream.First().(*table.ScanOperator)
+	if !ok || seq.Table != nil || len(seq.Ranges) > 0 || seq.Reverse {
+		return nil
+	}
+
+	// the rows of a join can reference columns of any of the joined tables
+	for n := seq.GetNext(); n != nil; n = n.GetNext() {
+		switch n.(type) {
+		case *join.NestedLoopOperator, *join.IndexLookupOperator:
+			return nil
+		}
+	}
+
+	is := indexSelector{
+		tableScan: seq,
+		sctx:      sctx,
+	}
+
+	for _, f := range sctx.Filters {
+		op, ok := f.Expr.(*expr.MatchOperator)
+		if !ok {
+			continue
+		}
+
+		path, ok := op.LeftHand().(expr.Path)
+		if !ok || exprContainsPath(op.RightHand()) {
+			continue
+		}
+
+		for _, idxName := range sctx.Catalog.ListIndexes(seq.TableName) {
+			info, err := sctx.Catalog.GetIndexInfo(idxName)
+			if err != nil {
+				return err
+			}
+
+			if !info.FullText || !info.Paths[0].IsEqual(object.Path(path)) {
+				continue
+			}
+
+			// a partial index only contains the rows matching its predicate
+			if info.Predicate != nil && !is.filtersImplyPredicate(info.Predicate) {
+				continue
+			}
+
+			sctx.removeFilterNode(f)
+			s := sctx.Stream
+			s.Remove(seq)
+			if s.Op == nil {
+				s.Op = index.FullTextScan(info.IndexName, op.RightHand())
+			} else {
+				stream.InsertBefore(s.First(), index.FullTextScan(info.IndexName, op.RightHand()))
+			}
+
+			return nil
+		}
+	}
+
+	return nil
+}
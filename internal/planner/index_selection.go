@@ -1,14 +1,19 @@
 package planner
 
 import (
+	"math"
+
+	"github.com/chaisql/chai/internal/database"
 	"github.com/chaisql/chai/internal/expr"
 	"github.com/chaisql/chai/internal/object"
 	"github.com/chaisql/chai/internal/sql/scanner"
 	"github.com/chaisql/chai/internal/stream"
 	"github.com/chaisql/chai/internal/stream/index"
+	"github.com/chaisql/chai/internal/stream/join"
 	"github.com/chaisql/chai/internal/stream/rows"
 	"github.com/chaisql/chai/internal/stream/table"
 	"github.com/chaisql/chai/internal/tree"
+	"github.com/chaisql/chai/internal/types"
 )
 
 // SelectIndex attempts to replace a sequential scan by an index scan or a pk scan by
@@ -29,6 +34,9 @@ import (
 // compatible operator: one of =, >, >=, <, <=, IN
 // expression: any expression
 //
+// For indexes on expressions, i.e. CREATE INDEX foo_lower_a_idx ON foo (lower(a)),
+// <path> is replaced by the indexed expression, i.e. lower(a) = 'x'.
+//
 // Index compatibility.
 //
 // Once we have a list of all compatible filter nodes, we try to associate
@@ -72,11 +80,24 @@ import (
 // foo_a_b_c_idx only matches with the first two filter nodes because while the first node uses the equal
 // operator, the second one doesn't, and thus the third node cannot be selected as well.
 //
+// # Joins
+//
+// When the table is joined with other tables, only the filters on columns qualified
+// by the name of the table, or by its alias, are associated with its indexes:
+//
+//	SELECT * FROM foo JOIN bar ON foo.id = bar.foo_id WHERE foo.a = 5
+//
 // # Candidates and cost
 //
 // Because a table can have multiple indexes, we need to establish which of these
 // indexes should be used to run the query, if not all of them.
 // For that we generate a cost for each selected index and return the one with the cheapest cost.
+//
+// If the table has been analyzed, the cost is the estimated number of rows read,
+// computed from the statistics of the columns and indexes. Otherwise, it is
+// derived from the number of filters and the shape of the ranges.
+// With statistics, an index is not used if scanning the whole table is expected
+// to be cheaper.
 func SelectIndex(sctx *StreamContext) error {
 	// Lookup the seq scan node.
 	// We will assume that at this point
@@ -107,6 +128,19 @@ func SelectIndex(sctx *StreamContext) error {
 		sctx:      sctx,
 	}
 
+	// the rows of a join can reference columns of any of the joined tables,
+	// only the filters on columns qualified by the name of the first table
+	// can be associated with its indexes
+	for n := firstNode.GetNext(); n != nil && is.joinedAs == ""; n = n.GetNext() {
+		switch n.(type) {
+		case *join.NestedLoopOperator, *join.IndexLookupOperator:
+			is.joinedAs = seq.TableName
+			if alias, ok := firstNode.GetNext().(*join.AliasOperator); ok {
+				is.joinedAs = alias.Name
+			}
+		}
+	}
+
 	return is.selectIndex()
 }
 
@@ -116,6 +150,13 @@ func SelectIndex(sctx *StreamContext) error {
 type indexSelector struct {
 	tableScan *table.ScanOperator
 	sctx      *StreamContext
+
+	// name referencing the rows of the table, if they are joined
+	// with other tables
+	joinedAs string
+
+	// statistics of the table, if it has been analyzed
+	stats *database.TableStats
 }
 
 func (i *indexSelector) selectIndex() error {
@@ -140,7 +181,7 @@ func (i *indexSelector) selectIndex() error {
 	// In this case, we can only associate the first TempSort node
 	// with an index, as the second one will be used to sort the
 	// results downstream.
-	if len(i.sctx.TempTreeSorts) > 0 {
+	if len(i.sctx.TempTreeSorts) > 0 && i.joinedAs == "" {
 		node := i.isTempTreeSortIndexable(i.sctx.TempTreeSorts[0])
 		if node != nil {
 			nodes = append(nodes, node)
@@ -156,9 +197,11 @@ func (i *indexSelector) selectIndex() error {
 	if err != nil {
 		return err
 	}
+	i.stats = i.sctx.Catalog.GetTableStats(tb.TableName)
+
 	pk := tb.PrimaryKey
 	if pk != nil {
-		selected = i.associateIndexWithNodes(tb.TableName, false, false, pk.Paths, pk.SortOrder, nodes)
+		selected = i.associateIndexWithNodes(tb.TableName, false, false, pk.Paths, nil, pk.SortOrder, nodes)
 		if selected != nil {
 			cost = selected.Cost()
 		}
@@ -172,7 +215,17 @@ func (i *indexSelector) selectIndex() error {
 			return err
 		}
 
-		candidate := i.associateIndexWithNodes(idxInfo.IndexName, true, idxInfo.Unique, idxInfo.Paths, idxInfo.KeySortOrder, nodes)
+		// full-text indexes only contain terms, see SelectFullTextIndex
+		if idxInfo.FullText {
+			continue
+		}
+
+		// a partial index only contains the rows matching its predicate
+		if idxInfo.Predicate != nil && (i.joinedAs != "" || !i.filtersImplyPredicate(idxInfo.Predicate)) {
+			continue
+		}
+
+		candidate := i.associateIndexWithNodes(idxInfo.IndexName, true, idxInfo.Unique, idxInfo.Paths, idxInfo.Exprs, idxInfo.KeySortOrder, nodes)
 
 		if candidate == nil {
 			continue
@@ -184,6 +237,15 @@ func (i *indexSelector) selectIndex() error {
 			continue
 		}
 
+		// with statistics, the candidate reading the fewest rows wins
+		if i.stats != nil {
+			c, sc := candidate.estimatedCost(), selected.estimatedCost()
+			if c < sc || (c == sc && len(selected.nodes) < len(candidate.nodes)) {
+				selected = candidate
+			}
+			continue
+		}
+
 		c := candidate.Cost()
 
 		if len(selected.nodes) < len(candidate.nodes) || (len(selected.nodes) == len(candidate.nodes) && c < cost) {
@@ -196,6 +258,11 @@ func (i *indexSelector) selectIndex() error {
 		return nil
 	}
 
+	// reading most of the table through an index is slower than scanning it
+	if i.stats != nil && selected.isIndex && !selected.sorts() && selected.estimatedCost() >= float64(i.stats.RowCount) {
+		return nil
+	}
+
 	// remove the filter nodes from the tree
 	for _, f := range selected.nodes {
 		switch tp := f.node.(type) {
@@ -238,33 +305,104 @@ func (i *indexSelector) isFilterIndexable(f *rows.FilterOperator) *indexableNode
 
 	// determine if the operator could benefit from an index
 	ok, path, e := operatorCanUseIndex(op)
-	if !ok {
+	if ok {
+		if path = i.tablePath(path); path == nil {
+			return nil
+		}
+
+		return &indexableNode{
+			node:     f,
+			path:     path,
+			operator: op.Token(),
+			operand:  e,
+		}
+	}
+
+	// or from an index on the elements of an array
+	ok, path, e = operatorCanUseElementsIndex(op)
+	if ok {
+		if path = i.tablePath(path); path == nil {
+			return nil
+		}
+
+		return &indexableNode{
+			node:     f,
+			path:     path,
+			elements: true,
+			operator: scanner.EQ,
+			operand:  e,
+		}
+	}
+
+	// or from an index on an expression, whose paths are not qualified
+	ok, indexed, e := operatorCanUseExprIndex(op)
+	if !ok || i.joinedAs != "" {
 		return nil
 	}
 
-	node := indexableNode{
+	return &indexableNode{
 		node:     f,
-		path:     path,
+		expr:     indexed,
 		operator: op.Token(),
 		operand:  e,
 	}
+}
 
-	return &node
+// tablePath returns the path of the column of the table referenced by p.
+// If the table is joined, p must be qualified by the name of the table, otherwise nil is returned.
+func (i *indexSelector) tablePath(p object.Path) object.Path {
+	if i.joinedAs == "" {
+		return p
+	}
+
+	return qualifiedColumn(expr.Path(p), i.joinedAs)
 }
 
 func (i *indexSelector) isTempTreeSortIndexable(n *rows.TempTreeSortOperator) *indexableNode {
-	// only paths can be associated with an index
-	path, ok := n.Expr.(expr.Path)
-	if !ok {
+	if len(n.Exprs) == 0 {
 		return nil
 	}
 
+	// only paths can be associated with an index
+	paths := make([]object.Path, len(n.Exprs))
+	for i, e := range n.Exprs {
+		path, ok := e.(expr.Path)
+		if !ok {
+			return nil
+		}
+		paths[i] = object.Path(path)
+	}
+
 	return &indexableNode{
-		node:     n,
-		path:     object.Path(path),
-		desc:     n.Desc,
-		operator: scanner.ORDER,
+		node:      n,
+		path:      paths[0],
+		desc:      n.Order.IsDesc(0),
+		operator:  scanner.ORDER,
+		sortPaths: paths,
+		sortOrder: n.Order,
+	}
+}
+
+// sortMatchesIndex returns whether the paths of the sort node are indexed consecutively,
+// starting at the given position, with the same relative direction.
+// It also returns whether the index must be read in reverse order.
+func sortMatchesIndex(n *indexableNode, paths []object.Path, sortOrder tree.SortOrder, pos int) (desc bool, ok bool) {
+	if pos+len(n.sortPaths) > len(paths) {
+		return false, false
 	}
+
+	desc = n.sortOrder.IsDesc(0) != sortOrder.IsDesc(pos)
+	for j, sp := range n.sortPaths {
+		if !paths[pos+j].IsEqual(sp) {
+			return false, false
+		}
+
+		if (n.sortOrder.IsDesc(j) != sortOrder.IsDesc(pos+j)) != desc {
+			return false, false
+		}
+	}
+
+	return desc, true
 }
 
 // for a given index, select all filter nodes that match according to the following rules:
@@ -282,14 +420,22 @@ func (i *indexSelector) isTempTreeSortIndexable(n *rows.TempTreeSortOperator) *i
 //	 -> range = {min: [3], exact: true}
 //	rows.Filter(a IN (1, 2))
 //	 -> ranges = [1], [2]
-func (i *indexSelector) associateIndexWithNodes(treeName string, isIndex bool, isUnique bool, paths []object.Path, sortOrder tree.SortOrder, nodes indexableNodes) *candidate {
+//
+// If exprs[k] is set, the k-th indexed value is computed by the expression
+// and is associated with the filter nodes using the same expression.
+func (i *indexSelector) associateIndexWithNodes(treeName string, isIndex bool, isUnique bool, paths []object.Path, exprs []*database.IndexExpr, sortOrder tree.SortOrder, nodes indexableNodes) *candidate {
 	found := make([]*indexableNode, 0, len(paths))
 	var desc bool
 
 	var hasIn bool
 	var sorter *indexableNode
-	for _, p := range paths {
-		ns := nodes.getByPath(p)
+	for k, p := range paths {
+		var ns []*indexableNode
+		if k < len(exprs) && exprs[k] != nil {
+			ns = nodes.getByExpr(exprs[k])
+		} else {
+			ns = nodes.getByPath(p)
+		}
 		if len(ns) == 0 {
 			break
 		}
@@ -297,9 +443,15 @@ func (i *indexSelector) associateIndexWithNodes(treeName string, isIndex bool, i
 		// get the filter node and the TempSort node if any
 		var filter *indexableNode
 		for i, n := range ns {
-			if n.operator == scanner.ORDER && sorter == nil {
-				sorter = ns[i]
-				desc = sorter.desc
+			if n.operator == scanner.ORDER {
+				if sorter == nil {
+					// the index can only be used to sort if all the sorted paths
+					// are indexed, from this position
+					if d, ok := sortMatchesIndex(n, paths, sortOrder, k); ok {
+						sorter = ns[i]
+						desc = d
+					}
+				}
 				continue
 			}
 			if filter == nil {
@@ -350,10 +502,8 @@ func (i *indexSelector) associateIndexWithNodes(treeName string, isIndex bool, i
 			isIndex:    isIndex,
 			isUnique:   isUnique,
 		}
-
-		// in case the primary key or index is descending, we need to use a reverse the order
-		if sortOrder.IsDesc(0) {
-			desc = !desc
+		if i.stats != nil {
+			c.rows = i.estimateRows(treeName, isIndex, paths, nil)
 		}
 
 		if !isIndex {
@@ -403,12 +553,8 @@ func (i *indexSelector) associateIndexWithNodes(treeName string, isIndex bool, i
 		isIndex:    isIndex,
 		isUnique:   isUnique,
 	}
-
-	// in case the indexed path is descending, we need to reverse the order
-	if found[len(found)-1].orderBy != nil {
-		if sortOrder.IsDesc(len(found) - 1) {
-			desc = !desc
-		}
+	if i.stats != nil {
+		c.rows = i.estimateRows(treeName, isIndex, paths, ranges) * i.residualSelectivity(treeName, isIndex, paths, exprs, nodes, found)
 	}
 
 	if !isIndex {
@@ -588,6 +734,28 @@ type indexableNode struct {
 	operand  expr.Expr
 	desc     bool
 
+	// For filter nodes comparing an expression
+	// that isn't a path, i.e. lower(a) = 'foo',
+	// the expression. path is empty in that case.
+	expr expr.Expr
+
+	// For filter nodes looking for a value in an array,
+	// i.e. 'foo' IN tags, elements is true and the node
+	// is treated as an equality on the elements of the array:
+	// - path: tags
+	// - operator: scanner.EQ
+	// - operand: 'foo'
+	elements bool
+
+	// For TempTreeSort nodes, the list of
+	// sorted paths and their direction.
+	// Ex:  ORDER BY a.b[0] ASC, c DESC
+	// Gives:
+	// - sortPaths: [a.b[0], c]
+	// - sortOrder: c is descending
+	sortPaths []object.Path
+	sortOrder tree.SortOrder
+
 	// merged TempTreeSort node to remove
 	// from the stream
 	orderBy *indexableNode
@@ -598,10 +766,50 @@ type indexableNodes []*indexableNode
 // getByPath returns all indexable nodes for the given path.
 // TODO(asdine): add a rule that merges nodes that point to the
 // same path.
+// contains returns whether the node is part of the list.
+func (n indexableNodes) contains(node *indexableNode) bool {
+	for _, fn := range n {
+		if fn == node {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (n indexableNodes) getByPath(p object.Path) []*indexableNode {
 	var nodes []*indexableNode
 	for _, fn := range n {
-		if fn.path.IsEqual(p) {
+		if fn.expr == nil && !fn.elements && fn.path.IsEqual(p) {
+			nodes = append(nodes, fn)
+		}
+	}
+
+	return nodes
+}
+
+// getByExpr returns all indexable nodes comparing the given indexed expression,
+// or looking for a value in the array if the elements of the array are indexed.
+func (n indexableNodes) getByExpr(e *database.IndexExpr) []*indexableNode {
+	if e.Elements {
+		var nodes []*indexableNode
+		for _, fn := range n {
+			if fn.elements && fn.path.IsEqual(e.Paths[0]) {
+				nodes = append(nodes, fn)
+			}
+		}
+
+		return nodes
+	}
+
+	ce, ok := e.Expr.(*expr.ConstraintExpr)
+	if !ok {
+		return nil
+	}
+
+	var nodes []*indexableNode
+	for _, fn := range n {
+		if fn.expr != nil && expr.Equal(fn.expr, ce.Expr) {
 			nodes = append(nodes, fn)
 		}
 	}
@@ -620,6 +828,10 @@ type candidate struct {
 	// cost of the associated ranges
 	rangesCost int
 
+	// estimated number of rows or index entries read,
+	// if the table has been analyzed
+	rows float64
+
 	// is this candidate reading from an index.
 	// if false, we are reading from the table
 	// primary key.
@@ -644,6 +856,218 @@ func (c *candidate) Cost() int {
 	return cost
 }
 
+// estimatedCost returns the cost of the candidate based on the estimated
+// number of rows it reads. Reading from an index also requires fetching
+// each row from the table.
+func (c *candidate) estimatedCost() float64 {
+	if c.isIndex {
+		return c.rows * 2
+	}
+
+	return c.rows
+}
+
+// sorts returns whether the candidate replaces a TempTreeSort node.
+func (c *candidate) sorts() bool {
+	for _, n := range c.nodes {
+		if n.operator == scanner.ORDER || n.orderBy != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// selectivities used when the statistics of a column are unknown
+const (
+	defaultEqualSelectivity = 0.005
+	defaultRangeSelectivity = 1.0 / 3
+)
+
+// estimateRows returns the number of rows of the table, or of entries of the index,
+// read when scanning the given ranges, based on the statistics computed by ANALYZE.
+// Without ranges, the whole table or index is read.
+func (i *indexSelector) estimateRows(treeName string, isIndex bool, paths []object.Path, ranges stream.Ranges) float64 {
+	count := float64(i.stats.RowCount)
+	if cs, ok := i.stats.Indexes[treeName]; ok && isIndex {
+		count = float64(cs.Count)
+	}
+
+	if len(ranges) == 0 {
+		return count
+	}
+
+	var sel float64
+	for _, rng := range ranges {
+		sel += i.rangeSelectivity(treeName, isIndex, paths, &rng)
+	}
+
+	return math.Min(sel, 1) * count
+}
+
+// residualSelectivity returns the estimated fraction of the rows read from the ranges
+// which match the filters on the indexed values that are not part of the ranges,
+// i.e. b <= 3 when the ranges only contain b >= 1.
+// These rows are read and filtered afterwards, but only those matching the filters
+// are returned. Conditions are assumed to be independent.
+func (i *indexSelector) residualSelectivity(treeName string, isIndex bool, paths []object.Path, exprs []*database.IndexExpr, nodes, found indexableNodes) float64 {
+	sel := 1.0
+
+	for k, p := range paths {
+		var ns []*indexableNode
+		if k < len(exprs) && exprs[k] != nil {
+			ns = nodes.getByExpr(exprs[k])
+		} else {
+			ns = nodes.getByPath(p)
+		}
+
+		for _, n := range ns {
+			if n.elements || found.contains(n) {
+				continue
+			}
+
+			switch n.operator {
+			case scanner.EQ, scanner.GT, scanner.GTE, scanner.LT, scanner.LTE, scanner.BETWEEN:
+			default:
+				continue
+			}
+
+			rng := i.buildRangeFromOperator(n.operator, paths[k:k+1], n.operand)
+			sel *= i.rangeSelectivity(treeName, isIndex && k == 0, paths[k:k+1], &rng)
+		}
+	}
+
+	return sel
+}
+
+// rangeSelectivity returns the estimated fraction of the rows, or index entries,
+// selected by the range. Values of a composite range are assumed to be independent.
+func (i *indexSelector) rangeSelectivity(treeName string, isIndex bool, paths []object.Path, rng *stream.Range) float64 {
+	n := len(rng.Min)
+	if len(rng.Max) > n {
+		n = len(rng.Max)
+	}
+
+	sel := 1.0
+	for k := 0; k < n; k++ {
+		cs := i.columnStats(treeName, isIndex, paths, k)
+
+		// all the values but the last one are compared with =
+		if k < n-1 || rng.Exact {
+			var e expr.Expr
+			if k < len(rng.Min) {
+				e = rng.Min[k]
+			} else {
+				e = rng.Max[k]
+			}
+
+			if cs == nil {
+				sel *= defaultEqualSelectivity
+			} else {
+				sel *= cs.EqualSelectivity(literalValue(e))
+			}
+			continue
+		}
+
+		var min, max types.Value
+		known := cs != nil
+		if len(rng.Min) == n {
+			min = literalValue(rng.Min[k])
+			known = known && min != nil
+		}
+		if len(rng.Max) == n {
+			max = literalValue(rng.Max[k])
+			known = known && max != nil
+		}
+
+		if !known {
+			sel *= defaultRangeSelectivity
+		} else {
+			sel *= cs.RangeSelectivity(min, max, rng.Exclusive)
+		}
+	}
+
+	return sel
+}
+
+// columnStats returns the statistics of the k-th value of the keys of a table or an index.
+// The first value of an index is described by the statistics of the index itself,
+// the others by the statistics of the indexed columns.
+func (i *indexSelector) columnStats(treeName string, isIndex bool, paths []object.Path, k int) *database.ColumnStats {
+	if k == 0 && isIndex {
+		if cs, ok := i.stats.Indexes[treeName]; ok {
+			return cs
+		}
+	}
+
+	if k < len(paths) && len(paths[k]) == 1 {
+		return i.stats.Columns[paths[k][0].FieldName]
+	}
+
+	return nil
+}
+
+// literalValue returns the value of e if it is a literal, nil otherwise.
+func literalValue(e expr.Expr) types.Value {
+	if lv, ok := e.(expr.LiteralValue); ok {
+		return lv.Value
+	}
+
+	return nil
+}
+
+// filtersImplyPredicate returns whether the filters of the stream only select rows
+// matching the predicate of a partial index.
+// Every condition of the predicate must either be one of the filters or,
+// for conditions like "a IS NOT NULL", be implied by a filter comparing a,
+// as comparisons with NULL are never true.
+func (i *indexSelector) filtersImplyPredicate(p *database.IndexExpr) bool {
+	ce, ok := p.Expr.(*expr.ConstraintExpr)
+	if !ok {
+		return false
+	}
+
+	for _, cond := range splitANDExpr(ce.Expr) {
+		if !i.filtersImplyCondition(cond) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (i *indexSelector) filtersImplyCondition(cond expr.Expr) bool {
+	var notNull expr.Path
+	if op, ok := cond.(*expr.IsNotOperator); ok {
+		p, isPath := op.LeftHand().(expr.Path)
+		if lit, isLit := op.RightHand().(expr.LiteralValue); isPath && isLit && lit.Value.Type() == types.TypeNull {
+			notNull = p
+		}
+	}
+
+	for _, f := range i.sctx.Filters {
+		if expr.Equal(f.Expr, cond) {
+			return true
+		}
+
+		if notNull == nil {
+			continue
+		}
+
+		op, ok := f.Expr.(expr.Operator)
+		if !ok || !operatorIsIndexCompatible(op) {
+			continue
+		}
+
+		ok, path, _ := operatorCanUseIndex(op)
+		if ok && path.IsEqual(object.Path(notNull)) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // operatorIsIndexCompatible returns whether the operator can be used to read from an index.
 func operatorIsIndexCompatible(op expr.Operator) bool {
 	switch op.Token() {
@@ -700,15 +1124,88 @@ func operatorCanUseIndex(op expr.Operator) (bool, object.Path, expr.Expr) {
 	return false, nil, nil
 }
 
+// operatorCanUseExprIndex is like operatorCanUseIndex for indexes on expressions:
+// one of the operands must depend on the row without being a path and
+// the other must not depend on the row.
+// As the indexed expression is expected to be on the left of the operator,
+// only = accepts it on the right.
+func operatorCanUseExprIndex(op expr.Operator) (bool, expr.Expr, expr.Expr) {
+	if op.Token() == scanner.BETWEEN {
+		bt := op.(*expr.BetweenOperator)
+		if !exprContainsPath(bt.X) || exprContainsPath(bt.LeftHand()) || exprContainsPath(bt.RightHand()) {
+			return false, nil, nil
+		}
+
+		return true, unwrapParentheses(bt.X), expr.LiteralExprList{bt.LeftHand(), bt.RightHand()}
+	}
+
+	lh, rh := op.LeftHand(), op.RightHand()
+	if op.Token() == scanner.EQ && !exprContainsPath(lh) {
+		lh, rh = rh, lh
+	}
+
+	if !exprContainsPath(lh) || exprContainsPath(rh) {
+		return false, nil, nil
+	}
+
+	// the IN operator can use indexes only if the right hand side is an expression list
+	if op.Token() == scanner.IN {
+		if _, ok := rh.(expr.LiteralExprList); !ok {
+			return false, nil, nil
+		}
+	}
+
+	return true, unwrapParentheses(lh), rh
+}
+
+// operatorCanUseElementsIndex returns whether the operator looks for a value
+// in an array, which can be read from an index on the elements of the array.
+// valid:   1 IN a
+// invalid: a IN b
+// invalid: NULL IN a
+func operatorCanUseElementsIndex(op expr.Operator) (bool, object.Path, expr.Expr) {
+	if op.Token() != scanner.IN {
+		return false, nil, nil
+	}
+
+	rf, ok := op.RightHand().(expr.Path)
+	if !ok || exprContainsPath(op.LeftHand()) {
+		return false, nil, nil
+	}
+
+	// NULL IN a is never true
+	if lit, ok := op.LeftHand().(expr.LiteralValue); ok && lit.Value.Type() == types.TypeNull {
+		return false, nil, nil
+	}
+
+	// a list of values is compared with the whole array
+	if _, ok := op.LeftHand().(expr.LiteralExprList); ok {
+		return false, nil, nil
+	}
+
+	return true, object.Path(rf), op.LeftHand()
+}
+
+// A correlatedExpr is an expression that depends on the row
+// being evaluated without containing any path, such as a subquery
+// referencing the tables of the enclosing statement.
+type correlatedExpr interface {
+	IsCorrelated() bool
+}
+
+// exprContainsPath returns true if the evaluation of e depends on the current row.
 func exprContainsPath(e expr.Expr) bool {
 	var hasPath bool
 
 	expr.Walk(e, func(e expr.Expr) bool {
-		if _, ok := e.(expr.Path); ok {
+		switch t := e.(type) {
+		case expr.Path:
 			hasPath = true
-			return false
+		case correlatedExpr:
+			hasPath = t.IsCorrelated()
 		}
-		return true
+
+		return !hasPath
 	})
 
 	return hasPath
@@ -0,0 +1,135 @@
+package planner
+
+import (
+	"github.com/chaisql/chai/internal/database"
+	"github.com/chaisql/chai/internal/expr"
+	"github.com/chaisql/chai/internal/sql/scanner"
+	"github.com/chaisql/chai/internal/stream"
+	"github.com/chaisql/chai/internal/stream/join"
+	"github.com/chaisql/chai/internal/stream/rows"
+	"github.com/chaisql/chai/internal/stream/table"
+)
+
+// SelectIndexUnion replaces a sequential scan by a union of index scans
+// if one of the filters is a disjunction of conditions that can all be
+// answered using an index or the primary key.
+// It only applies if no index was selected by the previous rules.
+//
+// Given the following indexes:
+//
+//	CREATE INDEX foo_a_idx ON foo (a)
+//	CREATE INDEX foo_b_idx ON foo (b)
+//
+// and this query:
+//
+//	SELECT * FROM foo WHERE a = 1 OR b > 2
+//	table.Scan('foo') | rows.Filter(a = 1 OR b > 2) | rows.Project(*)
+//
+// the stream becomes:
+//
+//	unionByKey(index.Scan("foo_a_idx", [{"min": [1], "exact": true}]), index.Scan("foo_b_idx", [{"min": [2], "exclusive": true}])) | rows.Project(*)
+//
+// Each condition is optimized as if it was the only filter of the query,
+// the filters that can't use the selected index are kept in its stream.
+// The union deduplicates the rows matching several conditions using their primary key,
+// which means that rows are only read once, in primary key order.
+func SelectIndexUnion(sctx *StreamContext) error {
+	seq, ok := sctx.Stream.First().(*table.ScanOperator)
+	if !ok || seq.Table != nil || len(seq.Ranges) > 0 || seq.Reverse {
+		return nil
+	}
+
+	// the rows of a join can reference columns of any of the joined tables
+	for n := seq.GetNext(); n != nil; n = n.GetNext() {
+		switch n.(type) {
+		case *join.NestedLoopOperator, *join.IndexLookupOperator:
+			return nil
+		}
+	}
+
+	for _, f := range sctx.Filters {
+		op, ok := unwrapParentheses(f.Expr).(expr.Operator)
+		if !ok || op.Token() != scanner.OR {
+			continue
+		}
+
+		streams, err := selectDisjunctionIndexes(sctx.Catalog, seq.TableName, splitORExpr(op))
+		if err != nil {
+			return err
+		}
+		if streams == nil {
+			continue
+		}
+
+		sctx.removeFilterNode(f)
+		s := sctx.Stream
+		s.Remove(seq)
+		if s.Op == nil {
+			s.Op = stream.UnionByKey(streams...)
+		} else {
+			stream.InsertBefore(s.First(), stream.UnionByKey(streams...))
+		}
+
+		return nil
+	}
+
+	return nil
+}
+
+// selectDisjunctionIndexes returns a stream reading the rows matching each condition
+// using an index or the primary key. If any of the conditions can't use one, it returns nil.
+func selectDisjunctionIndexes(catalog *database.Catalog, tableName string, conds []expr.Expr) ([]*stream.Stream, error) {
+	streams := make([]*stream.Stream, 0, len(conds))
+
+	for _, cond := range conds {
+		sctx := NewStreamContext(stream.New(table.Scan(tableName)).Pipe(rows.Filter(cond)))
+		sctx.Catalog = catalog
+
+		for _, rule := range []func(sctx *StreamContext) error{
+			SplitANDConditionRule,
+			SelectIndex,
+			SelectFullTextIndex,
+			SelectIndexUnion,
+		} {
+			err := rule(sctx)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if seq, ok := sctx.Stream.First().(*table.ScanOperator); ok && len(seq.Ranges) == 0 {
+			return nil, nil
+		}
+
+		streams = append(streams, sctx.Stream)
+	}
+
+	return streams, nil
+}
+
+// splitORExpr takes an expression and splits it by OR operator.
+// Parentheses around the operands are removed.
+func splitORExpr(cond expr.Expr) (exprs []expr.Expr) {
+	cond = unwrapParentheses(cond)
+	op, ok := cond.(expr.Operator)
+	if ok && op.Token() == scanner.OR {
+		exprs = append(exprs, splitORExpr(op.LeftHand())...)
+		exprs = append(exprs, splitORExpr(op.RightHand())...)
+		return
+	}
+
+	exprs = append(exprs, cond)
+
+	return
+}
+
+// unwrapParentheses returns the expression enclosed in parentheses, if any.
+func unwrapParentheses(e expr.Expr) expr.Expr {
+	for {
+		p, ok := e.(expr.Parentheses)
+		if !ok {
+			return e
+		}
+		e = p.E
+	}
+}
@@ -0,0 +1,208 @@
+package planner
+
+import (
+	"github.com/chaisql/chai/internal/database"
+	"github.com/chaisql/chai/internal/expr"
+	"github.com/chaisql/chai/internal/object"
+	"github.com/chaisql/chai/internal/sql/scanner"
+	"github.com/chaisql/chai/internal/stream"
+	"github.com/chaisql/chai/internal/stream/join"
+	"github.com/chaisql/chai/internal/stream/table"
+)
+
+// SelectJoinIndex replaces nested loop joins by index lookups when
+// the join condition compares indexed columns of the joined table
+// with expressions that only depend on the incoming rows.
+//
+// Given the following index:
+//
+//	CREATE INDEX bar_foo_id_idx ON bar (foo_id)
+//
+// and this query:
+//
+//	SELECT * FROM foo JOIN bar ON foo.id = bar.foo_id
+//	table.Scan("foo") | join.NestedLoop(table.Scan("bar"), foo.id = bar.foo_id)
+//
+// the join becomes:
+//
+//	table.Scan("foo") | join.IndexLookup(index.Scan("bar_foo_id_idx", [{"min": [foo.id], "exact": true}]), foo.id = bar.foo_id)
+//
+// Only equality conditions on qualified columns are considered.
+// For composite indexes, conditions must match the indexed paths consecutively,
+// from left to right. The primary key or index matching the highest number of paths is selected.
+func SelectJoinIndex(sctx *StreamContext) error {
+	for n := sctx.Stream.First(); n != nil; n = n.GetNext() {
+		nl, ok := n.(*join.NestedLoopOperator)
+		if !ok {
+			continue
+		}
+
+		lookup, err := selectJoinIndex(sctx, nl)
+		if err != nil {
+			return err
+		}
+		if lookup == nil {
+			continue
+		}
+
+		stream.InsertBefore(nl, lookup)
+		sctx.Stream.Remove(nl)
+		n = lookup
+	}
+
+	return nil
+}
+
+func selectJoinIndex(sctx *StreamContext, nl *join.NestedLoopOperator) (*join.IndexLookupOperator, error) {
+	if nl.On == nil || nl.Right == nil {
+		return nil, nil
+	}
+
+	// the right side must be a full table scan
+	scan, ok := nl.Right.Op.(*table.ScanOperator)
+	if !ok || scan.GetPrev() != nil || len(scan.Ranges) > 0 {
+		return nil, nil
+	}
+
+	name := nl.Alias
+	if name == "" {
+		name = scan.TableName
+	}
+
+	tb, err := sctx.Catalog.GetTableInfo(scan.TableName)
+	if err != nil {
+		return nil, err
+	}
+
+	// collect every "name.column = expr" condition
+	var paths []object.Path
+	var operands []expr.Expr
+	for _, e := range splitANDExpr(nl.On) {
+		p, operand := joinEqualityOperand(e, name, &tb.FieldConstraints)
+		if p != nil {
+			paths = append(paths, p)
+			operands = append(operands, operand)
+		}
+	}
+	if len(paths) == 0 {
+		return nil, nil
+	}
+
+	// returns the list of operands matching the indexed paths, from left to right
+	match := func(indexed []object.Path) expr.LiteralExprList {
+		var values expr.LiteralExprList
+		for _, ip := range indexed {
+			found := false
+			for i, p := range paths {
+				if p.IsEqual(ip) {
+					values = append(values, operands[i])
+					found = true
+					break
+				}
+			}
+
+			if !found {
+				break
+			}
+		}
+
+		return values
+	}
+
+	var indexName string
+	var values expr.LiteralExprList
+	var unique bool
+
+	// start with the primary key of the table
+	if tb.PrimaryKey != nil {
+		values = match(tb.PrimaryKey.Paths)
+		unique = true
+	}
+
+	for _, idxName := range sctx.Catalog.ListIndexes(scan.TableName) {
+		info, err := sctx.Catalog.GetIndexInfo(idxName)
+		if err != nil {
+			return nil, err
+		}
+
+		// partial indexes don't cover all the rows of the table
+		// and full-text indexes only contain terms
+		if info.Predicate != nil || info.FullText {
+			continue
+		}
+
+		v := match(info.Paths)
+		// prefer unique indexes when they match the same number of paths
+		if len(v) > len(values) || (len(v) > 0 && len(v) == len(values) && info.Unique && !unique) {
+			indexName = info.IndexName
+			values = v
+			unique = info.Unique
+		}
+	}
+
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	if nl.Outer {
+		return join.LeftIndexLookup(scan.TableName, indexName, values, nl.Alias, nl.On), nil
+	}
+
+	return join.IndexLookup(scan.TableName, indexName, values, nl.Alias, nl.On), nil
+}
+
+// joinEqualityOperand returns the column and the operand of an
+// equality condition of the form "name.column = expr" or "expr = name.column",
+// where expr only references qualified columns of other tables.
+func joinEqualityOperand(e expr.Expr, name string, fcs *database.FieldConstraints) (object.Path, expr.Expr) {
+	op, ok := e.(expr.Operator)
+	if !ok || op.Token() != scanner.EQ {
+		return nil, nil
+	}
+
+	if p := qualifiedColumn(op.LeftHand(), name); p != nil && dependsOnlyOnOtherTables(op.RightHand(), name, fcs) {
+		return p, op.RightHand()
+	}
+
+	if p := qualifiedColumn(op.RightHand(), name); p != nil && dependsOnlyOnOtherTables(op.LeftHand(), name, fcs) {
+		return p, op.LeftHand()
+	}
+
+	return nil, nil
+}
+
+// qualifiedColumn returns the path of the column if e is a path qualified by name.
+func qualifiedColumn(e expr.Expr, name string) object.Path {
+	p, ok := e.(expr.Path)
+	if !ok || len(p) < 2 || p[0].FieldName != name {
+		return nil
+	}
+
+	return object.Path(p[1:])
+}
+
+// dependsOnlyOnOtherTables returns true if every path of e is qualified
+// by the name of a table other than name. Qualifiers matching a column of the joined
+// table are rejected as they would reference the joined row.
+func dependsOnlyOnOtherTables(e expr.Expr, name string, fcs *database.FieldConstraints) bool {
+	ok := true
+
+	expr.Walk(e, func(e expr.Expr) bool {
+		switch t := e.(type) {
+		case expr.Path:
+			if len(t) < 2 || t[0].FieldName == "" || t[0].FieldName == name {
+				ok = false
+			} else if _, isColumn := fcs.ByField[t[0].FieldName]; isColumn {
+				ok = false
+			}
+		case expr.Wildcard, expr.NextValueFor:
+			ok = false
+		case correlatedExpr:
+			ok = !t.IsCorrelated()
+		}
+
+		return ok
+	})
+
+	return ok
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/chaisql/chai/internal/object"
 	"github.com/chaisql/chai/internal/sql/scanner"
 	"github.com/chaisql/chai/internal/stream"
+	"github.com/chaisql/chai/internal/stream/join"
 	"github.com/chaisql/chai/internal/stream/path"
 	"github.com/chaisql/chai/internal/stream/rows"
 	"github.com/chaisql/chai/internal/types"
@@ -18,7 +19,13 @@ var optimizerRules = []func(sctx *StreamContext) error{
 	RemoveUnnecessaryProjection,
 	RemoveUnnecessaryFilterNodesRule,
 	RemoveUnnecessaryTempSortNodesRule,
+	PushFiltersIntoViews,
 	SelectIndex,
+	SelectFullTextIndex,
+	SelectIndexUnion,
+	SelectCoveringIndex,
+	SelectJoinIndex,
+	InlineViews,
 }
 
 // Optimize takes a tree, applies a list of optimization rules
@@ -26,33 +33,30 @@ var optimizerRules = []func(sctx *StreamContext) error{
 // Depending on the rule, the tree may be modified in place or
 // replaced by a new one.
 func Optimize(s *stream.Stream, catalog *database.Catalog) (*stream.Stream, error) {
-	if firstNode, ok := s.First().(*stream.ConcatOperator); ok {
-		// If the first operation is a concat, optimize all streams individually.
-		for i, st := range firstNode.Streams {
-			ss, err := Optimize(st, catalog)
-			if err != nil {
-				return nil, err
-			}
-			firstNode.Streams[i] = ss
-		}
-
-		return s, nil
+	// If the first operation combines multiple streams, optimize all streams individually.
+	var streams []*stream.Stream
+	switch firstNode := s.First().(type) {
+	case *stream.ConcatOperator:
+		streams = firstNode.Streams
+	case *stream.UnionOperator:
+		streams = firstNode.Streams
+	case *stream.IntersectOperator:
+		streams = firstNode.Streams
+	case *stream.ExceptOperator:
+		streams = firstNode.Streams
+	default:
+		return optimize(s, catalog)
 	}
 
-	if firstNode, ok := s.First().(*stream.UnionOperator); ok {
-		// If the first operation is a union, optimize all streams individually.
-		for i, st := range firstNode.Streams {
-			ss, err := Optimize(st, catalog)
-			if err != nil {
-				return nil, err
-			}
-			firstNode.Streams[i] = ss
+	for i, st := range streams {
+		ss, err := Optimize(st, catalog)
+		if err != nil {
+			return nil, err
 		}
-
-		return s, nil
+		streams[i] = ss
 	}
 
-	return optimize(s, catalog)
+	return s, nil
 }
 
 type StreamContext struct {
@@ -71,19 +75,33 @@ func NewStreamContext(s *stream.Stream) *StreamContext {
 	n := s.First()
 
 	prevIsFilter := false
+	// filters following an aggregation apply to groups (i.e. HAVING)
+	// and must not be optimized as if they were applied to rows
+	grouped := false
+	// window functions depend on the order of the rows they receive,
+	// the sorts following them must be kept as is
+	windowed := false
 
 	for n != nil {
 		switch t := n.(type) {
 		case *rows.FilterOperator:
-			if prevIsFilter || len(sctx.Filters) == 0 {
+			if !grouped && (prevIsFilter || len(sctx.Filters) == 0) {
 				sctx.Filters = append(sctx.Filters, t)
 				prevIsFilter = true
 			}
+		case *rows.GroupAggregateOperator:
+			grouped = true
+			prevIsFilter = false
 		case *rows.ProjectOperator:
 			sctx.Projections = append(sctx.Projections, t)
 			prevIsFilter = false
+		case *rows.WindowOperator:
+			windowed = true
+			prevIsFilter = false
 		case *rows.TempTreeSortOperator:
-			sctx.TempTreeSorts = append(sctx.TempTreeSorts, t)
+			if !windowed {
+				sctx.TempTreeSorts = append(sctx.TempTreeSorts, t)
+			}
 			prevIsFilter = false
 		}
 
@@ -230,7 +248,16 @@ func PrecalculateExprRule(sctx *StreamContext) error {
 				}
 			}
 		case *rows.TempTreeSortOperator:
-			t.Expr, err = precalculateExpr(t.Expr)
+			for i := range t.Exprs {
+				t.Exprs[i], err = precalculateExpr(t.Exprs[i])
+				if err != nil {
+					return err
+				}
+			}
+		case *join.NestedLoopOperator:
+			if t.On != nil {
+				t.On, err = precalculateExpr(t.On)
+			}
 		case *path.SetOperator:
 			t.Expr, err = precalculateExpr(t.Expr)
 		case *rows.EmitOperator:
@@ -312,6 +339,55 @@ func precalculateExpr(e expr.Expr) (expr.Expr, error) {
 
 			return expr.LiteralValue{Value: types.NewObjectValue(&fb)}, nil
 		}
+	case *expr.Case:
+		// we assume that the CASE expression contains only literals
+		// until proven wrong.
+		literalsOnly := true
+		precalculate := func(e expr.Expr) (expr.Expr, error) {
+			if e == nil {
+				return nil, nil
+			}
+
+			e, err := precalculateExpr(e)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := e.(expr.LiteralValue); !ok {
+				literalsOnly = false
+			}
+			return e, nil
+		}
+
+		var err error
+		t.Operand, err = precalculate(t.Operand)
+		if err != nil {
+			return nil, err
+		}
+		for i := range t.Whens {
+			t.Whens[i].Cond, err = precalculate(t.Whens[i].Cond)
+			if err != nil {
+				return nil, err
+			}
+			t.Whens[i].Then, err = precalculate(t.Whens[i].Then)
+			if err != nil {
+				return nil, err
+			}
+		}
+		t.Else, err = precalculate(t.Else)
+		if err != nil {
+			return nil, err
+		}
+
+		// if every branch is constant, the CASE expression
+		// can be replaced with its result
+		if literalsOnly {
+			v, err := t.Eval(&environment.Environment{})
+			if err != nil {
+				return nil, err
+			}
+
+			return expr.LiteralValue{Value: v}, nil
+		}
 	case expr.Operator:
 		// since expr.Operator is an interface,
 		// this optimization must only be applied to
@@ -428,7 +504,7 @@ func RemoveUnnecessaryProjection(sctx *StreamContext) error {
 //	SELECT * FROM foo GROUP BY a ORDER BY a
 //	table.Scan('foo') | docs.TempSort(a) | docs.GroupBy(a) | docs.TempSort(a)
 //
-// This only works if both temp sort nodes use the same path
+// This only works if both temp sort nodes use the same paths, in the same order.
 func RemoveUnnecessaryTempSortNodesRule(sctx *StreamContext) error {
 	if len(sctx.TempTreeSorts) > 2 {
 		panic("unexpected number of TempSort nodes")
@@ -438,24 +514,31 @@ func RemoveUnnecessaryTempSortNodesRule(sctx *StreamContext) error {
 		return nil
 	}
 
-	lpath, ok := sctx.TempTreeSorts[0].Expr.(expr.Path)
-	if !ok {
+	left, right := sctx.TempTreeSorts[0], sctx.TempTreeSorts[1]
+	if len(left.Exprs) != len(right.Exprs) {
 		return nil
 	}
 
-	rpath, ok := sctx.TempTreeSorts[1].Expr.(expr.Path)
-	if !ok {
-		return nil
-	}
+	for i := range left.Exprs {
+		lpath, ok := left.Exprs[i].(expr.Path)
+		if !ok {
+			return nil
+		}
 
-	if !lpath.IsEqual(rpath) {
-		return nil
+		rpath, ok := right.Exprs[i].(expr.Path)
+		if !ok {
+			return nil
+		}
+
+		if !lpath.IsEqual(rpath) {
+			return nil
+		}
 	}
 
 	// we remove the rightmost one
 	// and we override the direction of the first one
-	sctx.TempTreeSorts[0].Desc = sctx.TempTreeSorts[1].Desc
-	sctx.removeTempTreeNodeNode(sctx.TempTreeSorts[1])
+	left.Order = right.Order
+	sctx.removeTempTreeNodeNode(right)
 
 	return nil
 }
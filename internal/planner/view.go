@@ -0,0 +1,241 @@
+package planner
+
+import (
+	"github.com/chaisql/chai/internal/expr"
+	"github.com/chaisql/chai/internal/object"
+	"github.com/chaisql/chai/internal/stream"
+	"github.com/chaisql/chai/internal/stream/index"
+	"github.com/chaisql/chai/internal/stream/join"
+	"github.com/chaisql/chai/internal/stream/rows"
+	"github.com/chaisql/chai/internal/stream/table"
+)
+
+// InlineViews replaces the views read by the stream with the operators of their query,
+// which are optimized when the view is prepared.
+// Example:
+//
+//	this:
+//	  rows.View("v") | join.Alias("v") | rows.Filter(v.a > 2)
+//	becomes this:
+//	  table.Scan("foo") | rows.Filter(b = 1) | rows.Project(a) | join.Alias("v") | rows.Filter(v.a > 2)
+//
+// The views joined to the stream are replaced as well.
+// This rule must run last: the other rules consider views as sources
+// of rows and must not optimize the operators of the stream
+// using the operators of the views, or the other way around.
+func InlineViews(sctx *StreamContext) error {
+	if v, ok := sctx.Stream.First().(*rows.ViewOperator); ok && v.Stream.Op != nil {
+		next := v.GetNext()
+		if next == nil {
+			sctx.Stream.Op = v.Stream.Op
+		} else {
+			v.SetNext(nil)
+			next.SetPrev(v.Stream.Op)
+			v.Stream.Op.SetNext(next)
+		}
+	}
+
+	for n := sctx.Stream.First(); n != nil; n = n.GetNext() {
+		nl, ok := n.(*join.NestedLoopOperator)
+		if !ok {
+			continue
+		}
+
+		if v, ok := nl.Right.Op.(*rows.ViewOperator); ok && v.GetPrev() == nil && v.Stream.Op != nil {
+			nl.Right = v.Stream
+		}
+	}
+
+	return nil
+}
+
+// PushFiltersIntoViews moves the filters applied to the rows of a view
+// into the query of the view, so that they can be used to select an index
+// of the table read by the view.
+// Only the views projecting the columns of a single table, without
+// aggregating, sorting or limiting them, are supported.
+// Example:
+//
+//	with v: SELECT id, b AS x FROM foo
+//	this:
+//	  rows.View("v") | join.Alias("v") | rows.Filter(v.x = 1)
+//	becomes this:
+//	  rows.View("v", table.Scan("foo") | rows.Filter(b = 1) | rows.Project(id, b AS x)) | join.Alias("v")
+//
+// The indexes of the table are then selected again.
+func PushFiltersIntoViews(sctx *StreamContext) error {
+	if len(sctx.Filters) == 0 {
+		return nil
+	}
+
+	v, ok := sctx.Stream.First().(*rows.ViewOperator)
+	if !ok || v.Stream.Op == nil {
+		return nil
+	}
+
+	alias, ok := v.GetNext().(*join.AliasOperator)
+	if !ok || sctx.Filters[0].GetPrev() != alias {
+		return nil
+	}
+
+	cols := viewColumns{alias: alias.Name, paths: make(map[string]object.Path)}
+
+	// the projection, if any, must follow the scan of a table,
+	// with only filters in between
+	last := v.Stream.Op
+	proj, ok := last.(*rows.ProjectOperator)
+	if ok {
+		last = proj.GetPrev()
+	} else {
+		cols.wildcard = true
+	}
+	for op := last; ; op = op.GetPrev() {
+		if op == nil {
+			return nil
+		}
+		if _, ok := op.(*rows.FilterOperator); ok {
+			continue
+		}
+		switch op.(type) {
+		case *table.ScanOperator, *index.ScanOperator:
+			if op.GetPrev() != nil {
+				return nil
+			}
+		default:
+			return nil
+		}
+		break
+	}
+
+	if proj != nil {
+		for _, e := range proj.Exprs {
+			switch t := e.(type) {
+			case expr.Wildcard:
+				cols.wildcard = true
+			case *expr.NamedExpr:
+				if p, ok := t.Expr.(expr.Path); ok {
+					cols.paths[t.ExprName] = object.Path(p)
+				} else {
+					// the column can't be referenced by the filters
+					cols.paths[t.ExprName] = nil
+				}
+			default:
+				return nil
+			}
+		}
+	}
+
+	var pushed bool
+	for _, f := range append([]*rows.FilterOperator(nil), sctx.Filters...) {
+		if !cols.canRewrite(f.Expr) {
+			continue
+		}
+
+		sctx.removeFilterNode(f)
+		if proj != nil {
+			stream.InsertBefore(proj, rows.Filter(cols.rewrite(f.Expr)))
+		} else {
+			v.Stream = v.Stream.Pipe(rows.Filter(cols.rewrite(f.Expr)))
+		}
+		pushed = true
+	}
+
+	if !pushed {
+		return nil
+	}
+
+	// select an index using the pushed filters
+	vctx := NewStreamContext(v.Stream)
+	vctx.Catalog = sctx.Catalog
+	for _, rule := range []func(*StreamContext) error{SelectIndex, SelectFullTextIndex, SelectIndexUnion, SelectCoveringIndex} {
+		err := rule(vctx)
+		if err != nil {
+			return err
+		}
+	}
+	v.Stream = vctx.Stream
+
+	return nil
+}
+
+// viewColumns maps the columns of a view to the columns
+// of the table it reads.
+type viewColumns struct {
+	// name of the rows of the view
+	alias string
+	// columns by name, nil if the column is not a column of the table
+	paths map[string]object.Path
+	// wildcard is true if the columns of the table are returned as is
+	wildcard bool
+}
+
+// column returns the path of the column of the table referenced by p.
+func (c *viewColumns) column(p expr.Path) (expr.Path, bool) {
+	if q := qualifiedColumn(p, c.alias); q != nil {
+		p = expr.Path(q)
+	} else if len(p) == 1 && p[0].FieldName == c.alias {
+		// the whole row of the view
+		return nil, false
+	}
+	if p[0].FieldName == "" {
+		return nil, false
+	}
+
+	col, ok := c.paths[p[0].FieldName]
+	if !ok && c.wildcard {
+		col, ok = object.Path(p[:1]), true
+	}
+	if !ok || col == nil {
+		return nil, false
+	}
+
+	return expr.Path(append(col[:len(col):len(col)], p[1:]...)), true
+}
+
+// canRewrite returns true if every column referenced by e
+// is a column of the table and can be rewritten.
+func (c *viewColumns) canRewrite(e expr.Expr) bool {
+	ok := true
+
+	expr.Walk(e, func(e expr.Expr) bool {
+		switch t := e.(type) {
+		case expr.Path:
+			_, ok = c.column(t)
+		case expr.Operator, expr.Parentheses, expr.LiteralExprList:
+		default:
+			// paths of the other expressions are not rewritten
+			ok = !exprContainsPath(e)
+			return false
+		}
+
+		return ok
+	})
+
+	return ok
+}
+
+// rewrite replaces the columns of the view referenced by e with the columns
+// of the table. The operators of e are modified in place.
+// It must only be called if canRewrite returns true.
+func (c *viewColumns) rewrite(e expr.Expr) expr.Expr {
+	switch t := e.(type) {
+	case expr.Path:
+		p, _ := c.column(t)
+		return p
+	case expr.Parentheses:
+		return expr.Parentheses{E: c.rewrite(t.E)}
+	case expr.LiteralExprList:
+		for i := range t {
+			t[i] = c.rewrite(t[i])
+		}
+	case *expr.BetweenOperator:
+		t.X = c.rewrite(t.X)
+		t.SetLeftHandExpr(c.rewrite(t.LeftHand()))
+		t.SetRightHandExpr(c.rewrite(t.RightHand()))
+	case expr.Operator:
+		t.SetLeftHandExpr(c.rewrite(t.LeftHand()))
+		t.SetRightHandExpr(c.rewrite(t.RightHand()))
+	}
+
+	return e
+}
@@ -3,9 +3,12 @@ package statement
 import (
 	"github.com/chaisql/chai/internal/database"
 	errs "github.com/chaisql/chai/internal/errors"
+	"github.com/chaisql/chai/internal/object"
 	"github.com/chaisql/chai/internal/stream"
 	"github.com/chaisql/chai/internal/stream/index"
+	"github.com/chaisql/chai/internal/stream/path"
 	"github.com/chaisql/chai/internal/stream/table"
+	"github.com/chaisql/chai/internal/types"
 	"github.com/cockroachdb/errors"
 )
 
@@ -85,14 +88,7 @@ func (stmt *AlterTableAddColumnStmt) Run(ctx *Context) (Result, error) {
 	var newIdxs []*database.IndexInfo
 	for _, tc := range stmt.TableConstraints {
 		if tc.Unique {
-			idx, err := ctx.Tx.CatalogWriter().CreateIndex(ctx.Tx, &database.IndexInfo{
-				Paths:  tc.Paths,
-				Unique: true,
-				Owner: database.Owner{
-					TableName: stmt.TableName,
-					Paths:     tc.Paths,
-				},
-			})
+			idx, err := createUniqueIndex(ctx, stmt.TableName, tc)
 			if err != nil {
 				return Result{}, err
 			}
@@ -113,52 +109,411 @@ func (stmt *AlterTableAddColumnStmt) Run(ctx *Context) (Result, error) {
 	// if a primary key was added, we need to delete the old records
 	// and old indexes, and insert the new records and indexes
 	if pkAdded {
-		// delete the old records from the indexes
-		for _, indexName := range indexNames {
-			s = s.Pipe(index.Delete(indexName))
+		s, err = pipeRebuild(ctx, s, stmt.TableName, indexNames)
+		if err != nil {
+			return Result{}, err
 		}
-		// delete the old records from the table
-		s = s.Pipe(table.Delete(stmt.TableName))
+	} else {
+		// otherwise, we can just replace the old records with the new ones
 
 		// validate the record against the new schema
 		s = s.Pipe(table.Validate(stmt.TableName))
 
-		// insert the record with the new primary key
-		s = s.Pipe(table.Insert(stmt.TableName))
+		// replace the old record with the new one
+		s = s.Pipe(table.Replace(stmt.TableName))
+
+		// update the new indexes only
+		for _, idx := range newIdxs {
+			if idx.Unique {
+				s = s.Pipe(index.Validate(idx.IndexName))
+			}
+
+			s = s.Pipe(index.Insert(idx.IndexName))
+		}
+	}
 
-		// insert the record into the all the indexes
-		indexNames = ctx.Tx.Catalog.ListIndexes(stmt.TableName)
-		for _, indexName := range indexNames {
+	// ALTER TABLE ADD COLUMN does not return any result
+	s = s.Pipe(stream.Discard())
+
+	// do NOT optimize the stream
+	return Result{
+		Iterator: &StreamStmtIterator{
+			Stream:  s,
+			Context: ctx,
+		},
+	}, nil
+}
+
+// AlterTableDropColumnStmt is a DSL that allows creating an ALTER TABLE DROP COLUMN query.
+type AlterTableDropColumnStmt struct {
+	TableName  string
+	ColumnName string
+}
+
+// IsReadOnly always returns false. It implements the Statement interface.
+func (stmt *AlterTableDropColumnStmt) IsReadOnly() bool {
+	return false
+}
+
+// Run runs the ALTER TABLE DROP COLUMN statement in the given transaction.
+// It implements the Statement interface.
+// The statement rebuilds the table.
+func (stmt *AlterTableDropColumnStmt) Run(ctx *Context) (Result, error) {
+	// get the table before removing the field constraint
+	// so that the rows can be decoded with the old schema
+	scan := table.Scan(stmt.TableName)
+	var err error
+	scan.Table, err = ctx.Tx.Catalog.GetTable(ctx.Tx, stmt.TableName)
+	if err != nil {
+		return Result{}, errors.Wrap(err, "failed to get table")
+	}
+
+	if scan.Table.Info.GetFieldConstraintForPath(object.NewPath(stmt.ColumnName)) == nil {
+		return Result{}, errors.Errorf("field %q does not exist for table %q", stmt.ColumnName, stmt.TableName)
+	}
+
+	err = ctx.Tx.CatalogWriter().DropFieldConstraint(ctx.Tx, stmt.TableName, stmt.ColumnName)
+	if err != nil {
+		return Result{}, err
+	}
+
+	// remove the column from every row and encode it with the new schema.
+	// no index depends on the column, they don't need to be updated.
+	s := stream.New(scan).
+		Pipe(path.Unset(stmt.ColumnName)).
+		Pipe(table.Validate(stmt.TableName)).
+		Pipe(table.Replace(stmt.TableName)).
+		Pipe(stream.Discard())
+
+	// do NOT optimize the stream
+	return Result{
+		Iterator: &StreamStmtIterator{
+			Stream:  s,
+			Context: ctx,
+		},
+	}, nil
+}
+
+// AlterColumnAction is the modification applied by an ALTER TABLE ALTER COLUMN statement.
+type AlterColumnAction int
+
+const (
+	// AlterColumnSetNotNull adds a NOT NULL constraint to the column.
+	AlterColumnSetNotNull AlterColumnAction = iota + 1
+
+	// AlterColumnDropNotNull removes the NOT NULL constraint of the column.
+	AlterColumnDropNotNull
+
+	// AlterColumnSetDefault sets the default value of the column.
+	AlterColumnSetDefault
+
+	// AlterColumnDropDefault removes the default value of the column.
+	AlterColumnDropDefault
+
+	// AlterColumnSetType changes the type of the column.
+	AlterColumnSetType
+)
+
+// AlterTableAlterColumnStmt is a DSL that allows creating an ALTER TABLE ALTER COLUMN query.
+type AlterTableAlterColumnStmt struct {
+	TableName  string
+	ColumnName string
+	Action     AlterColumnAction
+
+	// DefaultValue is used along with AlterColumnSetDefault.
+	DefaultValue database.TableExpression
+
+	// Type is used along with AlterColumnSetType.
+	Type types.Type
+}
+
+// IsReadOnly always returns false. It implements the Statement interface.
+func (stmt *AlterTableAlterColumnStmt) IsReadOnly() bool {
+	return false
+}
+
+// Run runs the ALTER TABLE ALTER COLUMN statement in the given transaction.
+// It implements the Statement interface.
+// Setting a NOT NULL constraint validates the existing rows,
+// changing the type of the column converts them and rebuilds the indexes using it.
+func (stmt *AlterTableAlterColumnStmt) Run(ctx *Context) (Result, error) {
+	// get the table before modifying the field constraint
+	// so that the rows can be decoded with the old schema
+	scan := table.Scan(stmt.TableName)
+	var err error
+	scan.Table, err = ctx.Tx.Catalog.GetTable(ctx.Tx, stmt.TableName)
+	if err != nil {
+		return Result{}, errors.Wrap(err, "failed to get table")
+	}
+	ti := scan.Table.Info
+
+	p := object.NewPath(stmt.ColumnName)
+	fc := ti.GetFieldConstraintForPath(p)
+	if fc == nil {
+		return Result{}, errors.Errorf("field %q does not exist for table %q", stmt.ColumnName, stmt.TableName)
+	}
+
+	var isPk bool
+	if ti.PrimaryKey != nil {
+		for _, pp := range ti.PrimaryKey.Paths {
+			if pp.IsEqual(p) {
+				isPk = true
+			}
+		}
+	}
+
+	newFc := *fc
+	switch stmt.Action {
+	case AlterColumnSetNotNull:
+		newFc.IsNotNull = true
+	case AlterColumnDropNotNull:
+		if isPk {
+			return Result{}, errors.Errorf("cannot drop NOT NULL constraint of primary key field %q", stmt.ColumnName)
+		}
+		newFc.IsNotNull = false
+	case AlterColumnSetDefault:
+		newFc.DefaultValue = stmt.DefaultValue
+	case AlterColumnDropDefault:
+		newFc.DefaultValue = nil
+	case AlterColumnSetType:
+		if isPk {
+			return Result{}, errors.Errorf("cannot change the type of primary key field %q", stmt.ColumnName)
+		}
+		newFc.Type = stmt.Type
+		newFc.AnonymousType = nil
+		if newFc.Type == types.TypeObject {
+			newFc.AnonymousType = &database.AnonymousType{}
+			newFc.AnonymousType.FieldConstraints.AllowExtraFields = true
+		}
+	default:
+		panic("unreachable")
+	}
+
+	err = ctx.Tx.CatalogWriter().ReplaceFieldConstraint(ctx.Tx, stmt.TableName, &newFc)
+	if err != nil {
+		return Result{}, err
+	}
+
+	var s *stream.Stream
+	switch stmt.Action {
+	case AlterColumnSetNotNull:
+		// ensure existing rows are valid
+		s = stream.New(scan).Pipe(table.Validate(stmt.TableName))
+	case AlterColumnSetType:
+		// convert the rows to the new type
+		s = stream.New(scan).
+			Pipe(table.Validate(stmt.TableName)).
+			Pipe(table.Replace(stmt.TableName))
+
+		// rebuild the indexes using the column
+		for _, indexName := range ctx.Tx.Catalog.ListIndexes(stmt.TableName) {
 			info, err := ctx.Tx.Catalog.GetIndexInfo(indexName)
 			if err != nil {
 				return Result{}, err
 			}
+			var usesColumn bool
+			for _, ip := range info.UsedPaths() {
+				if ip[0].FieldName == stmt.ColumnName {
+					usesColumn = true
+				}
+			}
+			if !usesColumn {
+				continue
+			}
+
+			idx, err := ctx.Tx.Catalog.GetIndex(ctx.Tx, indexName)
+			if err != nil {
+				return Result{}, err
+			}
+			err = idx.Truncate()
+			if err != nil {
+				return Result{}, err
+			}
+
 			if info.Unique {
 				s = s.Pipe(index.Validate(indexName))
 			}
-
 			s = s.Pipe(index.Insert(indexName))
 		}
-	} else {
-		// otherwise, we can just replace the old records with the new ones
+	default:
+		// the stored rows are not affected
+		return Result{}, nil
+	}
 
-		// validate the record against the new schema
-		s = s.Pipe(table.Validate(stmt.TableName))
+	// ALTER TABLE ALTER COLUMN does not return any result
+	s = s.Pipe(stream.Discard())
 
-		// replace the old record with the new one
-		s = s.Pipe(table.Replace(stmt.TableName))
+	// do NOT optimize the stream
+	return Result{
+		Iterator: &StreamStmtIterator{
+			Stream:  s,
+			Context: ctx,
+		},
+	}, nil
+}
 
-		// update the new indexes only
-		for _, idx := range newIdxs {
-			if idx.Unique {
-				s = s.Pipe(index.Validate(idx.IndexName))
-			}
+// pipeRebuild deletes the rows of the stream from the table and from the given indexes,
+// and inserts them again using the current schema of the table,
+// which generates new keys if the primary key has changed.
+func pipeRebuild(ctx *Context, s *stream.Stream, tableName string, oldIndexNames []string) (*stream.Stream, error) {
+	// delete the old records from the indexes
+	for _, indexName := range oldIndexNames {
+		s = s.Pipe(index.Delete(indexName))
+	}
+	// delete the old records from the table
+	s = s.Pipe(table.Delete(tableName))
 
-			s = s.Pipe(index.Insert(idx.IndexName))
+	// validate the record against the new schema
+	s = s.Pipe(table.Validate(tableName))
+
+	// insert the record with the new primary key
+	s = s.Pipe(table.Insert(tableName))
+
+	// insert the record into the all the indexes
+	for _, indexName := range ctx.Tx.Catalog.ListIndexes(tableName) {
+		info, err := ctx.Tx.Catalog.GetIndexInfo(indexName)
+		if err != nil {
+			return nil, err
+		}
+		if info.Unique {
+			s = s.Pipe(index.Validate(indexName))
 		}
+
+		s = s.Pipe(index.Insert(indexName))
 	}
 
-	// ALTER TABLE ADD COLUMN does not return any result
+	return s, nil
+}
+
+// createUniqueIndex creates the index of a unique constraint.
+func createUniqueIndex(ctx *Context, tableName string, tc *database.TableConstraint) (*database.IndexInfo, error) {
+	return ctx.Tx.CatalogWriter().CreateIndex(ctx.Tx, &database.IndexInfo{
+		Paths:  tc.Paths,
+		Unique: true,
+		Owner: database.Owner{
+			TableName: tableName,
+			Paths:     tc.Paths,
+		},
+		KeySortOrder: tc.SortOrder,
+	})
+}
+
+// AlterTableAddConstraintStmt is a DSL that allows creating an ALTER TABLE ADD CONSTRAINT query.
+type AlterTableAddConstraintStmt struct {
+	TableName  string
+	Constraint *database.TableConstraint
+}
+
+// IsReadOnly always returns false. It implements the Statement interface.
+func (stmt *AlterTableAddConstraintStmt) IsReadOnly() bool {
+	return false
+}
+
+// Run runs the ALTER TABLE ADD CONSTRAINT statement in the given transaction.
+// It implements the Statement interface.
+// The existing rows are validated against the new constraint.
+// Adding a primary key rebuilds the table.
+func (stmt *AlterTableAddConstraintStmt) Run(ctx *Context) (Result, error) {
+	var err error
+
+	// get the table before adding the constraint
+	// so that the rows can be decoded with the old schema
+	scan := table.Scan(stmt.TableName)
+	scan.Table, err = ctx.Tx.Catalog.GetTable(ctx.Tx, stmt.TableName)
+	if err != nil {
+		return Result{}, errors.Wrap(err, "failed to get table")
+	}
+
+	// get the current list of indexes
+	indexNames := ctx.Tx.Catalog.ListIndexes(stmt.TableName)
+
+	tc := *stmt.Constraint
+	if tc.ForeignKey != nil {
+		fk := *tc.ForeignKey
+		tc.ForeignKey = &fk
+	}
+	err = ctx.Tx.CatalogWriter().AddFieldConstraint(ctx.Tx, stmt.TableName, nil, database.TableConstraints{&tc})
+	if err != nil {
+		return Result{}, err
+	}
+
+	s := stream.New(scan)
+	switch {
+	case tc.PrimaryKey:
+		// the rows must be stored with their new key
+		s, err = pipeRebuild(ctx, s, stmt.TableName, indexNames)
+		if err != nil {
+			return Result{}, err
+		}
+	case tc.Unique:
+		// build the index of the constraint
+		idx, err := createUniqueIndex(ctx, stmt.TableName, &tc)
+		if err != nil {
+			return Result{}, err
+		}
+
+		s = s.Pipe(index.Validate(idx.IndexName)).Pipe(index.Insert(idx.IndexName))
+	default:
+		// validate the rows against the CHECK or FOREIGN KEY constraint
+		s = s.Pipe(table.Validate(stmt.TableName))
+	}
+
+	// ALTER TABLE ADD CONSTRAINT does not return any result
+	s = s.Pipe(stream.Discard())
+
+	// do NOT optimize the stream
+	return Result{
+		Iterator: &StreamStmtIterator{
+			Stream:  s,
+			Context: ctx,
+		},
+	}, nil
+}
+
+// AlterTableDropConstraintStmt is a DSL that allows creating an ALTER TABLE DROP CONSTRAINT query.
+type AlterTableDropConstraintStmt struct {
+	TableName      string
+	ConstraintName string
+}
+
+// IsReadOnly always returns false. It implements the Statement interface.
+func (stmt *AlterTableDropConstraintStmt) IsReadOnly() bool {
+	return false
+}
+
+// Run runs the ALTER TABLE DROP CONSTRAINT statement in the given transaction.
+// It implements the Statement interface.
+// Dropping the primary key rebuilds the table.
+func (stmt *AlterTableDropConstraintStmt) Run(ctx *Context) (Result, error) {
+	var err error
+
+	// get the table before dropping the constraint
+	// so that the rows can be decoded with the old schema
+	scan := table.Scan(stmt.TableName)
+	scan.Table, err = ctx.Tx.Catalog.GetTable(ctx.Tx, stmt.TableName)
+	if err != nil {
+		return Result{}, errors.Wrap(err, "failed to get table")
+	}
+
+	// get the current list of indexes
+	indexNames := ctx.Tx.Catalog.ListIndexes(stmt.TableName)
+
+	tc, err := ctx.Tx.CatalogWriter().DropTableConstraint(ctx.Tx, stmt.TableName, stmt.ConstraintName)
+	if err != nil {
+		return Result{}, err
+	}
+
+	// only the primary key determines how rows are stored
+	if !tc.PrimaryKey {
+		return Result{}, nil
+	}
+
+	s, err := pipeRebuild(ctx, stream.New(scan), stmt.TableName, indexNames)
+	if err != nil {
+		return Result{}, err
+	}
+
+	// ALTER TABLE DROP CONSTRAINT does not return any result
 	s = s.Pipe(stream.Discard())
 
 	// do NOT optimize the stream
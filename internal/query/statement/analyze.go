@@ -0,0 +1,50 @@
+package statement
+
+import (
+	"strings"
+
+	"github.com/chaisql/chai/internal/database"
+)
+
+// AnalyzeStmt is a DSL that allows creating an ANALYZE statement.
+// It computes the statistics of a table and of its indexes, or of
+// all the tables if TableName is empty, and stores them in the catalog
+// so that the planner can estimate the number of rows read by a query.
+type AnalyzeStmt struct {
+	TableName string
+}
+
+// IsReadOnly always returns false. It implements the Statement interface.
+func (stmt AnalyzeStmt) IsReadOnly() bool {
+	return false
+}
+
+// Run runs the Analyze statement in the given transaction.
+// It implements the Statement interface.
+func (stmt AnalyzeStmt) Run(ctx *Context) (Result, error) {
+	var res Result
+
+	tableNames := []string{stmt.TableName}
+	if stmt.TableName == "" {
+		tableNames = tableNames[:0]
+		for _, name := range ctx.Tx.Catalog.Cache.ListObjects(database.RelationTableType) {
+			if !strings.HasPrefix(name, database.InternalPrefix) {
+				tableNames = append(tableNames, name)
+			}
+		}
+	}
+
+	for _, name := range tableNames {
+		stats, err := database.ComputeTableStats(ctx.Tx, name)
+		if err != nil {
+			return res, err
+		}
+
+		err = ctx.Tx.CatalogWriter().SetTableStats(ctx.Tx, stats)
+		if err != nil {
+			return res, err
+		}
+	}
+
+	return res, nil
+}
@@ -1,8 +1,6 @@
 package statement
 
 import (
-	"math"
-
 	"github.com/chaisql/chai/internal/database"
 	errs "github.com/chaisql/chai/internal/errors"
 	"github.com/chaisql/chai/internal/stream"
@@ -28,16 +26,8 @@ func (stmt *CreateTableStmt) Run(ctx *Context) (Result, error) {
 
 	// if there is no primary key, create a rowid sequence
 	if stmt.Info.PrimaryKey == nil {
-		seq := database.SequenceInfo{
-			IncrementBy: 1,
-			Min:         1, Max: math.MaxInt64,
-			Start: 1,
-			Cache: 64,
-			Owner: database.Owner{
-				TableName: stmt.Info.TableName,
-			},
-		}
-		err := ctx.Tx.CatalogWriter().CreateSequence(ctx.Tx, &seq)
+		seq := database.NewRowidSequenceInfo(stmt.Info.TableName)
+		err := ctx.Tx.CatalogWriter().CreateSequence(ctx.Tx, seq)
 		if err != nil {
 			return res, err
 		}
@@ -55,15 +45,7 @@ func (stmt *CreateTableStmt) Run(ctx *Context) (Result, error) {
 	// create a unique index for every unique constraint
 	for _, tc := range stmt.Info.TableConstraints {
 		if tc.Unique {
-			_, err = ctx.Tx.CatalogWriter().CreateIndex(ctx.Tx, &database.IndexInfo{
-				Paths:  tc.Paths,
-				Unique: true,
-				Owner: database.Owner{
-					TableName: stmt.Info.TableName,
-					Paths:     tc.Paths,
-				},
-				KeySortOrder: tc.SortOrder,
-			})
+			_, err = createUniqueIndex(ctx, stmt.Info.TableName, tc)
 			if err != nil {
 				return res, err
 			}
@@ -135,3 +117,70 @@ func (stmt *CreateSequenceStmt) Run(ctx *Context) (Result, error) {
 	}
 	return res, err
 }
+
+// CreateViewStmt represents a parsed CREATE VIEW statement.
+type CreateViewStmt struct {
+	IfNotExists bool
+	Info        database.ViewInfo
+}
+
+// IsReadOnly always returns false. It implements the Statement interface.
+func (stmt *CreateViewStmt) IsReadOnly() bool {
+	return false
+}
+
+// Run the statement in the given transaction.
+// It implements the Statement interface.
+func (stmt *CreateViewStmt) Run(ctx *Context) (Result, error) {
+	var res Result
+
+	// ensure the query is valid
+	_, err := ctx.prepareView(&stmt.Info)
+	if err != nil {
+		return res, err
+	}
+
+	err = ctx.Tx.CatalogWriter().CreateView(ctx.Tx, &stmt.Info)
+	if stmt.IfNotExists {
+		if errs.IsAlreadyExistsError(err) {
+			return res, nil
+		}
+	}
+	return res, err
+}
+
+// CreateTriggerStmt represents a parsed CREATE TRIGGER statement.
+type CreateTriggerStmt struct {
+	IfNotExists bool
+	Info        database.TriggerInfo
+}
+
+// IsReadOnly always returns false. It implements the Statement interface.
+func (stmt *CreateTriggerStmt) IsReadOnly() bool {
+	return false
+}
+
+// Run the statement in the given transaction.
+// It implements the Statement interface.
+func (stmt *CreateTriggerStmt) Run(ctx *Context) (Result, error) {
+	var res Result
+
+	_, err := ctx.Tx.Catalog.GetTableInfo(stmt.Info.TableName)
+	if err != nil {
+		return res, err
+	}
+
+	// ensure the action is valid
+	_, err = ctx.prepareTrigger(&stmt.Info)
+	if err != nil {
+		return res, err
+	}
+
+	err = ctx.Tx.CatalogWriter().CreateTrigger(ctx.Tx, &stmt.Info)
+	if stmt.IfNotExists {
+		if errs.IsAlreadyExistsError(err) {
+			return res, nil
+		}
+	}
+	return res, err
+}
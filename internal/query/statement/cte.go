@@ -0,0 +1,257 @@
+package statement
+
+import (
+	"fmt"
+
+	errs "github.com/chaisql/chai/internal/errors"
+	"github.com/chaisql/chai/internal/expr"
+	"github.com/chaisql/chai/internal/sql/scanner"
+	"github.com/chaisql/chai/internal/stream"
+	"github.com/chaisql/chai/internal/stream/rows"
+	"github.com/chaisql/chai/internal/stream/table"
+	"github.com/cockroachdb/errors"
+)
+
+// A CommonTableExpr defines a temporary table in the WITH clause of a SELECT statement.
+// The table can be referenced by name by the statement and by the following common table expressions.
+type CommonTableExpr struct {
+	Name string
+	// Columns renames the columns returned by the statement, if any.
+	Columns []string
+	Stmt    *SelectStmt
+}
+
+// a commonTableRef is a common table that can be referenced
+// by the statement being prepared.
+type commonTableRef struct {
+	table *rows.CommonTable
+	// working is true when the reference is made by the recursive term
+	// of the table itself: it must read the rows of the previous iteration.
+	working bool
+}
+
+// scan returns an operator iterating over the rows of the given source,
+// which is either a common table, a view or a table of the database.
+// It returns true if the source is not a table of the database.
+func (ctx *Context) scan(name string) (stream.Operator, bool, error) {
+	for i := len(ctx.commonTables) - 1; i >= 0; i-- {
+		ref := ctx.commonTables[i]
+		if ref.table.Name != name {
+			continue
+		}
+
+		if ref.working {
+			return rows.WorkingTableScan(name), true, nil
+		}
+
+		return rows.CommonTableScan(ref.table), true, nil
+	}
+
+	info, err := ctx.Tx.Catalog.GetViewInfo(name)
+	if errs.IsNotFoundError(err) {
+		return table.Scan(name), false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	s, err := ctx.prepareView(info)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return rows.View(name, s), true, nil
+}
+
+// withCommonTable returns a copy of the context in which the given common table is visible.
+func (ctx *Context) withCommonTable(ref commonTableRef) *Context {
+	c := *ctx
+	c.commonTables = append(ctx.commonTables[:len(ctx.commonTables):len(ctx.commonTables)], ref)
+	return &c
+}
+
+// prepareCommonTables prepares the common table expressions of the statement and returns
+// a context in which they are visible. It also reports whether they are read-only.
+func (stmt *SelectStmt) prepareCommonTables(ctx *Context) (*Context, bool, error) {
+	readOnly := true
+	names := make(map[string]struct{}, len(stmt.With))
+
+	for _, cte := range stmt.With {
+		if _, ok := names[cte.Name]; ok {
+			return nil, false, fmt.Errorf("WITH query name %q specified more than once", cte.Name)
+		}
+		names[cte.Name] = struct{}{}
+
+		t := rows.CommonTable{
+			Name:    cte.Name,
+			Columns: cte.Columns,
+		}
+
+		var ro bool
+		var err error
+		if stmt.Recursive && cte.Stmt.references(cte.Name) > 0 {
+			ro, err = cte.prepareRecursive(ctx, &t)
+		} else {
+			t.Stream, ro, err = prepareSelect(ctx, cte.Stmt)
+		}
+		if err != nil {
+			return nil, false, err
+		}
+
+		// a table referencing the enclosing statement can't be computed once per execution
+		outer, err := cte.Stmt.outerColumns(ctx.withCommonTable(commonTableRef{table: &t}))
+		if err != nil {
+			return nil, false, err
+		}
+		t.Correlated = len(cte.Stmt.outerNames()) > 0 || len(outer) > 0
+
+		readOnly = readOnly && ro
+		ctx = ctx.withCommonTable(commonTableRef{table: &t})
+	}
+
+	return ctx, readOnly, nil
+}
+
+// prepareRecursive prepares the non-recursive and the recursive terms of a recursive common table.
+func (cte *CommonTableExpr) prepareRecursive(ctx *Context, t *rows.CommonTable) (bool, error) {
+	stmt := cte.Stmt
+
+	if len(stmt.CompoundSelect) != 2 || stmt.CompoundOperators[0].Token != scanner.UNION ||
+		stmt.CompoundSelect[0].references(cte.Name) > 0 {
+		return false, fmt.Errorf("recursive query %q does not have the form non-recursive-term UNION [ALL] recursive-term", cte.Name)
+	}
+
+	if stmt.CompoundSelect[1].references(cte.Name) > 1 {
+		return false, fmt.Errorf("recursive reference to query %q must not appear more than once", cte.Name)
+	}
+
+	if stmt.OrderBy != nil || stmt.LimitExpr != nil || stmt.OffsetExpr != nil {
+		return false, errors.New("ORDER BY, LIMIT and OFFSET are not supported in a recursive query")
+	}
+
+	var ro bool
+	var err error
+	t.Stream, ro, err = prepareSelect(ctx, &SelectStmt{CompoundSelect: stmt.CompoundSelect[:1]})
+	if err != nil {
+		return false, err
+	}
+
+	// within the recursive term, the table references the rows of the previous iteration
+	rctx := ctx.withCommonTable(commonTableRef{table: t, working: true})
+	var rro bool
+	t.Recursive, rro, err = prepareSelect(rctx, &SelectStmt{CompoundSelect: stmt.CompoundSelect[1:]})
+	if err != nil {
+		return false, err
+	}
+
+	t.Distinct = !stmt.CompoundOperators[0].All
+	return ro && rro, nil
+}
+
+// prepareSelect prepares the statement and returns its stream.
+func prepareSelect(ctx *Context, stmt *SelectStmt) (*stream.Stream, bool, error) {
+	st, err := stmt.Prepare(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return st.(*PreparedStreamStmt).Stream, st.IsReadOnly(), nil
+}
+
+// references returns the number of times the statement references the given table
+// in its FROM clauses.
+func (stmt *SelectStmt) references(name string) int {
+	var n int
+	for _, core := range stmt.CompoundSelect {
+		n += core.references(name)
+	}
+
+	return n
+}
+
+// references returns the number of times the statement references the given table
+// in its FROM clause.
+func (stmt *SelectCoreStmt) references(name string) int {
+	var n int
+	if stmt.TableName == name {
+		n++
+	}
+
+	for _, j := range stmt.Joins {
+		if j.TableName == name {
+			n++
+		}
+	}
+
+	return n
+}
+
+// sources returns the names of the tables and views read by the statement,
+// including the ones read by its common table expressions and subqueries.
+// Common tables are not part of the list.
+func (stmt *SelectStmt) sources() []string {
+	var names []string
+	stmt.collectSources(nil, func(name string) {
+		for _, n := range names {
+			if n == name {
+				return
+			}
+		}
+		names = append(names, name)
+	})
+
+	return names
+}
+
+// collectSources calls fn with every source read by the statement
+// which is not one of the given common tables.
+func (stmt *SelectStmt) collectSources(commonTables []string, fn func(name string)) {
+	// a common table is visible from the following ones,
+	// and from itself if the statement is recursive
+	commonTables = commonTables[:len(commonTables):len(commonTables)]
+	for _, cte := range stmt.With {
+		if stmt.Recursive {
+			commonTables = append(commonTables, cte.Name)
+		}
+		cte.Stmt.collectSources(commonTables, fn)
+		if !stmt.Recursive {
+			commonTables = append(commonTables, cte.Name)
+		}
+	}
+
+	add := func(name string) {
+		if name == "" {
+			return
+		}
+		for _, n := range commonTables {
+			if n == name {
+				return
+			}
+		}
+		fn(name)
+	}
+
+	walk := func(e expr.Expr) {
+		expr.Walk(e, func(e expr.Expr) bool {
+			if sq, ok := e.(interface{ statement() *SelectStmt }); ok {
+				sq.statement().collectSources(commonTables, fn)
+			}
+			return true
+		})
+	}
+
+	for _, core := range stmt.CompoundSelect {
+		add(core.TableName)
+		for _, j := range core.Joins {
+			add(j.TableName)
+		}
+
+		for _, e := range core.exprs() {
+			walk(e)
+		}
+	}
+
+	for _, e := range stmt.OrderBy {
+		walk(e)
+	}
+}
@@ -1,12 +1,13 @@
 package statement
 
 import (
+	"github.com/chaisql/chai/internal/database"
 	"github.com/chaisql/chai/internal/expr"
-	"github.com/chaisql/chai/internal/sql/scanner"
 	"github.com/chaisql/chai/internal/stream"
 	"github.com/chaisql/chai/internal/stream/index"
 	"github.com/chaisql/chai/internal/stream/rows"
 	"github.com/chaisql/chai/internal/stream/table"
+	"github.com/chaisql/chai/internal/tree"
 )
 
 // DeleteConfig holds DELETE configuration.
@@ -16,9 +17,17 @@ type DeleteStmt struct {
 	TableName        string
 	WhereExpr        expr.Expr
 	OffsetExpr       expr.Expr
-	OrderBy          expr.Path
+	OrderBy          []expr.Expr
 	LimitExpr        expr.Expr
-	OrderByDirection scanner.Token
+	OrderBySortOrder tree.SortOrder
+
+	// Using holds the tables joined with the deleted table, i.e. "USING other".
+	// The first table is joined without condition, the WHERE clause
+	// being evaluated against the joined rows.
+	Using []*JoinClause
+
+	// Returning is evaluated against the deleted rows.
+	Returning []expr.Expr
 }
 
 func NewDeleteStatement() *DeleteStmt {
@@ -33,18 +42,18 @@ func NewDeleteStatement() *DeleteStmt {
 }
 
 func (stmt *DeleteStmt) Prepare(c *Context) (Statement, error) {
-	s := stream.New(table.Scan(stmt.TableName))
+	err := prepareSubqueries(c, append([]expr.Expr{stmt.WhereExpr}, stmt.Returning...)...)
+	if err != nil {
+		return nil, err
+	}
 
-	if stmt.WhereExpr != nil {
-		s = s.Pipe(rows.Filter(stmt.WhereExpr))
+	s, err := prepareTargetScan(c, stmt.TableName, stmt.Using, stmt.WhereExpr)
+	if err != nil {
+		return nil, err
 	}
 
 	if stmt.OrderBy != nil {
-		if stmt.OrderByDirection == scanner.DESC {
-			s = s.Pipe(rows.TempTreeSortReverse(stmt.OrderBy))
-		} else {
-			s = s.Pipe(rows.TempTreeSort(stmt.OrderBy))
-		}
+		s = s.Pipe(rows.TempTreeSortWithOrder(stmt.OrderBy, stmt.OrderBySortOrder))
 	}
 
 	if stmt.OffsetExpr != nil {
@@ -55,6 +64,16 @@ func (stmt *DeleteStmt) Prepare(c *Context) (Statement, error) {
 		s = s.Pipe(rows.Take(stmt.LimitExpr))
 	}
 
+	s, err = pipeTriggers(c, s, stmt.TableName, database.TriggerDelete)
+	if err != nil {
+		return nil, err
+	}
+
+	// apply the ON DELETE actions of the foreign keys referencing the table
+	if len(c.Tx.Catalog.ListReferences(stmt.TableName)) > 0 {
+		s = s.Pipe(table.OnDelete(stmt.TableName))
+	}
+
 	indexNames := c.Tx.Catalog.ListIndexes(stmt.TableName)
 	for _, indexName := range indexNames {
 		s = s.Pipe(index.Delete(indexName))
@@ -62,7 +81,11 @@ func (stmt *DeleteStmt) Prepare(c *Context) (Statement, error) {
 
 	s = s.Pipe(table.Delete(stmt.TableName))
 
-	s = s.Pipe(stream.Discard())
+	if len(stmt.Returning) > 0 {
+		s = s.Pipe(rows.Project(stmt.Returning...))
+	} else {
+		s = s.Pipe(stream.Discard())
+	}
 
 	st := StreamStmt{
 		Stream:   s,
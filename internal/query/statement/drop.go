@@ -11,6 +11,10 @@ import (
 type DropTableStmt struct {
 	TableName string
 	IfExists  bool
+	// Cascade drops the foreign keys of the other tables referencing the table
+	// and the views reading it.
+	// Otherwise, the table cannot be dropped if it is referenced.
+	Cascade bool
 }
 
 // IsReadOnly always returns false. It implements the Statement interface.
@@ -29,20 +33,43 @@ func (stmt DropTableStmt) Run(ctx *Context) (Result, error) {
 
 	tb, err := ctx.Tx.Catalog.GetTable(ctx.Tx, stmt.TableName)
 	if err != nil {
-		if errs.IsNotFoundError(err) && stmt.IfExists {
-			err = nil
+		if errs.IsNotFoundError(err) {
+			if _, verr := ctx.Tx.Catalog.GetViewInfo(stmt.TableName); verr == nil {
+				return res, fmt.Errorf("%q is a view, use DROP VIEW to drop it", stmt.TableName)
+			}
+			if stmt.IfExists {
+				err = nil
+			}
 		}
 
 		return res, err
 	}
 
+	if stmt.Cascade {
+		for _, ref := range ctx.Tx.Catalog.ListReferences(stmt.TableName) {
+			if ref.TableName == stmt.TableName {
+				continue
+			}
+
+			_, err = ctx.Tx.CatalogWriter().DropTableConstraint(ctx.Tx, ref.TableName, ref.Constraint.Name)
+			if err != nil {
+				return res, err
+			}
+		}
+
+		err = dropDependentViews(ctx, stmt.TableName)
+		if err != nil {
+			return res, err
+		}
+	}
+
 	err = ctx.Tx.CatalogWriter().DropTable(ctx.Tx, stmt.TableName)
 	if err != nil {
 		return res, err
 	}
 
-	// if there is no primary key, drop the rowid sequence
-	if tb.Info.PrimaryKey == nil {
+	// drop the rowid sequence, if any
+	if tb.Info.RowidSequenceName != "" {
 		err = ctx.Tx.CatalogWriter().DropSequence(ctx.Tx, tb.Info.RowidSequenceName)
 		if err != nil {
 			return res, err
@@ -119,3 +146,98 @@ func (stmt DropSequenceStmt) Run(ctx *Context) (Result, error) {
 
 	return res, err
 }
+
+// DropViewStmt is a DSL that allows creating a DROP VIEW query.
+type DropViewStmt struct {
+	ViewName string
+	IfExists bool
+	// Cascade drops the views reading the view.
+	// Otherwise, the view cannot be dropped if it is read by another view.
+	Cascade bool
+}
+
+// IsReadOnly always returns false. It implements the Statement interface.
+func (stmt DropViewStmt) IsReadOnly() bool {
+	return false
+}
+
+// Run runs the DropView statement in the given transaction.
+// It implements the Statement interface.
+func (stmt DropViewStmt) Run(ctx *Context) (Result, error) {
+	var res Result
+
+	if stmt.ViewName == "" {
+		return res, errors.New("missing view name")
+	}
+
+	_, err := ctx.Tx.Catalog.GetViewInfo(stmt.ViewName)
+	if err != nil {
+		if errs.IsNotFoundError(err) {
+			if _, terr := ctx.Tx.Catalog.GetTableInfo(stmt.ViewName); terr == nil {
+				return res, fmt.Errorf("%q is a table, use DROP TABLE to drop it", stmt.ViewName)
+			}
+			if stmt.IfExists {
+				err = nil
+			}
+		}
+
+		return res, err
+	}
+
+	if stmt.Cascade {
+		err = dropDependentViews(ctx, stmt.ViewName)
+		if err != nil {
+			return res, err
+		}
+	}
+
+	err = ctx.Tx.CatalogWriter().DropView(ctx.Tx, stmt.ViewName)
+	return res, err
+}
+
+// dropDependentViews drops the views reading the given table or view,
+// along with the views reading them.
+func dropDependentViews(ctx *Context, name string) error {
+	for _, viewName := range ctx.Tx.Catalog.ListDependentViews(name) {
+		err := dropDependentViews(ctx, viewName)
+		if err != nil {
+			return err
+		}
+
+		// the view may have been dropped along with another one
+		err = ctx.Tx.CatalogWriter().DropView(ctx.Tx, viewName)
+		if err != nil && !errs.IsNotFoundError(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DropTriggerStmt is a DSL that allows creating a DROP TRIGGER query.
+type DropTriggerStmt struct {
+	TriggerName string
+	IfExists    bool
+}
+
+// IsReadOnly always returns false. It implements the Statement interface.
+func (stmt DropTriggerStmt) IsReadOnly() bool {
+	return false
+}
+
+// Run runs the DropTrigger statement in the given transaction.
+// It implements the Statement interface.
+func (stmt DropTriggerStmt) Run(ctx *Context) (Result, error) {
+	var res Result
+
+	if stmt.TriggerName == "" {
+		return res, errors.New("missing trigger name")
+	}
+
+	err := ctx.Tx.CatalogWriter().DropTrigger(ctx.Tx, stmt.TriggerName)
+	if errs.IsNotFoundError(err) && stmt.IfExists {
+		err = nil
+	}
+
+	return res, err
+}
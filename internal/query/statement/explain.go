@@ -1,7 +1,12 @@
 package statement
 
 import (
+	"time"
+
+	"github.com/chaisql/chai/internal/engine"
+	"github.com/chaisql/chai/internal/environment"
 	"github.com/chaisql/chai/internal/expr"
+	"github.com/chaisql/chai/internal/object"
 	"github.com/chaisql/chai/internal/stream"
 	"github.com/chaisql/chai/internal/stream/rows"
 	"github.com/chaisql/chai/internal/types"
@@ -11,8 +16,11 @@ import (
 // ExplainStmt is a Statement that
 // displays information about how a statement
 // is going to be executed, without executing it.
+// If Analyze is true, the statement is executed
+// and the runtime statistics of each operator are displayed instead.
 type ExplainStmt struct {
 	Statement Preparer
+	Analyze   bool
 }
 
 // Run analyses the inner statement and displays its execution plan.
@@ -30,6 +38,10 @@ func (stmt *ExplainStmt) Run(ctx *Context) (Result, error) {
 		return Result{}, errors.New("EXPLAIN only works on INSERT, SELECT, UPDATE AND DELETE statements")
 	}
 
+	if stmt.Analyze {
+		return analyzeStream(ctx, s.Stream)
+	}
+
 	var plan string
 	if s.Stream != nil {
 		plan = s.Stream.String()
@@ -51,7 +63,193 @@ func (stmt *ExplainStmt) Run(ctx *Context) (Result, error) {
 }
 
 // IsReadOnly indicates that this statement doesn't write anything into
-// the database.
+// the database, unless it executes a statement that does.
 func (s *ExplainStmt) IsReadOnly() bool {
-	return true
+	if !s.Analyze {
+		return true
+	}
+
+	st, ok := s.Statement.(Statement)
+	return !ok || st.IsReadOnly()
+}
+
+// analyzeStream executes the stream and returns one row per operator, in the order
+// of the stream, containing:
+//
+//   - operator: the operator, as displayed by EXPLAIN
+//   - rows_in: the number of rows received from the previous operator
+//   - rows_out: the number of rows passed to the next operator
+//   - time: the time spent in the operator, excluding the other operators
+//   - kv_reads: the number of keys read from the store by the operator
+//
+// The rows returned by the stream are read, then discarded.
+// The streams nested in an operator, like the ones of a union, are not
+// instrumented: their statistics are attributed to the operator.
+func analyzeStream(ctx *Context, s *stream.Stream) (Result, error) {
+	var ops []stream.Operator
+	if s != nil {
+		for op := s.First(); op != nil; op = op.GetNext() {
+			ops = append(ops, op)
+		}
+	}
+
+	// count the reads performed by the transaction while the stream is running
+	session := countingSession{Session: ctx.Tx.Session}
+	ctx.Tx.Session = &session
+	defer func() {
+		ctx.Tx.Session = session.Session
+	}()
+
+	a := analyzer{session: &session}
+	analyzed := make([]*analyzedOperator, len(ops))
+	for i, op := range ops {
+		analyzed[i] = &analyzedOperator{Operator: op, analyzer: &a}
+		if i > 0 {
+			op.SetPrev(analyzed[i-1])
+		}
+	}
+
+	if len(analyzed) > 0 {
+		var env environment.Environment
+		env.DB = ctx.DB
+		env.Tx = ctx.Tx
+		env.SetParams(ctx.Params)
+		defer env.Close()
+
+		// the rows returned by the stream are read as if they were
+		// returned to the user, which is attributed to the last operator
+		last := analyzed[len(analyzed)-1]
+		err := last.Iterate(&env, func(out *environment.Environment) error {
+			if out.Row == nil {
+				return nil
+			}
+
+			a.switchTo(last)
+			defer a.switchTo(nil)
+
+			return out.Row.Iterate(func(string, types.Value) error {
+				return nil
+			})
+		})
+		if err != nil && !errors.Is(err, stream.ErrStreamClosed) {
+			return Result{}, err
+		}
+	}
+
+	exprs := make([]expr.Expr, len(analyzed))
+	for i, op := range analyzed {
+		var rowsIn int64
+		if i > 0 {
+			rowsIn = analyzed[i-1].rowsOut
+		}
+
+		fb := object.NewFieldBuffer().
+			Add("operator", types.NewTextValue(op.Operator.String())).
+			Add("rows_in", types.NewIntegerValue(rowsIn)).
+			Add("rows_out", types.NewIntegerValue(op.rowsOut)).
+			Add("time", types.NewTextValue(op.time.String())).
+			Add("kv_reads", types.NewIntegerValue(op.kvReads))
+		exprs[i] = expr.LiteralValue{Value: types.NewObjectValue(fb)}
+	}
+
+	newStatement := PreparedStreamStmt{
+		Stream:   stream.New(rows.Emit(exprs...)),
+		ReadOnly: true,
+	}
+	return newStatement.Run(ctx)
+}
+
+// analyzer attributes the time spent and the keys read while running a stream
+// to the operator being executed. Each time the execution moves from one operator
+// to another, what happened since the previous move is added to the statistics
+// of the operator that was running.
+type analyzer struct {
+	session  *countingSession
+	current  *analyzedOperator
+	lastTime time.Time
+	lastRead int64
+}
+
+// switchTo marks op as the running operator and returns the previous one.
+func (a *analyzer) switchTo(op *analyzedOperator) *analyzedOperator {
+	now := time.Now()
+	if a.current != nil {
+		a.current.time += now.Sub(a.lastTime)
+		a.current.kvReads += a.session.reads - a.lastRead
+	}
+	a.lastTime = now
+	a.lastRead = a.session.reads
+
+	prev := a.current
+	a.current = op
+	return prev
+}
+
+// analyzedOperator wraps an operator and collects its runtime statistics.
+type analyzedOperator struct {
+	stream.Operator
+
+	analyzer *analyzer
+	rowsOut  int64
+	time     time.Duration
+	kvReads  int64
+}
+
+func (op *analyzedOperator) Iterate(in *environment.Environment, fn func(out *environment.Environment) error) error {
+	caller := op.analyzer.switchTo(op)
+	defer op.analyzer.switchTo(caller)
+
+	return op.Operator.Iterate(in, func(out *environment.Environment) error {
+		op.rowsOut++
+
+		op.analyzer.switchTo(caller)
+		err := fn(out)
+		op.analyzer.switchTo(op)
+		return err
+	})
+}
+
+// countingSession is a session that counts the keys read from the store.
+type countingSession struct {
+	engine.Session
+
+	reads int64
+}
+
+func (s *countingSession) Get(k []byte) ([]byte, error) {
+	s.reads++
+	return s.Session.Get(k)
 }
+
+func (s *countingSession) Exists(k []byte) (bool, error) {
+	s.reads++
+	return s.Session.Exists(k)
+}
+
+func (s *countingSession) Iterator(opts *engine.IterOptions) (engine.Iterator, error) {
+	it, err := s.Session.Iterator(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &countingIterator{Iterator: it, reads: &s.reads}, nil
+}
+
+// countingIterator counts the keys an iterator moves to.
+type countingIterator struct {
+	engine.Iterator
+
+	reads *int64
+}
+
+func (it *countingIterator) count(valid bool) bool {
+	if valid {
+		*it.reads++
+	}
+	return valid
+}
+
+func (it *countingIterator) First() bool { return it.count(it.Iterator.First()) }
+func (it *countingIterator) Last() bool  { return it.count(it.Iterator.Last()) }
+func (it *countingIterator) Next() bool  { return it.count(it.Iterator.Next()) }
+func (it *countingIterator) Prev() bool  { return it.count(it.Iterator.Prev()) }
@@ -3,6 +3,7 @@ package statement_test
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/chaisql/chai"
 	"github.com/chaisql/chai/internal/testutil/assert"
@@ -73,3 +74,107 @@ func TestExplainStmt(t *testing.T) {
 		})
 	}
 }
+
+func TestExplainAnalyzeStmt(t *testing.T) {
+	type operatorStats struct {
+		Operator string
+		RowsIn   int64 `chai:"rows_in"`
+		RowsOut  int64 `chai:"rows_out"`
+		Time     string
+		KVReads  int64 `chai:"kv_reads"`
+	}
+
+	tests := []struct {
+		query    string
+		fails    bool
+		expected []operatorStats
+	}{
+		{"EXPLAIN ANALYZE SELECT * FROM noexist", true, nil},
+		{"EXPLAIN ANALYZE SELECT 1 + 1", false, []operatorStats{
+			{Operator: "rows.Project(1 + 1)", RowsOut: 1},
+		}},
+		{"EXPLAIN ANALYZE SELECT * FROM test WHERE b > 1", false, []operatorStats{
+			{Operator: `table.Scan("test")`, RowsOut: 5, KVReads: 5},
+			{Operator: "rows.Filter(b > 1)", RowsIn: 5, RowsOut: 4},
+		}},
+		{"EXPLAIN ANALYZE SELECT b FROM test WHERE a = 1", false, []operatorStats{
+			{Operator: `index.Scan("idx_a", [{"min": [1], "exact": true}])`, RowsOut: 2, KVReads: 2},
+			{Operator: "rows.Project(b)", RowsIn: 2, RowsOut: 2, KVReads: 2},
+		}},
+		{"EXPLAIN ANALYZE SELECT * FROM test ORDER BY b DESC LIMIT 2", false, []operatorStats{
+			{Operator: `table.Scan("test")`, RowsOut: 5, KVReads: 5},
+			{Operator: "rows.TempTreeSortReverse(b)", RowsIn: 5, RowsOut: 3},
+			{Operator: "rows.Take(2)", RowsIn: 3, RowsOut: 2},
+		}},
+		{"EXPLAIN ANALYZE DELETE FROM test WHERE k = 5", false, []operatorStats{
+			{Operator: `table.Scan("test", [{"min": [5], "exact": true}])`, RowsOut: 1, KVReads: 1},
+			{Operator: `index.Delete("idx_a")`, RowsIn: 1, RowsOut: 1, KVReads: 2},
+			{Operator: `table.Delete('test')`, RowsIn: 1, RowsOut: 1},
+			{Operator: "discard()", RowsIn: 1},
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			db, err := chai.Open(":memory:")
+			assert.NoError(t, err)
+			defer db.Close()
+
+			err = db.Exec(`
+				CREATE TABLE test (k INTEGER PRIMARY KEY, a int, b int);
+				CREATE INDEX idx_a ON test (a);
+				INSERT INTO test (k, a, b) VALUES (1, 1, 1), (2, 1, 2), (3, 2, 3), (4, 3, 4), (5, 4, 5);
+			`)
+			assert.NoError(t, err)
+
+			res, err := db.Query(test.query)
+			if test.fails {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			defer res.Close()
+
+			var got []operatorStats
+			err = res.Iterate(func(r *chai.Row) error {
+				var s operatorStats
+				err := r.StructScan(&s)
+				if err != nil {
+					return err
+				}
+
+				_, err = time.ParseDuration(s.Time)
+				if err != nil {
+					return err
+				}
+				s.Time = ""
+
+				got = append(got, s)
+				return nil
+			})
+			assert.NoError(t, err)
+
+			require.Equal(t, test.expected, got)
+		})
+	}
+
+	t.Run("Writes", func(t *testing.T) {
+		db, err := chai.Open(":memory:")
+		assert.NoError(t, err)
+		defer db.Close()
+
+		err = db.Exec(`
+			CREATE TABLE test (a int);
+			EXPLAIN ANALYZE INSERT INTO test (a) VALUES (1), (2);
+		`)
+		assert.NoError(t, err)
+
+		r, err := db.QueryRow("SELECT COUNT(*) FROM test")
+		assert.NoError(t, err)
+
+		var count int
+		err = r.Scan(&count)
+		assert.NoError(t, err)
+		require.Equal(t, 2, count)
+	})
+}
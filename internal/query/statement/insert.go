@@ -3,6 +3,7 @@ package statement
 import (
 	"github.com/chaisql/chai/internal/database"
 	"github.com/chaisql/chai/internal/expr"
+	"github.com/chaisql/chai/internal/object"
 	"github.com/chaisql/chai/internal/stream"
 	"github.com/chaisql/chai/internal/stream/index"
 	"github.com/chaisql/chai/internal/stream/path"
@@ -21,6 +22,17 @@ type InsertStmt struct {
 	SelectStmt Preparer
 	Returning  []expr.Expr
 	OnConflict database.OnConflictAction
+
+	// OnConflictTarget holds the columns of the primary key
+	// or of the unique index whose conflicts are handled by the ON CONFLICT clause.
+	// If empty, all conflicts are handled.
+	OnConflictTarget []string
+
+	// OnConflictSet and OnConflictWhere are used along with ON CONFLICT DO UPDATE.
+	// They are evaluated against the existing row, the proposed row
+	// being referenced as "excluded".
+	OnConflictSet   []UpdateSetPair
+	OnConflictWhere expr.Expr
 }
 
 func NewInsertStatement() *InsertStmt {
@@ -37,6 +49,11 @@ func NewInsertStatement() *InsertStmt {
 func (stmt *InsertStmt) Prepare(c *Context) (Statement, error) {
 	var s *stream.Stream
 
+	err := prepareSubqueries(c, stmt.Values...)
+	if err != nil {
+		return nil, err
+	}
+
 	if stmt.Values != nil {
 		ti, err := c.Tx.Catalog.GetTableInfo(stmt.TableName)
 		if err != nil {
@@ -95,14 +112,39 @@ func (stmt *InsertStmt) Prepare(c *Context) (Statement, error) {
 	s = s.Pipe(table.Validate(stmt.TableName))
 
 	if stmt.OnConflict != 0 {
+		var op *stream.OnConflictOperator
 		switch stmt.OnConflict {
 		case database.OnConflictDoNothing:
-			s = s.Pipe(stream.OnConflict(nil))
+			op = stream.OnConflict(nil)
 		case database.OnConflictDoReplace:
-			s = s.Pipe(stream.OnConflict(stream.New(table.Replace(stmt.TableName))))
+			rs := stream.New(table.Replace(stmt.TableName))
+			if len(c.Tx.Catalog.ListReferences(stmt.TableName)) > 0 {
+				rs = stream.New(table.OnUpdate(stmt.TableName)).Pipe(table.Replace(stmt.TableName))
+			}
+			op = stream.OnConflict(rs)
+		case database.OnConflictDoUpdate:
+			us, err := stmt.prepareOnConflictUpdate(c)
+			if err != nil {
+				return nil, err
+			}
+			op = stream.OnConflict(us)
 		default:
 			panic("unreachable")
 		}
+
+		if len(stmt.OnConflictTarget) > 0 {
+			op.Target, err = stmt.conflictTarget(c)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		s = s.Pipe(op)
+	}
+
+	s, err = pipeTriggers(c, s, stmt.TableName, database.TriggerInsert)
+	if err != nil {
+		return nil, err
 	}
 
 	// check unique constraints
@@ -137,3 +179,84 @@ func (stmt *InsertStmt) Prepare(c *Context) (Statement, error) {
 
 	return st.Prepare(c)
 }
+
+// prepareOnConflictUpdate returns the stream updating the existing row
+// when the proposed row conflicts with it.
+func (stmt *InsertStmt) prepareOnConflictUpdate(c *Context) (*stream.Stream, error) {
+	ti, err := c.Tx.Catalog.GetTableInfo(stmt.TableName)
+	if err != nil {
+		return nil, err
+	}
+
+	exprs := []expr.Expr{stmt.OnConflictWhere}
+	for _, pair := range stmt.OnConflictSet {
+		exprs = append(exprs, pair.E)
+	}
+	err = prepareSubqueries(c, exprs...)
+	if err != nil {
+		return nil, err
+	}
+
+	s := stream.New(table.Lookup(stmt.TableName))
+	if stmt.OnConflictWhere != nil {
+		s = s.Pipe(rows.Filter(stmt.OnConflictWhere))
+	}
+
+	s, pkModified := pipeSetPairs(s, ti, stmt.OnConflictSet)
+
+	return pipeReplace(c, s, stmt.TableName, pkModified)
+}
+
+// conflictTarget returns the paths of the primary key or of the unique index
+// matching the ON CONFLICT target.
+func (stmt *InsertStmt) conflictTarget(c *Context) ([]object.Path, error) {
+	target := make([]object.Path, len(stmt.OnConflictTarget))
+	for i, name := range stmt.OnConflictTarget {
+		target[i] = object.NewPath(name)
+	}
+
+	ti, err := c.Tx.Catalog.GetTableInfo(stmt.TableName)
+	if err != nil {
+		return nil, err
+	}
+
+	if ti.PrimaryKey != nil && samePaths(ti.PrimaryKey.Paths, target) {
+		return target, nil
+	}
+
+	for _, indexName := range c.Tx.Catalog.ListIndexes(stmt.TableName) {
+		info, err := c.Tx.Catalog.GetIndexInfo(indexName)
+		if err != nil {
+			return nil, err
+		}
+
+		// partial indexes don't cover all the rows of the table
+		if info.Unique && info.Predicate == nil && samePaths(info.Paths, target) {
+			return target, nil
+		}
+	}
+
+	return nil, errors.New("no primary key or unique constraint matches the ON CONFLICT target")
+}
+
+// samePaths returns whether a and b contain the same paths, in any order.
+func samePaths(a, b []object.Path) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for _, pa := range a {
+		var found bool
+		for _, pb := range b {
+			if pa.IsEqual(pb) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
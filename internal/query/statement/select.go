@@ -7,34 +7,68 @@ import (
 	"github.com/chaisql/chai/internal/object"
 	"github.com/chaisql/chai/internal/sql/scanner"
 	"github.com/chaisql/chai/internal/stream"
+	"github.com/chaisql/chai/internal/stream/join"
 	"github.com/chaisql/chai/internal/stream/rows"
-	"github.com/chaisql/chai/internal/stream/table"
+	"github.com/chaisql/chai/internal/tree"
 	"github.com/cockroachdb/errors"
 )
 
 type SelectCoreStmt struct {
 	TableName       string
+	TableAlias      string
+	Joins           []*JoinClause
 	Distinct        bool
 	WhereExpr       expr.Expr
-	GroupByExpr     expr.Expr
+	GroupByExprs    []expr.Expr
+	HavingExpr      expr.Expr
 	ProjectionExprs []expr.Expr
 }
 
-func (stmt *SelectCoreStmt) Prepare(*Context) (*StreamStmt, error) {
+func (stmt *SelectCoreStmt) Prepare(ctx *Context) (*StreamStmt, error) {
 	isReadOnly := true
 
+	err := prepareSubqueries(ctx, stmt.exprs()...)
+	if err != nil {
+		return nil, err
+	}
+
 	var s *stream.Stream
 
 	if stmt.TableName != "" {
-		s = s.Pipe(table.Scan(stmt.TableName))
+		scan, common, err := ctx.scan(stmt.TableName)
+		if err != nil {
+			return nil, err
+		}
+		s = s.Pipe(scan)
+
+		// rows of common tables and views don't belong to any table,
+		// they must be named to be referenced
+		if stmt.TableAlias != "" || common {
+			s = s.Pipe(join.Alias(sourceName(stmt.TableName, stmt.TableAlias)))
+		}
+
+		err = stmt.checkSourceNames()
+		if err != nil {
+			return nil, err
+		}
+
+		s, err = pipeJoins(ctx, s, stmt.Joins)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	if stmt.WhereExpr != nil {
 		s = s.Pipe(rows.Filter(stmt.WhereExpr))
 	}
 
-	// when using GROUP BY, only aggregation functions or GroupByExpr can be selected
-	if stmt.GroupByExpr != nil {
+	windows, err := stmt.windowExprs()
+	if err != nil {
+		return nil, err
+	}
+
+	// when using GROUP BY, only aggregation functions or GroupByExprs can be selected
+	if len(stmt.GroupByExprs) > 0 {
 		var invalidProjectedField expr.Expr
 		var aggregators []expr.AggregatorBuilder
 
@@ -52,9 +86,9 @@ func (stmt *SelectCoreStmt) Prepare(*Context) (*StreamStmt, error) {
 				continue
 			}
 
-			// check if this is the same expression as the one used in the GROUP BY clause
-			if expr.Equal(e, stmt.GroupByExpr) {
-				// if so, replace the expression with a path expression
+			// check if this is the same expression as one of those used in the GROUP BY clause
+			if groupByIndex(e, stmt.GroupByExprs) != -1 {
+				// if so, replace the expression with a path to the group column
 				stmt.ProjectionExprs[i] = &expr.NamedExpr{
 					ExprName: ne.ExprName,
 					Expr:     expr.Path(object.NewPath(e.String())),
@@ -70,9 +104,15 @@ func (stmt *SelectCoreStmt) Prepare(*Context) (*StreamStmt, error) {
 		if invalidProjectedField != nil {
 			return nil, fmt.Errorf("field %q must appear in the GROUP BY clause or be used in an aggregate function", invalidProjectedField)
 		}
+
+		aggregators, err = stmt.prepareHaving(aggregators)
+		if err != nil {
+			return nil, err
+		}
+
 		// add Aggregation node
-		s = s.Pipe(rows.TempTreeSort(stmt.GroupByExpr))
-		s = s.Pipe(rows.GroupAggregate(stmt.GroupByExpr, aggregators...))
+		s = s.Pipe(rows.TempTreeSort(stmt.GroupByExprs...))
+		s = s.Pipe(rows.GroupAggregateBy(stmt.GroupByExprs, aggregators...))
 	} else if stmt.TableName != "" {
 		// if there is no GROUP BY clause, check if there are any aggregation function
 		// and if so add an aggregation node
@@ -91,12 +131,35 @@ func (stmt *SelectCoreStmt) Prepare(*Context) (*StreamStmt, error) {
 			}
 		}
 
+		aggregators, err = stmt.prepareHaving(aggregators)
+		if err != nil {
+			return nil, err
+		}
+
 		// add Aggregation node
-		if len(aggregators) > 0 {
+		if len(aggregators) > 0 || stmt.HavingExpr != nil {
 			s = s.Pipe(rows.GroupAggregate(nil, aggregators...))
 		}
 	}
 
+	// compute the window functions, one window definition at a time
+	if len(windows) > 0 {
+		if stmt.TableName == "" {
+			return nil, errors.New("no tables specified")
+		}
+
+		s = pipeWindows(s, windows)
+	}
+
+	// the HAVING clause filters the groups returned by the aggregation
+	if stmt.HavingExpr != nil {
+		if stmt.TableName == "" {
+			return nil, errors.New("no tables specified")
+		}
+
+		s = s.Pipe(rows.Filter(stmt.HavingExpr))
+	}
+
 	// If there is no FROM clause ensure there is no wildcard or path
 	if stmt.TableName == "" {
 		var err error
@@ -142,14 +205,336 @@ func (stmt *SelectCoreStmt) Prepare(*Context) (*StreamStmt, error) {
 	}, nil
 }
 
+// prepareHaving ensures the HAVING clause only references the GROUP BY
+// expressions and aggregate functions. The aggregate functions that are not
+// already computed are appended to aggregators.
+func (stmt *SelectCoreStmt) prepareHaving(aggregators []expr.AggregatorBuilder) ([]expr.AggregatorBuilder, error) {
+	if stmt.HavingExpr == nil {
+		return aggregators, nil
+	}
+
+	var err error
+	stmt.HavingExpr, err = groupedExpr(stmt.HavingExpr, stmt.GroupByExprs, &aggregators)
+	return aggregators, err
+}
+
+// groupedExpr returns an expression that can be evaluated against the rows
+// returned by the aggregation: the occurrences of the GROUP BY expressions are
+// replaced by a path to their group column, and the aggregate functions are added to aggregators.
+func groupedExpr(e expr.Expr, groupBy []expr.Expr, aggregators *[]expr.AggregatorBuilder) (expr.Expr, error) {
+	if i := groupByIndex(e, groupBy); i != -1 {
+		return expr.Path(object.NewPath(groupBy[i].String())), nil
+	}
+
+	switch t := e.(type) {
+	case expr.AggregatorBuilder:
+		for _, agg := range *aggregators {
+			if expr.Equal(agg, t) {
+				return e, nil
+			}
+		}
+		*aggregators = append(*aggregators, t)
+		return e, nil
+	case expr.Path, expr.Wildcard:
+		return nil, fmt.Errorf("field %q must appear in the GROUP BY clause or be used in an aggregate function", e)
+	case expr.Parentheses:
+		inner, err := groupedExpr(t.E, groupBy, aggregators)
+		if err != nil {
+			return nil, err
+		}
+		return expr.Parentheses{E: inner}, nil
+	case *expr.BetweenOperator:
+		x, err := groupedExpr(t.X, groupBy, aggregators)
+		if err != nil {
+			return nil, err
+		}
+		t.X = x
+	case expr.LiteralExprList:
+		for i := range t {
+			le, err := groupedExpr(t[i], groupBy, aggregators)
+			if err != nil {
+				return nil, err
+			}
+			t[i] = le
+		}
+		return t, nil
+	case expr.Cast:
+		inner, err := groupedExpr(t.Expr, groupBy, aggregators)
+		if err != nil {
+			return nil, err
+		}
+		t.Expr = inner
+		return t, nil
+	case expr.Function:
+		params := t.Params()
+		grouped := make([]expr.Expr, len(params))
+		for i, p := range params {
+			var err error
+			grouped[i], err = groupedExpr(p, groupBy, aggregators)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if ps, ok := t.(paramsSetter); ok && len(grouped) > 0 {
+			ps.SetParams(grouped...)
+		}
+		return e, nil
+	}
+
+	if op, ok := e.(expr.Operator); ok {
+		lh, err := groupedExpr(op.LeftHand(), groupBy, aggregators)
+		if err != nil {
+			return nil, err
+		}
+		rh, err := groupedExpr(op.RightHand(), groupBy, aggregators)
+		if err != nil {
+			return nil, err
+		}
+		op.SetLeftHandExpr(lh)
+		op.SetRightHandExpr(rh)
+	}
+
+	return e, nil
+}
+
+// a paramsSetter is a function whose parameters can be replaced.
+type paramsSetter interface {
+	SetParams(params ...expr.Expr)
+}
+
+// groupByIndex returns the position of e in the list of GROUP BY expressions,
+// or -1 if it's not one of them.
+func groupByIndex(e expr.Expr, groupBy []expr.Expr) int {
+	for i, g := range groupBy {
+		if expr.Equal(e, g) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// windowExprs returns the list of distinct window functions used by the projection.
+// Window functions are computed once the rows are filtered and thus cannot be used
+// in the other clauses, nor be combined with an aggregation.
+func (stmt *SelectCoreStmt) windowExprs() ([]*expr.Over, error) {
+	if containsWindow(stmt.WhereExpr) {
+		return nil, errors.New("window functions are not allowed in WHERE")
+	}
+	if containsWindow(stmt.HavingExpr) {
+		return nil, errors.New("window functions are not allowed in HAVING")
+	}
+	for _, e := range stmt.GroupByExprs {
+		if containsWindow(e) {
+			return nil, errors.New("window functions are not allowed in GROUP BY")
+		}
+	}
+	for _, j := range stmt.Joins {
+		if containsWindow(j.On) {
+			return nil, errors.New("window functions are not allowed in JOIN conditions")
+		}
+	}
+
+	var windows []*expr.Over
+	var aggregated bool
+	for _, pe := range stmt.ProjectionExprs {
+		if ne, ok := pe.(*expr.NamedExpr); ok {
+			if _, ok := ne.Expr.(expr.AggregatorBuilder); ok {
+				aggregated = true
+			}
+		}
+
+		expr.Walk(pe, func(e expr.Expr) bool {
+			o, ok := e.(*expr.Over)
+			if !ok {
+				return true
+			}
+
+			for _, w := range windows {
+				if w.IsEqual(o) {
+					return false
+				}
+			}
+			windows = append(windows, o)
+			return false
+		})
+	}
+
+	if len(windows) > 0 && (aggregated || len(stmt.GroupByExprs) > 0 || stmt.HavingExpr != nil) {
+		return nil, errors.New("window functions cannot be used along with aggregate functions or GROUP BY")
+	}
+
+	return windows, nil
+}
+
+// containsWindow returns true if the expression contains a window function.
+func containsWindow(e expr.Expr) bool {
+	var found bool
+	expr.Walk(e, func(e expr.Expr) bool {
+		_, found = e.(*expr.Over)
+		return !found
+	})
+
+	return found
+}
+
+// pipeWindows adds a window operator for each window definition,
+// preceded by a sort on the partition and the order of the window.
+func pipeWindows(s *stream.Stream, windows []*expr.Over) *stream.Stream {
+	var defs []*expr.WindowDefinition
+	groups := make(map[*expr.WindowDefinition][]*expr.Over)
+
+	for _, o := range windows {
+		def := o.Window
+		for _, d := range defs {
+			if d.IsEqual(def) {
+				def = d
+				break
+			}
+		}
+		if _, ok := groups[def]; !ok {
+			defs = append(defs, def)
+		}
+		groups[def] = append(groups[def], o)
+	}
+
+	for _, def := range defs {
+		exprs := append([]expr.Expr{}, def.PartitionBy...)
+		exprs = append(exprs, def.OrderBy...)
+
+		if len(exprs) > 0 {
+			var order tree.SortOrder
+			for i := range def.OrderBy {
+				if def.OrderBySortOrder.IsDesc(i) {
+					order = order.SetDesc(len(def.PartitionBy) + i)
+				}
+			}
+
+			s = s.Pipe(rows.TempTreeSortWithOrder(exprs, order))
+		}
+
+		s = s.Pipe(rows.Window(def, groups[def]...))
+	}
+
+	return s
+}
+
+// checkSourceNames ensures that every table of the FROM clause
+// can be referenced by a unique name.
+func (stmt *SelectCoreStmt) checkSourceNames() error {
+	return checkSourceNames(sourceName(stmt.TableName, stmt.TableAlias), stmt.Joins)
+}
+
+// checkSourceNames returns an error if the name of the first table
+// and the names of the joined tables are not all distinct.
+func checkSourceNames(first string, joins []*JoinClause) error {
+	names := map[string]struct{}{
+		first: {},
+	}
+
+	for _, j := range joins {
+		name := sourceName(j.TableName, j.Alias)
+		if _, ok := names[name]; ok {
+			return fmt.Errorf("table name %q specified more than once", name)
+		}
+		names[name] = struct{}{}
+	}
+
+	return nil
+}
+
+// sourceNames returns the names referencing the tables of the FROM clause.
+func (stmt *SelectCoreStmt) sourceNames() map[string]struct{} {
+	names := make(map[string]struct{})
+
+	if stmt.TableName != "" {
+		names[sourceName(stmt.TableName, stmt.TableAlias)] = struct{}{}
+	}
+
+	for _, j := range stmt.Joins {
+		names[sourceName(j.TableName, j.Alias)] = struct{}{}
+	}
+
+	return names
+}
+
+// exprs returns every expression of the statement.
+func (stmt *SelectCoreStmt) exprs() []expr.Expr {
+	exprs := append([]expr.Expr{stmt.WhereExpr, stmt.HavingExpr}, stmt.GroupByExprs...)
+	exprs = append(exprs, stmt.ProjectionExprs...)
+
+	for _, j := range stmt.Joins {
+		exprs = append(exprs, j.On)
+	}
+
+	return exprs
+}
+
+func sourceName(tableName, alias string) string {
+	if alias != "" {
+		return alias
+	}
+
+	return tableName
+}
+
+// pipeJoins joins the rows of s with the rows of the source of each join clause.
+func pipeJoins(ctx *Context, s *stream.Stream, joins []*JoinClause) (*stream.Stream, error) {
+	for _, j := range joins {
+		scan, common, err := ctx.scan(j.TableName)
+		if err != nil {
+			return nil, err
+		}
+		right := stream.New(scan)
+
+		// rows of common tables and views don't belong to any table,
+		// they must be named to be referenced
+		alias := j.Alias
+		if common {
+			alias = sourceName(j.TableName, j.Alias)
+		}
+
+		if j.Kind == scanner.LEFT {
+			s = s.Pipe(join.LeftNestedLoop(right, alias, j.On))
+		} else {
+			s = s.Pipe(join.NestedLoop(right, alias, j.On))
+		}
+	}
+
+	return s, nil
+}
+
+// A JoinClause joins the rows of a table with the rows
+// of the FROM clause.
+type JoinClause struct {
+	TableName string
+	Alias     string
+	On        expr.Expr
+	// Kind is either scanner.INNER or scanner.LEFT.
+	Kind scanner.Token
+}
+
+// A CompoundOperator combines the results of two SELECT statements.
+type CompoundOperator struct {
+	// Token is either scanner.UNION, scanner.INTERSECT or scanner.EXCEPT.
+	Token scanner.Token
+	// All keeps duplicate rows.
+	All bool
+}
+
 // SelectStmt holds SELECT configuration.
 type SelectStmt struct {
 	basePreparedStatement
 
+	// With holds the common table expressions of the WITH clause.
+	With []*CommonTableExpr
+	// Recursive is true if the common table expressions
+	// are allowed to reference themselves (WITH RECURSIVE).
+	Recursive         bool
 	CompoundSelect    []*SelectCoreStmt
-	CompoundOperators []scanner.Token
-	OrderBy           expr.Path
-	OrderByDirection  scanner.Token
+	CompoundOperators []CompoundOperator
+	OrderBy           []expr.Expr
+	OrderBySortOrder  tree.SortOrder
 	OffsetExpr        expr.Expr
 	LimitExpr         expr.Expr
 }
@@ -169,21 +554,21 @@ func NewSelectStatement() *SelectStmt {
 func (stmt *SelectStmt) Prepare(ctx *Context) (Statement, error) {
 	var s *stream.Stream
 
-	var prev scanner.Token
-
 	var coreStmts []*stream.Stream
 	var readOnly bool = true
 
-	for i, coreSelect := range stmt.CompoundSelect {
-		coreStmt, err := coreSelect.Prepare(ctx)
+	if len(stmt.With) > 0 {
+		var err error
+		ctx, readOnly, err = stmt.prepareCommonTables(ctx)
 		if err != nil {
 			return nil, err
 		}
+	}
 
-		if len(stmt.CompoundSelect) == 1 {
-			s = coreStmt.Stream
-			readOnly = coreStmt.ReadOnly
-			break
+	for _, coreSelect := range stmt.CompoundSelect {
+		coreStmt, err := coreSelect.Prepare(ctx)
+		if err != nil {
+			return nil, err
 		}
 
 		coreStmts = append(coreStmts, coreStmt.Stream)
@@ -191,32 +576,38 @@ func (stmt *SelectStmt) Prepare(ctx *Context) (Statement, error) {
 		if !coreStmt.ReadOnly {
 			readOnly = false
 		}
+	}
 
-		var tok scanner.Token
-		if i < len(stmt.CompoundOperators) {
-			tok = stmt.CompoundOperators[i]
-		}
-
-		if prev != 0 && prev != tok {
-			switch prev {
-			case scanner.UNION:
-				s = stream.New(stream.Union(coreStmts...))
-			case scanner.ALL:
-				s = stream.New(stream.Concat(coreStmts...))
-			}
+	err := stmt.checkColumnCounts(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-			coreStmts = []*stream.Stream{s}
+	// INTERSECT binds tighter than UNION and EXCEPT:
+	// consecutive intersected statements are combined first
+	var terms []*stream.Stream
+	var termOps []CompoundOperator
+	for i := 0; i < len(coreStmts); {
+		j := i
+		for j < len(stmt.CompoundOperators) && stmt.CompoundOperators[j].Token == scanner.INTERSECT {
+			j++
 		}
 
-		prev = tok
+		terms = append(terms, compoundStream(coreStmts[i:j+1], stmt.CompoundOperators[i:j]))
+		if j < len(stmt.CompoundOperators) {
+			termOps = append(termOps, stmt.CompoundOperators[j])
+		}
+		i = j + 1
 	}
+	s = compoundStream(terms, termOps)
 
 	if stmt.OrderBy != nil {
-		if stmt.OrderByDirection == scanner.DESC {
-			s = s.Pipe(rows.TempTreeSortReverse(stmt.OrderBy))
-		} else {
-			s = s.Pipe(rows.TempTreeSort(stmt.OrderBy))
+		err := prepareSubqueries(ctx, stmt.OrderBy...)
+		if err != nil {
+			return nil, err
 		}
+
+		s = s.Pipe(rows.TempTreeSortWithOrder(stmt.OrderBy, stmt.OrderBySortOrder))
 	}
 
 	if stmt.OffsetExpr != nil {
@@ -234,3 +625,89 @@ func (stmt *SelectStmt) Prepare(ctx *Context) (Statement, error) {
 
 	return st.Prepare(ctx)
 }
+
+// checkColumnCounts ensures the statements combined by compound operators
+// return the same number of columns. Statements whose number of columns
+// is not known before running them are not checked.
+func (stmt *SelectStmt) checkColumnCounts(ctx *Context) error {
+	want := -1
+	for i, core := range stmt.CompoundSelect {
+		n, ok, err := core.columnCount(ctx)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+
+		if want == -1 {
+			want = n
+			continue
+		}
+
+		if n != want {
+			return fmt.Errorf("each %s query must have the same number of columns", stmt.CompoundOperators[i-1].Token)
+		}
+	}
+
+	return nil
+}
+
+// columnCount returns the number of columns returned by the statement.
+// It returns false if it is not known before running the statement,
+// i.e. if it selects every column of a source whose columns are not known.
+func (stmt *SelectCoreStmt) columnCount(ctx *Context) (int, bool, error) {
+	var n int
+	for _, e := range stmt.ProjectionExprs {
+		if _, ok := e.(expr.Wildcard); !ok {
+			n++
+			continue
+		}
+
+		// the columns of joined rows may be merged
+		if len(stmt.Joins) > 0 {
+			return 0, false, nil
+		}
+
+		columns, ok, err := stmt.columns(ctx, nil)
+		if err != nil || !ok {
+			return 0, false, err
+		}
+		n += len(columns)
+	}
+
+	return n, true, nil
+}
+
+// compoundStream combines the streams using the compound operators,
+// from left to right. Consecutive identical operators are merged into one.
+func compoundStream(streams []*stream.Stream, ops []CompoundOperator) *stream.Stream {
+	s := streams[0]
+	group := []*stream.Stream{s}
+
+	for i, op := range ops {
+		group = append(group, streams[i+1])
+		if i+1 < len(ops) && ops[i+1] == op {
+			continue
+		}
+
+		switch {
+		case op.Token == scanner.UNION && op.All:
+			s = stream.New(stream.Concat(group...))
+		case op.Token == scanner.UNION:
+			s = stream.New(stream.Union(group...))
+		case op.Token == scanner.INTERSECT && op.All:
+			s = stream.New(stream.IntersectAll(group...))
+		case op.Token == scanner.INTERSECT:
+			s = stream.New(stream.Intersect(group...))
+		case op.Token == scanner.EXCEPT && op.All:
+			s = stream.New(stream.ExceptAll(group...))
+		case op.Token == scanner.EXCEPT:
+			s = stream.New(stream.Except(group...))
+		}
+
+		group = []*stream.Stream{s}
+	}
+
+	return s
+}
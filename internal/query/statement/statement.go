@@ -34,6 +34,11 @@ type Context struct {
 	DB     *database.Database
 	Tx     *database.Transaction
 	Params []environment.Param
+
+	// common tables visible to the statement being prepared
+	commonTables []commonTableRef
+	// names of the triggers the statement being prepared is part of
+	triggers []string
 }
 
 type Preparer interface {
@@ -64,6 +64,7 @@ func (s *StreamStmtIterator) Iterate(fn func(r database.Row) error) error {
 	env.DB = s.Context.DB
 	env.Tx = s.Context.Tx
 	env.SetParams(s.Context.Params)
+	defer env.Close()
 
 	err := s.Stream.Iterate(&env, func(env *environment.Environment) error {
 		// if there is no row in this specific environment,
@@ -0,0 +1,458 @@
+package statement
+
+import (
+	"fmt"
+
+	"github.com/chaisql/chai/internal/database"
+	"github.com/chaisql/chai/internal/environment"
+	errs "github.com/chaisql/chai/internal/errors"
+	"github.com/chaisql/chai/internal/expr"
+	"github.com/chaisql/chai/internal/object"
+	"github.com/chaisql/chai/internal/stream"
+	"github.com/chaisql/chai/internal/stream/join"
+	"github.com/chaisql/chai/internal/types"
+	"github.com/cockroachdb/errors"
+)
+
+// subquery is a SELECT statement used within an expression.
+// It is prepared along with the statement that contains it and
+// is run every time the expression is evaluated.
+//
+// A subquery can reference the tables of the enclosing statement
+// using qualified columns, i.e. "table.column" or "alias.column",
+// or using the name of a column that none of its tables has.
+// These references are resolved against the row being evaluated
+// by the enclosing statement.
+type subquery struct {
+	Stmt *SelectStmt
+
+	stream *stream.Stream
+	// names of the tables of the enclosing statements
+	// referenced by the subquery.
+	outer []string
+	// names of the columns of the enclosing statements
+	// referenced by the subquery without being qualified.
+	columns []string
+}
+
+// Prepare the subquery statement. It must be called before evaluating the subquery.
+func (s *subquery) Prepare(ctx *Context) error {
+	st, err := s.Stmt.Prepare(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.stream = st.(*PreparedStreamStmt).Stream
+	s.outer = s.Stmt.outerNames()
+	s.columns, err = s.Stmt.outerColumns(ctx)
+	return err
+}
+
+// statement returns the SELECT statement of the subquery.
+func (s *subquery) statement() *SelectStmt {
+	return s.Stmt
+}
+
+// outerNames returns the names of the tables of the enclosing statements
+// referenced by the subquery.
+func (s *subquery) outerNames() []string {
+	return s.outer
+}
+
+// outerColumns returns the names of the columns of the enclosing statements
+// referenced by the subquery without being qualified.
+func (s *subquery) outerColumns() []string {
+	return s.columns
+}
+
+// IsCorrelated returns true if the subquery references
+// the tables of the enclosing statement.
+func (s *subquery) IsCorrelated() bool {
+	return len(s.outer) > 0 || len(s.columns) > 0
+}
+
+// iterate runs the subquery for the row of the given environment.
+func (s *subquery) iterate(env *environment.Environment, fn func(r database.Row) error) error {
+	if s.stream == nil {
+		return errors.New("subquery not prepared")
+	}
+
+	var newEnv environment.Environment
+	newEnv.SetOuter(env)
+
+	// bind the referenced tables and columns of the enclosing statement
+	// so that they can be resolved
+	if r, ok := env.GetRow(); ok && s.IsCorrelated() {
+		vars := object.NewFieldBuffer()
+		for _, name := range s.outer {
+			if v, ok := sourceValue(r, name); ok {
+				vars.Add(name, v)
+			}
+		}
+		for _, name := range s.columns {
+			v, err := r.Get(name)
+			if errors.Is(err, types.ErrFieldNotFound) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			vars.Add(name, v)
+		}
+		newEnv.Vars = vars
+	}
+
+	err := s.stream.Iterate(&newEnv, func(out *environment.Environment) error {
+		r, ok := out.GetRow()
+		if !ok {
+			return nil
+		}
+
+		return fn(r)
+	})
+	if errors.Is(err, stream.ErrStreamClosed) {
+		err = nil
+	}
+	return err
+}
+
+func (s *subquery) String() string {
+	if s.stream == nil {
+		return "(SELECT ...)"
+	}
+
+	return fmt.Sprintf("(%s)", s.stream)
+}
+
+// sourceValue returns the value of the source row referenced by name.
+func sourceValue(r database.Row, name string) (types.Value, bool) {
+	if jr, ok := r.(*join.Row); ok {
+		src, ok := jr.Source(name)
+		if !ok {
+			return nil, false
+		}
+		if src == nil {
+			return types.NewNullValue(), true
+		}
+
+		return types.NewObjectValue(src.Object()), true
+	}
+
+	if r.TableName() != name {
+		return nil, false
+	}
+
+	return types.NewObjectValue(r.Object()), true
+}
+
+// firstColumn returns the value of the first column of r.
+// If r doesn't have any column, it returns NULL.
+func firstColumn(r database.Row) (types.Value, error) {
+	var v types.Value
+	var n int
+	err := r.Iterate(func(column string, value types.Value) error {
+		if n > 0 {
+			return errors.New("subquery must return only one column")
+		}
+		v = value
+		n++
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if v == nil {
+		return types.NewNullValue(), nil
+	}
+
+	return v, nil
+}
+
+// A ScalarSubquery is a subquery that evaluates to the first column
+// of the only row it returns, or NULL if it doesn't return any row.
+type ScalarSubquery struct {
+	subquery
+}
+
+// NewScalarSubquery creates a ScalarSubquery from a SELECT statement.
+func NewScalarSubquery(stmt *SelectStmt) *ScalarSubquery {
+	return &ScalarSubquery{subquery{Stmt: stmt}}
+}
+
+// Eval runs the subquery and returns the value of its first column.
+// It returns an error if the subquery returns more than one row.
+func (s *ScalarSubquery) Eval(env *environment.Environment) (types.Value, error) {
+	var v types.Value
+
+	err := s.iterate(env, func(r database.Row) error {
+		if v != nil {
+			return errors.New("more than one row returned by a subquery used as an expression")
+		}
+
+		var err error
+		v, err = firstColumn(r)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if v == nil {
+		return types.NewNullValue(), nil
+	}
+
+	return v, nil
+}
+
+// An ArraySubquery is a subquery that evaluates to an array
+// containing the first column of every row it returns.
+// It is used as the right operand of the IN operator.
+type ArraySubquery struct {
+	subquery
+}
+
+// NewArraySubquery creates an ArraySubquery from a SELECT statement.
+func NewArraySubquery(stmt *SelectStmt) *ArraySubquery {
+	return &ArraySubquery{subquery{Stmt: stmt}}
+}
+
+// Eval runs the subquery and returns the list of values of its first column.
+func (s *ArraySubquery) Eval(env *environment.Environment) (types.Value, error) {
+	vb := object.NewValueBuffer()
+
+	err := s.iterate(env, func(r database.Row) error {
+		v, err := firstColumn(r)
+		if err != nil {
+			return err
+		}
+
+		vb.Append(v)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return types.NewArrayValue(vb), nil
+}
+
+// An ExistsSubquery evaluates to true if its subquery returns at least one row.
+type ExistsSubquery struct {
+	subquery
+}
+
+// NewExistsSubquery creates an ExistsSubquery from a SELECT statement.
+func NewExistsSubquery(stmt *SelectStmt) *ExistsSubquery {
+	return &ExistsSubquery{subquery{Stmt: stmt}}
+}
+
+// Eval runs the subquery until it returns a row.
+func (s *ExistsSubquery) Eval(env *environment.Environment) (types.Value, error) {
+	var found bool
+
+	err := s.iterate(env, func(r database.Row) error {
+		found = true
+		return errors.WithStack(stream.ErrStreamClosed)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return types.NewBooleanValue(found), nil
+}
+
+func (s *ExistsSubquery) String() string {
+	return "EXISTS " + s.subquery.String()
+}
+
+// prepareSubqueries prepares every subquery found in the given expressions.
+func prepareSubqueries(ctx *Context, exprs ...expr.Expr) error {
+	var err error
+
+	for _, e := range exprs {
+		expr.Walk(e, func(e expr.Expr) bool {
+			if p, ok := e.(interface{ Prepare(*Context) error }); ok {
+				err = p.Prepare(ctx)
+			}
+
+			return err == nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// outerNames returns the list of table names the statement references
+// without declaring them in one of its FROM clauses.
+func (stmt *SelectStmt) outerNames() []string {
+	var names []string
+	seen := make(map[string]struct{})
+
+	add := func(name string) {
+		if _, ok := seen[name]; ok {
+			return
+		}
+		seen[name] = struct{}{}
+		names = append(names, name)
+	}
+
+	// common tables may reference the enclosing tables
+	for _, cte := range stmt.With {
+		for _, name := range cte.Stmt.outerNames() {
+			add(name)
+		}
+	}
+
+	for _, core := range stmt.CompoundSelect {
+		declared := core.sourceNames()
+
+		for _, e := range core.exprs() {
+			expr.Walk(e, func(e expr.Expr) bool {
+				switch t := e.(type) {
+				case expr.Path:
+					if len(t) > 1 && t[0].FieldName != "" {
+						if _, ok := declared[t[0].FieldName]; !ok {
+							add(t[0].FieldName)
+						}
+					}
+				case interface{ outerNames() []string }:
+					// nested subqueries may reference the enclosing tables
+					for _, name := range t.outerNames() {
+						if _, ok := declared[name]; !ok {
+							add(name)
+						}
+					}
+				}
+
+				return true
+			})
+		}
+	}
+
+	return names
+}
+
+// outerColumns returns the list of columns the statement references
+// without qualifying them, and that none of the tables of its FROM clauses has.
+// The columns of views, common tables and tables allowing extra columns
+// are not known before running the statement: the columns referenced
+// by a query reading one of them are always resolved against its own rows.
+func (stmt *SelectStmt) outerColumns(ctx *Context) ([]string, error) {
+	var names []string
+	seen := make(map[string]struct{})
+
+	add := func(name string) {
+		if _, ok := seen[name]; ok {
+			return
+		}
+		seen[name] = struct{}{}
+		names = append(names, name)
+	}
+
+	// the common tables of the statement are not visible in ctx yet
+	commonTables := make(map[string]struct{}, len(stmt.With))
+	for _, cte := range stmt.With {
+		commonTables[cte.Name] = struct{}{}
+
+		// and they may reference the enclosing columns
+		outer, err := cte.Stmt.outerColumns(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range outer {
+			add(name)
+		}
+	}
+
+	for _, core := range stmt.CompoundSelect {
+		columns, ok, err := core.columns(ctx, commonTables)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		declared := core.sourceNames()
+
+		for _, e := range core.exprs() {
+			expr.Walk(e, func(e expr.Expr) bool {
+				switch t := e.(type) {
+				case expr.Path:
+					if len(t) == 1 && t[0].FieldName != "" {
+						_, isColumn := columns[t[0].FieldName]
+						_, isSource := declared[t[0].FieldName]
+						if !isColumn && !isSource {
+							add(t[0].FieldName)
+						}
+					}
+				case interface{ outerColumns() []string }:
+					// nested subqueries may reference the enclosing columns
+					for _, name := range t.outerColumns() {
+						if _, ok := columns[name]; !ok {
+							add(name)
+						}
+					}
+				}
+
+				return true
+			})
+		}
+	}
+
+	return names, nil
+}
+
+// columns returns the names of the columns of the tables of the FROM clause
+// of the statement. It returns false if they are not all known.
+func (stmt *SelectCoreStmt) columns(ctx *Context, commonTables map[string]struct{}) (map[string]struct{}, bool, error) {
+	columns := make(map[string]struct{})
+
+	sources := make([]string, 0, len(stmt.Joins)+1)
+	if stmt.TableName != "" {
+		sources = append(sources, stmt.TableName)
+	}
+	for _, j := range stmt.Joins {
+		sources = append(sources, j.TableName)
+	}
+
+	for _, name := range sources {
+		if _, ok := commonTables[name]; ok {
+			return nil, false, nil
+		}
+		for _, ref := range ctx.commonTables {
+			if ref.table.Name == name {
+				return nil, false, nil
+			}
+		}
+
+		_, err := ctx.Tx.Catalog.GetViewInfo(name)
+		if err == nil {
+			return nil, false, nil
+		}
+		if !errs.IsNotFoundError(err) {
+			return nil, false, err
+		}
+
+		// the common tables preceding the one being prepared are not visible in ctx
+		info, err := ctx.Tx.Catalog.GetTableInfo(name)
+		if errs.IsNotFoundError(err) {
+			return nil, false, nil
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		if info.FieldConstraints.AllowExtraFields {
+			return nil, false, nil
+		}
+
+		for _, fc := range info.FieldConstraints.Ordered {
+			columns[fc.Field] = struct{}{}
+		}
+	}
+
+	return columns, true, nil
+}
@@ -0,0 +1,123 @@
+package statement
+
+import (
+	"fmt"
+
+	"github.com/chaisql/chai/internal/database"
+	"github.com/chaisql/chai/internal/expr"
+	"github.com/chaisql/chai/internal/stream"
+	"github.com/chaisql/chai/internal/stream/table"
+)
+
+// TriggerAction is the WHEN condition and the statements of a trigger.
+// Like the query of a view, it is parsed again every time the trigger is prepared.
+type TriggerAction struct {
+	Text  string
+	Parse func(text string) (*TriggerBody, error)
+}
+
+// Body parses the action and returns new statements.
+func (a *TriggerAction) Body() (*TriggerBody, error) {
+	return a.Parse(a.Text)
+}
+
+func (a *TriggerAction) String() string {
+	return a.Text
+}
+
+// TriggerBody is the parsed action of a trigger.
+type TriggerBody struct {
+	// When must evaluate to true for the statements to be executed, if set.
+	When       expr.Expr
+	Statements []Statement
+}
+
+// pipeTriggers pipes an operator executing the triggers of the table
+// for the given event, if the table has any.
+// Triggers are not recursive: a trigger modifying its own table,
+// directly or through other triggers, isn't executed again.
+func pipeTriggers(ctx *Context, s *stream.Stream, tableName string, event database.TriggerEvent) (*stream.Stream, error) {
+	var triggers []*table.PreparedTrigger
+
+	for _, info := range ctx.Tx.Catalog.ListTriggers(tableName, event) {
+		if ctx.isTriggerPrepared(info.TriggerName) {
+			continue
+		}
+
+		t, err := ctx.prepareTrigger(info)
+		if err != nil {
+			return nil, err
+		}
+
+		triggers = append(triggers, t)
+	}
+
+	if len(triggers) == 0 {
+		return s, nil
+	}
+
+	return s.Pipe(table.Trigger(tableName, event, triggers...)), nil
+}
+
+// isTriggerPrepared returns true if the statement being prepared
+// is part of the given trigger.
+func (ctx *Context) isTriggerPrepared(name string) bool {
+	for _, n := range ctx.triggers {
+		if n == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// prepareTrigger prepares the action of the given trigger.
+func (ctx *Context) prepareTrigger(info *database.TriggerInfo) (*table.PreparedTrigger, error) {
+	a, ok := info.Action.(*TriggerAction)
+	if !ok {
+		return nil, fmt.Errorf("unexpected action type %T for trigger %q", info.Action, info.TriggerName)
+	}
+
+	body, err := a.Body()
+	if err != nil {
+		return nil, err
+	}
+
+	// the statements of the trigger are independent of the statement executing it
+	c := *ctx
+	c.Params = nil
+	c.commonTables = nil
+	c.triggers = append(ctx.triggers[:len(ctx.triggers):len(ctx.triggers)], info.TriggerName)
+
+	err = prepareSubqueries(&c, body.When)
+	if err != nil {
+		return nil, err
+	}
+
+	t := table.PreparedTrigger{
+		Name:   info.TriggerName,
+		Timing: info.Timing,
+		When:   body.When,
+	}
+
+	for _, stmt := range body.Statements {
+		p, ok := stmt.(Preparer)
+		if !ok {
+			return nil, fmt.Errorf("unexpected statement type %T in trigger %q", stmt, info.TriggerName)
+		}
+
+		st, err := p.Prepare(&c)
+		if err != nil {
+			return nil, err
+		}
+
+		ps, ok := st.(*PreparedStreamStmt)
+		if !ok {
+			return nil, fmt.Errorf("unexpected statement type %T in trigger %q", st, info.TriggerName)
+		}
+
+		t.Statements = append(t.Statements, ps.Stream)
+	}
+
+	return &t, nil
+}
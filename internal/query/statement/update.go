@@ -1,10 +1,12 @@
 package statement
 
 import (
+	"github.com/chaisql/chai/internal/database"
 	"github.com/chaisql/chai/internal/expr"
 	"github.com/chaisql/chai/internal/object"
 	"github.com/chaisql/chai/internal/stream"
 	"github.com/chaisql/chai/internal/stream/index"
+	"github.com/chaisql/chai/internal/stream/join"
 	"github.com/chaisql/chai/internal/stream/path"
 	"github.com/chaisql/chai/internal/stream/rows"
 	"github.com/chaisql/chai/internal/stream/table"
@@ -26,7 +28,15 @@ type UpdateStmt struct {
 	// each path that should be unset from the object.
 	UnsetFields []string
 
+	// From holds the tables joined with the updated table, i.e. "FROM other".
+	// The first table is joined without condition, the WHERE clause
+	// being evaluated against the joined rows.
+	From []*JoinClause
+
 	WhereExpr expr.Expr
+
+	// Returning is evaluated against the updated rows.
+	Returning []expr.Expr
 }
 
 func NewUpdateStatement() *UpdateStmt {
@@ -53,27 +63,24 @@ func (stmt *UpdateStmt) Prepare(c *Context) (Statement, error) {
 	}
 	pk := ti.PrimaryKey
 
-	s := stream.New(table.Scan(stmt.TableName))
+	exprs := []expr.Expr{stmt.WhereExpr}
+	for _, pair := range stmt.SetPairs {
+		exprs = append(exprs, pair.E)
+	}
+	exprs = append(exprs, stmt.Returning...)
+	err = prepareSubqueries(c, exprs...)
+	if err != nil {
+		return nil, err
+	}
 
-	if stmt.WhereExpr != nil {
-		s = s.Pipe(rows.Filter(stmt.WhereExpr))
+	s, err := prepareTargetScan(c, stmt.TableName, stmt.From, stmt.WhereExpr)
+	if err != nil {
+		return nil, err
 	}
 
 	var pkModified bool
 	if stmt.SetPairs != nil {
-		for _, pair := range stmt.SetPairs {
-			// if we modify the primary key,
-			// we must remove the old row and create an new one
-			if pk != nil && !pkModified {
-				for _, p := range pk.Paths {
-					if p.IsEqual(pair.Path) {
-						pkModified = true
-						break
-					}
-				}
-			}
-			s = s.Pipe(path.Set(pair.Path, pair.E))
-		}
+		s, pkModified = pipeSetPairs(s, ti, stmt.SetPairs)
 	} else if stmt.UnsetFields != nil {
 		for _, name := range stmt.UnsetFields {
 			// ensure we do not unset any path the is used in the primary key
@@ -89,22 +96,124 @@ func (stmt *UpdateStmt) Prepare(c *Context) (Statement, error) {
 		}
 	}
 
+	s, err = pipeReplace(c, s, stmt.TableName, pkModified)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(stmt.Returning) > 0 {
+		s = s.Pipe(rows.Project(stmt.Returning...))
+	} else {
+		s = s.Pipe(stream.Discard())
+	}
+
+	st := StreamStmt{
+		Stream:   s,
+		ReadOnly: false,
+	}
+
+	return st.Prepare(c)
+}
+
+// prepareTargetScan returns a stream of the rows of the table modified by
+// an UPDATE or a DELETE statement that match the WHERE clause.
+// If the statement references other tables, the rows of the table
+// are joined with them before being filtered, then each joined row
+// is replaced by the row of the table.
+func prepareTargetScan(c *Context, tableName string, joins []*JoinClause, where expr.Expr) (*stream.Stream, error) {
+	s := stream.New(table.Scan(tableName))
+
+	if len(joins) > 0 {
+		err := checkSourceNames(tableName, joins)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, j := range joins {
+			err = prepareSubqueries(c, j.On)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		// with a single joined table, the WHERE clause is used as the join condition,
+		// allowing the planner to lookup the joined rows using an index
+		if len(joins) == 1 && where != nil {
+			j := *joins[0]
+			j.On = where
+			joins = []*JoinClause{&j}
+			where = nil
+		}
+
+		s, err = pipeJoins(c, s, joins)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if where != nil {
+		s = s.Pipe(rows.Filter(where))
+	}
+
+	if len(joins) > 0 {
+		s = s.Pipe(join.Target(tableName))
+	}
+
+	return s, nil
+}
+
+// pipeSetPairs sets the paths of the rows of the stream.
+// It also returns whether the primary key of the table is modified.
+func pipeSetPairs(s *stream.Stream, ti *database.TableInfo, pairs []UpdateSetPair) (*stream.Stream, bool) {
+	pk := ti.PrimaryKey
+
+	var pkModified bool
+	for _, pair := range pairs {
+		// if we modify the primary key,
+		// we must remove the old row and create an new one
+		if pk != nil && !pkModified {
+			for _, p := range pk.Paths {
+				if p.IsEqual(pair.Path) {
+					pkModified = true
+					break
+				}
+			}
+		}
+		s = s.Pipe(path.Set(pair.Path, pair.E))
+	}
+
+	return s, pkModified
+}
+
+// pipeReplace validates the rows of the stream and replaces
+// the rows of the table with the same key, updating the indexes.
+func pipeReplace(c *Context, s *stream.Stream, tableName string, pkModified bool) (*stream.Stream, error) {
 	// validate row
-	s = s.Pipe(table.Validate(stmt.TableName))
+	s = s.Pipe(table.Validate(tableName))
+
+	s, err := pipeTriggers(c, s, tableName, database.TriggerUpdate)
+	if err != nil {
+		return nil, err
+	}
+
+	// apply the ON UPDATE actions of the foreign keys referencing the table
+	if len(c.Tx.Catalog.ListReferences(tableName)) > 0 {
+		s = s.Pipe(table.OnUpdate(tableName))
+	}
 
 	// TODO(asdine): This removes ALL indexed fields for each row
 	// even if the update modified a single field. We should only
 	// update the indexed fields that were modified.
-	indexNames := c.Tx.Catalog.ListIndexes(stmt.TableName)
+	indexNames := c.Tx.Catalog.ListIndexes(tableName)
 	for _, indexName := range indexNames {
 		s = s.Pipe(index.Delete(indexName))
 	}
 
 	if pkModified {
-		s = s.Pipe(table.Delete(stmt.TableName))
-		s = s.Pipe(table.Insert(stmt.TableName))
+		s = s.Pipe(table.Delete(tableName))
+		s = s.Pipe(table.Insert(tableName))
 	} else {
-		s = s.Pipe(table.Replace(stmt.TableName))
+		s = s.Pipe(table.Replace(tableName))
 	}
 
 	for _, indexName := range indexNames {
@@ -119,12 +228,5 @@ func (stmt *UpdateStmt) Prepare(c *Context) (Statement, error) {
 		s = s.Pipe(index.Insert(indexName))
 	}
 
-	s = s.Pipe(stream.Discard())
-
-	st := StreamStmt{
-		Stream:   s,
-		ReadOnly: false,
-	}
-
-	return st.Prepare(c)
+	return s, nil
 }
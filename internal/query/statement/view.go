@@ -0,0 +1,63 @@
+package statement
+
+import (
+	"fmt"
+
+	"github.com/chaisql/chai/internal/database"
+	"github.com/chaisql/chai/internal/stream"
+)
+
+// ViewQuery is the SELECT statement of a view.
+// Preparing a statement modifies it and views are shared by
+// all the transactions: every time the view is used, a new statement
+// is parsed from the text of the query.
+type ViewQuery struct {
+	Text  string
+	Parse func(text string) (*SelectStmt, error)
+}
+
+// Stmt parses the query and returns a new statement.
+func (q *ViewQuery) Stmt() (*SelectStmt, error) {
+	return q.Parse(q.Text)
+}
+
+// Sources returns the names of the tables and views read by the query.
+func (q *ViewQuery) Sources() []string {
+	stmt, err := q.Stmt()
+	if err != nil {
+		return nil
+	}
+
+	return stmt.sources()
+}
+
+func (q *ViewQuery) String() string {
+	return q.Text
+}
+
+// prepareView returns the prepared query of the given view.
+func (ctx *Context) prepareView(info *database.ViewInfo) (*stream.Stream, error) {
+	q, ok := info.Query.(*ViewQuery)
+	if !ok {
+		return nil, fmt.Errorf("unexpected query type %T for view %q", info.Query, info.ViewName)
+	}
+
+	stmt, err := q.Stmt()
+	if err != nil {
+		return nil, err
+	}
+
+	// the common tables of the caller are not visible from the view
+	c := *ctx
+	c.commonTables = nil
+
+	s, readOnly, err := prepareSelect(&c, stmt)
+	if err != nil {
+		return nil, err
+	}
+	if !readOnly {
+		return nil, fmt.Errorf("query of view %q must be read-only", info.ViewName)
+	}
+
+	return s, nil
+}
@@ -1,8 +1,11 @@
 package parser
 
 import (
+	"strings"
+
 	"github.com/cockroachdb/errors"
 
+	"github.com/chaisql/chai/internal/expr"
 	"github.com/chaisql/chai/internal/query/statement"
 	"github.com/chaisql/chai/internal/sql/scanner"
 )
@@ -48,6 +51,125 @@ func (p *Parser) parseAlterTableAddFieldStatement(tableName string) (*statement.
 	return &stmt, nil
 }
 
+func (p *Parser) parseAlterTableAddConstraintStatement(tableName string) (*statement.AlterTableAddConstraintStmt, error) {
+	var stmt statement.AlterTableAddConstraintStmt
+	stmt.TableName = tableName
+
+	// Parse table constraint: [CONSTRAINT name] PRIMARY KEY | UNIQUE | CHECK
+	var err error
+	stmt.Constraint, err = p.parseTableConstraint(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if stmt.Constraint == nil {
+		tok, pos, lit := p.ScanIgnoreWhitespace()
+		return nil, newParseError(scanner.Tokstr(tok, lit), []string{"COLUMN", "CONSTRAINT", "PRIMARY", "UNIQUE", "CHECK"}, pos)
+	}
+
+	return &stmt, nil
+}
+
+func (p *Parser) parseAlterTableDropConstraintStatement(tableName string) (*statement.AlterTableDropConstraintStmt, error) {
+	var stmt statement.AlterTableDropConstraintStmt
+	stmt.TableName = tableName
+
+	// Parse constraint name.
+	tok, pos, lit := p.ScanIgnoreWhitespace()
+	switch tok {
+	case scanner.IDENT, scanner.STRING:
+		stmt.ConstraintName = lit
+	default:
+		return nil, newParseError(scanner.Tokstr(tok, lit), []string{"IDENT", "STRING"}, pos)
+	}
+
+	return &stmt, nil
+}
+
+func (p *Parser) parseAlterTableDropColumnStatement(tableName string) (*statement.AlterTableDropColumnStmt, error) {
+	var stmt statement.AlterTableDropColumnStmt
+	stmt.TableName = tableName
+
+	// Parse "COLUMN".
+	if err := p.parseTokens(scanner.COLUMN); err != nil {
+		return nil, err
+	}
+
+	// Parse column name.
+	var err error
+	stmt.ColumnName, err = p.parseIdent()
+	if err != nil {
+		return nil, err
+	}
+
+	return &stmt, nil
+}
+
+func (p *Parser) parseAlterTableAlterColumnStatement(tableName string) (*statement.AlterTableAlterColumnStmt, error) {
+	var stmt statement.AlterTableAlterColumnStmt
+	stmt.TableName = tableName
+
+	// Parse "COLUMN".
+	if err := p.parseTokens(scanner.COLUMN); err != nil {
+		return nil, err
+	}
+
+	// Parse column name.
+	var err error
+	stmt.ColumnName, err = p.parseIdent()
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse action: SET NOT NULL, DROP NOT NULL, SET DEFAULT expr, DROP DEFAULT or TYPE type.
+	tok, pos, lit := p.ScanIgnoreWhitespace()
+	switch tok {
+	case scanner.SET, scanner.DROP:
+		tok2, pos, lit := p.ScanIgnoreWhitespace()
+		switch tok2 {
+		case scanner.NOT:
+			if err := p.parseTokens(scanner.NULL); err != nil {
+				return nil, err
+			}
+
+			stmt.Action = statement.AlterColumnSetNotNull
+			if tok == scanner.DROP {
+				stmt.Action = statement.AlterColumnDropNotNull
+			}
+		case scanner.DEFAULT:
+			if tok == scanner.DROP {
+				stmt.Action = statement.AlterColumnDropDefault
+				break
+			}
+
+			e, err := p.parseDefaultValue()
+			if err != nil {
+				return nil, err
+			}
+
+			stmt.Action = statement.AlterColumnSetDefault
+			stmt.DefaultValue = expr.Constraint(e)
+		default:
+			return nil, newParseError(scanner.Tokstr(tok2, lit), []string{"NOT", "DEFAULT"}, pos)
+		}
+	case scanner.IDENT:
+		// TYPE is not a keyword, as it is commonly used as a column name
+		if !strings.EqualFold(lit, "type") {
+			return nil, newParseError(scanner.Tokstr(tok, lit), []string{"SET", "DROP", "TYPE"}, pos)
+		}
+
+		stmt.Action = statement.AlterColumnSetType
+		stmt.Type, err = p.parseType()
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, newParseError(scanner.Tokstr(tok, lit), []string{"SET", "DROP", "TYPE"}, pos)
+	}
+
+	return &stmt, nil
+}
+
 // parseAlterStatement parses a Alter query string and returns a Statement AST object.
 func (p *Parser) parseAlterStatement() (statement.Statement, error) {
 	var err error
@@ -70,8 +192,23 @@ func (p *Parser) parseAlterStatement() (statement.Statement, error) {
 	case scanner.RENAME:
 		return p.parseAlterTableRenameStatement(tableName)
 	case scanner.ADD_KEYWORD:
+		// ADD COLUMN or ADD table_constraint
+		if tok, _, _ := p.ScanIgnoreWhitespace(); tok != scanner.COLUMN {
+			p.Unscan()
+			return p.parseAlterTableAddConstraintStatement(tableName)
+		}
+		p.Unscan()
 		return p.parseAlterTableAddFieldStatement(tableName)
+	case scanner.DROP:
+		// DROP COLUMN or DROP CONSTRAINT
+		if tok, _, _ := p.ScanIgnoreWhitespace(); tok == scanner.CONSTRAINT {
+			return p.parseAlterTableDropConstraintStatement(tableName)
+		}
+		p.Unscan()
+		return p.parseAlterTableDropColumnStatement(tableName)
+	case scanner.ALTER:
+		return p.parseAlterTableAlterColumnStatement(tableName)
 	}
 
-	return nil, newParseError(scanner.Tokstr(tok, lit), []string{"ADD", "RENAME"}, pos)
+	return nil, newParseError(scanner.Tokstr(tok, lit), []string{"ADD", "ALTER", "DROP", "RENAME"}, pos)
 }
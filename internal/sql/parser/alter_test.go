@@ -107,3 +107,161 @@ func TestParserAlterTableAddField(t *testing.T) {
 		})
 	}
 }
+
+func TestParserAlterTableDropColumn(t *testing.T) {
+	tests := []struct {
+		name     string
+		s        string
+		expected statement.Statement
+		errored  bool
+	}{
+		{"Basic", "ALTER TABLE foo DROP COLUMN bar", &statement.AlterTableDropColumnStmt{TableName: "foo", ColumnName: "bar"}, false},
+		{"With error / missing COLUMN keyword", "ALTER TABLE foo DROP bar", nil, true},
+		{"With error / missing column name", "ALTER TABLE foo DROP COLUMN", nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			q, err := parser.ParseQuery(test.s)
+			if test.errored {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			require.Len(t, q.Statements, 1)
+			require.EqualValues(t, test.expected, q.Statements[0])
+		})
+	}
+}
+
+func TestParserAlterTableAlterColumn(t *testing.T) {
+	tests := []struct {
+		name     string
+		s        string
+		expected statement.Statement
+		errored  bool
+	}{
+		{"Set not null", "ALTER TABLE foo ALTER COLUMN bar SET NOT NULL", &statement.AlterTableAlterColumnStmt{
+			TableName:  "foo",
+			ColumnName: "bar",
+			Action:     statement.AlterColumnSetNotNull,
+		}, false},
+		{"Drop not null", "ALTER TABLE foo ALTER COLUMN bar DROP NOT NULL", &statement.AlterTableAlterColumnStmt{
+			TableName:  "foo",
+			ColumnName: "bar",
+			Action:     statement.AlterColumnDropNotNull,
+		}, false},
+		{"Set default", "ALTER TABLE foo ALTER COLUMN bar SET DEFAULT 10", &statement.AlterTableAlterColumnStmt{
+			TableName:    "foo",
+			ColumnName:   "bar",
+			Action:       statement.AlterColumnSetDefault,
+			DefaultValue: expr.Constraint(expr.LiteralValue{Value: types.NewIntegerValue(10)}),
+		}, false},
+		{"Drop default", "ALTER TABLE foo ALTER COLUMN bar DROP DEFAULT", &statement.AlterTableAlterColumnStmt{
+			TableName:  "foo",
+			ColumnName: "bar",
+			Action:     statement.AlterColumnDropDefault,
+		}, false},
+		{"Type", "ALTER TABLE foo ALTER COLUMN bar TYPE double", &statement.AlterTableAlterColumnStmt{
+			TableName:  "foo",
+			ColumnName: "bar",
+			Action:     statement.AlterColumnSetType,
+			Type:       types.TypeDouble,
+		}, false},
+		{"With error / missing COLUMN keyword", "ALTER TABLE foo ALTER bar SET NOT NULL", nil, true},
+		{"With error / missing action", "ALTER TABLE foo ALTER COLUMN bar", nil, true},
+		{"With error / SET NULL", "ALTER TABLE foo ALTER COLUMN bar SET NULL", nil, true},
+		{"With error / missing type", "ALTER TABLE foo ALTER COLUMN bar TYPE", nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			q, err := parser.ParseQuery(test.s)
+			if test.errored {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			require.Len(t, q.Statements, 1)
+			require.EqualValues(t, test.expected, q.Statements[0])
+		})
+	}
+}
+
+func TestParserAlterTableConstraints(t *testing.T) {
+	tests := []struct {
+		name     string
+		s        string
+		expected statement.Statement
+		errored  bool
+	}{
+		{"Add check", "ALTER TABLE foo ADD CONSTRAINT positive CHECK (a > 0)", &statement.AlterTableAddConstraintStmt{
+			TableName: "foo",
+			Constraint: &database.TableConstraint{
+				Name:  "positive",
+				Check: expr.Constraint(parser.MustParseExpr("a > 0")),
+				Paths: object.Paths{object.NewPath("a")},
+			},
+		}, false},
+		{"Add unique", "ALTER TABLE foo ADD UNIQUE (a, b)", &statement.AlterTableAddConstraintStmt{
+			TableName: "foo",
+			Constraint: &database.TableConstraint{
+				Unique: true,
+				Paths:  object.Paths{object.NewPath("a"), object.NewPath("b")},
+			},
+		}, false},
+		{"Add primary key", "ALTER TABLE foo ADD PRIMARY KEY (a)", &statement.AlterTableAddConstraintStmt{
+			TableName: "foo",
+			Constraint: &database.TableConstraint{
+				PrimaryKey: true,
+				Paths:      object.Paths{object.NewPath("a")},
+			},
+		}, false},
+		{"Add foreign key", "ALTER TABLE foo ADD CONSTRAINT fk FOREIGN KEY (a, b) REFERENCES bar (c, d) ON UPDATE CASCADE ON DELETE SET NULL", &statement.AlterTableAddConstraintStmt{
+			TableName: "foo",
+			Constraint: &database.TableConstraint{
+				Name:  "fk",
+				Paths: object.Paths{object.NewPath("a"), object.NewPath("b")},
+				ForeignKey: &database.ForeignKey{
+					TableName: "bar",
+					Paths:     object.Paths{object.NewPath("c"), object.NewPath("d")},
+					OnDelete:  database.ForeignKeySetNull,
+					OnUpdate:  database.ForeignKeyCascade,
+				},
+			},
+		}, false},
+		{"Add foreign key / referenced primary key", "ALTER TABLE foo ADD FOREIGN KEY (a) REFERENCES bar ON DELETE NO ACTION ON UPDATE RESTRICT", &statement.AlterTableAddConstraintStmt{
+			TableName: "foo",
+			Constraint: &database.TableConstraint{
+				Paths: object.Paths{object.NewPath("a")},
+				ForeignKey: &database.ForeignKey{
+					TableName: "bar",
+					OnUpdate:  database.ForeignKeyRestrict,
+				},
+			},
+		}, false},
+		{"Drop constraint", "ALTER TABLE foo DROP CONSTRAINT foo_pk", &statement.AlterTableDropConstraintStmt{
+			TableName:      "foo",
+			ConstraintName: "foo_pk",
+		}, false},
+		{"With error / missing constraint", "ALTER TABLE foo ADD CONSTRAINT positive", nil, true},
+		{"With error / missing paths", "ALTER TABLE foo ADD UNIQUE", nil, true},
+		{"With error / missing referenced table", "ALTER TABLE foo ADD FOREIGN KEY (a) REFERENCES", nil, true},
+		{"With error / duplicate action", "ALTER TABLE foo ADD FOREIGN KEY (a) REFERENCES bar ON DELETE CASCADE ON DELETE RESTRICT", nil, true},
+		{"With error / unknown action", "ALTER TABLE foo ADD FOREIGN KEY (a) REFERENCES bar ON DELETE NO", nil, true},
+		{"With error / missing constraint name", "ALTER TABLE foo DROP CONSTRAINT", nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			q, err := parser.ParseQuery(test.s)
+			if test.errored {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			require.Len(t, q.Statements, 1)
+			require.EqualValues(t, test.expected, q.Statements[0])
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package parser_test
+
+import (
+	"testing"
+
+	"github.com/chaisql/chai/internal/query/statement"
+	"github.com/chaisql/chai/internal/sql/parser"
+	"github.com/chaisql/chai/internal/testutil/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParserAnalyze(t *testing.T) {
+	tests := []struct {
+		name     string
+		s        string
+		expected statement.Statement
+		errored  bool
+	}{
+		{"All", "ANALYZE", statement.AnalyzeStmt{}, false},
+		{"With table", "ANALYZE test", statement.AnalyzeStmt{TableName: "test"}, false},
+		{"With extra", "ANALYZE test test", nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			q, err := parser.ParseQuery(test.s)
+			if test.errored {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			require.Len(t, q.Statements, 1)
+			require.EqualValues(t, test.expected, q.Statements[0])
+		})
+	}
+}
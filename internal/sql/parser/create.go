@@ -3,9 +3,11 @@ package parser
 import (
 	"fmt"
 	"math"
+	"strings"
 
 	"github.com/chaisql/chai/internal/database"
 	"github.com/chaisql/chai/internal/expr"
+	"github.com/chaisql/chai/internal/expr/functions"
 	"github.com/chaisql/chai/internal/object"
 	"github.com/chaisql/chai/internal/query/statement"
 	"github.com/chaisql/chai/internal/sql/scanner"
@@ -21,7 +23,7 @@ func (p *Parser) parseCreateStatement() (statement.Statement, error) {
 	}
 
 	tok, pos, lit := p.ScanIgnoreWhitespace()
-	switch tok {
+	switch p.keyword(tok, lit) {
 	case scanner.TABLE:
 		return p.parseCreateTableStatement()
 	case scanner.UNIQUE:
@@ -34,9 +36,24 @@ func (p *Parser) parseCreateStatement() (statement.Statement, error) {
 		return p.parseCreateIndexStatement(false)
 	case scanner.SEQUENCE:
 		return p.parseCreateSequenceStatement()
+	case scanner.VIEW:
+		return p.parseCreateViewStatement()
+	case scanner.TRIGGER:
+		return p.parseCreateTriggerStatement()
+	case scanner.IDENT:
+		// FULLTEXT is not a keyword
+		if !strings.EqualFold(lit, "FULLTEXT") {
+			break
+		}
+
+		if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != scanner.INDEX {
+			return nil, newParseError(scanner.Tokstr(tok, lit), []string{"INDEX"}, pos)
+		}
+
+		return p.parseCreateFullTextIndexStatement()
 	}
 
-	return nil, newParseError(scanner.Tokstr(tok, lit), []string{"TABLE", "INDEX", "SEQUENCE"}, pos)
+	return nil, newParseError(scanner.Tokstr(tok, lit), []string{"TABLE", "INDEX", "SEQUENCE", "VIEW", "TRIGGER"}, pos)
 }
 
 // parseCreateTableStatement parses a create table string and returns a Statement AST object.
@@ -192,7 +209,7 @@ func (p *Parser) parseFieldDefinition(parent object.Path) (*database.FieldConstr
 LOOP:
 	for {
 		tok, pos, lit := p.ScanIgnoreWhitespace()
-		switch tok {
+		switch p.keyword(tok, lit) {
 		case scanner.PRIMARY:
 			// Parse "KEY"
 			if err := p.parseTokens(scanner.KEY); err != nil {
@@ -238,51 +255,12 @@ LOOP:
 				return nil, nil, newParseError(scanner.Tokstr(tok, lit), []string{"CONSTRAINT", ")"}, pos)
 			}
 
-			withParentheses, err := p.parseOptional(scanner.LPAREN)
-			if err != nil {
-				return nil, nil, err
-			}
-
-			// Parse default value expression.
-			// Only a few tokens are allowed.
-			e, err := p.parseExprWithMinPrecedence(scanner.EQ.Precedence(),
-				scanner.EQ,
-				scanner.NEQ,
-				scanner.BITWISEOR,
-				scanner.BITWISEXOR,
-				scanner.BITWISEAND,
-				scanner.LT,
-				scanner.LTE,
-				scanner.GT,
-				scanner.GTE,
-				scanner.ADD,
-				scanner.SUB,
-				scanner.MUL,
-				scanner.DIV,
-				scanner.MOD,
-				scanner.CONCAT,
-				scanner.INTEGER,
-				scanner.NUMBER,
-				scanner.STRING,
-				scanner.TRUE,
-				scanner.FALSE,
-				scanner.NULL,
-				scanner.LPAREN,   // only opening parenthesis are necessary
-				scanner.LBRACKET, // only opening brackets are necessary
-				scanner.NEXT,
-			)
+			e, err := p.parseDefaultValue()
 			if err != nil {
 				return nil, nil, err
 			}
 
 			fc.DefaultValue = expr.Constraint(e)
-
-			if withParentheses {
-				_, err = p.parseOptional(scanner.RPAREN)
-				if err != nil {
-					return nil, nil, err
-				}
-			}
 		case scanner.UNIQUE:
 			tcs = append(tcs, &database.TableConstraint{
 				Unique: true,
@@ -298,6 +276,16 @@ LOOP:
 				Check: expr.Constraint(e),
 				Paths: paths,
 			})
+		case scanner.REFERENCES:
+			fk, err := p.parseReferences()
+			if err != nil {
+				return nil, nil, err
+			}
+
+			tcs = append(tcs, &database.TableConstraint{
+				ForeignKey: fk,
+				Paths:      object.Paths{path},
+			})
 		default:
 			p.Unscan()
 			break LOOP
@@ -307,6 +295,55 @@ LOOP:
 	return &fc, tcs, nil
 }
 
+// parseDefaultValue parses the expression of a DEFAULT clause.
+func (p *Parser) parseDefaultValue() (expr.Expr, error) {
+	withParentheses, err := p.parseOptional(scanner.LPAREN)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse default value expression.
+	// Only a few tokens are allowed.
+	e, err := p.parseExprWithMinPrecedence(scanner.EQ.Precedence(),
+		scanner.EQ,
+		scanner.NEQ,
+		scanner.BITWISEOR,
+		scanner.BITWISEXOR,
+		scanner.BITWISEAND,
+		scanner.LT,
+		scanner.LTE,
+		scanner.GT,
+		scanner.GTE,
+		scanner.ADD,
+		scanner.SUB,
+		scanner.MUL,
+		scanner.DIV,
+		scanner.MOD,
+		scanner.CONCAT,
+		scanner.INTEGER,
+		scanner.NUMBER,
+		scanner.STRING,
+		scanner.TRUE,
+		scanner.FALSE,
+		scanner.NULL,
+		scanner.LPAREN,   // only opening parenthesis are necessary
+		scanner.LBRACKET, // only opening brackets are necessary
+		scanner.NEXT,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if withParentheses {
+		_, err = p.parseOptional(scanner.RPAREN)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return e, nil
+}
+
 func (p *Parser) parseObjectDefinition(parent object.Path) (*database.AnonymousType, []*database.TableConstraint, error) {
 	err := p.parseTokens(scanner.LPAREN)
 	if err != nil {
@@ -373,7 +410,7 @@ func (p *Parser) parseTableConstraint(stmt *statement.CreateTableStmt) (*databas
 	}
 
 	tok, pos, lit := p.ScanIgnoreWhitespace()
-	switch tok {
+	switch p.keyword(tok, lit) {
 	case scanner.PRIMARY:
 		// Parse "KEY ("
 		err = p.parseTokens(scanner.KEY)
@@ -411,9 +448,45 @@ func (p *Parser) parseTableConstraint(stmt *statement.CreateTableStmt) (*databas
 
 		tc.Check = expr.Constraint(e)
 		tc.Paths = paths
+	case scanner.FOREIGN:
+		// FOREIGN is not reserved, it may be the name of a column
+		if tok, _, _ := p.ScanIgnoreWhitespace(); tok != scanner.KEY && !requiresTc {
+			p.Unscan()
+			if tk, _, _ := p.s.Curr(); tk == scanner.WS {
+				p.Unscan()
+			}
+			p.Unscan()
+			return nil, nil
+		}
+		p.Unscan()
+
+		// Parse "KEY ("
+		err = p.parseTokens(scanner.KEY)
+		if err != nil {
+			return nil, err
+		}
+
+		tc.Paths, _, err = p.parsePathList()
+		if err != nil {
+			return nil, err
+		}
+		if len(tc.Paths) == 0 {
+			tok, pos, lit := p.ScanIgnoreWhitespace()
+			return nil, newParseError(scanner.Tokstr(tok, lit), []string{"PATHS"}, pos)
+		}
+
+		err = p.parseTokens(scanner.REFERENCES)
+		if err != nil {
+			return nil, err
+		}
+
+		tc.ForeignKey, err = p.parseReferences()
+		if err != nil {
+			return nil, err
+		}
 	default:
 		if requiresTc {
-			return nil, newParseError(scanner.Tokstr(tok, lit), []string{"PRIMARY", "UNIQUE", "CHECK"}, pos)
+			return nil, newParseError(scanner.Tokstr(tok, lit), []string{"PRIMARY", "UNIQUE", "CHECK", "FOREIGN"}, pos)
 		}
 
 		p.Unscan()
@@ -423,6 +496,71 @@ func (p *Parser) parseTableConstraint(stmt *statement.CreateTableStmt) (*databas
 	return &tc, nil
 }
 
+// parseReferences parses the referenced table and paths of a foreign key
+// and its optional ON DELETE and ON UPDATE actions.
+// This function assumes the REFERENCES token has already been consumed.
+func (p *Parser) parseReferences() (*database.ForeignKey, error) {
+	var fk database.ForeignKey
+	var err error
+
+	fk.TableName, err = p.parseIdent()
+	if err != nil {
+		return nil, err
+	}
+
+	fk.Paths, _, err = p.parsePathList()
+	if err != nil {
+		return nil, err
+	}
+
+	var hasOnDelete, hasOnUpdate bool
+	for {
+		if ok, err := p.parseOptional(scanner.ON); !ok || err != nil {
+			return &fk, err
+		}
+
+		tok, pos, lit := p.ScanIgnoreWhitespace()
+		switch {
+		case tok == scanner.DELETE && !hasOnDelete:
+			fk.OnDelete, err = p.parseForeignKeyAction()
+			hasOnDelete = true
+		case tok == scanner.UPDATE && !hasOnUpdate:
+			fk.OnUpdate, err = p.parseForeignKeyAction()
+			hasOnUpdate = true
+		default:
+			return nil, newParseError(scanner.Tokstr(tok, lit), []string{"DELETE", "UPDATE"}, pos)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// parseForeignKeyAction parses the action of an ON DELETE or ON UPDATE clause.
+func (p *Parser) parseForeignKeyAction() (database.ForeignKeyAction, error) {
+	tok, pos, lit := p.ScanIgnoreWhitespace()
+	switch p.keyword(tok, lit) {
+	case scanner.CASCADE:
+		return database.ForeignKeyCascade, nil
+	case scanner.RESTRICT:
+		return database.ForeignKeyRestrict, nil
+	case scanner.SET:
+		if err := p.parseTokens(scanner.NULL); err != nil {
+			return 0, err
+		}
+		return database.ForeignKeySetNull, nil
+	case scanner.NO:
+		// ACTION is not a keyword
+		tok, pos, lit := p.ScanIgnoreWhitespace()
+		if tok != scanner.IDENT || !strings.EqualFold(lit, "ACTION") {
+			return 0, newParseError(scanner.Tokstr(tok, lit), []string{"ACTION"}, pos)
+		}
+		return database.ForeignKeyNoAction, nil
+	}
+
+	return 0, newParseError(scanner.Tokstr(tok, lit), []string{"CASCADE", "RESTRICT", "SET NULL", "NO ACTION"}, pos)
+}
+
 // parseCreateIndexStatement parses a create index string and returns a Statement AST object.
 // This function assumes the CREATE INDEX or CREATE UNIQUE INDEX tokens have already been consumed.
 func (p *Parser) parseCreateIndexStatement(unique bool) (*statement.CreateIndexStmt, error) {
@@ -458,21 +596,216 @@ func (p *Parser) parseCreateIndexStatement(unique bool) (*statement.CreateIndexS
 		return nil, err
 	}
 
-	paths, order, err := p.parsePathList()
-	if err != nil {
+	if err := p.parseTokens(scanner.LPAREN); err != nil {
 		return nil, err
 	}
-	if len(paths) == 0 {
-		tok, pos, lit := p.ScanIgnoreWhitespace()
-		return nil, newParseError(scanner.Tokstr(tok, lit), []string{"("}, pos)
+
+	for i := 0; ; i++ {
+		path, e, err := p.parseIndexKey()
+		if err != nil {
+			return nil, err
+		}
+
+		stmt.Info.Paths = append(stmt.Info.Paths, path)
+		if e != nil && stmt.Info.Exprs == nil {
+			// the previous values are paths
+			stmt.Info.Exprs = make([]*database.IndexExpr, i)
+		}
+		if stmt.Info.Exprs != nil {
+			stmt.Info.Exprs = append(stmt.Info.Exprs, e)
+		}
+
+		// Parse optional ASC/DESC token.
+		if ok, err := p.parseOptional(scanner.DESC); err != nil {
+			return nil, err
+		} else if ok {
+			stmt.Info.KeySortOrder = stmt.Info.KeySortOrder.SetDesc(i)
+		} else if _, err := p.parseOptional(scanner.ASC); err != nil {
+			return nil, err
+		}
+
+		if tok, _, _ := p.ScanIgnoreWhitespace(); tok != scanner.COMMA {
+			p.Unscan()
+			break
+		}
+	}
+
+	if err := p.parseTokens(scanner.RPAREN); err != nil {
+		return nil, err
+	}
+
+	var elements int
+	for _, e := range stmt.Info.Exprs {
+		if e != nil && e.Elements {
+			elements++
+		}
+	}
+	if elements > 1 {
+		return nil, fmt.Errorf("an index can only contain the elements of one array")
+	}
+	if elements > 0 && stmt.Info.Unique {
+		return nil, fmt.Errorf("unique indexes cannot contain the elements of an array")
+	}
+
+	// Parse optional INCLUDE clause of covering indexes.
+	// INCLUDE is not a keyword
+	if tok, _, lit := p.ScanIgnoreWhitespace(); tok == scanner.IDENT && strings.EqualFold(lit, "INCLUDE") {
+		stmt.Info.Include, err = p.parseIncludeList()
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		p.Unscan()
 	}
 
-	stmt.Info.Paths = paths
-	stmt.Info.KeySortOrder = order
+	// Parse optional WHERE clause of partial indexes
+	if ok, err := p.parseOptional(scanner.WHERE); err != nil {
+		return nil, err
+	} else if ok {
+		stmt.Info.Predicate, err = p.parseIndexPredicate()
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	return &stmt, nil
 }
 
+// parseCreateFullTextIndexStatement parses a create full-text index string and returns a Statement AST object.
+// This function assumes the CREATE FULLTEXT INDEX tokens have already been consumed.
+func (p *Parser) parseCreateFullTextIndexStatement() (*statement.CreateIndexStmt, error) {
+	stmt, err := p.parseCreateIndexStatement(false)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt.Info.FullText = true
+
+	if len(stmt.Info.Paths) != 1 || stmt.Info.Exprs != nil {
+		return nil, fmt.Errorf("full-text indexes must contain exactly one column")
+	}
+	if stmt.Info.KeySortOrder != 0 {
+		return nil, fmt.Errorf("full-text indexes cannot be sorted")
+	}
+	if len(stmt.Info.Include) > 0 {
+		return nil, fmt.Errorf("full-text indexes cannot include columns")
+	}
+
+	return stmt, nil
+}
+
+// parseIncludeList parses the list of columns of an INCLUDE clause.
+// This function assumes the INCLUDE token has already been consumed.
+func (p *Parser) parseIncludeList() (object.Paths, error) {
+	if err := p.parseTokens(scanner.LPAREN); err != nil {
+		return nil, err
+	}
+
+	idents, err := p.parseIdentList()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.parseTokens(scanner.RPAREN); err != nil {
+		return nil, err
+	}
+
+	paths := make(object.Paths, len(idents))
+	for i, ident := range idents {
+		paths[i] = object.NewPath(ident)
+	}
+
+	return paths, nil
+}
+
+// parseIndexKey parses an indexed value, either a path, the elements
+// of an array, i.e. tags[*], or an expression.
+// If it is not a path, the returned path is empty.
+func (p *Parser) parseIndexKey() (object.Path, *database.IndexExpr, error) {
+	e, err := p.ParseExpr()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// parentheses are only required by the syntax, i.e. ((a + b)),
+	// they are not part of the indexed value
+	for {
+		pe, ok := e.(expr.Parentheses)
+		if !ok {
+			break
+		}
+		e = pe.E
+	}
+
+	if path, ok := e.(expr.Path); ok {
+		// Parse optional [*] to index the elements of an array
+		if ok, err := p.parseOptional(scanner.LSBRACKET, scanner.MUL, scanner.RSBRACKET); err != nil {
+			return nil, nil, err
+		} else if ok {
+			return nil, &database.IndexExpr{
+				Expr:     expr.Constraint(path),
+				Paths:    object.Paths{object.Path(path)},
+				Elements: true,
+			}, nil
+		}
+
+		return object.Path(path), nil, nil
+	}
+
+	ie, err := parseIndexExpr(e, "index expressions")
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(ie.Paths) == 0 {
+		return nil, nil, fmt.Errorf("index expressions must reference at least one column")
+	}
+
+	return nil, ie, nil
+}
+
+// parseIndexPredicate parses the predicate of a partial index.
+// This function assumes the WHERE token has already been consumed.
+func (p *Parser) parseIndexPredicate() (*database.IndexExpr, error) {
+	e, err := p.ParseExpr()
+	if err != nil {
+		return nil, err
+	}
+
+	return parseIndexExpr(e, "index predicates")
+}
+
+// parseIndexExpr ensures the expression can be evaluated on the rows of the table
+// and always gives the same result for the same row, then returns it with the paths it uses.
+func parseIndexExpr(e expr.Expr, usage string) (*database.IndexExpr, error) {
+	paths, err := tableExprPaths(e, usage)
+	if err != nil {
+		return nil, err
+	}
+
+	expr.Walk(e, func(e expr.Expr) bool {
+		switch e.(type) {
+		case expr.PositionalParam, expr.NamedParam:
+			err = fmt.Errorf("parameters are not allowed in %s", usage)
+		case expr.AggregatorBuilder, *expr.Over:
+			err = fmt.Errorf("aggregate and window functions are not allowed in %s", usage)
+		}
+
+		return err == nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if functions.IsVolatile(e) {
+		return nil, fmt.Errorf("non-deterministic functions are not allowed in %s", usage)
+	}
+
+	return &database.IndexExpr{
+		Expr:  expr.Constraint(e),
+		Paths: paths,
+	}, nil
+}
+
 // This function assumes the CREATE SEQUENCE tokens have already been consumed.
 func (p *Parser) parseCreateSequenceStatement() (*statement.CreateSequenceStmt, error) {
 	var stmt statement.CreateSequenceStmt
@@ -715,10 +1048,30 @@ func (p *Parser) parseCheckConstraint() (expr.Expr, []object.Path, error) {
 		return nil, nil, err
 	}
 
+	paths, err := tableExprPaths(e, "CHECK constraints")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Parse ")"
+	err = p.parseTokens(scanner.RPAREN)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return e, paths, nil
+}
+
+// tableExprPaths extracts all the paths from an expression evaluated against
+// the rows of a table. It returns an error if the expression contains a subquery.
+func tableExprPaths(e expr.Expr, usage string) ([]object.Path, error) {
+	var err error
 	var paths []object.Path
-	// extract all the paths from the expression
 	expr.Walk(e, func(e expr.Expr) bool {
 		switch t := e.(type) {
+		case *statement.ScalarSubquery, *statement.ArraySubquery, *statement.ExistsSubquery:
+			err = fmt.Errorf("subqueries are not allowed in %s", usage)
+			return false
 		case expr.Path:
 			pt := object.Path(t)
 			// ensure that the path is not already in the list
@@ -737,11 +1090,206 @@ func (p *Parser) parseCheckConstraint() (expr.Expr, []object.Path, error) {
 		return true
 	})
 
-	// Parse ")"
-	err = p.parseTokens(scanner.RPAREN)
+	return paths, err
+}
+
+// parseCreateViewStatement parses a create view string and returns a Statement AST object.
+// This function assumes the CREATE VIEW tokens have already been consumed.
+func (p *Parser) parseCreateViewStatement() (*statement.CreateViewStmt, error) {
+	var stmt statement.CreateViewStmt
+	var err error
+
+	// Parse IF NOT EXISTS
+	stmt.IfNotExists, err = p.parseOptional(scanner.IF, scanner.NOT, scanner.EXISTS)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
-	return e, paths, nil
+	// Parse view name
+	stmt.Info.ViewName, err = p.parseIdent()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.parseTokens(scanner.AS); err != nil {
+		return nil, err
+	}
+
+	// only the text of the query is kept, it is parsed again every time the view is used
+	text, err := p.record(func() error {
+		_, err := p.parseSelectStatement()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = p.checkNoRecordedParams("views")
+	if err != nil {
+		return nil, err
+	}
+
+	stmt.Info.Query = &statement.ViewQuery{
+		Text:  text,
+		Parse: parseViewQuery,
+	}
+
+	return &stmt, nil
+}
+
+// parseViewQuery parses the query of a view.
+func parseViewQuery(s string) (*statement.SelectStmt, error) {
+	return NewParser(strings.NewReader(s)).parseSelectStatement()
+}
+
+// checkNoRecordedParams returns an error if the recorded tokens contain parameters,
+// which can't be used by the objects stored in the catalog.
+func (p *Parser) checkNoRecordedParams(objects string) error {
+	for _, t := range p.recorded {
+		if t.tok == scanner.NAMEDPARAM || t.tok == scanner.POSITIONALPARAM {
+			return &ParseError{Message: "parameters are not allowed in " + objects, Pos: t.pos}
+		}
+	}
+
+	return nil
+}
+
+// parseCreateTriggerStatement parses a create trigger string and returns a Statement AST object.
+// This function assumes the CREATE TRIGGER tokens have already been consumed.
+func (p *Parser) parseCreateTriggerStatement() (*statement.CreateTriggerStmt, error) {
+	var stmt statement.CreateTriggerStmt
+	var err error
+
+	// Parse IF NOT EXISTS
+	stmt.IfNotExists, err = p.parseOptional(scanner.IF, scanner.NOT, scanner.EXISTS)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse trigger name
+	stmt.Info.TriggerName, err = p.parseIdent()
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse BEFORE or AFTER
+	tok, pos, lit := p.ScanIgnoreWhitespace()
+	switch p.keyword(tok, lit) {
+	case scanner.BEFORE:
+		stmt.Info.Timing = database.TriggerBefore
+	case scanner.AFTER:
+		stmt.Info.Timing = database.TriggerAfter
+	default:
+		return nil, newParseError(scanner.Tokstr(tok, lit), []string{"BEFORE", "AFTER"}, pos)
+	}
+
+	// Parse INSERT, UPDATE or DELETE
+	tok, pos, lit = p.ScanIgnoreWhitespace()
+	switch tok {
+	case scanner.INSERT:
+		stmt.Info.Event = database.TriggerInsert
+	case scanner.UPDATE:
+		stmt.Info.Event = database.TriggerUpdate
+	case scanner.DELETE:
+		stmt.Info.Event = database.TriggerDelete
+	default:
+		return nil, newParseError(scanner.Tokstr(tok, lit), []string{"INSERT", "UPDATE", "DELETE"}, pos)
+	}
+
+	if err := p.parseTokens(scanner.ON); err != nil {
+		return nil, err
+	}
+
+	// Parse table name
+	stmt.Info.TableName, err = p.parseIdent()
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse optional FOR EACH ROW, triggers being always executed for each row
+	_, err = p.parseOptional(scanner.FOR, scanner.EACH, scanner.ROW)
+	if err != nil {
+		return nil, err
+	}
+
+	// only the text of the action is kept, it is parsed again every time the trigger is used
+	text, err := p.record(func() error {
+		_, err := p.parseTriggerBody()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = p.checkNoRecordedParams("triggers")
+	if err != nil {
+		return nil, err
+	}
+
+	stmt.Info.Action = &statement.TriggerAction{
+		Text:  text,
+		Parse: parseTriggerAction,
+	}
+
+	return &stmt, nil
+}
+
+// parseTriggerBody parses the action of a trigger:
+// an optional WHEN condition followed by one or more statements between BEGIN and END,
+// each one of them terminated by a semicolon.
+func (p *Parser) parseTriggerBody() (*statement.TriggerBody, error) {
+	var body statement.TriggerBody
+	var err error
+
+	// Parse optional WHEN condition
+	if ok, err := p.parseOptional(scanner.WHEN); err != nil {
+		return nil, err
+	} else if ok {
+		body.When, err = p.ParseExpr()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := p.parseTokens(scanner.BEGIN); err != nil {
+		return nil, err
+	}
+
+	for {
+		tok, pos, lit := p.ScanIgnoreWhitespace()
+		if p.keyword(tok, lit) == scanner.END && len(body.Statements) > 0 {
+			break
+		}
+		p.Unscan()
+
+		var s statement.Statement
+		switch tok {
+		case scanner.INSERT:
+			s, err = p.parseInsertStatement()
+		case scanner.UPDATE:
+			s, err = p.parseUpdateStatement()
+		case scanner.DELETE:
+			s, err = p.parseDeleteStatement()
+		case scanner.SELECT, scanner.WITH:
+			s, err = p.parseSelectStatement()
+		default:
+			return nil, newParseError(scanner.Tokstr(tok, lit), []string{"INSERT", "UPDATE", "DELETE", "SELECT", "WITH"}, pos)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if err := p.parseTokens(scanner.SEMICOLON); err != nil {
+			return nil, err
+		}
+
+		body.Statements = append(body.Statements, s)
+	}
+
+	return &body, nil
+}
+
+// parseTriggerAction parses the action of a trigger.
+func parseTriggerAction(s string) (*statement.TriggerBody, error) {
+	return NewParser(strings.NewReader(s)).parseTriggerBody()
 }
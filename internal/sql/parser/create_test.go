@@ -5,11 +5,13 @@ import (
 	"testing"
 
 	"github.com/chaisql/chai/internal/database"
+	"github.com/chaisql/chai/internal/expr"
 	"github.com/chaisql/chai/internal/object"
 	"github.com/chaisql/chai/internal/query/statement"
 	"github.com/chaisql/chai/internal/sql/parser"
 	"github.com/chaisql/chai/internal/testutil"
 	"github.com/chaisql/chai/internal/testutil/assert"
+	"github.com/chaisql/chai/internal/tree"
 	"github.com/stretchr/testify/require"
 )
 
@@ -48,6 +50,106 @@ func TestParserCreateIndex(t *testing.T) {
 			},
 			false},
 		{"No fields", "CREATE INDEX idx ON test", nil, true},
+		{"Partial", "CREATE UNIQUE INDEX idx ON test (foo) WHERE bar IS NULL",
+			&statement.CreateIndexStmt{
+				Info: database.IndexInfo{
+					IndexName: "idx",
+					Owner:     database.Owner{TableName: "test"},
+					Paths:     []object.Path{object.Path(testutil.ParseObjectPath(t, "foo"))},
+					Unique:    true,
+					Predicate: &database.IndexExpr{
+						Expr:  expr.Constraint(parser.MustParseExpr("bar IS NULL")),
+						Paths: object.Paths{object.Path(testutil.ParseObjectPath(t, "bar"))},
+					},
+				},
+			},
+			false},
+		{"Expression", "CREATE INDEX idx ON test (foo, lower(bar) DESC)",
+			&statement.CreateIndexStmt{
+				Info: database.IndexInfo{
+					IndexName: "idx",
+					Owner:     database.Owner{TableName: "test"},
+					Paths:     []object.Path{object.Path(testutil.ParseObjectPath(t, "foo")), nil},
+					Exprs: []*database.IndexExpr{nil, {
+						Expr:  expr.Constraint(parser.MustParseExpr("lower(bar)")),
+						Paths: object.Paths{object.Path(testutil.ParseObjectPath(t, "bar"))},
+					}},
+					KeySortOrder: tree.SortOrder(0).SetDesc(1),
+				},
+			},
+			false},
+		{"Expression in parentheses", "CREATE INDEX idx ON test ((foo * 2))",
+			&statement.CreateIndexStmt{
+				Info: database.IndexInfo{
+					IndexName: "idx",
+					Owner:     database.Owner{TableName: "test"},
+					Paths:     []object.Path{nil},
+					Exprs: []*database.IndexExpr{{
+						Expr:  expr.Constraint(parser.MustParseExpr("foo * 2")),
+						Paths: object.Paths{object.Path(testutil.ParseObjectPath(t, "foo"))},
+					}},
+				},
+			},
+			false},
+		{"Non-deterministic expression in parentheses", "CREATE INDEX idx ON test (foo + (random()))", nil, true},
+		{"Full-text", "CREATE FULLTEXT INDEX idx ON test (foo)",
+			&statement.CreateIndexStmt{
+				Info: database.IndexInfo{
+					IndexName: "idx",
+					Owner:     database.Owner{TableName: "test"},
+					Paths:     []object.Path{object.Path(testutil.ParseObjectPath(t, "foo"))},
+					FullText:  true,
+				},
+			},
+			false},
+		{"Full-text with several columns", "CREATE FULLTEXT INDEX idx ON test (foo, bar)", nil, true},
+		{"Full-text with expression", "CREATE FULLTEXT INDEX idx ON test (lower(foo))", nil, true},
+		{"Full-text sorted", "CREATE FULLTEXT INDEX idx ON test (foo DESC)", nil, true},
+		{"Full-text with include", "CREATE FULLTEXT INDEX idx ON test (foo) INCLUDE (bar)", nil, true},
+		{"Unique full-text", "CREATE UNIQUE FULLTEXT INDEX idx ON test (foo)", nil, true},
+		{"Array elements", "CREATE INDEX idx ON test (foo, bar.baz[*] DESC)",
+			&statement.CreateIndexStmt{
+				Info: database.IndexInfo{
+					IndexName: "idx",
+					Owner:     database.Owner{TableName: "test"},
+					Paths:     []object.Path{object.Path(testutil.ParseObjectPath(t, "foo")), nil},
+					Exprs: []*database.IndexExpr{nil, {
+						Expr:     expr.Constraint(parser.MustParseExpr("bar.baz")),
+						Paths:    object.Paths{object.Path(testutil.ParseObjectPath(t, "bar.baz"))},
+						Elements: true,
+					}},
+					KeySortOrder: tree.SortOrder(0).SetDesc(1),
+				},
+			},
+			false},
+		{"Array elements of several arrays", "CREATE INDEX idx ON test (foo[*], bar[*])", nil, true},
+		{"Unique array elements", "CREATE UNIQUE INDEX idx ON test (foo[*])", nil, true},
+		{"Array elements of expression", "CREATE INDEX idx ON test (lower(foo)[*])", nil, true},
+		{"Expression with param", "CREATE INDEX idx ON test (foo + ?)", nil, true},
+		{"Expression without column", "CREATE INDEX idx ON test (1 + 1)", nil, true},
+		{"Non-deterministic expression", "CREATE INDEX idx ON test (foo + random())", nil, true},
+		{"Aggregate expression", "CREATE INDEX idx ON test (max(foo))", nil, true},
+		{"Include", "CREATE INDEX idx ON test (foo) INCLUDE (bar, baz) WHERE bar > 1",
+			&statement.CreateIndexStmt{
+				Info: database.IndexInfo{
+					IndexName: "idx",
+					Owner:     database.Owner{TableName: "test"},
+					Paths:     []object.Path{object.Path(testutil.ParseObjectPath(t, "foo"))},
+					Include:   object.Paths{object.NewPath("bar"), object.NewPath("baz")},
+					Predicate: &database.IndexExpr{
+						Expr:  expr.Constraint(parser.MustParseExpr("bar > 1")),
+						Paths: object.Paths{object.Path(testutil.ParseObjectPath(t, "bar"))},
+					},
+				},
+			},
+			false},
+		{"Include without columns", "CREATE INDEX idx ON test (foo) INCLUDE ()", nil, true},
+		{"Include with path", "CREATE INDEX idx ON test (foo) INCLUDE (bar.baz)", nil, true},
+		{"Partial with param", "CREATE INDEX idx ON test (foo) WHERE bar > ?", nil, true},
+		{"Partial with subquery", "CREATE INDEX idx ON test (foo) WHERE bar IN (SELECT a FROM b)", nil, true},
+		{"Partial with aggregate", "CREATE INDEX idx ON test (foo) WHERE count(foo) > 0", nil, true},
+		{"Partial with window function", "CREATE INDEX idx ON test (foo) WHERE row_number() OVER () > 0", nil, true},
+		{"Partial with non-deterministic function", "CREATE INDEX idx ON test (foo) WHERE random() > 0", nil, true},
 	}
 
 	for _, test := range tests {
@@ -204,3 +306,100 @@ func TestParserCreateSequence(t *testing.T) {
 		})
 	}
 }
+
+func TestParserCreateView(t *testing.T) {
+	tests := []struct {
+		name        string
+		s           string
+		viewName    string
+		ifNotExists bool
+		query       string
+		errored     bool
+	}{
+		{"Basic", "CREATE VIEW v AS SELECT * FROM test", "v", false, "SELECT * FROM test", false},
+		{"If not exists", "CREATE VIEW IF NOT EXISTS v AS SELECT a FROM test", "v", true, "SELECT a FROM test", false},
+		{"Normalized", "create view v as select `a`, 'b' as c from test where d = \"e\" order by a limit 10", "v", false, `SELECT a, "b" AS c FROM test WHERE d = "e" ORDER BY a LIMIT 10`, false},
+		{"Quoted identifiers", "CREATE VIEW `my view` AS SELECT `my col` FROM `my table`", "my view", false, "SELECT `my col` FROM `my table`", false},
+		{"Concat", "CREATE VIEW v AS SELECT a || b FROM test", "v", false, "SELECT a || b FROM test", false},
+		{"With", "CREATE VIEW v AS WITH t AS (SELECT 1) SELECT * FROM t", "v", false, "WITH t AS (SELECT 1) SELECT * FROM t", false},
+		{"Positional param", "CREATE VIEW v AS SELECT * FROM test WHERE a = ?", "", false, "", true},
+		{"Named param", "CREATE VIEW v AS SELECT * FROM test WHERE a = $a", "", false, "", true},
+		{"No query", "CREATE VIEW v", "", false, "", true},
+		{"Not a select", "CREATE VIEW v AS DELETE FROM test", "", false, "", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			q, err := parser.ParseQuery(test.s)
+			if test.errored {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			require.Len(t, q.Statements, 1)
+
+			stmt := q.Statements[0].(*statement.CreateViewStmt)
+			require.Equal(t, test.viewName, stmt.Info.ViewName)
+			require.Equal(t, test.ifNotExists, stmt.IfNotExists)
+			require.Equal(t, test.query, stmt.Info.Query.String())
+
+			// the query can be parsed again
+			_, err = stmt.Info.Query.(*statement.ViewQuery).Stmt()
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestParserCreateTrigger(t *testing.T) {
+	tests := []struct {
+		name       string
+		s          string
+		expected   database.TriggerInfo
+		action     string
+		statements int
+		errored    bool
+	}{
+		{"Basic", "CREATE TRIGGER trg AFTER INSERT ON test BEGIN DELETE FROM foo; END",
+			database.TriggerInfo{TriggerName: "trg", TableName: "test", Timing: database.TriggerAfter, Event: database.TriggerInsert},
+			"BEGIN DELETE FROM foo; END", 1, false},
+		{"For each row", "CREATE TRIGGER trg BEFORE UPDATE ON test FOR EACH ROW BEGIN DELETE FROM foo; END",
+			database.TriggerInfo{TriggerName: "trg", TableName: "test", Timing: database.TriggerBefore, Event: database.TriggerUpdate},
+			"BEGIN DELETE FROM foo; END", 1, false},
+		{"When", "create trigger trg before delete on test when OLD.a > 'b' begin insert into foo (a) values (OLD.a); update foo set a = 1; end",
+			database.TriggerInfo{TriggerName: "trg", TableName: "test", Timing: database.TriggerBefore, Event: database.TriggerDelete},
+			`WHEN OLD.a > "b" BEGIN INSERT INTO foo (a) VALUES (OLD.a); UPDATE foo SET a = 1; END`, 2, false},
+		{"Select", "CREATE TRIGGER trg AFTER INSERT ON test BEGIN SELECT NEXT VALUE FOR seq; END",
+			database.TriggerInfo{TriggerName: "trg", TableName: "test", Timing: database.TriggerAfter, Event: database.TriggerInsert},
+			"BEGIN SELECT NEXT VALUE FOR seq; END", 1, false},
+		{"No statement", "CREATE TRIGGER trg AFTER INSERT ON test BEGIN END", database.TriggerInfo{}, "", 0, true},
+		{"Missing semicolon", "CREATE TRIGGER trg AFTER INSERT ON test BEGIN DELETE FROM foo END", database.TriggerInfo{}, "", 0, true},
+		{"Missing timing", "CREATE TRIGGER trg INSERT ON test BEGIN DELETE FROM foo; END", database.TriggerInfo{}, "", 0, true},
+		{"Invalid event", "CREATE TRIGGER trg AFTER SELECT ON test BEGIN DELETE FROM foo; END", database.TriggerInfo{}, "", 0, true},
+		{"Invalid statement", "CREATE TRIGGER trg AFTER INSERT ON test BEGIN DROP TABLE foo; END", database.TriggerInfo{}, "", 0, true},
+		{"Param", "CREATE TRIGGER trg AFTER INSERT ON test BEGIN DELETE FROM foo WHERE a = ?; END", database.TriggerInfo{}, "", 0, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			q, err := parser.ParseQuery(test.s)
+			if test.errored {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			require.Len(t, q.Statements, 1)
+
+			stmt := q.Statements[0].(*statement.CreateTriggerStmt)
+			require.Equal(t, test.expected.TriggerName, stmt.Info.TriggerName)
+			require.Equal(t, test.expected.TableName, stmt.Info.TableName)
+			require.Equal(t, test.expected.Timing, stmt.Info.Timing)
+			require.Equal(t, test.expected.Event, stmt.Info.Event)
+			require.Equal(t, test.action, stmt.Info.Action.String())
+
+			// the action can be parsed again
+			body, err := stmt.Info.Action.(*statement.TriggerAction).Body()
+			assert.NoError(t, err)
+			require.Len(t, body.Statements, test.statements)
+		})
+	}
+}
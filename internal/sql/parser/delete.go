@@ -25,14 +25,26 @@ func (p *Parser) parseDeleteStatement() (statement.Statement, error) {
 		return nil, pErr
 	}
 
+	// Parse optional joined tables: "USING table_name [JOIN ...]".
+	ok, err := p.parseOptional(scanner.USING)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		stmt.Using, err = p.parseJoinedTables()
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Parse condition: "WHERE EXPR".
 	stmt.WhereExpr, err = p.parseCondition()
 	if err != nil {
 		return nil, err
 	}
 
-	// Parse order by: "ORDER BY path [ASC|DESC]?"
-	stmt.OrderBy, stmt.OrderByDirection, err = p.parseOrderBy()
+	// Parse order by: "ORDER BY expr [ASC|DESC]?, ..."
+	stmt.OrderBy, stmt.OrderBySortOrder, err = p.parseOrderBy()
 	if err != nil {
 		return nil, err
 	}
@@ -49,5 +61,10 @@ func (p *Parser) parseDeleteStatement() (statement.Statement, error) {
 		return nil, err
 	}
 
+	stmt.Returning, err = p.parseReturning()
+	if err != nil {
+		return nil, err
+	}
+
 	return stmt, nil
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/chaisql/chai/internal/query/statement"
 	"github.com/chaisql/chai/internal/sql/parser"
 	"github.com/chaisql/chai/internal/stream"
+	"github.com/chaisql/chai/internal/stream/join"
 	"github.com/chaisql/chai/internal/stream/rows"
 	"github.com/chaisql/chai/internal/stream/table"
 	"github.com/chaisql/chai/internal/testutil"
@@ -50,6 +51,19 @@ func TestParserDelete(t *testing.T) {
 				Pipe(table.Delete("test")).
 				Pipe(stream.Discard()),
 		},
+		{"WithUsing", "DELETE FROM test USING test AS t WHERE t.age = test.age",
+			stream.New(table.Scan("test")).
+				Pipe(join.NestedLoop(stream.New(table.Scan("test")), "t", parser.MustParseExpr("t.age = test.age"))).
+				Pipe(join.Target("test")).
+				Pipe(table.Delete("test")).
+				Pipe(stream.Discard()),
+		},
+		{"WithReturning", "DELETE FROM test WHERE age = 10 RETURNING a, b AS c",
+			stream.New(table.Scan("test")).
+				Pipe(rows.Filter(parser.MustParseExpr("age = 10"))).
+				Pipe(table.Delete("test")).
+				Pipe(rows.Project(testutil.ParseNamedExpr(t, "a"), testutil.ParseNamedExpr(t, "b", "c"))),
+		},
 		{"WithOrderByThenLimitThenOffset", "DELETE FROM test WHERE age = 10 ORDER BY age LIMIT 10 OFFSET 20",
 			stream.New(table.Scan("test")).
 				Pipe(rows.Filter(parser.MustParseExpr("age = 10"))).
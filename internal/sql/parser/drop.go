@@ -15,16 +15,20 @@ func (p *Parser) parseDropStatement() (statement.Statement, error) {
 	}
 
 	tok, pos, lit := p.ScanIgnoreWhitespace()
-	switch tok {
+	switch p.keyword(tok, lit) {
 	case scanner.TABLE:
 		return p.parseDropTableStatement()
 	case scanner.INDEX:
 		return p.parseDropIndexStatement()
 	case scanner.SEQUENCE:
 		return p.parseDropSequenceStatement()
+	case scanner.VIEW:
+		return p.parseDropViewStatement()
+	case scanner.TRIGGER:
+		return p.parseDropTriggerStatement()
 	}
 
-	return nil, newParseError(scanner.Tokstr(tok, lit), []string{"TABLE", "INDEX", "SEQUENCE"}, pos)
+	return nil, newParseError(scanner.Tokstr(tok, lit), []string{"TABLE", "INDEX", "SEQUENCE", "VIEW", "TRIGGER"}, pos)
 }
 
 // parseDropTableStatement parses a drop table string and returns a Statement AST object.
@@ -46,7 +50,14 @@ func (p *Parser) parseDropTableStatement() (statement.DropTableStmt, error) {
 		return stmt, pErr
 	}
 
-	return stmt, nil
+	// Parse optional CASCADE or RESTRICT, the latter being the default
+	stmt.Cascade, err = p.parseOptional(scanner.CASCADE)
+	if err != nil || stmt.Cascade {
+		return stmt, err
+	}
+	_, err = p.parseOptional(scanner.RESTRICT)
+
+	return stmt, err
 }
 
 // parseDropIndexStatement parses a drop index string and returns a Statement AST object.
@@ -92,3 +103,54 @@ func (p *Parser) parseDropSequenceStatement() (statement.DropSequenceStmt, error
 
 	return stmt, nil
 }
+
+// parseDropViewStatement parses a drop view string and returns a Statement AST object.
+// This function assumes the DROP VIEW tokens have already been consumed.
+func (p *Parser) parseDropViewStatement() (statement.DropViewStmt, error) {
+	var stmt statement.DropViewStmt
+	var err error
+
+	stmt.IfExists, err = p.parseOptional(scanner.IF, scanner.EXISTS)
+	if err != nil {
+		return stmt, err
+	}
+
+	// Parse view name
+	stmt.ViewName, err = p.parseIdent()
+	if err != nil {
+		pErr := errors.Unwrap(err).(*ParseError)
+		pErr.Expected = []string{"view_name"}
+		return stmt, pErr
+	}
+
+	// Parse optional CASCADE or RESTRICT, the latter being the default
+	stmt.Cascade, err = p.parseOptional(scanner.CASCADE)
+	if err != nil || stmt.Cascade {
+		return stmt, err
+	}
+	_, err = p.parseOptional(scanner.RESTRICT)
+
+	return stmt, err
+}
+
+// parseDropTriggerStatement parses a drop trigger string and returns a Statement AST object.
+// This function assumes the DROP TRIGGER tokens have already been consumed.
+func (p *Parser) parseDropTriggerStatement() (statement.DropTriggerStmt, error) {
+	var stmt statement.DropTriggerStmt
+	var err error
+
+	stmt.IfExists, err = p.parseOptional(scanner.IF, scanner.EXISTS)
+	if err != nil {
+		return stmt, err
+	}
+
+	// Parse trigger name
+	stmt.TriggerName, err = p.parseIdent()
+	if err != nil {
+		pErr := errors.Unwrap(err).(*ParseError)
+		pErr.Expected = []string{"trigger_name"}
+		return stmt, pErr
+	}
+
+	return stmt, nil
+}
@@ -13,10 +13,16 @@ func (p *Parser) parseExplainStatement() (statement.Statement, error) {
 		return nil, err
 	}
 
+	// Parse optional "ANALYZE".
+	analyze, err := p.parseOptional(scanner.ANALYZE)
+	if err != nil {
+		return nil, err
+	}
+
 	// ensure we don't have multiple EXPLAIN keywords
 	tok, pos, lit := p.ScanIgnoreWhitespace()
-	if tok != scanner.SELECT && tok != scanner.UPDATE && tok != scanner.DELETE && tok != scanner.INSERT {
-		return nil, newParseError(scanner.Tokstr(tok, lit), []string{"INSERT", "SELECT", "UPDATE", "DELETE"}, pos)
+	if tok != scanner.SELECT && tok != scanner.WITH && tok != scanner.UPDATE && tok != scanner.DELETE && tok != scanner.INSERT {
+		return nil, newParseError(scanner.Tokstr(tok, lit), []string{"INSERT", "SELECT", "WITH", "UPDATE", "DELETE"}, pos)
 	}
 	p.Unscan()
 
@@ -25,5 +31,5 @@ func (p *Parser) parseExplainStatement() (statement.Statement, error) {
 		return nil, err
 	}
 
-	return &statement.ExplainStmt{Statement: innerStmt.(statement.Preparer)}, nil
+	return &statement.ExplainStmt{Statement: innerStmt.(statement.Preparer), Analyze: analyze}, nil
 }
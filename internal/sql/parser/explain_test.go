@@ -23,6 +23,8 @@ func TestParserExplain(t *testing.T) {
 		errored  bool
 	}{
 		{"Explain select", "EXPLAIN SELECT * FROM test", &statement.ExplainStmt{Statement: slct}, false},
+		{"Explain analyze select", "EXPLAIN ANALYZE SELECT * FROM test", &statement.ExplainStmt{Statement: slct, Analyze: true}, false},
+		{"Explain analyze table", "EXPLAIN ANALYZE test", nil, true},
 		{"Multiple Explains", "EXPLAIN EXPLAIN CREATE TABLE test", nil, true},
 	}
 
@@ -9,6 +9,7 @@ import (
 	"github.com/chaisql/chai/internal/environment"
 	"github.com/chaisql/chai/internal/expr"
 	"github.com/chaisql/chai/internal/object"
+	"github.com/chaisql/chai/internal/query/statement"
 	"github.com/chaisql/chai/internal/sql/scanner"
 	"github.com/chaisql/chai/internal/types"
 	"github.com/cockroachdb/errors"
@@ -64,6 +65,11 @@ func (p *Parser) parseExprWithMinPrecedence(precedence int, allowed ...scanner.T
 			return nil, err
 		}
 
+		// the right operand of IN is the list of values returned by the subquery
+		if sq, ok := rhs.(*statement.ScalarSubquery); ok && (tok == scanner.IN || tok == scanner.NIN) {
+			rhs = statement.NewArraySubquery(sq.Stmt)
+		}
+
 		// Find the right spot in the tree to add the new expression by
 		// descending the RHS of the expression tree until we reach the last
 		// BinaryExpr or a BinaryExpr whose RHS has an operator with
@@ -81,7 +87,8 @@ func (p *Parser) parseExprWithMinPrecedence(precedence int, allowed ...scanner.T
 }
 
 func (p *Parser) parseOperator(minPrecedence int, allowed ...scanner.Token) (func(lhs, rhs expr.Expr) expr.Expr, scanner.Token, error) {
-	op, _, _ := p.ScanIgnoreWhitespace()
+	op, _, lit := p.ScanIgnoreWhitespace()
+	op = p.keyword(op, lit)
 	if !op.IsOperator() && op != scanner.NOT {
 		p.Unscan()
 		return nil, 0, nil
@@ -160,6 +167,8 @@ func (p *Parser) parseOperator(minPrecedence int, allowed ...scanner.Token) (fun
 		return expr.Is, op, nil
 	case scanner.LIKE:
 		return expr.Like, op, nil
+	case scanner.MATCH:
+		return expr.Match, op, nil
 	case scanner.CONCAT:
 		return expr.Concat, op, nil
 	case scanner.BETWEEN:
@@ -193,6 +202,9 @@ func (p *Parser) parseUnaryExpr(allowed ...scanner.Token) (expr.Expr, error) {
 	case scanner.CAST:
 		p.Unscan()
 		return p.parseCastExpression()
+	case scanner.CASE:
+		p.Unscan()
+		return p.parseCaseExpression()
 	case scanner.IDENT:
 		tok1, _, _ := p.ScanIgnoreWhitespace()
 		// if the next token is a left parenthesis, this is a global function
@@ -301,6 +313,17 @@ func (p *Parser) parseUnaryExpr(allowed ...scanner.Token) (expr.Expr, error) {
 		p.Unscan()
 		return p.parseExprList(scanner.LSBRACKET, scanner.RSBRACKET)
 	case scanner.LPAREN:
+		// "(SELECT ...)" is a subquery
+		if tok, _, _ := p.ScanIgnoreWhitespace(); tok == scanner.SELECT || tok == scanner.WITH {
+			p.Unscan()
+			stmt, err := p.parseSubqueryStatement()
+			if err != nil {
+				return nil, err
+			}
+			return statement.NewScalarSubquery(stmt), nil
+		}
+		p.Unscan()
+
 		e, err := p.ParseExpr()
 		if err != nil {
 			return nil, err
@@ -328,6 +351,17 @@ func (p *Parser) parseUnaryExpr(allowed ...scanner.Token) (expr.Expr, error) {
 			return nil, err
 		}
 		return expr.Not(e), nil
+	case scanner.EXISTS:
+		err := p.parseTokens(scanner.LPAREN)
+		if err != nil {
+			return nil, err
+		}
+
+		stmt, err := p.parseSubqueryStatement()
+		if err != nil {
+			return nil, err
+		}
+		return statement.NewExistsSubquery(stmt), nil
 	case scanner.NEXT:
 		err := p.parseTokens(scanner.VALUE, scanner.FOR)
 		if err != nil {
@@ -344,6 +378,22 @@ func (p *Parser) parseUnaryExpr(allowed ...scanner.Token) (expr.Expr, error) {
 	}
 }
 
+// parseSubqueryStatement parses a SELECT statement followed by a closing parenthesis.
+// This function assumes the opening parenthesis has already been consumed.
+func (p *Parser) parseSubqueryStatement() (*statement.SelectStmt, error) {
+	stmt, err := p.parseSelectStatement()
+	if err != nil {
+		return nil, err
+	}
+
+	err = p.parseTokens(scanner.RPAREN)
+	if err != nil {
+		return nil, err
+	}
+
+	return stmt, nil
+}
+
 // parseInteger parses an integer.
 func (p *Parser) parseInteger() (int64, error) {
 	tok, pos, lit := p.ScanIgnoreWhitespace()
@@ -571,6 +621,12 @@ LOOP:
 			// if it's a quoted string, we have a field name
 			tok, pos, lit := p.Scan()
 			switch tok {
+			case scanner.MUL:
+				// [*] refers to all the elements of the array,
+				// which is only meaningful to the caller
+				p.Unscan()
+				p.Unscan()
+				break LOOP
 			case scanner.INTEGER:
 				// is the number negative?
 				if lit[0] == '-' {
@@ -592,6 +648,8 @@ LOOP:
 				path = append(path, object.PathFragment{
 					FieldName: lit,
 				})
+			default:
+				return nil, newParseError(scanner.Tokstr(tok, lit), []string{"integer", "string"}, pos)
 			}
 			// scan the next token for a closing left bracket
 			if err := p.parseTokens(scanner.RSBRACKET); err != nil {
@@ -676,7 +734,11 @@ func (p *Parser) parseFunction() (expr.Expr, error) {
 		if err != nil {
 			return nil, err
 		}
-		return def.Function()
+		fn, err := def.Function()
+		if err != nil {
+			return nil, err
+		}
+		return p.parseOver(fn)
 	}
 	p.Unscan()
 
@@ -706,7 +768,11 @@ func (p *Parser) parseFunction() (expr.Expr, error) {
 	if err != nil {
 		return nil, err
 	}
-	return def.Function(exprs...)
+	fn, err := def.Function(exprs...)
+	if err != nil {
+		return nil, err
+	}
+	return p.parseOver(fn)
 }
 
 // parseCastExpression parses a string of the form CAST(expr AS type).
@@ -753,3 +819,78 @@ func tokenIsAllowed(tok scanner.Token, allowed ...scanner.Token) bool {
 	}
 	return false
 }
+
+// parseCaseExpression parses a CASE expression, in its simple or searched form.
+// "CASE [expr] WHEN expr THEN expr [WHEN expr THEN expr]* [ELSE expr] END"
+func (p *Parser) parseCaseExpression() (expr.Expr, error) {
+	// Parse required CASE token.
+	if err := p.parseTokens(scanner.CASE); err != nil {
+		return nil, err
+	}
+
+	var c expr.Case
+
+	// Parse optional operand.
+	tok, _, lit := p.ScanIgnoreWhitespace()
+	tok = p.keyword(tok, lit)
+	p.Unscan()
+	if tok != scanner.WHEN && tok != scanner.END {
+		e, err := p.ParseExpr()
+		if err != nil {
+			return nil, err
+		}
+		c.Operand = e
+	}
+
+	// Parse at least one WHEN clause.
+	for {
+		ok, err := p.parseOptional(scanner.WHEN)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+
+		var w expr.WhenClause
+		w.Cond, err = p.ParseExpr()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := p.parseTokens(scanner.THEN); err != nil {
+			return nil, err
+		}
+
+		w.Then, err = p.ParseExpr()
+		if err != nil {
+			return nil, err
+		}
+
+		c.Whens = append(c.Whens, w)
+	}
+
+	if len(c.Whens) == 0 {
+		tok, pos, lit := p.ScanIgnoreWhitespace()
+		return nil, newParseError(scanner.Tokstr(tok, lit), []string{"WHEN"}, pos)
+	}
+
+	// Parse optional ELSE clause.
+	ok, err := p.parseOptional(scanner.ELSE)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		c.Else, err = p.ParseExpr()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Parse required END token.
+	if err := p.parseTokens(scanner.END); err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
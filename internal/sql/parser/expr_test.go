@@ -7,14 +7,22 @@ import (
 	"github.com/chaisql/chai/internal/expr"
 	"github.com/chaisql/chai/internal/expr/functions"
 	"github.com/chaisql/chai/internal/object"
+	"github.com/chaisql/chai/internal/query/statement"
 	"github.com/chaisql/chai/internal/sql/parser"
 	"github.com/chaisql/chai/internal/testutil"
 	"github.com/chaisql/chai/internal/testutil/assert"
+	"github.com/chaisql/chai/internal/tree"
 	"github.com/chaisql/chai/internal/types"
 	"github.com/stretchr/testify/require"
 )
 
 func TestParserExpr(t *testing.T) {
+	parseSelect := func(s string) *statement.SelectStmt {
+		q, err := parser.ParseQuery(s)
+		assert.NoError(t, err)
+		return q.Statements[0].(*statement.SelectStmt)
+	}
+
 	tests := []struct {
 		name     string
 		s        string
@@ -130,6 +138,8 @@ func TestParserExpr(t *testing.T) {
 		{"IS NOT", "age IS NOT NULL", expr.IsNot(testutil.ParsePath(t, "age"), testutil.NullValue()), false},
 		{"LIKE", "name LIKE 'foo'", expr.Like(testutil.ParsePath(t, "name"), testutil.TextValue("foo")), false},
 		{"NOT LIKE", "name NOT LIKE 'foo'", expr.NotLike(testutil.ParsePath(t, "name"), testutil.TextValue("foo")), false},
+		{"MATCH", "name MATCH 'foo'", expr.Match(testutil.ParsePath(t, "name"), testutil.TextValue("foo")), false},
+		{"@@", "name @@ 'foo' AND age > 10", expr.And(expr.Match(testutil.ParsePath(t, "name"), testutil.TextValue("foo")), expr.Gt(testutil.ParsePath(t, "age"), testutil.IntegerValue(10))), false},
 		{"NOT =", "name NOT = 'foo'", nil, true},
 		{"precedence", "4 > 1 + 2", expr.Gt(
 			testutil.IntegerValue(4),
@@ -173,6 +183,63 @@ func TestParserExpr(t *testing.T) {
 		{"count(*) function", "count(*)", functions.NewCount(expr.Wildcard{}), false},
 		{"count (*) function with spaces", "count      (*)", functions.NewCount(expr.Wildcard{}), false},
 		{"packaged function", "math.floor(1.2)", testutil.FunctionExpr(t, "math.floor", testutil.DoubleValue(1.2)), false},
+
+		// subqueries
+		{"scalar subquery", "(SELECT a FROM foo)", statement.NewScalarSubquery(parseSelect("SELECT a FROM foo")), false},
+		{"IN subquery", "a IN (SELECT b FROM foo WHERE c > 1)",
+			expr.In(testutil.ParsePath(t, "a"), statement.NewArraySubquery(parseSelect("SELECT b FROM foo WHERE c > 1"))), false},
+		{"NOT IN subquery", "a NOT IN (SELECT b FROM foo)",
+			expr.NotIn(testutil.ParsePath(t, "a"), statement.NewArraySubquery(parseSelect("SELECT b FROM foo"))), false},
+		{"EXISTS", "EXISTS (SELECT 1 FROM foo WHERE foo.a = bar.a)", statement.NewExistsSubquery(parseSelect("SELECT 1 FROM foo WHERE foo.a = bar.a")), false},
+		{"NOT EXISTS", "NOT EXISTS (SELECT 1 FROM foo)", expr.Not(statement.NewExistsSubquery(parseSelect("SELECT 1 FROM foo"))), false},
+		{"EXISTS without parentheses", "EXISTS SELECT 1 FROM foo", nil, true},
+		{"unclosed subquery", "(SELECT 1 FROM foo", nil, true},
+
+		// window functions
+		{"empty window", "row_number() OVER ()", &expr.Over{Fn: &functions.RowNumber{}, Window: &expr.WindowDefinition{}}, false},
+		{"partition and order", "rank() OVER (PARTITION BY a, b ORDER BY c DESC)",
+			&expr.Over{Fn: &functions.Rank{}, Window: &expr.WindowDefinition{
+				PartitionBy:      []expr.Expr{testutil.ParsePath(t, "a"), testutil.ParsePath(t, "b")},
+				OrderBy:          []expr.Expr{testutil.ParsePath(t, "c")},
+				OrderBySortOrder: tree.SortOrder(0).SetDesc(0),
+			}}, false},
+		{"aggregate with frame", "sum(a) OVER (ORDER BY b ROWS BETWEEN 2 PRECEDING AND UNBOUNDED FOLLOWING)",
+			&expr.Over{Fn: &functions.Sum{Expr: testutil.ParsePath(t, "a")}, Window: &expr.WindowDefinition{
+				OrderBy: []expr.Expr{testutil.ParsePath(t, "b")},
+				Frame: &expr.WindowFrame{
+					Start: expr.FrameBound{Type: expr.Preceding, Offset: 2},
+					End:   expr.FrameBound{Type: expr.UnboundedFollowing},
+				},
+			}}, false},
+		{"frame start only", "count(*) OVER (ROWS UNBOUNDED PRECEDING)",
+			&expr.Over{Fn: functions.NewCount(expr.Wildcard{}), Window: &expr.WindowDefinition{
+				Frame: &expr.WindowFrame{
+					Start: expr.FrameBound{Type: expr.UnboundedPreceding},
+					End:   expr.FrameBound{Type: expr.CurrentRow},
+				},
+			}}, false},
+		{"window function without OVER", "lag(a)", nil, true},
+		{"OVER on a scalar function", "typeof(a) OVER ()", nil, true},
+		{"nested window functions", "lag(row_number() OVER ()) OVER ()", nil, true},
+		{"invalid frame", "sum(a) OVER (ROWS BETWEEN UNBOUNDED FOLLOWING AND CURRENT ROW)", nil, true},
+		{"unclosed window", "row_number() OVER (ORDER BY a", nil, true},
+
+		// case
+		{"searched case", "CASE WHEN a > 1 THEN 'big' ELSE 'small' END",
+			&expr.Case{
+				Whens: []expr.WhenClause{{Cond: expr.Gt(testutil.ParsePath(t, "a"), testutil.IntegerValue(1)), Then: testutil.TextValue("big")}},
+				Else:  testutil.TextValue("small"),
+			}, false},
+		{"simple case", "CASE a WHEN 1 THEN 'one' WHEN 2 THEN 'two' END",
+			&expr.Case{
+				Operand: testutil.ParsePath(t, "a"),
+				Whens: []expr.WhenClause{
+					{Cond: testutil.IntegerValue(1), Then: testutil.TextValue("one")},
+					{Cond: testutil.IntegerValue(2), Then: testutil.TextValue("two")},
+				},
+			}, false},
+		{"case without when", "CASE a END", nil, true},
+		{"case without end", "CASE WHEN a THEN 1", nil, true},
 	}
 
 	for _, test := range tests {
@@ -230,6 +297,7 @@ func TestParsePath(t *testing.T) {
 		{"with spaces", `a.  b[100].  c`, nil, true},
 		{"starting with array", `[10].a`, nil, true},
 		{"starting with brackets", `['a']`, nil, true},
+		{"with ident in brackets", `a[b]`, nil, true},
 	}
 
 	for _, test := range tests {
@@ -43,7 +43,7 @@ func (p *Parser) parseInsertStatement() (*statement.InsertStmt, error) {
 		if err != nil {
 			return nil, err
 		}
-	case scanner.SELECT:
+	case scanner.SELECT, scanner.WITH:
 		p.Unscan()
 		stmt.SelectStmt, err = p.parseSelectStatement()
 		if err != nil {
@@ -54,7 +54,7 @@ func (p *Parser) parseInsertStatement() (*statement.InsertStmt, error) {
 	}
 
 	// Parse ON CONFLICT clause
-	stmt.OnConflict, err = p.parseOnConflictClause()
+	err = p.parseOnConflictClause(stmt)
 	if err != nil {
 		return nil, err
 	}
@@ -214,34 +214,57 @@ func (p *Parser) parseParamOrObject() (expr.Expr, error) {
 	return p.ParseObject()
 }
 
-func (p *Parser) parseOnConflictClause() (database.OnConflictAction, error) {
-	// Parse ON CONFLICT DO clause: ON CONFLICT DO action
+func (p *Parser) parseOnConflictClause(stmt *statement.InsertStmt) error {
+	// Parse ON CONFLICT DO clause: ON CONFLICT [(column, ...)] DO action
 	if ok, err := p.parseOptional(scanner.ON, scanner.CONFLICT); !ok || err != nil {
-		return 0, err
+		return err
+	}
+
+	var err error
+	stmt.OnConflictTarget, err = p.parseFieldList()
+	if err != nil {
+		return err
 	}
 
 	tok, pos, lit := p.ScanIgnoreWhitespace()
 	// SQLite compatibility: ON CONFLICT [IGNORE | REPLACE]
 	switch tok {
 	case scanner.IGNORE:
-		return database.OnConflictDoNothing, nil
+		stmt.OnConflict = database.OnConflictDoNothing
+		return nil
 	case scanner.REPLACE:
-		return database.OnConflictDoReplace, nil
+		stmt.OnConflict = database.OnConflictDoReplace
+		return nil
 	}
 
-	// DO [NOTHING | REPLACE]
+	// DO [NOTHING | REPLACE | UPDATE SET ... [WHERE expr]]
 	if tok != scanner.DO {
-		return 0, newParseError(scanner.Tokstr(tok, lit), []string{scanner.DO.String()}, pos)
+		return newParseError(scanner.Tokstr(tok, lit), []string{scanner.DO.String()}, pos)
 	}
 
 	tok, pos, lit = p.ScanIgnoreWhitespace()
 	switch tok {
 	case scanner.NOTHING:
-		return database.OnConflictDoNothing, nil
+		stmt.OnConflict = database.OnConflictDoNothing
+		return nil
 	case scanner.REPLACE:
-		return database.OnConflictDoReplace, nil
+		stmt.OnConflict = database.OnConflictDoReplace
+		return nil
+	case scanner.UPDATE:
+		stmt.OnConflict = database.OnConflictDoUpdate
+		if err := p.parseTokens(scanner.SET); err != nil {
+			return err
+		}
+
+		stmt.OnConflictSet, err = p.parseSetClause()
+		if err != nil {
+			return err
+		}
+
+		stmt.OnConflictWhere, err = p.parseCondition()
+		return err
 	}
-	return 0, newParseError(scanner.Tokstr(tok, lit), []string{scanner.NOTHING.String(), scanner.REPLACE.String()}, pos)
+	return newParseError(scanner.Tokstr(tok, lit), []string{scanner.NOTHING.String(), scanner.REPLACE.String(), scanner.UPDATE.String()}, pos)
 }
 
 func (p *Parser) parseReturning() ([]expr.Expr, error) {
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/chaisql/chai/internal/expr"
+	"github.com/chaisql/chai/internal/object"
 	"github.com/chaisql/chai/internal/query"
 	"github.com/chaisql/chai/internal/query/statement"
 	"github.com/chaisql/chai/internal/sql/parser"
@@ -156,6 +157,27 @@ func TestParserInsert(t *testing.T) {
 				Pipe(stream.OnConflict(stream.New(table.Replace("test")))).
 				Pipe(table.Insert("test")),
 			false},
+		{"Values / ON CONFLICT DO UPDATE", "INSERT INTO test (a, b) VALUES ('c', 'd') ON CONFLICT DO UPDATE SET b = excluded.b, c = c + 1 WHERE c < 10",
+			stream.New(rows.Emit(
+				&expr.KVPairs{Pairs: []expr.KVPair{
+					{K: "a", V: testutil.TextValue("c")},
+					{K: "b", V: testutil.TextValue("d")},
+				}},
+			)).
+				Pipe(table.Validate("test")).
+				Pipe(stream.OnConflict(
+					stream.New(table.Lookup("test")).
+						Pipe(rows.Filter(parser.MustParseExpr("c < 10"))).
+						Pipe(path.Set(object.NewPath("b"), parser.MustParseExpr("excluded.b"))).
+						Pipe(path.Set(object.NewPath("c"), parser.MustParseExpr("c + 1"))).
+						Pipe(table.Validate("test")).
+						Pipe(table.Replace("test")),
+				)).
+				Pipe(table.Insert("test")).
+				Pipe(stream.Discard()),
+			false},
+		{"Values / ON CONFLICT DO UPDATE without SET", "INSERT INTO test (a, b) VALUES ('c', 'd') ON CONFLICT DO UPDATE",
+			nil, true},
 		{"Values / ON CONFLICT BLA", "INSERT INTO test (a, b) VALUES ('c', 'd') ON CONFLICT BLA RETURNING *",
 			nil, true},
 		{"Values / ON CONFLICT DO BLA", "INSERT INTO test (a, b) VALUES ('c', 'd') ON CONFLICT DO BLA RETURNING *",
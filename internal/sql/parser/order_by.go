@@ -2,31 +2,51 @@ package parser
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/chaisql/chai/internal/expr"
 	"github.com/chaisql/chai/internal/sql/scanner"
+	"github.com/chaisql/chai/internal/stream/rows"
+	"github.com/chaisql/chai/internal/tree"
 )
 
-func (p *Parser) parseOrderBy() (expr.Path, scanner.Token, error) {
+// parseOrderBy parses the list of terms of an ORDER BY clause
+// and returns the direction of each term.
+// "ORDER BY expr [ASC|DESC] [, expr [ASC|DESC]]*"
+func (p *Parser) parseOrderBy() ([]expr.Expr, tree.SortOrder, error) {
 	// parse ORDER token
 	ok, err := p.parseOptional(scanner.ORDER, scanner.BY)
 	if err != nil || !ok {
 		return nil, 0, err
 	}
 
-	// parse path
-	path, err := p.parsePath()
-	if err != nil {
-		return nil, 0, err
-	}
+	var exprs []expr.Expr
+	var order tree.SortOrder
 
-	// parse optional ASC or DESC
-	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == scanner.ASC || tok == scanner.DESC {
-		return expr.Path(path), tok, nil
+	for i := 0; ; i++ {
+		e, err := p.ParseExpr()
+		if err != nil {
+			return nil, 0, err
+		}
+		exprs = append(exprs, e)
+		if len(exprs) > rows.MaxSortExprs {
+			return nil, 0, fmt.Errorf("too many terms in ORDER BY, the maximum is %d", rows.MaxSortExprs)
+		}
+
+		// parse optional ASC or DESC
+		if tok, _, _ := p.ScanIgnoreWhitespace(); tok == scanner.DESC {
+			order = order.SetDesc(i)
+		} else if tok != scanner.ASC {
+			p.Unscan()
+		}
+
+		if tok, _, _ := p.ScanIgnoreWhitespace(); tok != scanner.COMMA {
+			p.Unscan()
+			break
+		}
 	}
-	p.Unscan()
 
-	return expr.Path(path), 0, nil
+	return exprs, order, nil
 }
 
 func (p *Parser) parseLimit() (expr.Expr, error) {
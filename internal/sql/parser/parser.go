@@ -3,6 +3,7 @@ package parser
 import (
 	"fmt"
 	"io"
+	"strconv"
 	"strings"
 
 	"github.com/chaisql/chai/internal/expr"
@@ -11,6 +12,7 @@ import (
 	"github.com/chaisql/chai/internal/query"
 	"github.com/chaisql/chai/internal/query/statement"
 	"github.com/chaisql/chai/internal/sql/scanner"
+	"github.com/chaisql/chai/internal/stringutil"
 	"github.com/chaisql/chai/internal/tree"
 	"github.com/cockroachdb/errors"
 )
@@ -21,6 +23,16 @@ type Parser struct {
 	orderedParams int
 	namedParams   int
 	packagesTable functions.Packages
+	// if recording is true, the scanned tokens are stored in recorded.
+	recording bool
+	recorded  []recordedToken
+}
+
+// a recordedToken is a token scanned while recording.
+type recordedToken struct {
+	tok scanner.Token
+	pos scanner.Pos
+	lit string
 }
 
 // NewParser returns a new instance of Parser.
@@ -118,15 +130,18 @@ func (p *Parser) Parse(fn func(statement.Statement) error) error {
 // ParseStatement parses a Chai SQL string and returns a statement.
 func (p *Parser) ParseStatement() (statement.Statement, error) {
 	tok, pos, lit := p.ScanIgnoreWhitespace()
+	kw := p.keyword(tok, lit)
 	p.Unscan()
-	switch tok {
+	switch kw {
 	case scanner.ALTER:
 		return p.parseAlterStatement()
+	case scanner.ANALYZE:
+		return p.parseAnalyzeStatement()
 	case scanner.BEGIN:
 		return p.parseBeginStatement()
 	case scanner.COMMIT:
 		return p.parseCommitStatement()
-	case scanner.SELECT:
+	case scanner.SELECT, scanner.WITH:
 		return p.parseSelectStatement()
 	case scanner.DELETE:
 		return p.parseDeleteStatement()
@@ -147,7 +162,7 @@ func (p *Parser) ParseStatement() (statement.Statement, error) {
 	}
 
 	return nil, newParseError(scanner.Tokstr(tok, lit), []string{
-		"ALTER", "BEGIN", "COMMIT", "SELECT", "DELETE", "UPDATE", "INSERT", "CREATE", "DROP", "EXPLAIN", "REINDEX", "ROLLBACK",
+		"ALTER", "ANALYZE", "BEGIN", "COMMIT", "SELECT", "WITH", "DELETE", "UPDATE", "INSERT", "CREATE", "DROP", "EXPLAIN", "REINDEX", "ROLLBACK",
 	}, pos)
 }
 
@@ -253,7 +268,14 @@ func (p *Parser) parsePathList() ([]object.Path, tree.SortOrder, error) {
 }
 
 // Scan returns the next token from the underlying scanner.
-func (p *Parser) Scan() (tok scanner.Token, pos scanner.Pos, lit string) { return p.s.Scan() }
+func (p *Parser) Scan() (tok scanner.Token, pos scanner.Pos, lit string) {
+	tok, pos, lit = p.s.Scan()
+	if p.recording {
+		p.recorded = append(p.recorded, recordedToken{tok: tok, pos: pos, lit: lit})
+	}
+
+	return tok, pos, lit
+}
 
 // ScanIgnoreWhitespace scans the next non-whitespace and non-comment token.
 func (p *Parser) ScanIgnoreWhitespace() (tok scanner.Token, pos scanner.Pos, lit string) {
@@ -269,13 +291,46 @@ func (p *Parser) ScanIgnoreWhitespace() (tok scanner.Token, pos scanner.Pos, lit
 // Unscan pushes the previously read token back onto the buffer.
 func (p *Parser) Unscan() {
 	p.s.Unscan()
+	if p.recording && len(p.recorded) > 0 {
+		p.recorded = p.recorded[:len(p.recorded)-1]
+	}
+}
+
+// record calls fn and returns the text of the tokens it parsed.
+// Identifiers and strings are quoted, keywords are uppercased and comments are removed.
+func (p *Parser) record(fn func() error) (string, error) {
+	p.recording = true
+	p.recorded = p.recorded[:0]
+	err := fn()
+	p.recording = false
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	for _, t := range p.recorded {
+		switch t.tok {
+		case scanner.WS:
+			sb.WriteString(t.lit)
+		case scanner.COMMENT:
+			sb.WriteRune(' ')
+		case scanner.IDENT:
+			sb.WriteString(stringutil.NormalizeIdentifier(t.lit, '`'))
+		case scanner.STRING:
+			sb.WriteString(strconv.Quote(t.lit))
+		default:
+			sb.WriteString(scanner.Tokstr(t.tok, t.lit))
+		}
+	}
+
+	return strings.TrimSpace(sb.String()), nil
 }
 
 // parseTokens parses all the given tokens one after the other.
 // It returns an error if one of the token is missing.
 func (p *Parser) parseTokens(tokens ...scanner.Token) error {
 	for _, t := range tokens {
-		if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != t {
+		if tok, pos, lit := p.ScanIgnoreWhitespace(); p.keyword(tok, lit) != t {
 			return newParseError(scanner.Tokstr(tok, lit), []string{t.String()}, pos)
 		}
 	}
@@ -288,7 +343,7 @@ func (p *Parser) parseTokens(tokens ...scanner.Token) error {
 // must be parsed otherwise an error is returned.
 func (p *Parser) parseOptional(tokens ...scanner.Token) (bool, error) {
 	// Parse optional first token
-	if tok, _, _ := p.ScanIgnoreWhitespace(); tok != tokens[0] {
+	if tok, _, lit := p.ScanIgnoreWhitespace(); p.keyword(tok, lit) != tokens[0] {
 		p.Unscan()
 		return false, nil
 	}
@@ -301,6 +356,28 @@ func (p *Parser) parseOptional(tokens ...scanner.Token) (bool, error) {
 	return err == nil, err
 }
 
+// keyword returns the keyword spelled by the last scanned token, if it is
+// a non-reserved keyword, or tok otherwise.
+// If the parser is recording, the token is recorded as a keyword.
+func (p *Parser) keyword(tok scanner.Token, lit string) scanner.Token {
+	if tok != scanner.IDENT {
+		return tok
+	}
+
+	kw, ok := scanner.Keyword(lit)
+	if !ok {
+		return tok
+	}
+
+	if p.recording && len(p.recorded) > 0 {
+		if t := &p.recorded[len(p.recorded)-1]; t.tok == scanner.IDENT && t.lit == lit {
+			t.tok, t.lit = kw, ""
+		}
+	}
+
+	return kw
+}
+
 // ParseError represents an error that occurred during parsing.
 type ParseError struct {
 	Message  string
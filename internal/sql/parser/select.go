@@ -4,6 +4,7 @@ import (
 	"github.com/chaisql/chai/internal/expr"
 	"github.com/chaisql/chai/internal/query/statement"
 	"github.com/chaisql/chai/internal/sql/scanner"
+	"github.com/chaisql/chai/internal/stream/rows"
 	"github.com/cockroachdb/errors"
 )
 
@@ -12,14 +13,20 @@ import (
 func (p *Parser) parseSelectStatement() (*statement.SelectStmt, error) {
 	stmt := statement.NewSelectStatement()
 
-	// Parse SELECT ... [UNION | UNION ALL | INTERSECT] SELECT ...
-	err := p.parseCompoundSelectStatement(stmt)
+	// Parse optional WITH clause
+	err := p.parseWith(stmt)
 	if err != nil {
 		return nil, err
 	}
 
-	// Parse order by: "ORDER BY path [ASC|DESC]?"
-	stmt.OrderBy, stmt.OrderByDirection, err = p.parseOrderBy()
+	// Parse SELECT ... [UNION | INTERSECT | EXCEPT] [ALL] SELECT ...
+	err = p.parseCompoundSelectStatement(stmt)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse order by: "ORDER BY expr [ASC|DESC]?, ..."
+	stmt.OrderBy, stmt.OrderBySortOrder, err = p.parseOrderBy()
 	if err != nil {
 		return nil, err
 	}
@@ -39,33 +46,83 @@ func (p *Parser) parseSelectStatement() (*statement.SelectStmt, error) {
 	return stmt, nil
 }
 
-func (p *Parser) parseCompoundSelectStatement(stmt *statement.SelectStmt) error {
+// parseWith parses the optional list of common table expressions preceding a SELECT statement.
+// "WITH [RECURSIVE] name [(column [, column]*)] AS (select_stmt) [, ...]"
+func (p *Parser) parseWith(stmt *statement.SelectStmt) error {
+	if ok, err := p.parseOptional(scanner.WITH); !ok || err != nil {
+		return err
+	}
+
+	var err error
+	stmt.Recursive, err = p.parseOptional(scanner.RECURSIVE)
+	if err != nil {
+		return err
+	}
+
 	for {
-		core, err := p.parseSelectCore()
+		var cte statement.CommonTableExpr
+
+		cte.Name, err = p.parseIdent()
 		if err != nil {
 			return err
 		}
 
-		// Parse optional compound operator
-		tok, _, _ := p.ScanIgnoreWhitespace()
-		if tok == scanner.UNION {
-			all, err := p.parseOptional(scanner.ALL)
+		// Parse optional column list
+		ok, err := p.parseOptional(scanner.LPAREN)
+		if err != nil {
+			return err
+		}
+		if ok {
+			cte.Columns, err = p.parseIdentList()
 			if err != nil {
 				return err
 			}
-			if all {
-				tok = scanner.ALL
+
+			if err := p.parseTokens(scanner.RPAREN); err != nil {
+				return err
 			}
 		}
 
+		if err := p.parseTokens(scanner.AS, scanner.LPAREN); err != nil {
+			return err
+		}
+
+		cte.Stmt, err = p.parseSubqueryStatement()
+		if err != nil {
+			return err
+		}
+
+		stmt.With = append(stmt.With, &cte)
+
+		if tok, _, _ := p.ScanIgnoreWhitespace(); tok != scanner.COMMA {
+			p.Unscan()
+			return nil
+		}
+	}
+}
+
+func (p *Parser) parseCompoundSelectStatement(stmt *statement.SelectStmt) error {
+	for {
+		core, err := p.parseSelectCore()
+		if err != nil {
+			return err
+		}
+
 		stmt.CompoundSelect = append(stmt.CompoundSelect, core)
 
-		if tok != scanner.UNION && tok != scanner.ALL {
+		// Parse optional compound operator
+		tok, _, lit := p.ScanIgnoreWhitespace()
+		if tok = p.keyword(tok, lit); tok != scanner.UNION && tok != scanner.INTERSECT && tok != scanner.EXCEPT {
 			p.Unscan()
 			break
 		}
 
-		stmt.CompoundOperators = append(stmt.CompoundOperators, tok)
+		all, err := p.parseOptional(scanner.ALL)
+		if err != nil {
+			return err
+		}
+
+		stmt.CompoundOperators = append(stmt.CompoundOperators, statement.CompoundOperator{Token: tok, All: all})
 	}
 
 	return nil
@@ -92,7 +149,7 @@ func (p *Parser) parseSelectCore() (*statement.SelectCoreStmt, error) {
 	}
 
 	// Parse "FROM".
-	stmt.TableName, err = p.parseFrom()
+	err = p.parseFrom(&stmt)
 	if err != nil {
 		return nil, err
 	}
@@ -103,8 +160,14 @@ func (p *Parser) parseSelectCore() (*statement.SelectCoreStmt, error) {
 		return nil, err
 	}
 
-	// Parse group by: "GROUP BY expr"
-	stmt.GroupByExpr, err = p.parseGroupBy()
+	// Parse group by: "GROUP BY expr [, expr]*"
+	stmt.GroupByExprs, err = p.parseGroupBy()
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse having: "HAVING expr"
+	stmt.HavingExpr, err = p.parseHaving()
 	if err != nil {
 		return nil, err
 	}
@@ -168,29 +231,177 @@ func (p *Parser) parseProjectedExpr() (expr.Expr, error) {
 	return ne, nil
 }
 
-func (p *Parser) parseFrom() (string, error) {
+// parseFrom parses the FROM clause and the optional list of joins:
+// "FROM table_name [[AS] alias] [[INNER | LEFT [OUTER]] JOIN table_name [[AS] alias] ON expr]*"
+func (p *Parser) parseFrom(stmt *statement.SelectCoreStmt) error {
 	if ok, err := p.parseOptional(scanner.FROM); !ok || err != nil {
-		return "", err
+		return err
+	}
+
+	var err error
+	stmt.TableName, stmt.TableAlias, err = p.parseTableRef()
+	if err != nil {
+		return err
+	}
+
+	for {
+		j, err := p.parseJoin()
+		if err != nil {
+			return err
+		}
+		if j == nil {
+			return nil
+		}
+
+		stmt.Joins = append(stmt.Joins, j)
 	}
+}
 
+// parseJoinedTables parses the tables joined with the table modified by
+// UPDATE ... FROM or DELETE ... USING. The first table is joined without condition:
+// "table_name [[AS] alias] [[INNER | LEFT [OUTER]] JOIN table_name [[AS] alias] ON expr]*"
+func (p *Parser) parseJoinedTables() ([]*statement.JoinClause, error) {
+	first := statement.JoinClause{Kind: scanner.INNER}
+
+	var err error
+	first.TableName, first.Alias, err = p.parseTableRef()
+	if err != nil {
+		return nil, err
+	}
+
+	joins := []*statement.JoinClause{&first}
+	for {
+		j, err := p.parseJoin()
+		if err != nil {
+			return nil, err
+		}
+		if j == nil {
+			return joins, nil
+		}
+
+		joins = append(joins, j)
+	}
+}
+
+// parseTableRef parses a table name followed by an optional alias.
+func (p *Parser) parseTableRef() (tableName string, alias string, err error) {
 	// Parse table name
-	ident, err := p.parseIdent()
+	tableName, err = p.parseIdent()
 	if err != nil {
 		pErr := errors.Unwrap(err).(*ParseError)
 		pErr.Expected = []string{"table_name"}
-		return ident, pErr
+		return tableName, "", pErr
+	}
+
+	// Parse optional alias, with or without the AS keyword
+	tok, _, lit := p.ScanIgnoreWhitespace()
+	switch tok {
+	case scanner.AS:
+		alias, err = p.parseIdent()
+		if err != nil {
+			return "", "", err
+		}
+	case scanner.IDENT:
+		// non-reserved keywords following a table, like JOIN,
+		// can only be used as an alias with the AS keyword
+		if p.keyword(tok, lit) != scanner.IDENT {
+			p.Unscan()
+			break
+		}
+		alias = lit
+	default:
+		p.Unscan()
 	}
 
-	return ident, nil
+	return tableName, alias, nil
 }
 
-func (p *Parser) parseGroupBy() (expr.Expr, error) {
+// parseJoin parses a join clause. It returns nil if there is no join clause.
+func (p *Parser) parseJoin() (*statement.JoinClause, error) {
+	var j statement.JoinClause
+
+	tok, _, lit := p.ScanIgnoreWhitespace()
+	switch p.keyword(tok, lit) {
+	case scanner.JOIN:
+		j.Kind = scanner.INNER
+	case scanner.INNER:
+		if err := p.parseTokens(scanner.JOIN); err != nil {
+			return nil, err
+		}
+		j.Kind = scanner.INNER
+	case scanner.LEFT:
+		if _, err := p.parseOptional(scanner.OUTER); err != nil {
+			return nil, err
+		}
+		if err := p.parseTokens(scanner.JOIN); err != nil {
+			return nil, err
+		}
+		j.Kind = scanner.LEFT
+	default:
+		p.Unscan()
+		return nil, nil
+	}
+
+	var err error
+	j.TableName, j.Alias, err = p.parseTableRef()
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse "ON expr"
+	if err := p.parseTokens(scanner.ON); err != nil {
+		return nil, err
+	}
+
+	j.On, err = p.ParseExpr()
+	if err != nil {
+		return nil, err
+	}
+
+	expr.Walk(j.On, func(e expr.Expr) bool {
+		switch e.(type) {
+		case expr.AggregatorBuilder:
+			err = errors.New("aggregator functions are not allowed in JOIN conditions")
+			return false
+		}
+		return true
+	})
+
+	return &j, err
+}
+
+func (p *Parser) parseGroupBy() ([]expr.Expr, error) {
 	ok, err := p.parseOptional(scanner.GROUP, scanner.BY)
 	if err != nil || !ok {
 		return nil, err
 	}
 
-	// parse expr
-	e, err := p.ParseExpr()
-	return e, err
+	// parse comma-separated list of expressions
+	var exprs []expr.Expr
+	for {
+		e, err := p.ParseExpr()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, e)
+		if len(exprs) > rows.MaxSortExprs {
+			return nil, errors.Errorf("too many terms in GROUP BY, the maximum is %d", rows.MaxSortExprs)
+		}
+
+		if tok, _, _ := p.ScanIgnoreWhitespace(); tok != scanner.COMMA {
+			p.Unscan()
+			break
+		}
+	}
+
+	return exprs, nil
+}
+
+func (p *Parser) parseHaving() (expr.Expr, error) {
+	ok, err := p.parseOptional(scanner.HAVING)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	return p.ParseExpr()
 }
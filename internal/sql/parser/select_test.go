@@ -2,6 +2,7 @@ package parser_test
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/chaisql/chai/internal/expr"
@@ -11,10 +12,12 @@ import (
 	"github.com/chaisql/chai/internal/query/statement"
 	"github.com/chaisql/chai/internal/sql/parser"
 	"github.com/chaisql/chai/internal/stream"
+	"github.com/chaisql/chai/internal/stream/join"
 	"github.com/chaisql/chai/internal/stream/rows"
 	"github.com/chaisql/chai/internal/stream/table"
 	"github.com/chaisql/chai/internal/testutil"
 	"github.com/chaisql/chai/internal/testutil/assert"
+	"github.com/chaisql/chai/internal/tree"
 	"github.com/stretchr/testify/require"
 )
 
@@ -86,6 +89,53 @@ func TestParserSelect(t *testing.T) {
 				Pipe(rows.Project(&expr.NamedExpr{ExprName: "a.b.c", Expr: expr.Path(object.NewPath("a.b.c"))})),
 			true, false,
 		},
+		{"WithHaving", "SELECT a, COUNT(*) FROM test GROUP BY a HAVING COUNT(*) > 1",
+			stream.New(table.Scan("test")).
+				Pipe(rows.TempTreeSort(parser.MustParseExpr("a"))).
+				Pipe(rows.GroupAggregate(parser.MustParseExpr("a"), functions.NewCount(expr.Wildcard{}))).
+				Pipe(rows.Filter(parser.MustParseExpr("COUNT(*) > 1"))).
+				Pipe(rows.Project(
+					&expr.NamedExpr{ExprName: "a", Expr: expr.Path(object.NewPath("a"))},
+					&expr.NamedExpr{ExprName: "COUNT(*)", Expr: functions.NewCount(expr.Wildcard{})},
+				)),
+			true, false,
+		},
+		{"WithMultipleGroupBy", "SELECT a, b, COUNT(*) FROM test GROUP BY a, b",
+			stream.New(table.Scan("test")).
+				Pipe(rows.TempTreeSort(parser.MustParseExpr("a"), parser.MustParseExpr("b"))).
+				Pipe(rows.GroupAggregateBy([]expr.Expr{parser.MustParseExpr("a"), parser.MustParseExpr("b")}, functions.NewCount(expr.Wildcard{}))).
+				Pipe(rows.Project(
+					&expr.NamedExpr{ExprName: "a", Expr: expr.Path(object.NewPath("a"))},
+					&expr.NamedExpr{ExprName: "b", Expr: expr.Path(object.NewPath("b"))},
+					&expr.NamedExpr{ExprName: "COUNT(*)", Expr: functions.NewCount(expr.Wildcard{})},
+				)),
+			true, false,
+		},
+		{"With", "WITH foo AS (SELECT a FROM test) SELECT * FROM foo",
+			stream.New(rows.CommonTableScan(&rows.CommonTable{
+				Name:   "foo",
+				Stream: stream.New(table.Scan("test")).Pipe(rows.Project(testutil.ParseNamedExpr(t, "a"))),
+			})).
+				Pipe(join.Alias("foo")),
+			true, false,
+		},
+		{"WithRecursive", "WITH RECURSIVE foo(n) AS (SELECT 1 UNION SELECT n + 1 FROM foo WHERE n < 3) SELECT n FROM foo",
+			stream.New(rows.CommonTableScan(&rows.CommonTable{
+				Name:    "foo",
+				Columns: []string{"n"},
+				Stream:  stream.New(rows.Project(testutil.ParseNamedExpr(t, "1"))),
+				Recursive: stream.New(rows.WorkingTableScan("foo")).
+					Pipe(join.Alias("foo")).
+					Pipe(rows.Filter(parser.MustParseExpr("n < 3"))).
+					Pipe(rows.Project(testutil.ParseNamedExpr(t, "n + 1"))),
+				Distinct: true,
+			})).
+				Pipe(join.Alias("foo")).
+				Pipe(rows.Project(testutil.ParseNamedExpr(t, "n"))),
+			true, false,
+		},
+		{"WithoutSelect", "WITH foo AS (SELECT a FROM test)", nil, true, true},
+		{"WithoutAs", "WITH foo (SELECT a FROM test) SELECT * FROM foo", nil, true, true},
 		{"WithOrderBy", "SELECT * FROM test WHERE age = 10 ORDER BY a.b.c",
 			stream.New(table.Scan("test")).
 				Pipe(rows.Filter(parser.MustParseExpr("age = 10"))).
@@ -104,6 +154,14 @@ func TestParserSelect(t *testing.T) {
 				Pipe(rows.TempTreeSortReverse(testutil.ParsePath(t, "a.b.c"))),
 			true, false,
 		},
+		{"WithOrderBy multiple terms", "SELECT * FROM test ORDER BY a DESC, b + 1, c ASC",
+			stream.New(table.Scan("test")).
+				Pipe(rows.TempTreeSortWithOrder(
+					[]expr.Expr{testutil.ParsePath(t, "a"), parser.MustParseExpr("b + 1"), testutil.ParsePath(t, "c")},
+					tree.SortOrder(0).SetDesc(0),
+				)),
+			true, false,
+		},
 		{"WithLimit", "SELECT * FROM test WHERE age = 10 LIMIT 20",
 			stream.New(table.Scan("test")).
 				Pipe(rows.Filter(parser.MustParseExpr("age = 10"))).
@@ -133,6 +191,43 @@ func TestParserSelect(t *testing.T) {
 			stream.New(table.Scan("test")).
 				Pipe(rows.Project(testutil.ParseNamedExpr(t, "NEXT VALUE FOR foo"))),
 			false, false},
+		{"WithJoin", "SELECT * FROM test JOIN test1 ON test.a = test1.a",
+			stream.New(table.Scan("test")).
+				Pipe(join.NestedLoop(stream.New(table.Scan("test1")), "", parser.MustParseExpr("test.a = test1.a"))),
+			true, false,
+		},
+		{"WithInnerJoinAndAliases", "SELECT t.a FROM test AS t INNER JOIN test1 u ON t.a = u.a WHERE u.b > 1",
+			stream.New(table.Scan("test")).
+				Pipe(join.Alias("t")).
+				Pipe(join.NestedLoop(stream.New(table.Scan("test1")), "u", parser.MustParseExpr("t.a = u.a"))).
+				Pipe(rows.Filter(parser.MustParseExpr("u.b > 1"))).
+				Pipe(rows.Project(testutil.ParseNamedExpr(t, "t.a"))),
+			true, false,
+		},
+		{"WithLeftJoin", "SELECT * FROM test LEFT JOIN test1 ON test.a = test1.a LEFT OUTER JOIN test2 ON test.a = test2.a",
+			stream.New(table.Scan("test")).
+				Pipe(join.LeftNestedLoop(stream.New(table.Scan("test1")), "", parser.MustParseExpr("test.a = test1.a"))).
+				Pipe(join.LeftNestedLoop(stream.New(table.Scan("test2")), "", parser.MustParseExpr("test.a = test2.a"))),
+			true, false,
+		},
+		{"WithNonReservedKeywordsAsNames", "SELECT left, match FROM test AS join WHERE row MATCH 'a'",
+			stream.New(table.Scan("test")).
+				Pipe(join.Alias("join")).
+				Pipe(rows.Filter(parser.MustParseExpr("row MATCH 'a'"))).
+				Pipe(rows.Project(testutil.ParseNamedExpr(t, "left"), testutil.ParseNamedExpr(t, "match"))),
+			true, false,
+		},
+		{"WithKeywordsAsAliases", "SELECT * FROM test AS left LEFT JOIN test1 AS inner ON left.a = inner.a",
+			stream.New(table.Scan("test")).
+				Pipe(join.Alias("left")).
+				Pipe(join.LeftNestedLoop(stream.New(table.Scan("test1")), "inner", parser.MustParseExpr("left.a = inner.a"))),
+			true, false,
+		},
+		{"WithJoinWithoutCondition", "SELECT * FROM test JOIN test1", nil, true, true},
+		{"WithJoinWithAggregator", "SELECT * FROM test JOIN test1 ON COUNT(*) > 1", nil, true, true},
+		{"WithTooManyOrderByTerms", "SELECT * FROM test ORDER BY a" + strings.Repeat(", a DESC", 60), nil, true, true},
+		{"WithTooManyGroupByTerms", "SELECT a FROM test GROUP BY a" + strings.Repeat(", a", 60), nil, true, true},
+		{"WithTooManyWindowTerms", "SELECT ROW_NUMBER() OVER (PARTITION BY a" + strings.Repeat(", a", 30) + " ORDER BY a" + strings.Repeat(", a", 29) + ") FROM test", nil, true, true},
 		{"WithUnionAll", "SELECT * FROM test1 UNION ALL SELECT * FROM test2",
 			stream.New(stream.Concat(
 				stream.New(table.Scan("test1")),
@@ -244,6 +339,38 @@ func TestParserSelect(t *testing.T) {
 			)).Pipe(rows.TempTreeSort(testutil.ParsePath(t, "a"))).Pipe(rows.Skip(parser.MustParseExpr("20"))).Pipe(rows.Take(parser.MustParseExpr("10"))),
 			true, false,
 		},
+		{"WithIntersect", "SELECT * FROM test1 INTERSECT SELECT * FROM test2",
+			stream.New(stream.Intersect(
+				stream.New(table.Scan("test1")),
+				stream.New(table.Scan("test2")),
+			)),
+			true, false,
+		},
+		{"WithIntersectAll", "SELECT * FROM test1 INTERSECT ALL SELECT * FROM test2",
+			stream.New(stream.IntersectAll(
+				stream.New(table.Scan("test1")),
+				stream.New(table.Scan("test2")),
+			)),
+			true, false,
+		},
+		{"WithExcept", "SELECT * FROM test1 EXCEPT SELECT * FROM test2 ORDER BY a",
+			stream.New(stream.Except(
+				stream.New(table.Scan("test1")),
+				stream.New(table.Scan("test2")),
+			)).Pipe(rows.TempTreeSort(testutil.ParsePath(t, "a"))),
+			true, false,
+		},
+		{"WithExceptAll", "SELECT * FROM test1 EXCEPT ALL SELECT * FROM test2",
+			stream.New(stream.ExceptAll(
+				stream.New(table.Scan("test1")),
+				stream.New(table.Scan("test2")),
+			)),
+			true, false,
+		},
+		{"WithIntersectAfterLimit", "SELECT * FROM test1 LIMIT 10 INTERSECT SELECT * FROM test2",
+			nil,
+			true, true,
+		},
 		{"WithMultipleCompoundOps/1", "SELECT * FROM a UNION ALL SELECT * FROM b UNION ALL SELECT * FROM c",
 			stream.New(stream.Concat(
 				stream.New(table.Scan("a")),
@@ -297,6 +424,19 @@ func TestParserSelect(t *testing.T) {
 			)),
 			true, false,
 		},
+		{"WithMultipleCompoundOps/5", "SELECT * FROM a UNION SELECT * FROM b INTERSECT SELECT * FROM c EXCEPT ALL SELECT * FROM d",
+			stream.New(stream.ExceptAll(
+				stream.New(stream.Union(
+					stream.New(table.Scan("a")),
+					stream.New(stream.Intersect(
+						stream.New(table.Scan("b")),
+						stream.New(table.Scan("c")),
+					)),
+				)),
+				stream.New(table.Scan("d")),
+			)),
+			true, false,
+		},
 		{"WithMultipleCompoundOpsAndNextValueFor/4", "SELECT * FROM a UNION ALL SELECT * FROM b UNION SELECT * FROM c UNION ALL SELECT NEXT VALUE FOR foo FROM d",
 			stream.New(stream.Concat(
 				stream.New(stream.Union(
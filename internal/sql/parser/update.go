@@ -38,12 +38,29 @@ func (p *Parser) parseUpdateStatement() (*statement.UpdateStmt, error) {
 		return nil, err
 	}
 
+	// Parse optional joined tables: "FROM table_name [JOIN ...]".
+	ok, err := p.parseOptional(scanner.FROM)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		stmt.From, err = p.parseJoinedTables()
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Parse condition: "WHERE EXPR".
 	stmt.WhereExpr, err = p.parseCondition()
 	if err != nil {
 		return nil, err
 	}
 
+	stmt.Returning, err = p.parseReturning()
+	if err != nil {
+		return nil, err
+	}
+
 	return stmt, nil
 }
 
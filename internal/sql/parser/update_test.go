@@ -9,6 +9,7 @@ import (
 	"github.com/chaisql/chai/internal/query/statement"
 	"github.com/chaisql/chai/internal/sql/parser"
 	"github.com/chaisql/chai/internal/stream"
+	"github.com/chaisql/chai/internal/stream/join"
 	"github.com/chaisql/chai/internal/stream/path"
 	"github.com/chaisql/chai/internal/stream/rows"
 	"github.com/chaisql/chai/internal/stream/table"
@@ -76,6 +77,39 @@ func TestParserUpdate(t *testing.T) {
 				Pipe(stream.Discard()),
 			false,
 		},
+		{"SET/FROM", "UPDATE test SET a = t.b FROM test AS t WHERE t.age = test.age",
+			stream.New(table.Scan("test")).
+				Pipe(join.NestedLoop(stream.New(table.Scan("test")), "t", parser.MustParseExpr("t.age = test.age"))).
+				Pipe(join.Target("test")).
+				Pipe(path.Set(object.Path(testutil.ParsePath(t, "a")), parser.MustParseExpr("t.b"))).
+				Pipe(table.Validate("test")).
+				Pipe(table.Replace("test")).
+				Pipe(stream.Discard()),
+			false,
+		},
+		{"SET/FROM with join", "UPDATE test SET a = 1 FROM test AS t JOIN test AS u ON t.a = u.a WHERE t.age = test.age",
+			stream.New(table.Scan("test")).
+				Pipe(join.NestedLoop(stream.New(table.Scan("test")), "t", nil)).
+				Pipe(join.NestedLoop(stream.New(table.Scan("test")), "u", parser.MustParseExpr("t.a = u.a"))).
+				Pipe(rows.Filter(parser.MustParseExpr("t.age = test.age"))).
+				Pipe(join.Target("test")).
+				Pipe(path.Set(object.Path(testutil.ParsePath(t, "a")), testutil.IntegerValue(1))).
+				Pipe(table.Validate("test")).
+				Pipe(table.Replace("test")).
+				Pipe(stream.Discard()),
+			false,
+		},
+		{"SET/RETURNING", "UPDATE test SET a = 1 WHERE age = 10 RETURNING a, b AS c",
+			stream.New(table.Scan("test")).
+				Pipe(rows.Filter(parser.MustParseExpr("age = 10"))).
+				Pipe(path.Set(object.Path(testutil.ParsePath(t, "a")), testutil.IntegerValue(1))).
+				Pipe(table.Validate("test")).
+				Pipe(table.Replace("test")).
+				Pipe(rows.Project(testutil.ParseNamedExpr(t, "a"), testutil.ParseNamedExpr(t, "b", "c"))),
+			false,
+		},
+		{"RETURNING without expr", "UPDATE test SET a = 1 RETURNING", nil, true},
+		{"FROM without table", "UPDATE test SET a = 1 FROM WHERE age = 10", nil, true},
 		{"Trailing comma", "UPDATE test SET a = 1, WHERE age = 10", nil, true},
 		{"No SET", "UPDATE test WHERE age = 10", nil, true},
 		{"No pair", "UPDATE test SET WHERE age = 10", nil, true},
@@ -0,0 +1,182 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/chaisql/chai/internal/expr"
+	"github.com/chaisql/chai/internal/sql/scanner"
+	"github.com/chaisql/chai/internal/stream/rows"
+)
+
+// parseOver parses the optional OVER clause following a function call.
+// Window functions require an OVER clause, while aggregate functions
+// can be used with or without it.
+// "OVER ( [PARTITION BY expr [, expr]*] [ORDER BY expr [ASC|DESC] [, expr [ASC|DESC]]*] [frame] )"
+func (p *Parser) parseOver(fn expr.Function) (expr.Expr, error) {
+	ok, err := p.parseOptional(scanner.OVER, scanner.LPAREN)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		if _, ok := fn.(expr.WindowFunction); ok {
+			return nil, fmt.Errorf("window function %s requires an OVER clause", fn)
+		}
+
+		return fn, nil
+	}
+
+	_, isWindowFn := fn.(expr.WindowFunction)
+	_, isAggregator := fn.(expr.AggregatorBuilder)
+	if !isWindowFn && !isAggregator {
+		return nil, fmt.Errorf("OVER specified, but %s is not a window function nor an aggregate function", fn)
+	}
+
+	var w expr.WindowDefinition
+
+	// Parse optional "PARTITION BY expr [, expr]*"
+	ok, err = p.parseOptional(scanner.PARTITION, scanner.BY)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		for {
+			e, err := p.ParseExpr()
+			if err != nil {
+				return nil, err
+			}
+			w.PartitionBy = append(w.PartitionBy, e)
+
+			if tok, _, _ := p.ScanIgnoreWhitespace(); tok != scanner.COMMA {
+				p.Unscan()
+				break
+			}
+		}
+	}
+
+	w.OrderBy, w.OrderBySortOrder, err = p.parseOrderBy()
+	if err != nil {
+		return nil, err
+	}
+
+	// rows are sorted by partition, then by order
+	if len(w.PartitionBy)+len(w.OrderBy) > rows.MaxSortExprs {
+		return nil, fmt.Errorf("too many terms in PARTITION BY and ORDER BY, the maximum is %d", rows.MaxSortExprs)
+	}
+
+	w.Frame, err = p.parseWindowFrame()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.parseTokens(scanner.RPAREN); err != nil {
+		return nil, err
+	}
+
+	// window functions cannot be nested
+	exprs := append([]expr.Expr{}, fn.Params()...)
+	exprs = append(exprs, w.PartitionBy...)
+	exprs = append(exprs, w.OrderBy...)
+	for _, e := range exprs {
+		err = checkNoWindowFunction(e)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &expr.Over{Fn: fn, Window: &w}, nil
+}
+
+// parseWindowFrame parses the optional frame of a window definition.
+// "ROWS frame_start" or "ROWS BETWEEN frame_start AND frame_end"
+func (p *Parser) parseWindowFrame() (*expr.WindowFrame, error) {
+	ok, err := p.parseOptional(scanner.ROWS)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	var f expr.WindowFrame
+
+	between, err := p.parseOptional(scanner.BETWEEN)
+	if err != nil {
+		return nil, err
+	}
+
+	f.Start, err = p.parseFrameBound()
+	if err != nil {
+		return nil, err
+	}
+
+	if between {
+		if err := p.parseTokens(scanner.AND); err != nil {
+			return nil, err
+		}
+
+		f.End, err = p.parseFrameBound()
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		f.End = expr.FrameBound{Type: expr.CurrentRow}
+	}
+
+	err = f.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return &f, nil
+}
+
+// parseFrameBound parses a bound of a window frame.
+// "UNBOUNDED PRECEDING", "n PRECEDING", "CURRENT ROW", "n FOLLOWING" or "UNBOUNDED FOLLOWING"
+func (p *Parser) parseFrameBound() (expr.FrameBound, error) {
+	tok, pos, lit := p.ScanIgnoreWhitespace()
+	switch p.keyword(tok, lit) {
+	case scanner.CURRENT:
+		if err := p.parseTokens(scanner.ROW); err != nil {
+			return expr.FrameBound{}, err
+		}
+		return expr.FrameBound{Type: expr.CurrentRow}, nil
+	case scanner.UNBOUNDED:
+		tok, pos, lit := p.ScanIgnoreWhitespace()
+		switch p.keyword(tok, lit) {
+		case scanner.PRECEDING:
+			return expr.FrameBound{Type: expr.UnboundedPreceding}, nil
+		case scanner.FOLLOWING:
+			return expr.FrameBound{Type: expr.UnboundedFollowing}, nil
+		}
+		return expr.FrameBound{}, newParseError(scanner.Tokstr(tok, lit), []string{"PRECEDING", "FOLLOWING"}, pos)
+	case scanner.INTEGER:
+		p.Unscan()
+		n, err := p.parseInteger()
+		if err != nil {
+			return expr.FrameBound{}, err
+		}
+
+		tok, pos, lit := p.ScanIgnoreWhitespace()
+		switch p.keyword(tok, lit) {
+		case scanner.PRECEDING:
+			return expr.FrameBound{Type: expr.Preceding, Offset: n}, nil
+		case scanner.FOLLOWING:
+			return expr.FrameBound{Type: expr.Following, Offset: n}, nil
+		}
+		return expr.FrameBound{}, newParseError(scanner.Tokstr(tok, lit), []string{"PRECEDING", "FOLLOWING"}, pos)
+	}
+
+	return expr.FrameBound{}, newParseError(scanner.Tokstr(tok, lit), []string{"UNBOUNDED", "CURRENT", "integer"}, pos)
+}
+
+// checkNoWindowFunction returns an error if the expression contains a window function.
+func checkNoWindowFunction(e expr.Expr) error {
+	var err error
+	expr.Walk(e, func(e expr.Expr) bool {
+		if _, ok := e.(*expr.Over); ok {
+			err = errors.New("window function calls cannot be nested")
+			return false
+		}
+		return true
+	})
+
+	return err
+}
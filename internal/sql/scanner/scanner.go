@@ -21,6 +21,18 @@ func init() {
 	for _, tok := range []Token{AND, OR, TRUE, FALSE, NULL, IN, IS, LIKE, BETWEEN} {
 		keywords[strings.ToLower(tokens[tok])] = tok
 	}
+
+	nonReservedKeywords = make(map[string]Token)
+	for _, tok := range []Token{
+		AFTER, ANALYZE, BEFORE, CASCADE, CURRENT, EACH, ELSE, END, EXCEPT, FOLLOWING,
+		FOREIGN, HAVING, INNER, INTERSECT, JOIN, LEFT, MATCH, OUTER, OVER, PARTITION,
+		PRECEDING, RECURSIVE, REFERENCES, RESTRICT, ROW, ROWS, THEN, TRIGGER, UNBOUNDED,
+		USING, VIEW, WHEN,
+	} {
+		lit := strings.ToLower(tokens[tok])
+		delete(keywords, lit)
+		nonReservedKeywords[lit] = tok
+	}
 }
 
 // scanner represents a lexical scanner for chai.
@@ -123,6 +135,11 @@ func (s *scanner) Scan() (tok Token, pos Pos, lit string) {
 		return BITWISEOR, pos, ""
 	case '^':
 		return BITWISEXOR, pos, ""
+	case '@':
+		if ch1, _ := s.r.read(); ch1 == '@' {
+			return MATCH, pos, ""
+		}
+		s.r.unread()
 	case '=':
 		ch1, _ := s.r.read()
 		if ch1 == '~' {
@@ -54,6 +54,10 @@ func TestScanner_Scan(t *testing.T) {
 		{s: `IS`, tok: IS},
 		{s: `LIKE`, tok: LIKE},
 		{s: `||`, tok: CONCAT},
+		{s: `MATCH`, tok: IDENT, lit: `MATCH`},
+		{s: `match`, tok: IDENT, lit: `match`},
+		{s: `@@`, tok: MATCH},
+		{s: `@`, tok: ILLEGAL, lit: "@"},
 
 		// Misc tokens
 		{s: `(`, tok: LPAREN},
@@ -143,6 +147,7 @@ func TestScanner_Scan(t *testing.T) {
 		{s: `DROP`, tok: DROP},
 		{s: `EXPLAIN`, tok: EXPLAIN},
 		{s: `GROUP`, tok: GROUP},
+		{s: `ANALYZE`, tok: IDENT, lit: `ANALYZE`},
 		{s: `COLUMN`, tok: COLUMN},
 		{s: `FOR`, tok: FOR},
 		{s: `FROM`, tok: FROM},
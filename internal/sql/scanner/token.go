@@ -62,6 +62,7 @@ const (
 	NLIKE    // NOT LIKE
 	CONCAT   // ||
 	BETWEEN  // BETWEEN
+	MATCH    // MATCH
 	operatorEnd
 
 	LPAREN      // (
@@ -79,13 +80,18 @@ const (
 	keywordBeg
 	// ALL and the following are Chai SQL Keywords
 	ADD_KEYWORD
+	AFTER
 	ALL
 	ALTER
+	ANALYZE
 	AS
 	ASC
+	BEFORE
 	BEGIN
 	BY
 	CACHE
+	CASCADE
+	CASE
 	CAST
 	CHECK
 	COLUMN
@@ -93,6 +99,7 @@ const (
 	CONFLICT
 	CONSTRAINT
 	CREATE
+	CURRENT
 	CYCLE
 	DEFAULT
 	DELETE
@@ -100,18 +107,29 @@ const (
 	DISTINCT
 	DO
 	DROP
+	EACH
+	ELSE
+	END
+	EXCEPT
 	EXISTS
 	EXPLAIN
+	FOLLOWING
 	FOR
+	FOREIGN
 	FROM
 	GROUP
+	HAVING
 	IF
 	IGNORE
 	INCREMENT
 	INDEX
+	INNER
 	INSERT
+	INTERSECT
 	INTO
+	JOIN
 	KEY
+	LEFT
 	LIMIT
 	MAXVALUE
 	MINVALUE
@@ -123,27 +141,42 @@ const (
 	ON
 	ONLY
 	ORDER
+	OUTER
+	OVER
+	PARTITION
+	PRECEDING
 	PRECISION
 	PRIMARY
 	READ
+	RECURSIVE
+	REFERENCES
 	REINDEX
 	RENAME
 	REPLACE
+	RESTRICT
 	RETURNING
 	ROLLBACK
+	ROW
+	ROWS
 	SELECT
 	SEQUENCE
 	SET
 	START
 	TABLE
+	THEN
 	TO
 	TRANSACTION
+	TRIGGER
+	UNBOUNDED
 	UNION
 	UNIQUE
 	UNSET
 	UPDATE
+	USING
 	VALUE
 	VALUES
+	VIEW
+	WHEN
 	WITH
 	WHERE
 	WRITE
@@ -199,6 +232,7 @@ var tokens = [...]string{
 	BITWISEOR:  "|",
 	BITWISEXOR: "^",
 	BETWEEN:    "BETWEEN",
+	MATCH:      "MATCH",
 
 	AND: "AND",
 	OR:  "OR",
@@ -214,6 +248,7 @@ var tokens = [...]string{
 	IN:       "IN",
 	IS:       "IS",
 	LIKE:     "LIKE",
+	CONCAT:   "||",
 
 	LPAREN:      "(",
 	RPAREN:      ")",
@@ -228,13 +263,18 @@ var tokens = [...]string{
 	DOT:         ".",
 
 	ADD_KEYWORD: "ADD",
+	AFTER:       "AFTER",
 	ALL:         "ALL",
 	ALTER:       "ALTER",
+	ANALYZE:     "ANALYZE",
 	AS:          "AS",
 	ASC:         "ASC",
+	BEFORE:      "BEFORE",
 	BEGIN:       "BEGIN",
 	BY:          "BY",
 	CACHE:       "CACHE",
+	CASCADE:     "CASCADE",
+	CASE:        "CASE",
 	CAST:        "CAST",
 	CHECK:       "CHECK",
 	COLUMN:      "COLUMN",
@@ -242,6 +282,7 @@ var tokens = [...]string{
 	CONFLICT:    "CONFLICT",
 	CONSTRAINT:  "CONSTRAINT",
 	CREATE:      "CREATE",
+	CURRENT:     "CURRENT",
 	CYCLE:       "CYCLE",
 	DO:          "DO",
 	DEFAULT:     "DEFAULT",
@@ -249,18 +290,29 @@ var tokens = [...]string{
 	DESC:        "DESC",
 	DISTINCT:    "DISTINCT",
 	DROP:        "DROP",
+	EACH:        "EACH",
+	ELSE:        "ELSE",
+	END:         "END",
+	EXCEPT:      "EXCEPT",
 	EXISTS:      "EXISTS",
 	EXPLAIN:     "EXPLAIN",
 	GROUP:       "GROUP",
+	HAVING:      "HAVING",
 	KEY:         "KEY",
+	FOLLOWING:   "FOLLOWING",
 	FOR:         "FOR",
+	FOREIGN:     "FOREIGN",
 	FROM:        "FROM",
 	IF:          "IF",
 	IGNORE:      "IGNORE",
 	INCREMENT:   "INCREMENT",
 	INDEX:       "INDEX",
+	INNER:       "INNER",
 	INSERT:      "INSERT",
+	INTERSECT:   "INTERSECT",
 	INTO:        "INTO",
+	JOIN:        "JOIN",
+	LEFT:        "LEFT",
 	LIMIT:       "LIMIT",
 	MAXVALUE:    "MAXVALUE",
 	MINVALUE:    "MINVALUE",
@@ -272,27 +324,42 @@ var tokens = [...]string{
 	ON:          "ON",
 	ONLY:        "ONLY",
 	ORDER:       "ORDER",
+	OUTER:       "OUTER",
+	OVER:        "OVER",
+	PARTITION:   "PARTITION",
+	PRECEDING:   "PRECEDING",
 	PRECISION:   "PRECISION",
 	PRIMARY:     "PRIMARY",
 	READ:        "READ",
+	RECURSIVE:   "RECURSIVE",
+	REFERENCES:  "REFERENCES",
 	REINDEX:     "REINDEX",
 	RENAME:      "RENAME",
 	RETURNING:   "RETURNING",
 	REPLACE:     "REPLACE",
+	RESTRICT:    "RESTRICT",
 	ROLLBACK:    "ROLLBACK",
+	ROW:         "ROW",
+	ROWS:        "ROWS",
 	START:       "START",
 	SELECT:      "SELECT",
 	SET:         "SET",
 	SEQUENCE:    "SEQUENCE",
 	TABLE:       "TABLE",
+	THEN:        "THEN",
 	TO:          "TO",
 	TRANSACTION: "TRANSACTION",
+	TRIGGER:     "TRIGGER",
+	UNBOUNDED:   "UNBOUNDED",
 	UNION:       "UNION",
 	UNIQUE:      "UNIQUE",
 	UNSET:       "UNSET",
 	UPDATE:      "UPDATE",
+	USING:       "USING",
 	VALUE:       "VALUE",
 	VALUES:      "VALUES",
+	VIEW:        "VIEW",
+	WHEN:        "WHEN",
 	WITH:        "WITH",
 	WHERE:       "WHERE",
 	WRITE:       "WRITE",
@@ -322,6 +389,12 @@ var tokens = [...]string{
 
 var keywords map[string]Token
 
+// nonReservedKeywords are keywords that are scanned as identifiers,
+// so they can still be used as table or column names.
+// The parser recognizes them using the Keyword function, where the
+// grammar expects them.
+var nonReservedKeywords map[string]Token
+
 // String returns the string representation of the token.
 func (tok Token) String() string {
 	if tok >= 0 && tok < Token(len(tokens)) {
@@ -339,7 +412,7 @@ func (tok Token) Precedence() int {
 		return 2
 	case NOT:
 		return 3
-	case EQ, NEQ, IS, ISN, IN, NIN, LIKE, NLIKE, EQREGEX, NEQREGEX, BETWEEN:
+	case EQ, NEQ, IS, ISN, IN, NIN, LIKE, NLIKE, EQREGEX, NEQREGEX, BETWEEN, MATCH:
 		return 4
 	case LT, LTE, GT, GTE:
 		return 5
@@ -374,6 +447,14 @@ func lookup(ident string) Token {
 	return IDENT
 }
 
+// Keyword returns the token of a non-reserved keyword.
+// Non-reserved keywords are scanned as IDENT and it's up to the parser
+// to interpret them as keywords where the grammar expects them.
+func Keyword(ident string) (Token, bool) {
+	tok, ok := nonReservedKeywords[strings.ToLower(ident)]
+	return tok, ok
+}
+
 // Pos specifies the line and character position of a token.
 // The Char and Line are both zero-based indexes.
 type Pos struct {
@@ -383,9 +464,12 @@ type Pos struct {
 
 // AllKeywords returns all defined tokens corresponding to keywords.
 func AllKeywords() []Token {
-	tokens := make([]Token, 0, len(keywords))
+	tokens := make([]Token, 0, len(keywords)+len(nonReservedKeywords))
 	for _, tok := range keywords {
 		tokens = append(tokens, tok)
 	}
+	for _, tok := range nonReservedKeywords {
+		tokens = append(tokens, tok)
+	}
 	return tokens
 }
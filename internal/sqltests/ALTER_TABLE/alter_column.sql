@@ -0,0 +1,110 @@
+-- setup:
+CREATE TABLE test(a int PRIMARY KEY, b int, c text DEFAULT 'foo', d int NOT NULL);
+CREATE INDEX test_b_idx ON test(b);
+INSERT INTO test (a, b, d) VALUES (1, 10, 1), (2, NULL, 2);
+
+-- test: set not null
+ALTER TABLE test ALTER COLUMN c SET NOT NULL;
+INSERT INTO test (a, c, d) VALUES (3, NULL, 3);
+-- error: NOT NULL constraint error: [c]
+
+-- test: set not null with null values
+ALTER TABLE test ALTER COLUMN b SET NOT NULL;
+-- error: NOT NULL constraint error: [b]
+
+-- test: drop not null
+ALTER TABLE test ALTER COLUMN d DROP NOT NULL;
+INSERT INTO test (a) VALUES (3);
+SELECT a, d FROM test WHERE a = 3;
+/* result:
+{"a": 3, "d": null}
+*/
+
+-- test: drop not null of primary key
+ALTER TABLE test ALTER COLUMN a DROP NOT NULL;
+-- error: cannot drop NOT NULL constraint of primary key field "a"
+
+-- test: set default
+ALTER TABLE test ALTER COLUMN b SET DEFAULT 42;
+INSERT INTO test (a, d) VALUES (3, 3);
+SELECT a, b, c FROM test;
+/* result:
+{"a": 1, "b": 10, "c": "foo"}
+{"a": 2, "b": null, "c": "foo"}
+{"a": 3, "b": 42, "c": "foo"}
+*/
+
+-- test: set incompatible default
+ALTER TABLE test ALTER COLUMN b SET DEFAULT 'hello';
+-- error:
+
+-- test: drop default
+ALTER TABLE test ALTER COLUMN c DROP DEFAULT;
+INSERT INTO test (a, d) VALUES (3, 3);
+SELECT a, c FROM test;
+/* result:
+{"a": 1, "c": "foo"}
+{"a": 2, "c": "foo"}
+{"a": 3, "c": null}
+*/
+
+-- test: type
+ALTER TABLE test ALTER COLUMN b TYPE double;
+SELECT name, sql FROM __chai_catalog WHERE type = "table" AND name = "test";
+/* result:
+{
+  "name": "test",
+  "sql": "CREATE TABLE test (a INTEGER NOT NULL, b DOUBLE, c TEXT DEFAULT \"foo\", d INTEGER NOT NULL, CONSTRAINT test_pk PRIMARY KEY (a))"
+}
+*/
+
+-- test: type converts rows
+ALTER TABLE test ALTER COLUMN b TYPE text;
+SELECT a, b FROM test;
+/* result:
+{"a": 1, "b": "10"}
+{"a": 2, "b": null}
+*/
+
+-- test: type rebuilds indexes
+ALTER TABLE test ALTER COLUMN b TYPE text;
+SELECT a FROM test WHERE b = '10';
+/* result:
+{"a": 1}
+*/
+
+-- test: type with incompatible values
+ALTER TABLE test ALTER COLUMN c TYPE integer;
+-- error:
+
+-- test: type of primary key
+ALTER TABLE test ALTER COLUMN a TYPE double;
+-- error: cannot change the type of primary key field "a"
+
+-- test: unknown column
+ALTER TABLE test ALTER COLUMN z SET NOT NULL;
+-- error: field "z" does not exist for table "test"
+
+-- test: bad syntax: unknown action
+ALTER TABLE test ALTER COLUMN b RENAME TO c;
+-- error:
+
+-- test: type of a foreign key
+CREATE TABLE child(x int REFERENCES test);
+ALTER TABLE child ALTER COLUMN x TYPE text;
+-- error: constraint "child_x_fkey": field "x" of type text cannot reference field "a" of type integer
+
+-- test: type of a referenced column
+ALTER TABLE test ADD CONSTRAINT test_b_unique UNIQUE (b);
+CREATE TABLE child(x int REFERENCES test(b));
+ALTER TABLE test ALTER COLUMN b TYPE text;
+-- error: constraint "child_x_fkey": field "x" of type integer cannot reference field "b" of type text
+
+-- test: compatible type of a foreign key
+CREATE TABLE child(x int REFERENCES test);
+INSERT INTO child (x) VALUES (1);
+ALTER TABLE child ALTER COLUMN x TYPE double;
+SELECT * FROM child;
+/* result:
+{"x": 1.0}
+*/
@@ -0,0 +1,226 @@
+-- setup:
+CREATE TABLE test(a int, b int, c text);
+INSERT INTO test (a, b, c) VALUES (1, 10, 'x'), (2, 20, 'y');
+
+-- test: add check
+ALTER TABLE test ADD CONSTRAINT positive_b CHECK (b > 0);
+SELECT name, sql FROM __chai_catalog WHERE type = "table" AND name = "test";
+/* result:
+{
+  "name": "test",
+  "sql": "CREATE TABLE test (a INTEGER, b INTEGER, c TEXT, CONSTRAINT positive_b CHECK (b > 0))"
+}
+*/
+
+-- test: add check is enforced
+ALTER TABLE test ADD CHECK (b > 0);
+INSERT INTO test (a, b) VALUES (3, -1);
+-- error: row violates check constraint "test_check"
+
+-- test: add check violated by existing rows
+ALTER TABLE test ADD CONSTRAINT big_b CHECK (b > 15);
+-- error: row violates check constraint "big_b"
+
+-- test: add unique
+ALTER TABLE test ADD UNIQUE (b);
+SELECT name, sql FROM __chai_catalog WHERE owner.table_name = "test" OR name = "test";
+/* result:
+{
+  "name": "test",
+  "sql": "CREATE TABLE test (a INTEGER, b INTEGER, c TEXT, CONSTRAINT test_b_unique UNIQUE (b))"
+}
+{
+  "name": "test_b_idx",
+  "sql": "CREATE UNIQUE INDEX test_b_idx ON test (b)"
+}
+{
+  "name": "test_seq",
+  "sql": "CREATE SEQUENCE test_seq CACHE 64"
+}
+*/
+
+-- test: add unique builds the index
+ALTER TABLE test ADD UNIQUE (b);
+EXPLAIN SELECT a FROM test WHERE b = 20;
+/* result:
+{
+  "plan": 'index.Scan("test_b_idx", [{"min": [20], "exact": true}]) | rows.Project(a)'
+}
+*/
+
+-- test: add unique is enforced
+ALTER TABLE test ADD UNIQUE (b);
+INSERT INTO test (a, b) VALUES (3, 10);
+-- error: UNIQUE constraint error: [b]
+
+-- test: add unique violated by existing rows
+INSERT INTO test (a, b) VALUES (3, 10);
+ALTER TABLE test ADD UNIQUE (b);
+-- error: UNIQUE constraint error: [b]
+
+-- test: add primary key
+CREATE INDEX test_c_idx ON test(c);
+ALTER TABLE test ADD PRIMARY KEY (a);
+INSERT INTO test (a, b, c) VALUES (0, 0, 'z');
+SELECT * FROM test;
+/* result:
+{"a": 0, "b": 0, "c": "z"}
+{"a": 1, "b": 10, "c": "x"}
+{"a": 2, "b": 20, "c": "y"}
+*/
+
+-- test: add primary key keeps indexes
+CREATE INDEX test_c_idx ON test(c);
+ALTER TABLE test ADD PRIMARY KEY (a);
+SELECT a FROM test WHERE c = 'y';
+/* result:
+{"a": 2}
+*/
+
+-- test: add primary key is enforced
+ALTER TABLE test ADD PRIMARY KEY (a);
+INSERT INTO test (a, b) VALUES (1, 30);
+-- error: PRIMARY KEY constraint error: [a]
+
+-- test: add primary key violated by existing rows
+INSERT INTO test (a, b) VALUES (1, 30);
+ALTER TABLE test ADD PRIMARY KEY (a);
+-- error: PRIMARY KEY constraint error: [a]
+
+-- test: add primary key with null values
+INSERT INTO test (b) VALUES (30);
+ALTER TABLE test ADD PRIMARY KEY (a);
+-- error: NOT NULL constraint error: [a]
+
+-- test: add second primary key
+ALTER TABLE test ADD PRIMARY KEY (a);
+ALTER TABLE test ADD PRIMARY KEY (b);
+-- error:
+
+-- test: add duplicate name
+ALTER TABLE test ADD CONSTRAINT foo CHECK (a > 0);
+ALTER TABLE test ADD CONSTRAINT foo CHECK (b > 0);
+-- error: duplicate table constraint name "foo"
+
+-- test: drop check
+ALTER TABLE test ADD CONSTRAINT positive_b CHECK (b > 0);
+ALTER TABLE test DROP CONSTRAINT positive_b;
+INSERT INTO test (a, b) VALUES (3, -1);
+SELECT name, sql FROM __chai_catalog WHERE type = "table" AND name = "test";
+/* result:
+{
+  "name": "test",
+  "sql": "CREATE TABLE test (a INTEGER, b INTEGER, c TEXT)"
+}
+*/
+
+-- test: drop unique
+ALTER TABLE test ADD UNIQUE (b);
+ALTER TABLE test DROP CONSTRAINT test_b_unique;
+INSERT INTO test (a, b) VALUES (3, 10);
+SELECT name FROM __chai_catalog WHERE owner.table_name = "test" OR name = "test";
+/* result:
+{"name": "test"}
+{"name": "test_seq"}
+*/
+
+-- test: drop primary key
+CREATE TABLE test_pk(a int PRIMARY KEY, b int UNIQUE);
+INSERT INTO test_pk (a, b) VALUES (2, 20), (1, 10);
+ALTER TABLE test_pk DROP CONSTRAINT test_pk_pk;
+INSERT INTO test_pk (a, b) VALUES (1, 30);
+SELECT pk(), a, b FROM test_pk;
+/* result:
+{"pk()": [1], "a": 1, "b": 10}
+{"pk()": [2], "a": 2, "b": 20}
+{"pk()": [3], "a": 1, "b": 30}
+*/
+
+-- test: drop primary key keeps indexes
+CREATE TABLE test_pk(a int PRIMARY KEY, b int UNIQUE);
+INSERT INTO test_pk (a, b) VALUES (2, 20), (1, 10);
+ALTER TABLE test_pk DROP CONSTRAINT test_pk_pk;
+SELECT a FROM test_pk WHERE b = 20;
+/* result:
+{"a": 2}
+*/
+
+-- test: drop unknown constraint
+ALTER TABLE test DROP CONSTRAINT foo;
+-- error: constraint "foo" does not exist for table "test"
+
+-- test: rollback
+BEGIN;
+ALTER TABLE test ADD CONSTRAINT positive_b CHECK (b > 0);
+ROLLBACK;
+INSERT INTO test (a, b) VALUES (3, -1);
+SELECT name, sql FROM __chai_catalog WHERE type = "table" AND name = "test";
+/* result:
+{
+  "name": "test",
+  "sql": "CREATE TABLE test (a INTEGER, b INTEGER, c TEXT)"
+}
+*/
+
+-- test: bad syntax: missing constraint
+ALTER TABLE test ADD CONSTRAINT foo;
+-- error:
+
+-- test: add foreign key
+CREATE TABLE parent (id INT PRIMARY KEY);
+INSERT INTO parent (id) VALUES (1), (2);
+ALTER TABLE test ADD FOREIGN KEY (a) REFERENCES parent ON DELETE CASCADE;
+SELECT name, sql FROM __chai_catalog WHERE type = "table" AND name = "test";
+/* result:
+{
+  "name": "test",
+  "sql": "CREATE TABLE test (a INTEGER, b INTEGER, c TEXT, CONSTRAINT test_a_fkey FOREIGN KEY (a) REFERENCES parent (id) ON DELETE CASCADE)"
+}
+*/
+
+-- test: add foreign key is enforced
+CREATE TABLE parent (id INT PRIMARY KEY);
+INSERT INTO parent (id) VALUES (1), (2);
+ALTER TABLE test ADD FOREIGN KEY (a) REFERENCES parent ON DELETE CASCADE;
+DELETE FROM parent WHERE id = 1;
+SELECT a FROM test;
+/* result:
+{"a": 2}
+*/
+
+-- test: add foreign key with violating rows
+CREATE TABLE parent (id INT PRIMARY KEY);
+INSERT INTO parent (id) VALUES (1);
+ALTER TABLE test ADD FOREIGN KEY (a) REFERENCES parent;
+-- error: insert or update on table "test" violates foreign key constraint "test_a_fkey"
+
+-- test: drop foreign key
+CREATE TABLE parent (id INT PRIMARY KEY);
+INSERT INTO parent (id) VALUES (1), (2);
+ALTER TABLE test ADD CONSTRAINT fk FOREIGN KEY (a) REFERENCES parent;
+ALTER TABLE test DROP CONSTRAINT fk;
+DELETE FROM parent;
+SELECT COUNT(*) FROM parent;
+/* result:
+{"COUNT(*)": 0}
+*/
+
+-- test: drop referenced primary key
+CREATE TABLE parent (id INT PRIMARY KEY);
+INSERT INTO parent (id) VALUES (1), (2);
+ALTER TABLE test ADD CONSTRAINT fk FOREIGN KEY (a) REFERENCES parent;
+ALTER TABLE parent DROP CONSTRAINT parent_pk;
+-- error: cannot drop constraint "parent_pk" because constraint "fk" of table "test" depends on it
+
+-- test: rename referenced table
+CREATE TABLE parent (id INT PRIMARY KEY);
+INSERT INTO parent (id) VALUES (1), (2);
+ALTER TABLE test ADD CONSTRAINT fk FOREIGN KEY (a) REFERENCES parent;
+ALTER TABLE parent RENAME TO other;
+SELECT name, sql FROM __chai_catalog WHERE type = "table" AND name = "test";
+/* result:
+{
+  "name": "test",
+  "sql": "CREATE TABLE test (a INTEGER, b INTEGER, c TEXT, CONSTRAINT fk FOREIGN KEY (a) REFERENCES other (id))"
+}
+*/
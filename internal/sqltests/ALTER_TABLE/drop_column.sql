@@ -0,0 +1,71 @@
+-- setup:
+CREATE TABLE test(a int PRIMARY KEY, b text, c double, d int UNIQUE, e int CHECK (e > 0), f int);
+CREATE INDEX test_f_idx ON test(f);
+INSERT INTO test VALUES (1, 'x', 1.5, 10, 1, 100), (2, 'y', 2.5, 20, 2, 200);
+
+-- test: field constraints are updated
+ALTER TABLE test DROP COLUMN b;
+SELECT name, sql FROM __chai_catalog WHERE type = "table" AND name = "test";
+/* result:
+{
+  "name": "test",
+  "sql": "CREATE TABLE test (a INTEGER NOT NULL, c DOUBLE, d INTEGER, e INTEGER, f INTEGER, CONSTRAINT test_pk PRIMARY KEY (a), CONSTRAINT test_d_unique UNIQUE (d), CONSTRAINT test_check CHECK (e > 0))"
+}
+*/
+
+-- test: rows are rewritten
+ALTER TABLE test DROP COLUMN b;
+SELECT * FROM test;
+/* result:
+{"a": 1, "c": 1.5, "d": 10, "e": 1, "f": 100}
+{"a": 2, "c": 2.5, "d": 20, "e": 2, "f": 200}
+*/
+
+-- test: indexes still work
+ALTER TABLE test DROP COLUMN c;
+INSERT INTO test (a, d, e, f) VALUES (3, 30, 3, 300);
+SELECT a FROM test WHERE f = 300;
+/* result:
+{"a": 3}
+*/
+
+-- test: unique constraints still work
+ALTER TABLE test DROP COLUMN c;
+INSERT INTO test (a, d, e, f) VALUES (3, 10, 3, 300);
+-- error: UNIQUE constraint error: [d]
+
+-- test: primary key
+ALTER TABLE test DROP COLUMN a;
+-- error: cannot drop field "a" because constraint "test_pk" depends on it
+
+-- test: unique
+ALTER TABLE test DROP COLUMN d;
+-- error: cannot drop field "d" because constraint "test_d_unique" depends on it
+
+-- test: check
+ALTER TABLE test DROP COLUMN e;
+-- error: cannot drop field "e" because constraint "test_check" depends on it
+
+-- test: index
+ALTER TABLE test DROP COLUMN f;
+-- error: cannot drop field "f" because index "test_f_idx" depends on it
+
+-- test: unknown column
+ALTER TABLE test DROP COLUMN z;
+-- error: field "z" does not exist for table "test"
+
+-- test: bad syntax: missing COLUMN keyword
+ALTER TABLE test DROP b;
+-- error:
+
+-- test: rows without a value for the column
+CREATE TABLE u(id int PRIMARY KEY, a int, b text);
+INSERT INTO u (id, b) VALUES (1, 'p');
+INSERT INTO u (id, a, b) VALUES (2, NULL, 'q'), (3, 3, 'r');
+ALTER TABLE u DROP COLUMN a;
+SELECT * FROM u;
+/* result:
+{"id": 1, "b": "p"}
+{"id": 2, "b": "q"}
+{"id": 3, "b": "r"}
+*/
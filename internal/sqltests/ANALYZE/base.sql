@@ -0,0 +1,181 @@
+-- setup:
+CREATE TABLE users (id int PRIMARY KEY, status text, age int);
+CREATE INDEX users_status_idx ON users (status);
+CREATE INDEX users_age_idx ON users (age);
+INSERT INTO users (id, status, age)
+    WITH RECURSIVE cnt(n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM cnt WHERE n < 1000)
+    SELECT n, CASE WHEN n % 100 = 0 THEN 'banned' ELSE 'active' END, n % 100 FROM cnt;
+CREATE TABLE colors (name text PRIMARY KEY);
+INSERT INTO colors (name) VALUES ('red'), ('green'), ('blue'), ('yellow'), ('black');
+
+-- test: stats
+ANALYZE users;
+SELECT type, name, row_count, null_count, distinct_count FROM __chai_stats;
+/* result:
+{"type": "column", "name": "age", "row_count": 1000, "null_count": 0, "distinct_count": 100}
+{"type": "column", "name": "id", "row_count": 1000, "null_count": 0, "distinct_count": 1000}
+{"type": "column", "name": "status", "row_count": 1000, "null_count": 0, "distinct_count": 2}
+{"type": "index", "name": "users_age_idx", "row_count": 1000, "null_count": 0, "distinct_count": 100}
+{"type": "index", "name": "users_status_idx", "row_count": 1000, "null_count": 0, "distinct_count": 2}
+{"type": "table", "name": "users", "row_count": 1000, "null_count": null, "distinct_count": null}
+*/
+
+-- test: histogram
+ANALYZE colors;
+SELECT histogram FROM __chai_stats WHERE table_name = 'colors' AND name = 'name';
+/* result:
+{"histogram": ["black", "blue", "green", "red", "yellow"]}
+*/
+
+-- test: all tables
+ANALYZE;
+SELECT table_name, row_count FROM __chai_stats WHERE type = 'table';
+/* result:
+{"table_name": "colors", "row_count": 5}
+{"table_name": "users", "row_count": 1000}
+*/
+
+-- test: analyze twice
+ANALYZE users;
+INSERT INTO users (id, status, age) VALUES (1001, 'active', 1);
+ANALYZE users;
+SELECT row_count FROM __chai_stats WHERE type = 'table';
+/* result:
+{"row_count": 1001}
+*/
+
+-- test: unknown table
+ANALYZE unknown;
+-- error:
+
+-- test: drop table
+ANALYZE;
+DROP TABLE colors;
+SELECT DISTINCT table_name FROM __chai_stats;
+/* result:
+{"table_name": "users"}
+*/
+
+-- test: drop index
+ANALYZE users;
+DROP INDEX users_age_idx;
+SELECT name FROM __chai_stats WHERE type = 'index';
+/* result:
+{"name": "users_status_idx"}
+*/
+
+-- test: drop column
+ANALYZE users;
+DROP INDEX users_age_idx;
+ALTER TABLE users DROP COLUMN age;
+SELECT type, name FROM __chai_stats WHERE type != 'table';
+/* result:
+{"type": "column", "name": "id"}
+{"type": "column", "name": "status"}
+{"type": "index", "name": "users_status_idx"}
+*/
+
+-- test: alter column type
+ANALYZE users;
+ALTER TABLE users ALTER COLUMN age TYPE double;
+SELECT type, name FROM __chai_stats WHERE type != 'table';
+/* result:
+{"type": "column", "name": "id"}
+{"type": "column", "name": "status"}
+{"type": "index", "name": "users_status_idx"}
+*/
+
+-- test: alter column without changing its type
+ANALYZE users;
+ALTER TABLE users ALTER COLUMN age SET DEFAULT 0;
+SELECT type, name FROM __chai_stats WHERE type != 'table';
+/* result:
+{"type": "column", "name": "age"}
+{"type": "column", "name": "id"}
+{"type": "column", "name": "status"}
+{"type": "index", "name": "users_age_idx"}
+{"type": "index", "name": "users_status_idx"}
+*/
+
+-- test: rename table
+ANALYZE users;
+ALTER TABLE users RENAME TO members;
+SELECT DISTINCT table_name FROM __chai_stats;
+/* result:
+{"table_name": "members"}
+*/
+
+-- test: explain without stats
+EXPLAIN SELECT * FROM users WHERE status = 'active' AND age < 5;
+/* result:
+{
+  "plan": 'index.Scan("users_status_idx", [{"min": ["active"], "exact": true}]) | rows.Filter(age < 5)'
+}
+*/
+
+-- test: explain with stats
+ANALYZE users;
+EXPLAIN SELECT * FROM users WHERE status = 'active' AND age < 5;
+/* result:
+{
+  "plan": 'index.Scan("users_age_idx", [{"max": [5], "exclusive": true}]) | rows.Filter(status = "active")'
+}
+*/
+
+-- test: explain selective value
+ANALYZE users;
+EXPLAIN SELECT * FROM users WHERE status = 'banned' AND age < 5;
+/* result:
+{
+  "plan": 'index.Scan("users_status_idx", [{"min": ["banned"], "exact": true}]) | rows.Filter(age < 5)'
+}
+*/
+
+-- test: explain not selective
+ANALYZE users;
+EXPLAIN SELECT * FROM users WHERE status = 'active';
+/* result:
+{
+  "plan": 'table.Scan("users") | rows.Filter(status = "active")'
+}
+*/
+
+-- test: explain primary key
+ANALYZE users;
+EXPLAIN SELECT * FROM users WHERE id < 10 AND age = 5;
+/* result:
+{
+  "plan": 'table.Scan("users", [{"max": [10], "exclusive": true}]) | rows.Filter(age = 5)'
+}
+*/
+
+-- test: explain order by
+ANALYZE users;
+EXPLAIN SELECT * FROM users WHERE status = 'active' ORDER BY status;
+/* result:
+{
+  "plan": 'index.Scan("users_status_idx", [{"min": ["active"], "exact": true}])'
+}
+*/
+
+-- test: results with stats
+ANALYZE users;
+SELECT id FROM users WHERE status = 'active' AND age < 2 AND id < 210;
+/* result:
+{"id": 1}
+{"id": 101}
+{"id": 201}
+*/
+
+-- test: explain range with a residual filter
+CREATE TABLE items (id int PRIMARY KEY, b int UNIQUE);
+INSERT INTO items (id, b)
+    WITH RECURSIVE cnt(n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM cnt WHERE n < 2000)
+    SELECT n, n FROM cnt;
+ANALYZE items;
+EXPLAIN SELECT * FROM items WHERE b >= 1 AND b <= 3;
+/* result:
+{
+  "plan": 'index.Scan("items_b_idx", [{"min": [1]}]) | rows.Filter(b <= 3)'
+}
+*/
@@ -0,0 +1,110 @@
+-- setup:
+CREATE TABLE posts (id int PRIMARY KEY, author text, tags array, scores array);
+INSERT INTO posts (id, author, tags) VALUES (1, 'a', ['go', 'sql']), (2, 'b', ['rust', 'go', 'go']), (3, 'a', ['c']), (4, 'b', []), (5, 'a', NULL);
+INSERT INTO posts (id, author, scores) VALUES (6, 'c', [1, 2.5, 'x']), (7, 'c', [2, 1.5]);
+
+-- test: catalog
+CREATE INDEX posts_tags_idx ON posts (tags[*]);
+SELECT name, sql FROM __chai_catalog WHERE type = "index";
+/* result:
+{
+  "name": "posts_tags_idx",
+  "sql": "CREATE INDEX posts_tags_idx ON posts (tags[*])"
+}
+*/
+
+-- test: generated name
+CREATE INDEX ON posts (author, tags[*] DESC);
+SELECT name, sql FROM __chai_catalog WHERE type = "index";
+/* result:
+{
+  "name": "posts_author_tags_idx",
+  "sql": "CREATE INDEX posts_author_tags_idx ON posts (author, tags[*] DESC)"
+}
+*/
+
+-- test: select
+CREATE INDEX posts_tags_idx ON posts (tags[*]);
+SELECT id FROM posts WHERE 'go' IN tags;
+/* result:
+{"id": 1}
+{"id": 2}
+*/
+
+-- test: explain
+CREATE INDEX posts_tags_idx ON posts (tags[*]);
+EXPLAIN SELECT id FROM posts WHERE 'go' IN tags;
+/* result:
+{
+  "plan": 'index.Scan("posts_tags_idx", [{"min": ["go"], "exact": true}]) | rows.Project(id)'
+}
+*/
+
+-- test: explain array
+CREATE INDEX posts_tags_idx ON posts (tags[*]);
+EXPLAIN SELECT id FROM posts WHERE tags IN (['go'], ['c']);
+/* result:
+{
+  "plan": 'table.Scan("posts") | rows.Filter(tags IN [["go"], ["c"]]) | rows.Project(id)'
+}
+*/
+
+-- test: numbers
+CREATE INDEX posts_scores_idx ON posts (scores[*]);
+SELECT id FROM posts WHERE 1 IN scores;
+/* result:
+{"id": 6}
+*/
+
+-- test: composite
+CREATE INDEX posts_author_tags_idx ON posts (author, tags[*]);
+SELECT id FROM posts WHERE author = 'b' AND 'go' IN tags;
+/* result:
+{"id": 2}
+*/
+
+-- test: explain composite
+CREATE INDEX posts_author_tags_idx ON posts (author, tags[*]);
+EXPLAIN SELECT id FROM posts WHERE author = 'b' AND 'go' IN tags;
+/* result:
+{
+  "plan": 'index.Scan("posts_author_tags_idx", [{"min": ["b", "go"], "exact": true}]) | rows.Project(id)'
+}
+*/
+
+-- test: update
+CREATE INDEX posts_tags_idx ON posts (tags[*]);
+UPDATE posts SET tags = ['go'] WHERE id = 3;
+UPDATE posts SET tags = ['sql'] WHERE id = 2;
+SELECT id FROM posts WHERE 'go' IN tags;
+/* result:
+{"id": 1}
+{"id": 3}
+*/
+
+-- test: delete
+CREATE INDEX posts_tags_idx ON posts (tags[*]);
+DELETE FROM posts WHERE id < 3;
+SELECT id FROM posts WHERE 'go' IN tags;
+/* result:
+*/
+
+-- test: reindex
+CREATE INDEX posts_tags_idx ON posts (tags[*]);
+REINDEX posts_tags_idx;
+SELECT id FROM posts WHERE 'sql' IN tags;
+/* result:
+{"id": 1}
+*/
+
+-- test: unique
+CREATE UNIQUE INDEX posts_tags_idx ON posts (tags[*]);
+-- error:
+
+-- test: not an array
+CREATE INDEX posts_author_idx ON posts (author[*]);
+-- error: cannot index the elements of field "author" of type text
+
+-- test: several arrays
+CREATE INDEX posts_tags_idx ON posts (tags[*], scores[*]);
+-- error:
@@ -0,0 +1,171 @@
+-- setup:
+CREATE TABLE users (id int PRIMARY KEY, email text, age int);
+INSERT INTO users (id, email, age) VALUES (1, 'Foo@example.com', 10), (2, 'bar@example.com', 20), (3, 'BAZ@example.com', 30);
+
+-- test: catalog
+CREATE INDEX users_email_idx ON users (lower(email));
+SELECT name, sql FROM __chai_catalog WHERE type = "index";
+/* result:
+{
+  "name": "users_email_idx",
+  "sql": "CREATE INDEX users_email_idx ON users (LOWER(email))"
+}
+*/
+
+-- test: generated name
+CREATE INDEX ON users (lower(email), age DESC);
+SELECT name, sql FROM __chai_catalog WHERE type = "index";
+/* result:
+{
+  "name": "users_email_age_idx",
+  "sql": "CREATE INDEX users_email_age_idx ON users (LOWER(email), age DESC)"
+}
+*/
+
+-- test: select
+CREATE INDEX users_email_idx ON users (lower(email));
+SELECT id FROM users WHERE lower(email) = 'baz@example.com';
+/* result:
+{"id": 3}
+*/
+
+-- test: explain
+CREATE INDEX users_email_idx ON users (lower(email));
+EXPLAIN SELECT id FROM users WHERE lower(email) = 'baz@example.com';
+/* result:
+{
+  "plan": 'index.Scan("users_email_idx", [{"min": ["baz@example.com"], "exact": true}]) | rows.Project(id)'
+}
+*/
+
+-- test: explain with the value on the left
+CREATE INDEX users_email_idx ON users (lower(email));
+EXPLAIN SELECT id FROM users WHERE 'baz@example.com' = lower(email);
+/* result:
+{
+  "plan": 'index.Scan("users_email_idx", [{"min": ["baz@example.com"], "exact": true}]) | rows.Project(id)'
+}
+*/
+
+-- test: explain different expression
+CREATE INDEX users_email_idx ON users (lower(email));
+EXPLAIN SELECT id FROM users WHERE upper(email) = 'BAZ@EXAMPLE.COM';
+/* result:
+{
+  "plan": 'table.Scan("users") | rows.Filter(UPPER(email) = "BAZ@EXAMPLE.COM") | rows.Project(id)'
+}
+*/
+
+-- test: range
+CREATE INDEX users_age_idx ON users (age * 2);
+SELECT id FROM users WHERE age * 2 > 30;
+/* result:
+{"id": 2}
+{"id": 3}
+*/
+
+-- test: explain range
+CREATE INDEX users_age_idx ON users (age * 2);
+EXPLAIN SELECT id FROM users WHERE age * 2 > 30;
+/* result:
+{
+  "plan": 'index.Scan("users_age_idx", [{"min": [30], "exclusive": true}]) | rows.Project(id)'
+}
+*/
+
+-- test: explain scalar function
+CREATE INDEX users_age_idx ON users (abs(age - 20));
+EXPLAIN SELECT id FROM users WHERE abs(age - 20) = 10;
+/* result:
+{
+  "plan": 'index.Scan("users_age_idx", [{"min": [10], "exact": true}]) | rows.Project(id)'
+}
+*/
+
+-- test: parentheses
+CREATE INDEX users_age_idx ON users ((age * 2));
+SELECT name, sql FROM __chai_catalog WHERE type = "index";
+/* result:
+{
+  "name": "users_age_idx",
+  "sql": "CREATE INDEX users_age_idx ON users (age * 2)"
+}
+*/
+
+-- test: explain parentheses
+CREATE INDEX users_age_idx ON users ((age * 2));
+EXPLAIN SELECT id FROM users WHERE (age * 2) > 30;
+/* result:
+{
+  "plan": 'index.Scan("users_age_idx", [{"min": [30], "exclusive": true}]) | rows.Project(id)'
+}
+*/
+
+-- test: in
+CREATE INDEX users_email_idx ON users (lower(email));
+SELECT id FROM users WHERE lower(email) IN ('foo@example.com', 'bar@example.com');
+/* result:
+{"id": 1}
+{"id": 2}
+*/
+
+-- test: composite
+CREATE INDEX users_email_age_idx ON users (lower(email), age);
+EXPLAIN SELECT id FROM users WHERE lower(email) = 'foo@example.com' AND age > 5;
+/* result:
+{
+  "plan": 'index.Scan("users_email_age_idx", [{"min": ["foo@example.com", 5], "exclusive": true}]) | rows.Project(id)'
+}
+*/
+
+-- test: update and delete
+CREATE INDEX users_email_idx ON users (lower(email));
+UPDATE users SET email = 'QUX@example.com' WHERE id = 1;
+DELETE FROM users WHERE id = 2;
+SELECT id FROM users WHERE lower(email) IN ('foo@example.com', 'bar@example.com', 'qux@example.com');
+/* result:
+{"id": 1}
+*/
+
+-- test: unique
+CREATE UNIQUE INDEX users_email_idx ON users (lower(email));
+INSERT INTO users (id, email) VALUES (4, 'foo@EXAMPLE.com');
+-- error: UNIQUE constraint error: [LOWER(email)]
+
+-- test: unique with a column
+CREATE UNIQUE INDEX users_email_idx ON users (age, lower(email));
+INSERT INTO users (id, email, age) VALUES (4, 'foo@EXAMPLE.com', 10);
+-- error: UNIQUE constraint error: [age LOWER(email)]
+
+-- test: reindex
+CREATE INDEX users_email_idx ON users (lower(email));
+REINDEX users_email_idx;
+SELECT id FROM users WHERE lower(email) = 'bar@example.com';
+/* result:
+{"id": 2}
+*/
+
+-- test: drop column
+CREATE INDEX users_email_idx ON users (lower(email));
+ALTER TABLE users DROP COLUMN email;
+-- error:
+
+-- test: unknown column
+CREATE INDEX users_email_idx ON users (lower(unknown));
+-- error:
+
+-- test: non-deterministic
+CREATE INDEX users_idx ON users (age + random());
+-- error:
+
+-- test: non-deterministic in parentheses
+CREATE INDEX users_idx ON users (age + (random()));
+-- error:
+
+-- test: aggregate
+CREATE INDEX users_idx ON users (COUNT(age));
+-- error:
+
+-- test: constant
+CREATE INDEX users_idx ON users (1 + 1);
+-- error:
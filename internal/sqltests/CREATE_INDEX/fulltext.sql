@@ -0,0 +1,169 @@
+-- setup:
+CREATE TABLE posts (id int PRIMARY KEY, title text, body text, views int);
+INSERT INTO posts (id, title, body, views) VALUES
+    (1, 'Embedded databases', 'Chai is an embedded SQL database written in Go.', 10),
+    (2, 'Searching text', 'Full-text search finds the documents containing the searched words.', 20),
+    (3, 'Go tips', 'Go makes it easy to build fast databases and servers. Go go go!', 30),
+    (4, 'Empty', NULL, 40);
+
+-- test: catalog
+CREATE FULLTEXT INDEX posts_body_idx ON posts (body);
+SELECT name, sql FROM __chai_catalog WHERE type = "index";
+/* result:
+{
+  "name": "posts_body_idx",
+  "sql": "CREATE FULLTEXT INDEX posts_body_idx ON posts (body)"
+}
+*/
+
+-- test: generated name
+CREATE FULLTEXT INDEX ON posts (body) WHERE views > 10;
+SELECT name, sql FROM __chai_catalog WHERE type = "index";
+/* result:
+{
+  "name": "posts_body_idx",
+  "sql": "CREATE FULLTEXT INDEX posts_body_idx ON posts (body) WHERE views > 10"
+}
+*/
+
+-- test: match
+CREATE FULLTEXT INDEX posts_body_idx ON posts (body);
+SELECT id FROM posts WHERE body MATCH 'database';
+/* result:
+{"id": 1}
+{"id": 3}
+*/
+
+-- test: match all terms
+CREATE FULLTEXT INDEX posts_body_idx ON posts (body);
+SELECT id FROM posts WHERE body @@ 'embedded databases';
+/* result:
+{"id": 1}
+*/
+
+-- test: not match
+CREATE FULLTEXT INDEX posts_body_idx ON posts (body);
+SELECT id FROM posts WHERE NOT (body MATCH 'database');
+/* result:
+{"id": 2}
+*/
+
+-- test: match stop words
+CREATE FULLTEXT INDEX posts_body_idx ON posts (body);
+SELECT id FROM posts WHERE body MATCH 'the';
+/* result:
+*/
+
+-- test: explain
+CREATE FULLTEXT INDEX posts_body_idx ON posts (body);
+EXPLAIN SELECT id FROM posts WHERE body MATCH 'database' AND views > 10;
+/* result:
+{
+  "plan": 'index.FullTextScan("posts_body_idx", "database") | rows.Filter(views > 10) | rows.Project(id)'
+}
+*/
+
+-- test: explain with other index
+CREATE FULLTEXT INDEX posts_body_idx ON posts (body);
+EXPLAIN SELECT id FROM posts WHERE body MATCH 'database' AND id = 1;
+/* result:
+{
+  "plan": 'table.Scan("posts", [{"min": [1], "exact": true}]) | rows.Filter(body MATCH "database") | rows.Project(id)'
+}
+*/
+
+-- test: explain without index
+EXPLAIN SELECT id FROM posts WHERE body MATCH 'database';
+/* result:
+{
+  "plan": 'table.Scan("posts") | rows.Filter(body MATCH "database") | rows.Project(id)'
+}
+*/
+
+-- test: explain equality
+CREATE FULLTEXT INDEX posts_body_idx ON posts (body);
+EXPLAIN SELECT id FROM posts WHERE body = 'database';
+/* result:
+{
+  "plan": 'table.Scan("posts") | rows.Filter(body = "database") | rows.Project(id)'
+}
+*/
+
+-- test: partial
+CREATE FULLTEXT INDEX posts_body_idx ON posts (body) WHERE views > 10;
+SELECT id FROM posts WHERE body MATCH 'database' AND views > 10;
+/* result:
+{"id": 3}
+*/
+
+-- test: explain partial
+CREATE FULLTEXT INDEX posts_body_idx ON posts (body) WHERE views > 10;
+EXPLAIN SELECT id FROM posts WHERE body MATCH 'database' AND views > 10;
+/* result:
+{
+  "plan": 'index.FullTextScan("posts_body_idx", "database") | rows.Filter(views > 10) | rows.Project(id)'
+}
+*/
+
+-- test: explain partial not implied
+CREATE FULLTEXT INDEX posts_body_idx ON posts (body) WHERE views > 10;
+EXPLAIN SELECT id FROM posts WHERE body MATCH 'database';
+/* result:
+{
+  "plan": 'table.Scan("posts") | rows.Filter(body MATCH "database") | rows.Project(id)'
+}
+*/
+
+-- test: rank
+CREATE FULLTEXT INDEX posts_body_idx ON posts (body);
+SELECT id FROM posts WHERE body MATCH 'go database' ORDER BY rank(body, 'go database') DESC;
+/* result:
+{"id": 3}
+{"id": 1}
+*/
+
+-- test: update
+CREATE FULLTEXT INDEX posts_body_idx ON posts (body);
+UPDATE posts SET body = 'Nothing to see here' WHERE id = 3;
+UPDATE posts SET body = 'A search engine' WHERE id = 4;
+SELECT id FROM posts WHERE body MATCH 'database';
+/* result:
+{"id": 1}
+*/
+
+-- test: update search
+CREATE FULLTEXT INDEX posts_body_idx ON posts (body);
+UPDATE posts SET body = 'A search engine' WHERE id = 4;
+SELECT id FROM posts WHERE body MATCH 'search';
+/* result:
+{"id": 2}
+{"id": 4}
+*/
+
+-- test: delete
+CREATE FULLTEXT INDEX posts_body_idx ON posts (body);
+DELETE FROM posts WHERE id = 1;
+SELECT id FROM posts WHERE body MATCH 'database';
+/* result:
+{"id": 3}
+*/
+
+-- test: reindex
+CREATE FULLTEXT INDEX posts_body_idx ON posts (body);
+REINDEX posts_body_idx;
+SELECT id FROM posts WHERE body MATCH 'words';
+/* result:
+{"id": 2}
+*/
+
+-- test: non text column
+CREATE FULLTEXT INDEX posts_views_idx ON posts (views);
+-- error:
+
+-- test: several columns
+CREATE FULLTEXT INDEX posts_idx ON posts (title, body);
+-- error:
+
+-- test: unknown column
+CREATE FULLTEXT INDEX posts_idx ON posts (foo);
+-- error:
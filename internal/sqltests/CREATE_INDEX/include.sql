@@ -0,0 +1,117 @@
+-- setup:
+CREATE TABLE test (id int PRIMARY KEY, a int, b text, c double, d int);
+INSERT INTO test (id, a, b, c, d) VALUES (1, 10, 'foo', 1.5, 100), (2, 20, 'bar', 2.5, 200), (3, 30, NULL, 3.5, 300);
+
+-- test: catalog
+CREATE INDEX test_a_idx ON test (a) INCLUDE (b, c);
+SELECT name, sql FROM __chai_catalog WHERE type = "index";
+/* result:
+{
+  "name": "test_a_idx",
+  "sql": "CREATE INDEX test_a_idx ON test (a) INCLUDE (b, c)"
+}
+*/
+
+-- test: with predicate
+CREATE INDEX test_a_idx ON test (a) INCLUDE (b) WHERE d > 100;
+SELECT name, sql FROM __chai_catalog WHERE type = "index";
+/* result:
+{
+  "name": "test_a_idx",
+  "sql": "CREATE INDEX test_a_idx ON test (a) INCLUDE (b) WHERE d > 100"
+}
+*/
+
+-- test: covering scan
+CREATE INDEX test_a_idx ON test (a) INCLUDE (b, c);
+SELECT id, a, b, c FROM test WHERE a > 10;
+/* result:
+{"id": 2, "a": 20, "b": "bar", "c": 2.5}
+{"id": 3, "a": 30, "b": null, "c": 3.5}
+*/
+
+-- test: explain covering scan
+CREATE INDEX test_a_idx ON test (a) INCLUDE (b, c);
+EXPLAIN SELECT id, a, b FROM test WHERE a > 10 AND c < 3.0;
+/* result:
+{
+  "plan": 'index.CoveringScan("test_a_idx", [{"min": [10], "exclusive": true}]) | rows.Filter(c < 3.0) | rows.Project(id, a, b)'
+}
+*/
+
+-- test: explain reverse covering scan
+CREATE INDEX test_a_idx ON test (a DESC) INCLUDE (b);
+EXPLAIN SELECT a, b FROM test WHERE a > 10 ORDER BY a;
+/* result:
+{
+  "plan": 'index.CoveringScanReverse("test_a_idx", [{"min": [10], "exclusive": true}]) | rows.Project(a, b)'
+}
+*/
+
+-- test: reverse covering scan
+CREATE INDEX test_a_idx ON test (a DESC) INCLUDE (b);
+SELECT a, b FROM test WHERE a > 10 ORDER BY a;
+/* result:
+{"a": 20, "b": "bar"}
+{"a": 30, "b": null}
+*/
+
+-- test: aggregate
+CREATE INDEX test_a_idx ON test (a) INCLUDE (c);
+SELECT COUNT(*), SUM(c) FROM test WHERE a >= 20;
+/* result:
+{"COUNT(*)": 2, "SUM(c)": 6.0}
+*/
+
+-- test: explain column not included
+CREATE INDEX test_a_idx ON test (a) INCLUDE (b);
+EXPLAIN SELECT a, d FROM test WHERE a > 10;
+/* result:
+{
+  "plan": 'index.Scan("test_a_idx", [{"min": [10], "exclusive": true}]) | rows.Project(a, d)'
+}
+*/
+
+-- test: explain wildcard
+CREATE INDEX test_a_idx ON test (a) INCLUDE (b);
+EXPLAIN SELECT * FROM test WHERE a > 10;
+/* result:
+{
+  "plan": 'index.Scan("test_a_idx", [{"min": [10], "exclusive": true}])'
+}
+*/
+
+-- test: update
+CREATE INDEX test_a_idx ON test (a) INCLUDE (b);
+UPDATE test SET b = 'baz' WHERE id = 3;
+DELETE FROM test WHERE id = 1;
+SELECT a, b FROM test WHERE a > 0;
+/* result:
+{"a": 20, "b": "bar"}
+{"a": 30, "b": "baz"}
+*/
+
+-- test: reindex
+CREATE INDEX test_a_idx ON test (a) INCLUDE (b);
+REINDEX test_a_idx;
+SELECT a, b FROM test WHERE a = 20;
+/* result:
+{"a": 20, "b": "bar"}
+*/
+
+-- test: drop included column
+CREATE INDEX test_a_idx ON test (a) INCLUDE (b);
+ALTER TABLE test DROP COLUMN b;
+-- error:
+
+-- test: unknown column
+CREATE INDEX test_a_idx ON test (a) INCLUDE (unknown);
+-- error:
+
+-- test: indexed column
+CREATE INDEX test_a_idx ON test (a) INCLUDE (a);
+-- error:
+
+-- test: duplicate column
+CREATE INDEX test_a_idx ON test (a) INCLUDE (b, b);
+-- error:
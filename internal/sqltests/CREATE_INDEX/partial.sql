@@ -0,0 +1,101 @@
+-- setup:
+CREATE TABLE test (a int PRIMARY KEY, b int, deleted_at int);
+INSERT INTO test (a, b, deleted_at) VALUES (1, 10, NULL), (2, 20, 100), (3, 10, NULL);
+
+-- test: catalog
+CREATE INDEX test_b_idx ON test (b) WHERE deleted_at IS NULL;
+SELECT name, sql FROM __chai_catalog WHERE type = "index";
+/* result:
+{
+  "name": "test_b_idx",
+  "sql": "CREATE INDEX test_b_idx ON test (b) WHERE deleted_at IS NULL"
+}
+*/
+
+-- test: only matching rows are indexed
+CREATE INDEX test_b_idx ON test (b) WHERE deleted_at IS NULL;
+SELECT a FROM test WHERE b > 0 AND deleted_at IS NULL;
+/* result:
+{"a": 1}
+{"a": 3}
+*/
+
+-- test: unique outside of the predicate
+UPDATE test SET deleted_at = 200 WHERE a = 3;
+CREATE UNIQUE INDEX test_b_idx ON test (b) WHERE deleted_at IS NULL;
+INSERT INTO test (a, b, deleted_at) VALUES (4, 10, 300);
+SELECT COUNT(*) FROM test WHERE b = 10;
+/* result:
+{"COUNT(*)": 3}
+*/
+
+-- test: unique violation
+UPDATE test SET deleted_at = 200 WHERE a = 3;
+CREATE UNIQUE INDEX test_b_idx ON test (b) WHERE deleted_at IS NULL;
+INSERT INTO test (a, b) VALUES (4, 10);
+-- error:
+
+-- test: update in and out of the predicate
+CREATE INDEX test_b_idx ON test (b) WHERE deleted_at IS NULL;
+UPDATE test SET deleted_at = 200 WHERE a = 1;
+UPDATE test SET deleted_at = NULL WHERE a = 2;
+SELECT a FROM test WHERE b >= 10 AND deleted_at IS NULL;
+/* result:
+{"a": 3}
+{"a": 2}
+*/
+
+-- test: delete
+CREATE INDEX test_b_idx ON test (b) WHERE deleted_at IS NULL;
+DELETE FROM test WHERE a = 1;
+SELECT a FROM test WHERE b = 10 AND deleted_at IS NULL;
+/* result:
+{"a": 3}
+*/
+
+-- test: reindex
+CREATE INDEX test_b_idx ON test (b) WHERE deleted_at IS NULL;
+REINDEX test_b_idx;
+SELECT a FROM test WHERE b = 20 AND deleted_at IS NULL;
+/* result:
+*/
+
+-- test: explain with predicate
+CREATE INDEX test_b_idx ON test (b) WHERE deleted_at IS NULL;
+EXPLAIN SELECT * FROM test WHERE b = 10 AND deleted_at IS NULL;
+/* result:
+{
+  "plan": 'index.Scan("test_b_idx", [{"min": [10], "exact": true}]) | rows.Filter(deleted_at IS NULL)'
+}
+*/
+
+-- test: explain without predicate
+CREATE INDEX test_b_idx ON test (b) WHERE deleted_at IS NULL;
+EXPLAIN SELECT * FROM test WHERE b = 10;
+/* result:
+{
+  "plan": 'table.Scan("test") | rows.Filter(b = 10)'
+}
+*/
+
+-- test: explain IS NOT NULL implied by comparison
+CREATE INDEX test_b_idx ON test (b) WHERE deleted_at IS NOT NULL;
+EXPLAIN SELECT * FROM test WHERE b = 10 AND deleted_at > 50;
+/* result:
+{
+  "plan": 'index.Scan("test_b_idx", [{"min": [10], "exact": true}]) | rows.Filter(deleted_at > 50)'
+}
+*/
+
+-- test: unknown column
+CREATE INDEX test_b_idx ON test (b) WHERE unknown IS NULL;
+-- error:
+
+-- test: drop column of the predicate
+CREATE INDEX test_b_idx ON test (b) WHERE deleted_at IS NULL;
+ALTER TABLE test DROP COLUMN deleted_at;
+-- error:
+
+-- test: subquery
+CREATE INDEX test_b_idx ON test (b) WHERE a IN (SELECT a FROM test);
+-- error:
@@ -0,0 +1,80 @@
+-- setup:
+CREATE TABLE parent (id INT PRIMARY KEY, code TEXT UNIQUE, name TEXT);
+
+-- test: as field constraint
+CREATE TABLE child (a INT REFERENCES parent);
+SELECT name, sql FROM __chai_catalog WHERE name = "child";
+/* result:
+{
+  name: "child",
+  sql: "CREATE TABLE child (a INTEGER, CONSTRAINT child_a_fkey FOREIGN KEY (a) REFERENCES parent (id))"
+}
+*/
+
+-- test: as field constraint, with referenced field and actions
+CREATE TABLE child (a TEXT REFERENCES parent (code) ON DELETE CASCADE ON UPDATE SET NULL);
+SELECT name, sql FROM __chai_catalog WHERE name = "child";
+/* result:
+{
+  name: "child",
+  sql: "CREATE TABLE child (a TEXT, CONSTRAINT child_a_fkey FOREIGN KEY (a) REFERENCES parent (code) ON DELETE CASCADE ON UPDATE SET NULL)"
+}
+*/
+
+-- test: as table constraint
+CREATE TABLE child (a INT, b INT, CONSTRAINT fk_parent FOREIGN KEY (b) REFERENCES parent (id) ON DELETE RESTRICT ON UPDATE NO ACTION);
+SELECT name, sql FROM __chai_catalog WHERE name = "child";
+/* result:
+{
+  name: "child",
+  sql: "CREATE TABLE child (a INTEGER, b INTEGER, CONSTRAINT fk_parent FOREIGN KEY (b) REFERENCES parent (id) ON DELETE RESTRICT)"
+}
+*/
+
+-- test: self reference
+CREATE TABLE node (id INT PRIMARY KEY, parent_id INT REFERENCES node);
+SELECT name, sql FROM __chai_catalog WHERE name = "node";
+/* result:
+{
+  name: "node",
+  sql: "CREATE TABLE node (id INTEGER NOT NULL, parent_id INTEGER, CONSTRAINT node_pk PRIMARY KEY (id), CONSTRAINT node_parent_id_fkey FOREIGN KEY (parent_id) REFERENCES node (id))"
+}
+*/
+
+-- test: unknown table
+CREATE TABLE child (a INT REFERENCES unknown);
+-- error:
+
+-- test: unknown field
+CREATE TABLE child (a INT REFERENCES parent (unknown));
+-- error:
+
+-- test: field without unique constraint
+CREATE TABLE child (a TEXT REFERENCES parent (name));
+-- error: no primary key or unique constraint of table "parent" matches (name)
+
+-- test: incompatible types
+CREATE TABLE child (a TEXT REFERENCES parent);
+-- error: constraint "child_a_fkey": field "a" of type text cannot reference field "id" of type integer
+
+-- test: compatible numeric types
+CREATE TABLE child (a DOUBLE REFERENCES parent);
+INSERT INTO parent (id) VALUES (1);
+INSERT INTO child (a) VALUES (1.0);
+SELECT * FROM child;
+/* result:
+{"a": 1.0}
+*/
+
+-- test: table without primary key
+CREATE TABLE other (a INT);
+CREATE TABLE child (a INT REFERENCES other);
+-- error: table "other" referenced by constraint "child_a_fkey" has no primary key
+
+-- test: number of fields
+CREATE TABLE child (a INT, b INT, FOREIGN KEY (a, b) REFERENCES parent (id));
+-- error:
+
+-- test: bad action
+CREATE TABLE child (a INT REFERENCES parent ON DELETE NOTHING);
+-- error:
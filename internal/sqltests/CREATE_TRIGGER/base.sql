@@ -0,0 +1,238 @@
+-- setup:
+CREATE TABLE test(a INT PRIMARY KEY, b TEXT);
+CREATE TABLE audit(id INT PRIMARY KEY, op TEXT, old_b TEXT, new_b TEXT);
+CREATE SEQUENCE audit_seq;
+CREATE TABLE counter(name TEXT PRIMARY KEY, n INT);
+INSERT INTO counter (name, n) VALUES ('test', 0);
+
+-- test: after insert
+CREATE TRIGGER test_insert AFTER INSERT ON test FOR EACH ROW
+BEGIN
+    INSERT INTO audit (id, op, new_b) VALUES (NEXT VALUE FOR audit_seq, 'insert', NEW.b);
+    UPDATE counter SET n = n + 1 WHERE name = 'test';
+END;
+INSERT INTO test (a, b) VALUES (1, 'foo'), (2, 'bar');
+SELECT * FROM audit;
+/* result:
+{"id": 1, "op": "insert", "new_b": "foo"}
+{"id": 2, "op": "insert", "new_b": "bar"}
+*/
+
+-- test: counter
+CREATE TRIGGER test_insert AFTER INSERT ON test
+BEGIN
+    UPDATE counter SET n = n + 1 WHERE name = 'test';
+END;
+CREATE TRIGGER test_delete AFTER DELETE ON test
+BEGIN
+    UPDATE counter SET n = n - 1 WHERE name = 'test';
+END;
+INSERT INTO test (a, b) VALUES (1, 'foo'), (2, 'bar'), (3, 'baz');
+DELETE FROM test WHERE a = 2;
+SELECT n FROM counter;
+/* result:
+{"n": 2}
+*/
+
+-- test: update
+CREATE TRIGGER test_update AFTER UPDATE ON test
+BEGIN
+    INSERT INTO audit (id, op, old_b, new_b) VALUES (OLD.a, 'update', OLD.b, NEW.b);
+END;
+INSERT INTO test (a, b) VALUES (1, 'foo'), (2, 'bar');
+UPDATE test SET b = 'qux' WHERE a = 2;
+SELECT * FROM audit;
+/* result:
+{"id": 2, "op": "update", "old_b": "bar", "new_b": "qux"}
+*/
+
+-- test: delete
+CREATE TRIGGER test_delete BEFORE DELETE ON test
+BEGIN
+    INSERT INTO audit (id, op, old_b) VALUES (OLD.a, 'delete', OLD.b);
+END;
+INSERT INTO test (a, b) VALUES (1, 'foo'), (2, 'bar');
+DELETE FROM test;
+SELECT * FROM audit;
+/* result:
+{"id": 1, "op": "delete", "old_b": "foo"}
+{"id": 2, "op": "delete", "old_b": "bar"}
+*/
+
+-- test: before and after
+CREATE TRIGGER test_before BEFORE INSERT ON test
+BEGIN
+    INSERT INTO audit (id, op, new_b) SELECT NEXT VALUE FOR audit_seq, 'before', COUNT(*) FROM test;
+END;
+CREATE TRIGGER test_after AFTER INSERT ON test
+BEGIN
+    INSERT INTO audit (id, op, new_b) SELECT NEXT VALUE FOR audit_seq, 'after', COUNT(*) FROM test;
+END;
+INSERT INTO test (a, b) VALUES (1, 'foo');
+SELECT op, new_b FROM audit;
+/* result:
+{"op": "before", "new_b": "0"}
+{"op": "after", "new_b": "1"}
+*/
+
+-- test: when
+CREATE TRIGGER test_insert AFTER INSERT ON test WHEN NEW.a > 1
+BEGIN
+    INSERT INTO audit (id, op, new_b) VALUES (NEW.a, 'insert', NEW.b);
+END;
+INSERT INTO test (a, b) VALUES (1, 'foo'), (2, 'bar'), (3, 'baz');
+SELECT id FROM audit;
+/* result:
+{"id": 2}
+{"id": 3}
+*/
+
+-- test: when with subquery
+CREATE TRIGGER test_insert AFTER INSERT ON test WHEN NOT EXISTS (SELECT 1 FROM audit WHERE new_b = NEW.b)
+BEGIN
+    INSERT INTO audit (id, op, new_b) VALUES (NEW.a, 'insert', NEW.b);
+END;
+INSERT INTO test (a, b) VALUES (1, 'foo'), (2, 'bar'), (3, 'foo');
+SELECT id, new_b FROM audit;
+/* result:
+{"id": 1, "new_b": "foo"}
+{"id": 2, "new_b": "bar"}
+*/
+
+-- test: default values
+CREATE TABLE t(a INT PRIMARY KEY, b INT DEFAULT 10);
+CREATE TRIGGER t_insert AFTER INSERT ON t
+BEGIN
+    INSERT INTO audit (id, op, new_b) VALUES (NEW.a, 'insert', NEW.b);
+END;
+INSERT INTO t (a) VALUES (1);
+SELECT id, new_b FROM audit;
+/* result:
+{"id": 1, "new_b": "10"}
+*/
+
+-- test: not recursive
+CREATE TRIGGER test_insert AFTER INSERT ON test WHEN NEW.a < 10
+BEGIN
+    INSERT INTO test (a, b) VALUES (NEW.a + 10, NEW.b);
+END;
+INSERT INTO test (a, b) VALUES (1, 'foo');
+SELECT * FROM test;
+/* result:
+{"a": 1, "b": "foo"}
+{"a": 11, "b": "foo"}
+*/
+
+-- test: not executed by foreign key actions
+CREATE TABLE child(id INT PRIMARY KEY, a INT REFERENCES test ON DELETE CASCADE);
+CREATE TRIGGER child_delete AFTER DELETE ON child
+BEGIN
+    UPDATE counter SET n = n + 1 WHERE name = 'test';
+END;
+INSERT INTO test (a, b) VALUES (1, 'foo');
+INSERT INTO child (id, a) VALUES (1, 1), (2, 1);
+DELETE FROM test;
+SELECT n FROM counter;
+/* result:
+{"n": 0}
+*/
+
+-- test: error rolls back the statement
+CREATE TRIGGER test_insert AFTER INSERT ON test
+BEGIN
+    INSERT INTO audit (id, op) VALUES (1, 'insert');
+END;
+INSERT INTO test (a, b) VALUES (1, 'foo'), (2, 'bar');
+-- error:
+
+-- test: catalog
+CREATE TRIGGER test_insert AFTER INSERT ON test
+WHEN NEW.a > 1
+BEGIN
+    UPDATE counter SET n = n + 1 WHERE name = 'test';
+END;
+SELECT name, type, sql, owner FROM __chai_catalog WHERE name = "test_insert";
+/* result:
+{
+  "name": "test_insert",
+  "type": "trigger",
+  "sql": "CREATE TRIGGER test_insert AFTER INSERT ON test FOR EACH ROW WHEN NEW.a > 1\nBEGIN\nUPDATE counter SET n = n + 1 WHERE name = \"test\";\nEND",
+  "owner": {
+    "table_name": "test"
+  }
+}
+*/
+
+-- test: same name
+CREATE TRIGGER test_insert AFTER INSERT ON test
+BEGIN
+    UPDATE counter SET n = n + 1;
+END;
+CREATE TRIGGER test_insert AFTER DELETE ON test
+BEGIN
+    UPDATE counter SET n = n - 1;
+END;
+-- error:
+
+-- test: if not exists
+CREATE TRIGGER test_insert AFTER INSERT ON test
+BEGIN
+    UPDATE counter SET n = n + 1;
+END;
+CREATE TRIGGER IF NOT EXISTS test_insert AFTER DELETE ON test
+BEGIN
+    UPDATE counter SET n = n - 1;
+END;
+SELECT name FROM __chai_catalog WHERE type = "trigger";
+/* result:
+{"name": "test_insert"}
+*/
+
+-- test: unknown table
+CREATE TRIGGER test_insert AFTER INSERT ON unknown
+BEGIN
+    UPDATE counter SET n = n + 1;
+END;
+-- error:
+
+-- test: invalid statement
+CREATE TRIGGER test_insert AFTER INSERT ON test
+BEGIN
+    UPDATE unknown SET n = n + 1;
+END;
+-- error:
+
+-- test: drop table
+CREATE TRIGGER test_insert AFTER INSERT ON test
+BEGIN
+    UPDATE counter SET n = n + 1;
+END;
+DROP TABLE test;
+SELECT COUNT(*) FROM __chai_catalog WHERE type = "trigger";
+/* result:
+{"COUNT(*)": 0}
+*/
+
+-- test: rename table
+CREATE TRIGGER test_insert AFTER INSERT ON test
+BEGIN
+    UPDATE counter SET n = n + 1;
+END;
+ALTER TABLE test RENAME TO test2;
+INSERT INTO test2 (a, b) VALUES (1, 'foo');
+SELECT n FROM counter;
+/* result:
+{"n": 1}
+*/
+
+-- test: explain
+CREATE TRIGGER test_delete AFTER DELETE ON test
+BEGIN
+    UPDATE counter SET n = n - 1;
+END;
+EXPLAIN DELETE FROM test WHERE a = 1;
+/* result:
+{
+  "plan": 'table.Scan("test", [{"min": [1], "exact": true}]) | table.Trigger("test", "test_delete") | table.Delete(\'test\') | discard()'
+}
+*/
@@ -0,0 +1,164 @@
+-- setup:
+CREATE TABLE test(a INT PRIMARY KEY, b TEXT, c INT);
+INSERT INTO test (a, b, c) VALUES (1, 'foo', 10), (2, 'bar', 20), (3, 'baz', 30);
+
+-- test: basic
+CREATE VIEW v AS SELECT a, b FROM test WHERE c > 10;
+SELECT * FROM v;
+/* result:
+{"a": 2, "b": "bar"}
+{"a": 3, "b": "baz"}
+*/
+
+-- test: catalog
+CREATE VIEW v AS SELECT a, b FROM test WHERE c > 10;
+SELECT name, type, sql FROM __chai_catalog WHERE name = "v";
+/* result:
+{
+  "name": "v",
+  "type": "view",
+  "sql": "CREATE VIEW v AS SELECT a, b FROM test WHERE c > 10"
+}
+*/
+
+-- test: normalized query
+CREATE VIEW v AS select a, b
+    FROM `test` WHERE b = 'foo';
+SELECT sql FROM __chai_catalog WHERE name = "v";
+/* result:
+{
+  "sql": "CREATE VIEW v AS SELECT a, b\nFROM test WHERE b = \"foo\""
+}
+*/
+
+-- test: filter and projection
+CREATE VIEW v AS SELECT a, b, c * 2 AS d FROM test;
+SELECT b, d FROM v WHERE d > 20 ORDER BY d DESC;
+/* result:
+{"b": "baz", "d": 60}
+{"b": "bar", "d": 40}
+*/
+
+-- test: qualified columns
+CREATE VIEW v AS SELECT a, b FROM test;
+SELECT v.b FROM v WHERE v.a = 1;
+/* result:
+{"v.b": "foo"}
+*/
+
+-- test: alias
+CREATE VIEW v AS SELECT a, b FROM test;
+SELECT x.b FROM v AS x WHERE x.a = 2;
+/* result:
+{"x.b": "bar"}
+*/
+
+-- test: aggregation
+CREATE VIEW v AS SELECT a, c FROM test ORDER BY c DESC LIMIT 2;
+SELECT COUNT(*), SUM(c) FROM v;
+/* result:
+{"COUNT(*)": 2, "SUM(c)": 50}
+*/
+
+-- test: group by
+CREATE VIEW v AS SELECT c > 15 AS big, COUNT(*) AS n FROM test GROUP BY c > 15;
+SELECT * FROM v ORDER BY n;
+/* result:
+{"big": false, "n": 1}
+{"big": true, "n": 2}
+*/
+
+-- test: join
+CREATE TABLE other(id INT PRIMARY KEY, name TEXT);
+INSERT INTO other (id, name) VALUES (1, 'one'), (3, 'three');
+CREATE VIEW v AS SELECT a, b FROM test;
+SELECT v.b, other.name FROM other JOIN v ON v.a = other.id;
+/* result:
+{"v.b": "foo", "other.name": "one"}
+{"v.b": "baz", "other.name": "three"}
+*/
+
+-- test: joined rows
+CREATE TABLE other(id INT PRIMARY KEY, name TEXT);
+INSERT INTO other (id, name) VALUES (1, 'one'), (3, 'three');
+CREATE VIEW v AS SELECT * FROM test JOIN other ON test.a = other.id;
+SELECT v.name, c FROM v WHERE a > 1;
+/* result:
+{"v.name": "three", "c": 30}
+*/
+
+-- test: joined rows with the same columns
+CREATE VIEW v AS SELECT * FROM test AS t1 JOIN test AS t2 ON t1.a = t2.a;
+SELECT * FROM v;
+-- error: cannot alias a joined row as "v": duplicate column "a"
+
+-- test: view of a view
+CREATE VIEW v1 AS SELECT a, c FROM test WHERE a > 1;
+CREATE VIEW v2 AS SELECT a FROM v1 WHERE c < 30;
+SELECT * FROM v2;
+/* result:
+{"a": 2}
+*/
+
+-- test: compound query
+CREATE VIEW v AS SELECT a FROM test WHERE a = 1 UNION ALL SELECT a FROM test WHERE a = 3;
+SELECT * FROM v;
+/* result:
+{"a": 1}
+{"a": 3}
+*/
+
+-- test: common table
+CREATE VIEW v AS SELECT a FROM test WHERE a < 3;
+WITH test AS (SELECT 10 AS a) SELECT * FROM v;
+/* result:
+{"a": 1}
+{"a": 2}
+*/
+
+-- test: subquery
+CREATE VIEW v AS SELECT a FROM test WHERE a > 1;
+SELECT b FROM test WHERE a IN (SELECT a FROM v);
+/* result:
+{"b": "bar"}
+{"b": "baz"}
+*/
+
+-- test: rows inserted after the creation of the view
+CREATE VIEW v AS SELECT a FROM test WHERE a > 2;
+INSERT INTO test (a, b, c) VALUES (4, 'qux', 40);
+SELECT * FROM v;
+/* result:
+{"a": 3}
+{"a": 4}
+*/
+
+-- test: if not exists
+CREATE VIEW v AS SELECT a FROM test;
+CREATE VIEW IF NOT EXISTS v AS SELECT b FROM test;
+SELECT * FROM v WHERE a = 1;
+/* result:
+{"a": 1}
+*/
+
+-- test: already exists
+CREATE VIEW v AS SELECT a FROM test;
+CREATE VIEW v AS SELECT b FROM test;
+-- error:
+
+-- test: same name as a table
+CREATE VIEW test AS SELECT 1;
+-- error:
+
+-- test: unknown table
+CREATE VIEW v AS SELECT * FROM unknown;
+-- error:
+
+-- test: parameters
+CREATE VIEW v AS SELECT * FROM test WHERE a = ?;
+-- error:
+
+-- test: insert into view
+CREATE VIEW v AS SELECT a FROM test;
+INSERT INTO v (a) VALUES (10);
+-- error:
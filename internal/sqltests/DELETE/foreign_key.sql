@@ -0,0 +1,99 @@
+-- setup:
+CREATE TABLE parent (id INT PRIMARY KEY, name TEXT);
+INSERT INTO parent (id, name) VALUES (1, 'a'), (2, 'b'), (3, 'c');
+
+-- test: no action
+CREATE TABLE child (id INT PRIMARY KEY, parent_id INT REFERENCES parent);
+INSERT INTO child (id, parent_id) VALUES (1, 1);
+DELETE FROM parent WHERE id = 1;
+-- error: update or delete on table "parent" violates foreign key constraint "child_parent_id_fkey" on table "child"
+
+-- test: restrict
+CREATE TABLE child (id INT PRIMARY KEY, parent_id INT REFERENCES parent ON DELETE RESTRICT);
+INSERT INTO child (id, parent_id) VALUES (1, 1);
+DELETE FROM parent WHERE id = 1;
+-- error: update or delete on table "parent" violates foreign key constraint "child_parent_id_fkey" on table "child"
+
+-- test: unreferenced rows
+CREATE TABLE child (id INT PRIMARY KEY, parent_id INT REFERENCES parent);
+INSERT INTO child (id, parent_id) VALUES (1, 1);
+DELETE FROM parent WHERE id > 1;
+SELECT id FROM parent;
+/* result:
+{"id": 1}
+*/
+
+-- test: cascade
+CREATE TABLE child (id INT PRIMARY KEY, parent_id INT REFERENCES parent ON DELETE CASCADE);
+CREATE INDEX ON child (parent_id);
+INSERT INTO child (id, parent_id) VALUES (1, 1), (2, 1), (3, 2);
+DELETE FROM parent WHERE id = 1;
+SELECT * FROM child;
+/* result:
+{"id": 3, "parent_id": 2}
+*/
+
+-- test: cascade, without index
+CREATE TABLE child (id INT PRIMARY KEY, parent_id INT REFERENCES parent ON DELETE CASCADE);
+INSERT INTO child (id, parent_id) VALUES (1, 1), (2, 1), (3, 2);
+DELETE FROM parent WHERE id < 3;
+SELECT * FROM child;
+/* result:
+*/
+
+-- test: cascade, updates the indexes
+CREATE TABLE child (id INT PRIMARY KEY, parent_id INT REFERENCES parent ON DELETE CASCADE, name TEXT UNIQUE);
+INSERT INTO child (id, parent_id, name) VALUES (1, 1, 'x');
+DELETE FROM parent WHERE id = 1;
+INSERT INTO child (id, parent_id, name) VALUES (2, 2, 'x');
+SELECT * FROM child WHERE name = 'x';
+/* result:
+{"id": 2, "parent_id": 2, "name": "x"}
+*/
+
+-- test: cascade, recursive
+CREATE TABLE child (id INT PRIMARY KEY, parent_id INT REFERENCES parent ON DELETE CASCADE);
+CREATE TABLE grandchild (id INT PRIMARY KEY, child_id INT REFERENCES child ON DELETE CASCADE);
+INSERT INTO child (id, parent_id) VALUES (1, 1), (2, 2);
+INSERT INTO grandchild (id, child_id) VALUES (1, 1), (2, 2);
+DELETE FROM parent WHERE id = 1;
+SELECT * FROM grandchild;
+/* result:
+{"id": 2, "child_id": 2}
+*/
+
+-- test: cascade, self reference
+CREATE TABLE node (id INT PRIMARY KEY, parent_id INT REFERENCES node ON DELETE CASCADE);
+INSERT INTO node (id, parent_id) VALUES (1, 1), (2, 1), (3, 2), (4, NULL);
+DELETE FROM node WHERE id < 3;
+SELECT * FROM node;
+/* result:
+{"id": 4}
+*/
+
+-- test: set null
+CREATE TABLE child (id INT PRIMARY KEY, parent_id INT REFERENCES parent ON DELETE SET NULL);
+CREATE INDEX ON child (parent_id);
+INSERT INTO child (id, parent_id) VALUES (1, 1), (2, 2);
+DELETE FROM parent WHERE id = 1;
+SELECT * FROM child;
+/* result:
+{"id": 1}
+{"id": 2, "parent_id": 2}
+*/
+
+-- test: set null, indexes
+CREATE TABLE child (id INT PRIMARY KEY, parent_id INT REFERENCES parent ON DELETE SET NULL);
+CREATE INDEX ON child (parent_id);
+INSERT INTO child (id, parent_id) VALUES (1, 1), (2, 2);
+DELETE FROM parent WHERE id = 1;
+SELECT id FROM child WHERE parent_id IS NULL;
+/* result:
+{"id": 1}
+*/
+
+-- test: set null, NOT NULL field
+CREATE TABLE child (id INT PRIMARY KEY, parent_id INT NOT NULL REFERENCES parent ON DELETE SET NULL);
+INSERT INTO child (id, parent_id) VALUES (1, 1);
+DELETE FROM parent WHERE id = 1;
+-- error:
@@ -0,0 +1,47 @@
+-- setup:
+CREATE TABLE test (id int PRIMARY KEY, name text, score int);
+CREATE INDEX on test(score);
+INSERT INTO test (id, name, score) VALUES (1, 'a', 10), (2, 'b', 20), (3, 'c', 30);
+
+-- test: returning *
+DELETE FROM test WHERE id > 1 RETURNING *;
+/* result:
+{"id": 2, "name": "b", "score": 20}
+{"id": 3, "name": "c", "score": 30}
+*/
+
+-- test: returning expressions
+DELETE FROM test WHERE score < 25 RETURNING id, name AS old_name, pk();
+/* result:
+{"id": 1, "old_name": "a", "pk()": [1]}
+{"id": 2, "old_name": "b", "pk()": [2]}
+*/
+
+-- test: returning with order by and limit
+DELETE FROM test ORDER BY score DESC LIMIT 2 RETURNING id;
+/* result:
+{"id": 3}
+{"id": 2}
+*/
+
+-- test: returning with using
+CREATE TABLE banned (name text);
+INSERT INTO banned (name) VALUES ('b');
+DELETE FROM test USING banned WHERE test.name = banned.name RETURNING id, banned.name AS reason;
+/* result:
+{"id": 2, "reason": "b"}
+*/
+
+-- test: rows are deleted
+DELETE FROM test WHERE id = 1 RETURNING id;
+SELECT id FROM test;
+/* result:
+{"id": 2}
+{"id": 3}
+*/
+
+-- test: index is updated
+DELETE FROM test WHERE score = 20 RETURNING id;
+SELECT id FROM test WHERE score = 20;
+/* result:
+*/
@@ -0,0 +1,48 @@
+-- setup:
+CREATE TABLE customers (id int PRIMARY KEY, name text, vip bool);
+CREATE TABLE orders (id int PRIMARY KEY, customer_id int, status text);
+CREATE INDEX on orders(customer_id);
+INSERT INTO customers (id, name, vip) VALUES (1, 'a', true), (2, 'b', false), (3, 'c', true);
+INSERT INTO orders (id, customer_id, status) VALUES (1, 1, 'new'), (2, 2, 'new'), (3, 3, 'new'), (4, 1, 'new');
+
+-- test: delete using
+DELETE FROM orders USING customers WHERE orders.customer_id = customers.id AND NOT customers.vip;
+SELECT id FROM orders;
+/* result:
+{"id": 1}
+{"id": 3}
+{"id": 4}
+*/
+
+-- test: delete using with alias
+DELETE FROM orders USING customers AS c WHERE customer_id = c.id AND c.name = 'a';
+SELECT id FROM orders;
+/* result:
+{"id": 2}
+{"id": 3}
+*/
+
+-- test: multiple matching rows delete once
+DELETE FROM customers USING orders WHERE customers.id = orders.customer_id;
+SELECT id FROM customers;
+/* result:
+*/
+
+-- test: index is updated
+DELETE FROM orders USING customers WHERE orders.customer_id = customers.id AND customers.id = 1;
+SELECT id FROM orders WHERE customer_id = 1;
+/* result:
+*/
+
+-- test: delete using with limit
+DELETE FROM orders USING customers WHERE orders.customer_id = customers.id LIMIT 1;
+SELECT id FROM orders;
+/* result:
+{"id": 2}
+{"id": 3}
+{"id": 4}
+*/
+
+-- test: duplicate table name
+DELETE FROM orders USING customers AS orders WHERE orders.id = 1;
+-- error: table name "orders" specified more than once
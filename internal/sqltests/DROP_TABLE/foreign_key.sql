@@ -0,0 +1,37 @@
+-- setup:
+CREATE TABLE parent (id INT PRIMARY KEY);
+CREATE TABLE child (id INT PRIMARY KEY, parent_id INT REFERENCES parent);
+
+-- test: referenced table
+DROP TABLE parent;
+-- error: cannot drop table "parent" because constraint "child_parent_id_fkey" of table "child" depends on it
+
+-- test: restrict
+DROP TABLE parent RESTRICT;
+-- error: cannot drop table "parent" because constraint "child_parent_id_fkey" of table "child" depends on it
+
+-- test: referencing table
+DROP TABLE child;
+DROP TABLE parent;
+SELECT COUNT(*) FROM __chai_catalog WHERE name = "parent" OR name = "child";
+/* result:
+{"COUNT(*)": 0}
+*/
+
+-- test: cascade
+DROP TABLE parent CASCADE;
+SELECT name, sql FROM __chai_catalog WHERE name = "parent" OR name = "child";
+/* result:
+{
+  "name": "child",
+  "sql": "CREATE TABLE child (id INTEGER NOT NULL, parent_id INTEGER, CONSTRAINT child_pk PRIMARY KEY (id))"
+}
+*/
+
+-- test: self reference
+CREATE TABLE node (id INT PRIMARY KEY, parent_id INT REFERENCES node);
+DROP TABLE node;
+SELECT COUNT(*) FROM __chai_catalog WHERE name = "node";
+/* result:
+{"COUNT(*)": 0}
+*/
@@ -0,0 +1,37 @@
+-- setup:
+CREATE TABLE test (a INT PRIMARY KEY, b TEXT);
+CREATE VIEW v AS SELECT a FROM test;
+
+-- test: read by a view
+DROP TABLE test;
+-- error: cannot drop table "test" because view "v" depends on it
+
+-- test: restrict
+DROP TABLE test RESTRICT;
+-- error: cannot drop table "test" because view "v" depends on it
+
+-- test: dropped view
+DROP VIEW v;
+DROP TABLE test;
+SELECT COUNT(*) FROM __chai_catalog WHERE name = "test" OR name = "v";
+/* result:
+{"COUNT(*)": 0}
+*/
+
+-- test: cascade
+CREATE VIEW v2 AS SELECT * FROM v;
+CREATE TABLE other (a INT);
+CREATE VIEW v3 AS SELECT a FROM other;
+DROP TABLE test CASCADE;
+SELECT name FROM __chai_catalog WHERE type = "view";
+/* result:
+{"name": "v3"}
+*/
+
+-- test: view
+DROP TABLE v;
+-- error: "v" is a view, use DROP VIEW to drop it
+
+-- test: view if exists
+DROP TABLE IF EXISTS v;
+-- error: "v" is a view, use DROP VIEW to drop it
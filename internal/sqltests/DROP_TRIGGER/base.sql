@@ -0,0 +1,38 @@
+-- setup:
+CREATE TABLE test(a INT PRIMARY KEY, b TEXT);
+CREATE TABLE counter(n INT);
+INSERT INTO counter (n) VALUES (0);
+CREATE TRIGGER trg AFTER INSERT ON test
+BEGIN
+    UPDATE counter SET n = n + 1;
+END;
+
+-- test: basic
+DROP TRIGGER trg;
+SELECT COUNT(*) FROM __chai_catalog WHERE name = "trg";
+/* result:
+{"COUNT(*)": 0}
+*/
+
+-- test: not executed
+DROP TRIGGER trg;
+INSERT INTO test (a, b) VALUES (1, 'foo');
+SELECT n FROM counter;
+/* result:
+{"n": 0}
+*/
+
+-- test: if exists
+DROP TRIGGER IF EXISTS unknown;
+SELECT COUNT(*) FROM __chai_catalog WHERE name = "trg";
+/* result:
+{"COUNT(*)": 1}
+*/
+
+-- test: unknown trigger
+DROP TRIGGER unknown;
+-- error:
+
+-- test: table
+DROP TRIGGER test;
+-- error:
@@ -0,0 +1,75 @@
+-- setup:
+CREATE TABLE test(a INT PRIMARY KEY, b TEXT);
+CREATE VIEW v AS SELECT a FROM test;
+
+-- test: basic
+DROP VIEW v;
+SELECT COUNT(*) FROM __chai_catalog WHERE name = "v";
+/* result:
+{"COUNT(*)": 0}
+*/
+
+-- test: query dropped view
+DROP VIEW v;
+SELECT * FROM v;
+-- error:
+
+-- test: recreate
+DROP VIEW v;
+CREATE VIEW v AS SELECT b FROM test;
+INSERT INTO test (a, b) VALUES (1, 'foo');
+SELECT * FROM v;
+/* result:
+{"b": "foo"}
+*/
+
+-- test: if exists
+DROP VIEW IF EXISTS unknown;
+SELECT COUNT(*) FROM __chai_catalog WHERE name = "v";
+/* result:
+{"COUNT(*)": 1}
+*/
+
+-- test: unknown view
+DROP VIEW unknown;
+-- error:
+
+-- test: table
+DROP VIEW test;
+-- error:
+
+-- test: dependent view
+CREATE VIEW v2 AS SELECT a FROM v;
+DROP VIEW v;
+-- error: cannot drop view "v" because view "v2" depends on it
+
+-- test: dependent view, subquery
+CREATE VIEW v2 AS SELECT a FROM test WHERE a IN (SELECT a FROM v);
+DROP VIEW v RESTRICT;
+-- error: cannot drop view "v" because view "v2" depends on it
+
+-- test: dependent view, common table
+CREATE VIEW v2 AS WITH v AS (SELECT 1 AS a) SELECT a FROM v;
+DROP VIEW v;
+SELECT * FROM v2;
+/* result:
+{"a": 1}
+*/
+
+-- test: cascade
+CREATE VIEW v2 AS SELECT a FROM v;
+CREATE VIEW v3 AS SELECT * FROM v2 JOIN v ON v2.a = v.a;
+CREATE VIEW other AS SELECT b FROM test;
+DROP VIEW v CASCADE;
+SELECT name FROM __chai_catalog WHERE type = "view";
+/* result:
+{"name": "other"}
+*/
+
+-- test: table
+DROP VIEW test;
+-- error: "test" is a table, use DROP TABLE to drop it
+
+-- test: table if exists
+DROP VIEW IF EXISTS test;
+-- error: "test" is a table, use DROP TABLE to drop it
@@ -0,0 +1,61 @@
+-- setup:
+CREATE TABLE parent (id INT PRIMARY KEY, code TEXT UNIQUE);
+CREATE TABLE child (id INT PRIMARY KEY, parent_id INT REFERENCES parent, parent_code TEXT REFERENCES parent (code));
+INSERT INTO parent (id, code) VALUES (1, 'a'), (2, 'b');
+
+-- test: referenced primary key
+INSERT INTO child (id, parent_id) VALUES (1, 1), (2, 2);
+SELECT id, parent_id FROM child;
+/* result:
+{"id": 1, "parent_id": 1}
+{"id": 2, "parent_id": 2}
+*/
+
+-- test: referenced unique field
+INSERT INTO child (id, parent_code) VALUES (1, 'b');
+SELECT id, parent_code FROM child;
+/* result:
+{"id": 1, "parent_code": "b"}
+*/
+
+-- test: converted value
+INSERT INTO child (id, parent_id) VALUES (1, 1.0);
+SELECT id, parent_id FROM child;
+/* result:
+{"id": 1, "parent_id": 1}
+*/
+
+-- test: NULL
+INSERT INTO child (id, parent_id, parent_code) VALUES (1, NULL, NULL);
+INSERT INTO child (id) VALUES (2);
+SELECT COUNT(*) FROM child;
+/* result:
+{"COUNT(*)": 2}
+*/
+
+-- test: missing parent
+INSERT INTO child (id, parent_id) VALUES (1, 3);
+-- error: insert or update on table "child" violates foreign key constraint "child_parent_id_fkey"
+
+-- test: missing parent, unique field
+INSERT INTO child (id, parent_code) VALUES (1, 'c');
+-- error: insert or update on table "child" violates foreign key constraint "child_parent_code_fkey"
+
+-- test: missing parent, ON CONFLICT DO NOTHING
+INSERT INTO child (id, parent_id) VALUES (1, 3) ON CONFLICT DO NOTHING;
+-- error: insert or update on table "child" violates foreign key constraint "child_parent_id_fkey"
+
+-- test: self reference
+CREATE TABLE node (id INT PRIMARY KEY, parent_id INT REFERENCES node);
+INSERT INTO node (id, parent_id) VALUES (1, 1), (2, 1), (3, 2);
+SELECT * FROM node;
+/* result:
+{"id": 1, "parent_id": 1}
+{"id": 2, "parent_id": 1}
+{"id": 3, "parent_id": 2}
+*/
+
+-- test: self reference, missing parent
+CREATE TABLE node (id INT PRIMARY KEY, parent_id INT REFERENCES node);
+INSERT INTO node (id, parent_id) VALUES (1, 2);
+-- error: insert or update on table "node" violates foreign key constraint "node_parent_id_fkey"
@@ -0,0 +1,104 @@
+-- setup:
+CREATE TABLE test (id int PRIMARY KEY, name text, email text UNIQUE, counter int DEFAULT 0);
+CREATE INDEX on test(counter);
+INSERT INTO test (id, name, email) VALUES (1, 'a', 'a@x'), (2, 'b', 'b@x');
+
+-- test: primary key conflict
+INSERT INTO test (id, name) VALUES (1, 'z')
+ON CONFLICT (id) DO UPDATE SET name = excluded.name, counter = counter + 1;
+SELECT * FROM test;
+/* result:
+{"id": 1, "name": "z", "email": "a@x", "counter": 1}
+{"id": 2, "name": "b", "email": "b@x", "counter": 0}
+*/
+
+-- test: keeps the columns that are not set
+INSERT INTO test (id, name, email) VALUES (1, 'z', 'z@x')
+ON CONFLICT (id) DO UPDATE SET counter = counter + 1;
+SELECT * FROM test WHERE id = 1;
+/* result:
+{"id": 1, "name": "a", "email": "a@x", "counter": 1}
+*/
+
+-- test: unique constraint conflict
+INSERT INTO test (id, name, email) VALUES (3, 'c', 'b@x')
+ON CONFLICT (email) DO UPDATE SET name = excluded.name || '!';
+SELECT * FROM test;
+/* result:
+{"id": 1, "name": "a", "email": "a@x", "counter": 0}
+{"id": 2, "name": "c!", "email": "b@x", "counter": 0}
+*/
+
+-- test: without target
+INSERT INTO test (id, name) VALUES (2, 'z')
+ON CONFLICT DO UPDATE SET name = excluded.name;
+SELECT name FROM test WHERE id = 2;
+/* result:
+{"name": "z"}
+*/
+
+-- test: no conflict
+INSERT INTO test (id, name, email) VALUES (3, 'c', 'c@x')
+ON CONFLICT (id) DO UPDATE SET counter = counter + 1;
+SELECT * FROM test;
+/* result:
+{"id": 1, "name": "a", "email": "a@x", "counter": 0}
+{"id": 2, "name": "b", "email": "b@x", "counter": 0}
+{"id": 3, "name": "c", "email": "c@x", "counter": 0}
+*/
+
+-- test: where
+INSERT INTO test (id, name) VALUES (1, 'y'), (2, 'z')
+ON CONFLICT (id) DO UPDATE SET name = excluded.name WHERE excluded.name > 'y';
+SELECT id, name FROM test;
+/* result:
+{"id": 1, "name": "a"}
+{"id": 2, "name": "z"}
+*/
+
+-- test: multiple rows
+INSERT INTO test (id, name) VALUES (1, 'a'), (1, 'a'), (4, 'd')
+ON CONFLICT (id) DO UPDATE SET counter = counter + 1;
+SELECT id, counter FROM test;
+/* result:
+{"id": 1, "counter": 2}
+{"id": 2, "counter": 0}
+{"id": 4, "counter": 0}
+*/
+
+-- test: indexes are updated
+INSERT INTO test (id) VALUES (2)
+ON CONFLICT (id) DO UPDATE SET counter = 10;
+SELECT id FROM test WHERE counter = 10;
+/* result:
+{"id": 2}
+*/
+
+-- test: update primary key
+INSERT INTO test (id) VALUES (2)
+ON CONFLICT (id) DO UPDATE SET id = 20;
+SELECT id, name FROM test;
+/* result:
+{"id": 1, "name": "a"}
+{"id": 20, "name": "b"}
+*/
+
+-- test: conflict on another constraint
+INSERT INTO test (id, email) VALUES (3, 'a@x')
+ON CONFLICT (id) DO UPDATE SET counter = counter + 1;
+-- error: UNIQUE constraint error: [email]
+
+-- test: update conflicts with another row
+INSERT INTO test (id) VALUES (2)
+ON CONFLICT (id) DO UPDATE SET email = 'a@x';
+-- error: UNIQUE constraint error: [email]
+
+-- test: target without constraint
+INSERT INTO test (id, name) VALUES (1, 'z')
+ON CONFLICT (name) DO UPDATE SET counter = counter + 1;
+-- error: no primary key or unique constraint matches the ON CONFLICT target
+
+-- test: target with do nothing
+INSERT INTO test (id, email) VALUES (1, 'c@x') ON CONFLICT (id) DO NOTHING;
+INSERT INTO test (id, email) VALUES (3, 'a@x') ON CONFLICT (id) DO NOTHING;
+-- error: UNIQUE constraint error: [email]
@@ -0,0 +1,29 @@
+-- setup:
+CREATE TABLE test(id int PRIMARY KEY, a int);
+INSERT INTO test (id, a) VALUES (1, 1), (2, 2), (3, NULL);
+
+-- test: not
+SELECT id FROM test WHERE NOT (a = 1);
+/* result:
+{"id": 2}
+*/
+
+-- test: not excludes null
+SELECT id FROM test WHERE NOT (a > 1);
+/* result:
+{"id": 1}
+*/
+
+-- test: not of null is null
+SELECT id, NOT (a > 1) AS n FROM test;
+/* result:
+{"id": 1, "n": true}
+{"id": 2, "n": false}
+{"id": 3, "n": null}
+*/
+
+-- test: is null
+SELECT id FROM test WHERE (NOT (a > 1)) IS NULL;
+/* result:
+{"id": 3}
+*/
@@ -0,0 +1,43 @@
+-- setup:
+CREATE TABLE test(id int PRIMARY KEY, score int, grade text);
+INSERT INTO test (id, score, grade) VALUES (1, 95, 'A'), (2, 75, 'B'), (3, 40, 'C'), (4, NULL, NULL);
+
+-- test: searched case in projection
+SELECT id, CASE WHEN score >= 90 THEN 'high' WHEN score >= 50 THEN 'medium' ELSE 'low' END AS level FROM test;
+/* result:
+{"id": 1, "level": "high"}
+{"id": 2, "level": "medium"}
+{"id": 3, "level": "low"}
+{"id": 4, "level": "low"}
+*/
+
+-- test: simple case in projection
+SELECT id, CASE grade WHEN 'A' THEN 4 WHEN 'B' THEN 3 END AS points FROM test;
+/* result:
+{"id": 1, "points": 4}
+{"id": 2, "points": 3}
+{"id": 3, "points": null}
+{"id": 4, "points": null}
+*/
+
+-- test: case in where
+SELECT id FROM test WHERE CASE WHEN score IS NULL THEN true ELSE score < 50 END;
+/* result:
+{"id": 3}
+{"id": 4}
+*/
+
+-- test: case in order by
+SELECT id, grade FROM test ORDER BY CASE grade WHEN 'C' THEN 0 ELSE 1 END, id;
+/* result:
+{"id": 3, "grade": "C"}
+{"id": 1, "grade": "A"}
+{"id": 2, "grade": "B"}
+{"id": 4, "grade": null}
+*/
+
+-- test: case in aggregate
+SELECT COUNT(CASE WHEN score >= 50 THEN 1 END) AS passed FROM test;
+/* result:
+{"passed": 2}
+*/
@@ -0,0 +1,96 @@
+-- setup:
+CREATE TABLE foo(a int, b int);
+CREATE TABLE bar(a int, b int);
+CREATE TABLE baz(a int, b int);
+INSERT INTO foo (a, b) VALUES (1, 1), (1, 1), (1, 1), (2, 2), (3, 3);
+INSERT INTO bar (a, b) VALUES (1, 1), (1, 1), (3, 3), (4, 4);
+INSERT INTO baz (a, b) VALUES (1, 1), (2, 2);
+
+-- test: except
+SELECT * FROM foo
+EXCEPT
+SELECT * FROM bar;
+/* result:
+{"a": 2, "b": 2}
+*/
+
+-- test: except all
+SELECT * FROM foo
+EXCEPT ALL
+SELECT * FROM bar;
+/* result:
+{"a": 1, "b": 1}
+{"a": 2, "b": 2}
+*/
+
+-- test: multiple excepts
+SELECT * FROM foo
+EXCEPT ALL
+SELECT * FROM bar
+EXCEPT ALL
+SELECT * FROM baz;
+/* result:
+*/
+
+-- test: except with projection
+SELECT a FROM foo
+EXCEPT
+SELECT a FROM baz;
+/* result:
+{"a": 3}
+*/
+
+-- test: except with order by
+SELECT * FROM bar
+EXCEPT
+SELECT * FROM baz
+ORDER BY a DESC;
+/* result:
+{"a": 4, "b": 4}
+{"a": 3, "b": 3}
+*/
+
+-- test: except then intersect
+-- intersect is evaluated first
+SELECT * FROM foo
+EXCEPT
+SELECT * FROM baz
+INTERSECT
+SELECT * FROM bar;
+/* result:
+{"a": 2, "b": 2}
+{"a": 3, "b": 3}
+*/
+
+-- test: except with different column names
+SELECT a AS x FROM foo
+EXCEPT
+SELECT b AS y FROM baz;
+/* result:
+{"x": 3}
+*/
+
+-- test: except with different numeric types
+SELECT 1 AS a, 2 AS b
+EXCEPT
+SELECT 1.0, 2;
+/* result:
+*/
+
+-- test: union then except
+SELECT a FROM foo
+UNION
+SELECT a FROM bar
+EXCEPT
+SELECT 4;
+/* result:
+{"a": 1}
+{"a": 2}
+{"a": 3}
+*/
+
+-- test: different number of columns
+SELECT a FROM foo
+EXCEPT
+SELECT a, b FROM bar;
+-- error: each EXCEPT query must have the same number of columns
@@ -0,0 +1,69 @@
+-- setup:
+CREATE TABLE sales(id int PRIMARY KEY, country text, city text, amount int);
+INSERT INTO sales (id, country, city, amount) VALUES
+    (1, 'fr', 'paris', 10),
+    (2, 'fr', 'lyon', 20),
+    (3, 'us', 'nyc', 30),
+    (4, 'fr', 'paris', 40),
+    (5, 'us', 'nyc', 50),
+    (6, 'us', 'sf', 60);
+
+-- suite: no index
+
+-- suite: with index
+CREATE INDEX ON sales(country, city);
+
+-- test: two columns
+SELECT country, city, COUNT(*) AS n, SUM(amount) AS total FROM sales GROUP BY country, city;
+/* result:
+{"country": "fr", "city": "lyon", "n": 1, "total": 20}
+{"country": "fr", "city": "paris", "n": 2, "total": 50}
+{"country": "us", "city": "nyc", "n": 2, "total": 80}
+{"country": "us", "city": "sf", "n": 1, "total": 60}
+*/
+
+-- test: projection in a different order
+SELECT COUNT(*), city, country FROM sales GROUP BY country, city;
+/* result:
+{"COUNT(*)": 1, "city": "lyon", "country": "fr"}
+{"COUNT(*)": 2, "city": "paris", "country": "fr"}
+{"COUNT(*)": 2, "city": "nyc", "country": "us"}
+{"COUNT(*)": 1, "city": "sf", "country": "us"}
+*/
+
+-- test: subset of the group columns
+SELECT city FROM sales GROUP BY country, city;
+/* result:
+{"city": "lyon"}
+{"city": "paris"}
+{"city": "nyc"}
+{"city": "sf"}
+*/
+
+-- test: expressions
+SELECT country, amount % 20 AS r, COUNT(*) FROM sales GROUP BY country, amount % 20;
+/* result:
+{"country": "fr", "r": 0, "COUNT(*)": 2}
+{"country": "fr", "r": 10, "COUNT(*)": 1}
+{"country": "us", "r": 0, "COUNT(*)": 1}
+{"country": "us", "r": 10, "COUNT(*)": 2}
+*/
+
+-- test: with having
+SELECT country, city FROM sales GROUP BY country, city HAVING COUNT(*) > 1 AND city != 'nyc';
+/* result:
+{"country": "fr", "city": "paris"}
+*/
+
+-- test: with order by
+SELECT country, city, MAX(amount) AS m FROM sales GROUP BY country, city ORDER BY m DESC;
+/* result:
+{"country": "us", "city": "sf", "m": 60}
+{"country": "us", "city": "nyc", "m": 50}
+{"country": "fr", "city": "paris", "m": 40}
+{"country": "fr", "city": "lyon", "m": 20}
+*/
+
+-- test: column not in group by
+SELECT country, amount FROM sales GROUP BY country, city;
+-- error: field "amount" must appear in the GROUP BY clause or be used in an aggregate function
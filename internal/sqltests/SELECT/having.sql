@@ -0,0 +1,81 @@
+-- setup:
+CREATE TABLE sales(id int PRIMARY KEY, country text, amount int);
+INSERT INTO sales (id, country, amount) VALUES
+    (1, 'fr', 10), (2, 'fr', 20), (3, 'us', 5), (4, 'uk', 30), (5, 'uk', 40), (6, 'uk', 1);
+
+-- test: count
+SELECT country, COUNT(*) AS n FROM sales GROUP BY country HAVING COUNT(*) > 1;
+/* result:
+{"country": "fr", "n": 2}
+{"country": "uk", "n": 3}
+*/
+
+-- test: aggregate not projected
+SELECT country FROM sales GROUP BY country HAVING SUM(amount) >= 30;
+/* result:
+{"country": "fr"}
+{"country": "uk"}
+*/
+
+-- test: multiple aggregates
+SELECT country, SUM(amount) AS total FROM sales GROUP BY country HAVING COUNT(*) > 1 AND MIN(amount) > 5;
+/* result:
+{"country": "fr", "total": 30}
+*/
+
+-- test: group by column
+SELECT country, MAX(amount) AS m FROM sales GROUP BY country HAVING country != 'uk';
+/* result:
+{"country": "fr", "m": 20}
+{"country": "us", "m": 5}
+*/
+
+-- test: group by expression
+SELECT id % 2, COUNT(*) FROM sales GROUP BY id % 2 HAVING id % 2 = 1;
+/* result:
+{"id % 2": 1, "COUNT(*)": 3}
+*/
+
+-- test: function of a group by expression
+SELECT id - 4, COUNT(*) FROM sales GROUP BY id - 4 HAVING abs(id - 4) > 1;
+/* result:
+{"id - 4": -3, "COUNT(*)": 1}
+{"id - 4": -2, "COUNT(*)": 1}
+{"id - 4": 2, "COUNT(*)": 1}
+*/
+
+-- test: function of a group by column
+SELECT country FROM sales GROUP BY country HAVING upper(country) = 'UK' OR CAST(country AS TEXT) = 'us';
+/* result:
+{"country": "uk"}
+{"country": "us"}
+*/
+
+-- test: with where
+SELECT country, COUNT(*) AS n FROM sales WHERE amount > 5 GROUP BY country HAVING COUNT(*) >= 2;
+/* result:
+{"country": "fr", "n": 2}
+{"country": "uk", "n": 2}
+*/
+
+-- test: without group by
+SELECT COUNT(*) AS n FROM sales HAVING COUNT(*) > 5;
+/* result:
+{"n": 6}
+*/
+
+-- test: without group by, no result
+SELECT COUNT(*) AS n FROM sales HAVING SUM(amount) > 1000;
+/* result:
+*/
+
+-- test: with order by
+SELECT country, SUM(amount) AS total FROM sales GROUP BY country HAVING SUM(amount) > 10 ORDER BY total DESC;
+/* result:
+{"country": "uk", "total": 71}
+{"country": "fr", "total": 30}
+*/
+
+-- test: non grouped column
+SELECT country FROM sales GROUP BY country HAVING amount > 10;
+-- error: field "amount" must appear in the GROUP BY clause or be used in an aggregate function
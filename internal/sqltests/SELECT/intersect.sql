@@ -0,0 +1,97 @@
+-- setup:
+CREATE TABLE foo(a int, b int);
+CREATE TABLE bar(a int, b int);
+CREATE TABLE baz(a int, b int);
+INSERT INTO foo (a, b) VALUES (1, 1), (1, 1), (1, 1), (2, 2), (3, 3);
+INSERT INTO bar (a, b) VALUES (1, 1), (1, 1), (3, 3), (4, 4);
+INSERT INTO baz (a, b) VALUES (1, 1), (2, 2);
+
+-- test: intersect
+SELECT * FROM foo
+INTERSECT
+SELECT * FROM bar;
+/* result:
+{"a": 1, "b": 1}
+{"a": 3, "b": 3}
+*/
+
+-- test: intersect all
+SELECT * FROM foo
+INTERSECT ALL
+SELECT * FROM bar;
+/* result:
+{"a": 1, "b": 1}
+{"a": 1, "b": 1}
+{"a": 3, "b": 3}
+*/
+
+-- test: multiple intersects
+SELECT * FROM foo
+INTERSECT
+SELECT * FROM bar
+INTERSECT
+SELECT * FROM baz;
+/* result:
+{"a": 1, "b": 1}
+*/
+
+-- test: intersect with projection
+SELECT a FROM foo WHERE a > 1
+INTERSECT
+SELECT a FROM baz;
+/* result:
+{"a": 2}
+*/
+
+-- test: intersect with no common rows
+SELECT * FROM bar WHERE a = 4
+INTERSECT
+SELECT * FROM baz;
+/* result:
+*/
+
+-- test: intersect with order by and limit
+SELECT * FROM foo
+INTERSECT ALL
+SELECT * FROM bar
+ORDER BY a DESC
+LIMIT 2;
+/* result:
+{"a": 3, "b": 3}
+{"a": 1, "b": 1}
+*/
+
+-- test: intersect then union
+SELECT * FROM foo
+INTERSECT
+SELECT * FROM bar
+UNION
+SELECT * FROM baz;
+/* result:
+{"a": 1, "b": 1}
+{"a": 2, "b": 2}
+{"a": 3, "b": 3}
+*/
+
+-- test: intersect with different column names
+SELECT a AS x FROM foo
+INTERSECT
+SELECT b AS y FROM bar;
+/* result:
+{"x": 1}
+{"x": 3}
+*/
+
+-- test: intersect with different numeric types
+SELECT 1
+INTERSECT
+SELECT 1.0;
+/* result:
+{"1": 1}
+*/
+
+-- test: different number of columns
+SELECT a FROM foo
+INTERSECT
+SELECT * FROM bar;
+-- error: each INTERSECT query must have the same number of columns
@@ -0,0 +1,114 @@
+-- setup:
+CREATE TABLE customers(id int PRIMARY KEY, name text);
+CREATE TABLE orders(oid int PRIMARY KEY, customer_id int, amount int);
+INSERT INTO customers (id, name) VALUES (1, 'alice'), (2, 'bob'), (3, 'carol');
+INSERT INTO orders (oid, customer_id, amount) VALUES (10, 1, 100), (11, 1, 50), (12, 2, 70), (13, 4, 10);
+
+-- test: inner join
+SELECT name, amount FROM customers JOIN orders ON customers.id = orders.customer_id;
+/* result:
+{"name": "alice", "amount": 100}
+{"name": "alice", "amount": 50}
+{"name": "bob", "amount": 70}
+*/
+
+-- test: inner join keyword
+SELECT name, amount FROM customers INNER JOIN orders ON customers.id = orders.customer_id;
+/* result:
+{"name": "alice", "amount": 100}
+{"name": "alice", "amount": 50}
+{"name": "bob", "amount": 70}
+*/
+
+-- test: aliases
+SELECT c.name, o.oid FROM customers AS c JOIN orders o ON c.id = o.customer_id;
+/* result:
+{"c.name": "alice", "o.oid": 10}
+{"c.name": "alice", "o.oid": 11}
+{"c.name": "bob", "o.oid": 12}
+*/
+
+-- test: left join
+SELECT c.name, o.amount FROM customers c LEFT JOIN orders o ON c.id = o.customer_id;
+/* result:
+{"c.name": "alice", "o.amount": 100}
+{"c.name": "alice", "o.amount": 50}
+{"c.name": "bob", "o.amount": 70}
+{"c.name": "carol", "o.amount": null}
+*/
+
+-- test: left outer join
+SELECT c.name, o.amount FROM customers c LEFT OUTER JOIN orders o ON c.id = o.customer_id WHERE o.amount IS NULL;
+/* result:
+{"c.name": "carol", "o.amount": null}
+*/
+
+-- test: wildcard
+SELECT * FROM customers c JOIN orders o ON c.id = o.customer_id WHERE o.oid = 12;
+/* result:
+{"id": 2, "name": "bob", "oid": 12, "customer_id": 2, "amount": 70}
+*/
+
+-- test: where and aggregation
+SELECT COUNT(*) AS n, SUM(o.amount) AS total FROM customers c JOIN orders o ON c.id = o.customer_id WHERE c.name = 'alice';
+/* result:
+{"n": 2, "total": 150}
+*/
+
+-- test: order by qualified column
+SELECT o.oid FROM customers c JOIN orders o ON c.id = o.customer_id ORDER BY o.amount;
+/* result:
+{"o.oid": 11}
+{"o.oid": 12}
+{"o.oid": 10}
+*/
+
+-- test: multiple joins
+CREATE TABLE items(order_id int, label text);
+INSERT INTO items (order_id, label) VALUES (10, 'a'), (10, 'b'), (12, 'c');
+SELECT c.name, i.label FROM customers c JOIN orders o ON c.id = o.customer_id JOIN items i ON i.order_id = o.oid;
+/* result:
+{"c.name": "alice", "i.label": "a"}
+{"c.name": "alice", "i.label": "b"}
+{"c.name": "bob", "i.label": "c"}
+*/
+
+-- test: non equi join
+SELECT c.name, o.oid FROM customers c JOIN orders o ON o.amount > 60 AND c.id = 3;
+/* result:
+{"c.name": "carol", "o.oid": 10}
+{"c.name": "carol", "o.oid": 12}
+*/
+
+-- test: single table alias
+SELECT c.name FROM customers c WHERE c.id > 1;
+/* result:
+{"c.name": "bob"}
+{"c.name": "carol"}
+*/
+
+-- test: ambiguous column
+SELECT id FROM customers a JOIN customers b ON a.id = b.id;
+-- error: column reference "id" is ambiguous
+
+-- test: duplicate table name
+SELECT * FROM customers JOIN customers ON customers.id = customers.id;
+-- error: table name "customers" specified more than once
+
+-- test: self join
+SELECT a.name AS x, b.name AS y FROM customers a JOIN customers b ON a.id + 1 = b.id;
+/* result:
+{"x": "alice", "y": "bob"}
+{"x": "bob", "y": "carol"}
+*/
+
+-- test: missing ON
+SELECT * FROM customers JOIN orders;
+-- error:
+
+-- test: distinct
+SELECT DISTINCT c.name FROM customers c JOIN orders o ON c.id = o.customer_id;
+/* result:
+{"c.name": "alice"}
+{"c.name": "bob"}
+*/
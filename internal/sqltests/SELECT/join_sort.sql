@@ -0,0 +1,53 @@
+-- setup:
+CREATE TABLE foo(id int PRIMARY KEY, a int);
+CREATE TABLE bar(id int PRIMARY KEY, foo_id int, c int);
+INSERT INTO foo (id, a) VALUES (1, 10), (2, 20), (3, 30);
+INSERT INTO bar (id, foo_id, c) VALUES (1, 1, 5), (2, 1, 3), (3, 2, 4), (4, 4, 1);
+
+-- test: group by
+SELECT foo.id, COUNT(*) FROM foo JOIN bar ON foo.id = bar.foo_id GROUP BY foo.id;
+/* result:
+{"foo.id": 1, "COUNT(*)": 2}
+{"foo.id": 2, "COUNT(*)": 1}
+*/
+
+-- test: group by with left join
+SELECT foo.id, COUNT(bar.id) FROM foo LEFT JOIN bar ON foo.id = bar.foo_id GROUP BY foo.id;
+/* result:
+{"foo.id": 1, "COUNT(bar.id)": 2}
+{"foo.id": 2, "COUNT(bar.id)": 1}
+{"foo.id": 3, "COUNT(bar.id)": 0}
+*/
+
+-- test: order by
+SELECT foo.id, bar.id FROM foo JOIN bar ON foo.id = bar.foo_id ORDER BY bar.c;
+/* result:
+{"foo.id": 1, "bar.id": 2}
+{"foo.id": 2, "bar.id": 3}
+{"foo.id": 1, "bar.id": 1}
+*/
+
+-- test: order by with duplicate column names
+SELECT * FROM foo JOIN bar ON foo.id = bar.foo_id ORDER BY bar.c DESC;
+/* result:
+{"id": 1, "a": 10, "id": 1, "foo_id": 1, "c": 5}
+{"id": 2, "a": 20, "id": 3, "foo_id": 2, "c": 4}
+{"id": 1, "a": 10, "id": 2, "foo_id": 1, "c": 3}
+*/
+
+-- test: order by with left join
+SELECT foo.id, bar.c FROM foo LEFT JOIN bar ON foo.id = bar.foo_id ORDER BY foo.a DESC, bar.c;
+/* result:
+{"foo.id": 3, "bar.c": null}
+{"foo.id": 2, "bar.c": 4}
+{"foo.id": 1, "bar.c": 3}
+{"foo.id": 1, "bar.c": 5}
+*/
+
+-- test: window
+SELECT foo.id, bar.c, row_number() OVER (PARTITION BY foo.id ORDER BY bar.c) AS n FROM foo JOIN bar ON foo.id = bar.foo_id;
+/* result:
+{"foo.id": 1, "bar.c": 3, "n": 1}
+{"foo.id": 1, "bar.c": 5, "n": 2}
+{"foo.id": 2, "bar.c": 4, "n": 1}
+*/
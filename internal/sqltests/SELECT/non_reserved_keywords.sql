@@ -0,0 +1,38 @@
+-- setup:
+CREATE TABLE view(id int PRIMARY KEY, left int, foreign int, match text, row int);
+CREATE TABLE join(id int PRIMARY KEY, view_id int);
+INSERT INTO view (id, left, foreign, match, row) VALUES (1, 10, 100, 'a', 1), (2, 20, 200, 'b', 2);
+INSERT INTO join (id, view_id) VALUES (1, 1);
+
+-- test: keywords as column names
+SELECT left, foreign, match, row FROM view WHERE left > 10;
+/* result:
+{"left": 20, "foreign": 200, "match": "b", "row": 2}
+*/
+
+-- test: keywords as table names in a join
+SELECT view.id, join.id FROM view LEFT JOIN join ON view.id = join.view_id;
+/* result:
+{"view.id": 1, "join.id": 1}
+{"view.id": 2, "join.id": NULL}
+*/
+
+-- test: keyword as alias
+SELECT left.id FROM view AS left JOIN join AS inner ON left.id = inner.view_id;
+/* result:
+{"left.id": 1}
+*/
+
+-- test: match operator with a column named match
+SELECT id FROM view WHERE match MATCH 'b';
+/* result:
+{"id": 2}
+*/
+
+-- test: keywords used by compound selects and CASE as names
+CREATE TABLE except(when int, then int, else int, end int, having int);
+INSERT INTO except (when, then, else, end, having) VALUES (1, 2, 3, 4, 5);
+SELECT CASE WHEN when = 1 THEN then ELSE else END AS c, end, having FROM except WHERE having = 5 EXCEPT SELECT 0, 0, 0;
+/* result:
+{"c": 2, "end": 4, "having": 5}
+*/
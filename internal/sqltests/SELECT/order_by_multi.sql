@@ -0,0 +1,74 @@
+-- setup:
+CREATE TABLE test(id int PRIMARY KEY, a int, b text);
+INSERT INTO test (id, a, b) VALUES (1, 2, 'B'), (2, 1, 'c'), (3, 2, 'a'), (4, 1, 'A'), (5, 2, 'b');
+
+-- suite: no index
+
+-- suite: with index
+CREATE INDEX ON test(a, b);
+
+-- test: asc, asc
+SELECT id FROM test ORDER BY a, b;
+/* result:
+{"id": 4}
+{"id": 2}
+{"id": 1}
+{"id": 3}
+{"id": 5}
+*/
+
+-- test: desc, asc
+SELECT id FROM test ORDER BY a DESC, b;
+/* result:
+{"id": 1}
+{"id": 3}
+{"id": 5}
+{"id": 4}
+{"id": 2}
+*/
+
+-- test: asc, desc
+SELECT id FROM test ORDER BY a ASC, b DESC;
+/* result:
+{"id": 2}
+{"id": 4}
+{"id": 5}
+{"id": 3}
+{"id": 1}
+*/
+
+-- test: expression
+SELECT id FROM test ORDER BY a DESC, lower(b) DESC, id;
+/* result:
+{"id": 1}
+{"id": 5}
+{"id": 3}
+{"id": 2}
+{"id": 4}
+*/
+
+-- test: projected alias
+SELECT a + 1 AS x, id FROM test ORDER BY x, id DESC;
+/* result:
+{"x": 2, "id": 4}
+{"x": 2, "id": 2}
+{"x": 3, "id": 5}
+{"x": 3, "id": 3}
+{"x": 3, "id": 1}
+*/
+
+-- test: with limit
+SELECT id FROM test ORDER BY a, b DESC LIMIT 2;
+/* result:
+{"id": 2}
+{"id": 4}
+*/
+
+-- test: projection of a subset of the columns
+CREATE TABLE ts(id int PRIMARY KEY, t timestamp NOT NULL, n int NOT NULL);
+INSERT INTO ts (id, t, n) VALUES (1, '2023-01-01', 2), (2, '2021-01-01', 1);
+SELECT t AS d, n FROM ts ORDER BY d;
+/* result:
+{"d": "2021-01-01T00:00:00Z", "n": 1}
+{"d": "2023-01-01T00:00:00Z", "n": 2}
+*/
@@ -0,0 +1,165 @@
+-- setup:
+CREATE TABLE customers(id int PRIMARY KEY, name text);
+CREATE TABLE orders(oid int PRIMARY KEY, customer_id int, amount int);
+INSERT INTO customers (id, name) VALUES (1, 'alice'), (2, 'bob'), (3, 'carol');
+INSERT INTO orders (oid, customer_id, amount) VALUES (10, 1, 100), (11, 1, 50), (12, 2, 70);
+
+-- test: in
+SELECT name FROM customers WHERE id IN (SELECT customer_id FROM orders);
+/* result:
+{"name": "alice"}
+{"name": "bob"}
+*/
+
+-- test: not in
+SELECT name FROM customers WHERE id NOT IN (SELECT customer_id FROM orders WHERE amount > 60);
+/* result:
+{"name": "carol"}
+*/
+
+-- test: not in with null
+INSERT INTO orders (oid, customer_id, amount) VALUES (13, NULL, 10);
+SELECT name FROM customers WHERE id NOT IN (SELECT customer_id FROM orders);
+/* result:
+*/
+
+-- test: not in with null as expression
+INSERT INTO orders (oid, customer_id, amount) VALUES (13, NULL, 10);
+SELECT 3 NOT IN (SELECT customer_id FROM orders) AS a, 1 NOT IN (SELECT customer_id FROM orders) AS b;
+/* result:
+{"a": null, "b": false}
+*/
+
+-- test: in with empty result
+SELECT name FROM customers WHERE id IN (SELECT customer_id FROM orders WHERE amount > 1000);
+/* result:
+*/
+
+-- test: exists
+SELECT name FROM customers c WHERE EXISTS (SELECT 1 FROM orders o WHERE o.customer_id = c.id);
+/* result:
+{"name": "alice"}
+{"name": "bob"}
+*/
+
+-- test: unqualified outer column
+SELECT name FROM customers WHERE (SELECT COUNT(*) FROM orders WHERE customer_id = id) > 1;
+/* result:
+{"name": "alice"}
+*/
+
+-- test: unqualified outer column in a nested subquery
+SELECT name FROM customers WHERE EXISTS (SELECT 1 FROM orders WHERE customer_id = id AND oid IN (SELECT oid FROM orders WHERE amount > id * 30));
+/* result:
+{"name": "alice"}
+{"name": "bob"}
+*/
+
+-- test: inner column shadowing an outer column
+SELECT name FROM customers WHERE id IN (SELECT customer_id FROM orders WHERE oid = 10 AND name IS NULL);
+/* result:
+*/
+
+-- test: common table referencing the outer row
+SELECT name FROM customers c WHERE EXISTS (WITH t AS (SELECT oid FROM orders WHERE customer_id = c.id) SELECT 1 FROM t);
+/* result:
+{"name": "alice"}
+{"name": "bob"}
+*/
+
+-- test: common table referencing an unqualified outer column
+SELECT name FROM customers WHERE (WITH t AS (SELECT oid FROM orders WHERE customer_id = id) SELECT COUNT(*) FROM t) > 1;
+/* result:
+{"name": "alice"}
+*/
+
+-- test: not exists
+SELECT name FROM customers WHERE NOT EXISTS (SELECT 1 FROM orders WHERE orders.customer_id = customers.id);
+/* result:
+{"name": "carol"}
+*/
+
+-- test: uncorrelated exists
+SELECT name FROM customers WHERE EXISTS (SELECT 1 FROM orders WHERE amount > 1000);
+/* result:
+*/
+
+-- test: scalar
+SELECT name FROM customers WHERE id = (SELECT customer_id FROM orders WHERE amount = 70);
+/* result:
+{"name": "bob"}
+*/
+
+-- test: scalar with aggregate
+SELECT oid FROM orders WHERE amount > (SELECT AVG(amount) FROM orders);
+/* result:
+{"oid": 10}
+*/
+
+-- test: scalar without rows
+SELECT (SELECT name FROM customers WHERE id = 10) AS name;
+/* result:
+{"name": null}
+*/
+
+-- test: correlated scalar in projection
+SELECT c.name, (SELECT SUM(o.amount) FROM orders o WHERE o.customer_id = c.id) AS total FROM customers c;
+/* result:
+{"c.name": "alice", "total": 150}
+{"c.name": "bob", "total": 70}
+{"c.name": "carol", "total": null}
+*/
+
+-- test: correlated in
+SELECT oid FROM orders o WHERE o.amount IN (SELECT MAX(amount) FROM orders WHERE customer_id = o.customer_id);
+/* result:
+{"oid": 10}
+{"oid": 12}
+*/
+
+-- test: inner name shadows outer name
+SELECT name FROM customers c WHERE EXISTS (SELECT 1 FROM customers c WHERE c.id = 3);
+/* result:
+{"name": "alice"}
+{"name": "bob"}
+{"name": "carol"}
+*/
+
+-- test: nested
+SELECT name FROM customers c WHERE EXISTS (SELECT 1 FROM orders o WHERE o.customer_id = c.id AND o.oid IN (SELECT oid FROM orders WHERE amount > c.id * 40));
+/* result:
+{"name": "alice"}
+*/
+
+-- test: update
+UPDATE customers SET name = 'vip' WHERE id IN (SELECT customer_id FROM orders WHERE amount >= 100);
+SELECT name FROM customers WHERE id = 1;
+/* result:
+{"name": "vip"}
+*/
+
+-- test: delete
+DELETE FROM orders WHERE NOT EXISTS (SELECT 1 FROM customers WHERE customers.id = orders.customer_id AND customers.name = 'alice');
+SELECT oid FROM orders;
+/* result:
+{"oid": 10}
+{"oid": 11}
+*/
+
+-- test: scalar with more than one row
+SELECT name FROM customers WHERE id = (SELECT customer_id FROM orders);
+-- error: more than one row returned by a subquery used as an expression
+
+-- test: more than one column
+SELECT name FROM customers WHERE id IN (SELECT oid, customer_id FROM orders);
+-- error: subquery must return only one column
+
+-- test: check constraint
+CREATE TABLE test(a int CHECK (a IN (SELECT id FROM customers)));
+-- error: subqueries are not allowed in CHECK constraints
+
+-- test: column qualified by the table name
+SELECT customers.name FROM customers WHERE customers.id = 2;
+/* result:
+{"customers.name": "bob"}
+*/
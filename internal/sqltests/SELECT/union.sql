@@ -135,4 +135,30 @@ SELECT * FROM baz;
 {"a": 2.0, "b": 2.0}
 {"a": 3.0, "b": 3.0}
 {"x": "a", "y": "a"}
-{"x": "b", "y": "b"}
\ No newline at end of file
+{"x": "b", "y": "b"}
+*/
+
+-- test: union with order by
+SELECT * FROM foo
+UNION
+SELECT * FROM bar
+ORDER BY a DESC;
+/* result:
+{"a": 3.0, "b": 3.0}
+{"a": 2.0, "b": 2.0}
+{"a": 1.0, "b": 1.0}
+*/
+
+-- test: union with different column names
+SELECT 1 AS a
+UNION
+SELECT 1.0 AS b;
+/* result:
+{"a": 1}
+*/
+
+-- test: union with different number of columns
+SELECT 1 AS a
+UNION
+SELECT 1, 2;
+-- error: each UNION query must have the same number of columns
@@ -0,0 +1,171 @@
+-- setup:
+CREATE TABLE emp(id int PRIMARY KEY, dept text, salary int);
+INSERT INTO emp (id, dept, salary) VALUES (1, 'a', 100), (2, 'a', 200), (3, 'b', 150), (4, 'a', 200), (5, 'b', 50);
+
+-- test: row_number with partition
+SELECT id, row_number() OVER (PARTITION BY dept ORDER BY salary DESC, id) AS rn FROM emp;
+/* result:
+{"id": 2, "rn": 1}
+{"id": 4, "rn": 2}
+{"id": 1, "rn": 3}
+{"id": 3, "rn": 1}
+{"id": 5, "rn": 2}
+*/
+
+-- test: row_number without partition
+SELECT id, row_number() OVER (ORDER BY salary, id) AS rn FROM emp;
+/* result:
+{"id": 5, "rn": 1}
+{"id": 1, "rn": 2}
+{"id": 3, "rn": 3}
+{"id": 2, "rn": 4}
+{"id": 4, "rn": 5}
+*/
+
+-- test: rank and dense_rank
+SELECT id, rank() OVER (ORDER BY dept) AS r, dense_rank() OVER (ORDER BY dept) AS dr FROM emp;
+/* result:
+{"id": 1, "r": 1, "dr": 1}
+{"id": 2, "r": 1, "dr": 1}
+{"id": 4, "r": 1, "dr": 1}
+{"id": 3, "r": 4, "dr": 2}
+{"id": 5, "r": 4, "dr": 2}
+*/
+
+-- test: lag and lead
+SELECT id, lag(salary) OVER (ORDER BY id) AS l, lead(salary, 2, 0) OVER (ORDER BY id) AS ld FROM emp;
+/* result:
+{"id": 1, "l": null, "ld": 150}
+{"id": 2, "l": 100, "ld": 200}
+{"id": 3, "l": 200, "ld": 50}
+{"id": 4, "l": 150, "ld": 0}
+{"id": 5, "l": 200, "ld": 0}
+*/
+
+-- test: lag with partition
+SELECT id, lag(id) OVER (PARTITION BY dept ORDER BY id) AS prev FROM emp;
+/* result:
+{"id": 1, "prev": null}
+{"id": 2, "prev": 1}
+{"id": 4, "prev": 2}
+{"id": 3, "prev": null}
+{"id": 5, "prev": 3}
+*/
+
+-- test: running sum
+SELECT id, SUM(salary) OVER (PARTITION BY dept ORDER BY id) AS s FROM emp ORDER BY id;
+/* result:
+{"id": 1, "s": 100}
+{"id": 2, "s": 300}
+{"id": 3, "s": 150}
+{"id": 4, "s": 500}
+{"id": 5, "s": 200}
+*/
+
+-- test: running sum with peers
+SELECT id, SUM(salary) OVER (ORDER BY dept) AS s FROM emp;
+/* result:
+{"id": 1, "s": 500}
+{"id": 2, "s": 500}
+{"id": 4, "s": 500}
+{"id": 3, "s": 700}
+{"id": 5, "s": 700}
+*/
+
+-- test: aggregate over the whole partition
+SELECT id, AVG(salary) OVER (PARTITION BY dept) AS a, COUNT(*) OVER () AS c FROM emp ORDER BY id;
+/* result:
+{"id": 1, "a": 166.66666666666666, "c": 5}
+{"id": 2, "a": 166.66666666666666, "c": 5}
+{"id": 3, "a": 100.0, "c": 5}
+{"id": 4, "a": 166.66666666666666, "c": 5}
+{"id": 5, "a": 100.0, "c": 5}
+*/
+
+-- test: rows frame
+SELECT id, SUM(salary) OVER (ORDER BY id ROWS BETWEEN 1 PRECEDING AND 1 FOLLOWING) AS s FROM emp;
+/* result:
+{"id": 1, "s": 300}
+{"id": 2, "s": 450}
+{"id": 3, "s": 550}
+{"id": 4, "s": 400}
+{"id": 5, "s": 250}
+*/
+
+-- test: moving average
+SELECT id, AVG(salary) OVER (ORDER BY id ROWS 1 PRECEDING) AS a FROM emp;
+/* result:
+{"id": 1, "a": 100.0}
+{"id": 2, "a": 150.0}
+{"id": 3, "a": 175.0}
+{"id": 4, "a": 175.0}
+{"id": 5, "a": 125.0}
+*/
+
+-- test: rows frame until the end of the partition
+SELECT id, SUM(salary) OVER (PARTITION BY dept ORDER BY id ROWS BETWEEN CURRENT ROW AND UNBOUNDED FOLLOWING) AS s FROM emp ORDER BY id;
+/* result:
+{"id": 1, "s": 500}
+{"id": 2, "s": 400}
+{"id": 3, "s": 200}
+{"id": 4, "s": 200}
+{"id": 5, "s": 50}
+*/
+
+-- test: multiple windows
+SELECT id, row_number() OVER (ORDER BY salary, id) AS rn, row_number() OVER (ORDER BY id DESC) AS rev FROM emp ORDER BY id;
+/* result:
+{"id": 1, "rn": 2, "rev": 5}
+{"id": 2, "rn": 4, "rev": 4}
+{"id": 3, "rn": 3, "rev": 3}
+{"id": 4, "rn": 5, "rev": 2}
+{"id": 5, "rn": 1, "rev": 1}
+*/
+
+-- test: order by window alias
+SELECT id, row_number() OVER (ORDER BY salary DESC, id DESC) AS rn FROM emp ORDER BY rn LIMIT 2;
+/* result:
+{"id": 4, "rn": 1}
+{"id": 2, "rn": 2}
+*/
+
+-- test: expression using a window function
+SELECT id, salary - lag(salary, 1, salary) OVER (ORDER BY id) AS diff FROM emp;
+/* result:
+{"id": 1, "diff": 0}
+{"id": 2, "diff": 100}
+{"id": 3, "diff": -50}
+{"id": 4, "diff": 50}
+{"id": 5, "diff": -150}
+*/
+
+-- test: with where
+SELECT id, row_number() OVER (ORDER BY id) AS rn FROM emp WHERE dept = 'b';
+/* result:
+{"id": 3, "rn": 1}
+{"id": 5, "rn": 2}
+*/
+
+-- test: missing over
+SELECT row_number() FROM emp;
+-- error: window function ROW_NUMBER() requires an OVER clause
+
+-- test: over on a scalar function
+SELECT typeof(id) OVER () FROM emp;
+-- error: OVER specified, but typeof(id) is not a window function nor an aggregate function
+
+-- test: window in where
+SELECT id FROM emp WHERE row_number() OVER () > 1;
+-- error: window functions are not allowed in WHERE
+
+-- test: window with group by
+SELECT dept, row_number() OVER () FROM emp GROUP BY dept;
+-- error: window functions cannot be used along with aggregate functions or GROUP BY
+
+-- test: nested windows
+SELECT SUM(row_number() OVER ()) OVER () FROM emp;
+-- error: window function calls cannot be nested
+
+-- test: invalid frame
+SELECT SUM(salary) OVER (ROWS BETWEEN CURRENT ROW AND 1 PRECEDING) FROM emp;
+-- error: frame end cannot precede frame start
@@ -0,0 +1,205 @@
+-- setup:
+CREATE TABLE categories(id int PRIMARY KEY, parent_id int, name text NOT NULL);
+INSERT INTO categories (id, parent_id, name) VALUES
+    (1, NULL, 'root'),
+    (2, 1, 'books'),
+    (3, 1, 'music'),
+    (4, 2, 'novels'),
+    (5, 4, 'scifi'),
+    (6, 3, 'jazz');
+
+-- test: simple
+WITH c AS (SELECT id, name FROM categories WHERE parent_id = 1)
+SELECT * FROM c;
+/* result:
+{"id": 2, "name": "books"}
+{"id": 3, "name": "music"}
+*/
+
+-- test: column list
+WITH c(x, y) AS (SELECT id, name FROM categories WHERE parent_id = 1)
+SELECT c.x, y FROM c WHERE x > 2;
+/* result:
+{"c.x": 3, "y": "music"}
+*/
+
+-- test: alias
+WITH c AS (SELECT id, name FROM categories)
+SELECT t.name FROM c AS t WHERE t.id = 4;
+/* result:
+{"t.name": "novels"}
+*/
+
+-- test: multiple
+WITH a AS (SELECT id, parent_id FROM categories WHERE parent_id IS NOT NULL),
+     b AS (SELECT id FROM a WHERE parent_id = 1)
+SELECT a.id FROM a JOIN b ON a.parent_id = b.id;
+/* result:
+{"a.id": 4}
+{"a.id": 6}
+*/
+
+-- test: join with a table
+WITH leaves AS (SELECT id, parent_id FROM categories WHERE id NOT IN (SELECT parent_id FROM categories WHERE parent_id IS NOT NULL))
+SELECT l.id, p.name FROM leaves l LEFT JOIN categories p ON l.parent_id = p.id;
+/* result:
+{"l.id": 5, "p.name": "novels"}
+{"l.id": 6, "p.name": "music"}
+*/
+
+-- test: aggregation
+WITH c AS (SELECT parent_id FROM categories WHERE parent_id IS NOT NULL)
+SELECT parent_id, COUNT(*) AS n FROM c GROUP BY parent_id;
+/* result:
+{"parent_id": 1, "n": 2}
+{"parent_id": 2, "n": 1}
+{"parent_id": 3, "n": 1}
+{"parent_id": 4, "n": 1}
+*/
+
+-- test: order by and limit
+WITH c AS (SELECT id, name FROM categories ORDER BY name LIMIT 3)
+SELECT name FROM c ORDER BY id DESC;
+/* result:
+{"name": "jazz"}
+{"name": "music"}
+{"name": "books"}
+*/
+
+-- test: subquery
+SELECT name FROM categories WHERE id IN (WITH c AS (SELECT 2 AS x) SELECT x FROM c);
+/* result:
+{"name": "books"}
+*/
+
+-- test: visible to subqueries
+WITH c AS (SELECT id FROM categories WHERE id > 4)
+SELECT name FROM categories WHERE id IN (SELECT id FROM c);
+/* result:
+{"name": "scifi"}
+{"name": "jazz"}
+*/
+
+-- test: computed once
+WITH c AS (SELECT random() AS r)
+SELECT COUNT(*) AS n FROM c AS a JOIN c AS b ON a.r = b.r;
+/* result:
+{"n": 1}
+*/
+
+-- test: computed once per outer row when correlated
+SELECT name FROM categories p WHERE id < 4 AND EXISTS (
+    WITH c AS (SELECT id FROM categories WHERE parent_id = p.id)
+    SELECT 1 FROM c AS a JOIN c AS b ON a.id = b.id
+);
+/* result:
+{"name": "root"}
+{"name": "books"}
+{"name": "music"}
+*/
+
+-- test: shadows a table
+WITH categories AS (SELECT 1 AS a)
+SELECT * FROM categories;
+/* result:
+{"a": 1}
+*/
+
+-- test: insert
+CREATE TABLE test(id int PRIMARY KEY, name text);
+INSERT INTO test (id, name) WITH c AS (SELECT id, name FROM categories WHERE id < 3) SELECT id, name FROM c;
+SELECT * FROM test;
+/* result:
+{"id": 1, "name": "root"}
+{"id": 2, "name": "books"}
+*/
+
+-- test: recursive tree
+WITH RECURSIVE sub AS (
+    SELECT id, name, 0 AS depth FROM categories WHERE id = 2
+    UNION ALL
+    SELECT c.id, c.name, sub.depth + 1 FROM categories c JOIN sub ON c.parent_id = sub.id
+)
+SELECT * FROM sub;
+/* result:
+{"id": 2, "name": "books", "depth": 0}
+{"id": 4, "name": "novels", "depth": 1}
+{"id": 5, "name": "scifi", "depth": 2}
+*/
+
+-- test: recursive ancestors
+WITH RECURSIVE ancestors(id, parent_id, name) AS (
+    SELECT id, parent_id, name FROM categories WHERE name = 'scifi'
+    UNION ALL
+    SELECT c.id, c.parent_id, c.name FROM ancestors a JOIN categories c ON c.id = a.parent_id
+)
+SELECT name FROM ancestors;
+/* result:
+{"name": "scifi"}
+{"name": "novels"}
+{"name": "books"}
+{"name": "root"}
+*/
+
+-- test: recursive with limit
+WITH RECURSIVE cnt(n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM cnt)
+SELECT n FROM cnt LIMIT 4;
+/* result:
+{"n": 1}
+{"n": 2}
+{"n": 3}
+{"n": 4}
+*/
+
+-- test: recursive computed once
+WITH RECURSIVE cnt(n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM cnt WHERE n < 3)
+SELECT a.n AS a, b.n AS b FROM cnt AS a JOIN cnt AS b ON a.n = b.n;
+/* result:
+{"a": 1, "b": 1}
+{"a": 2, "b": 2}
+{"a": 3, "b": 3}
+*/
+
+-- test: recursive union discards duplicates
+WITH RECURSIVE cnt(n) AS (SELECT 1 UNION SELECT n % 3 + 1 FROM cnt)
+SELECT n FROM cnt;
+/* result:
+{"n": 1}
+{"n": 2}
+{"n": 3}
+*/
+
+-- test: recursive keyword without recursion
+WITH RECURSIVE c AS (SELECT id FROM categories WHERE id = 1)
+SELECT * FROM c;
+/* result:
+{"id": 1}
+*/
+
+-- test: duplicate name
+WITH c AS (SELECT 1 AS a), c AS (SELECT 2 AS a) SELECT * FROM c;
+-- error: WITH query name "c" specified more than once
+
+-- test: wrong number of columns
+WITH c(a, b) AS (SELECT id FROM categories) SELECT * FROM c;
+-- error: table "c" has 1 columns available but 2 columns specified
+
+-- test: recursive without union
+WITH RECURSIVE c AS (SELECT id FROM c) SELECT * FROM c;
+-- error: recursive query "c" does not have the form non-recursive-term UNION [ALL] recursive-term
+
+-- test: recursive reference in the non-recursive term
+WITH RECURSIVE c AS (SELECT id FROM c UNION SELECT 1 AS id) SELECT * FROM c;
+-- error: recursive query "c" does not have the form non-recursive-term UNION [ALL] recursive-term
+
+-- test: multiple recursive references
+WITH RECURSIVE c(n) AS (SELECT 1 UNION ALL SELECT a.n + 1 FROM c a JOIN c b ON a.n = b.n) SELECT * FROM c;
+-- error: recursive reference to query "c" must not appear more than once
+
+-- test: recursive with order by
+WITH RECURSIVE c(n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM c WHERE n < 3 ORDER BY n) SELECT * FROM c;
+-- error: ORDER BY, LIMIT and OFFSET are not supported in a recursive query
+
+-- test: recursive terms with different columns
+WITH RECURSIVE c AS (SELECT 1 AS n UNION ALL SELECT n + 1, n FROM c WHERE n < 3) SELECT * FROM c;
+-- error: each term of the recursive query "c" must return the same number of columns
@@ -0,0 +1,86 @@
+-- setup:
+CREATE TABLE parent (id INT PRIMARY KEY, code TEXT UNIQUE);
+INSERT INTO parent (id, code) VALUES (1, 'a'), (2, 'b');
+
+-- test: referencing row
+CREATE TABLE child (id INT PRIMARY KEY, parent_id INT REFERENCES parent);
+INSERT INTO child (id, parent_id) VALUES (1, 1);
+UPDATE child SET parent_id = 2;
+SELECT * FROM child;
+/* result:
+{"id": 1, "parent_id": 2}
+*/
+
+-- test: referencing row, missing parent
+CREATE TABLE child (id INT PRIMARY KEY, parent_id INT REFERENCES parent);
+INSERT INTO child (id, parent_id) VALUES (1, 1);
+UPDATE child SET parent_id = 3;
+-- error: insert or update on table "child" violates foreign key constraint "child_parent_id_fkey"
+
+-- test: no action
+CREATE TABLE child (id INT PRIMARY KEY, parent_id INT REFERENCES parent);
+INSERT INTO child (id, parent_id) VALUES (1, 1);
+UPDATE parent SET id = 3 WHERE id = 1;
+-- error: update or delete on table "parent" violates foreign key constraint "child_parent_id_fkey" on table "child"
+
+-- test: no action, unchanged value
+CREATE TABLE child (id INT PRIMARY KEY, parent_code TEXT REFERENCES parent (code));
+INSERT INTO child (id, parent_code) VALUES (1, 'a');
+UPDATE parent SET code = 'a', id = 10 WHERE id = 1;
+SELECT * FROM parent;
+/* result:
+{"id": 2, "code": "b"}
+{"id": 10, "code": "a"}
+*/
+
+-- test: cascade
+CREATE TABLE child (id INT PRIMARY KEY, parent_id INT REFERENCES parent ON UPDATE CASCADE);
+CREATE INDEX ON child (parent_id);
+INSERT INTO child (id, parent_id) VALUES (1, 1), (2, 1), (3, 2);
+UPDATE parent SET id = 10 WHERE id = 1;
+SELECT * FROM child;
+/* result:
+{"id": 1, "parent_id": 10}
+{"id": 2, "parent_id": 10}
+{"id": 3, "parent_id": 2}
+*/
+
+-- test: cascade, unique field
+CREATE TABLE child (id INT PRIMARY KEY, parent_code TEXT REFERENCES parent (code) ON UPDATE CASCADE);
+INSERT INTO child (id, parent_code) VALUES (1, 'a'), (2, 'b');
+UPDATE parent SET code = 'z' WHERE id = 1;
+SELECT * FROM child WHERE parent_code = 'z';
+/* result:
+{"id": 1, "parent_code": "z"}
+*/
+
+-- test: set null
+CREATE TABLE child (id INT PRIMARY KEY, parent_code TEXT REFERENCES parent (code) ON UPDATE SET NULL);
+INSERT INTO child (id, parent_code) VALUES (1, 'a'), (2, 'b');
+UPDATE parent SET code = 'z' WHERE id = 1;
+SELECT * FROM child;
+/* result:
+{"id": 1}
+{"id": 2, "parent_code": "b"}
+*/
+
+-- test: on conflict do update
+CREATE TABLE child (id INT PRIMARY KEY, parent_code TEXT REFERENCES parent (code) ON UPDATE CASCADE);
+INSERT INTO child (id, parent_code) VALUES (1, 'a');
+INSERT INTO parent (id, code) VALUES (1, 'x') ON CONFLICT (id) DO UPDATE SET code = excluded.code;
+SELECT * FROM child;
+/* result:
+{"id": 1, "parent_code": "x"}
+*/
+
+-- test: cascade, check constraint of the referencing table
+CREATE TABLE child (id INT PRIMARY KEY, parent_id INT REFERENCES parent ON UPDATE CASCADE, CHECK (parent_id < 5));
+INSERT INTO child (id, parent_id) VALUES (1, 1);
+UPDATE parent SET id = 10 WHERE id = 1;
+-- error: row violates check constraint "child_check"
+
+-- test: set null, not null field
+CREATE TABLE child (id INT PRIMARY KEY, parent_code TEXT NOT NULL REFERENCES parent (code) ON UPDATE SET NULL);
+INSERT INTO child (id, parent_code) VALUES (1, 'a');
+UPDATE parent SET code = 'z' WHERE id = 1;
+-- error: NOT NULL constraint error: [parent_code]
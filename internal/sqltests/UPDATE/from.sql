@@ -0,0 +1,84 @@
+-- setup:
+CREATE TABLE customers (id int PRIMARY KEY, name text, vip bool);
+CREATE TABLE orders (id int PRIMARY KEY, customer_id int, status text, total int);
+CREATE INDEX on orders(customer_id);
+INSERT INTO customers (id, name, vip) VALUES (1, 'a', true), (2, 'b', false), (3, 'c', true);
+INSERT INTO orders (id, customer_id, status, total) VALUES (1, 1, 'new', 10), (2, 2, 'new', 20), (3, 3, 'new', 30), (4, 1, 'new', 40);
+
+-- test: update from
+UPDATE orders SET status = 'priority' FROM customers WHERE orders.customer_id = customers.id AND customers.vip;
+SELECT id, status FROM orders;
+/* result:
+{"id": 1, "status": "priority"}
+{"id": 2, "status": "new"}
+{"id": 3, "status": "priority"}
+{"id": 4, "status": "priority"}
+*/
+
+-- test: set from the joined table
+UPDATE orders SET status = c.name, total = total + c.id FROM customers AS c WHERE orders.customer_id = c.id;
+SELECT * FROM orders;
+/* result:
+{"id": 1, "customer_id": 1, "status": "a", "total": 11}
+{"id": 2, "customer_id": 2, "status": "b", "total": 22}
+{"id": 3, "customer_id": 3, "status": "c", "total": 33}
+{"id": 4, "customer_id": 1, "status": "a", "total": 41}
+*/
+
+-- test: unqualified column of the joined table
+UPDATE orders SET status = name FROM customers WHERE customer_id = customers.id AND orders.id > 2;
+SELECT id, status FROM orders;
+/* result:
+{"id": 1, "status": "new"}
+{"id": 2, "status": "new"}
+{"id": 3, "status": "c"}
+{"id": 4, "status": "a"}
+*/
+
+-- test: column of the updated table takes precedence
+UPDATE customers SET name = CAST(id AS TEXT) FROM orders WHERE customers.id = orders.customer_id AND orders.id = 2;
+SELECT * FROM customers;
+/* result:
+{"id": 1, "name": "a", "vip": true}
+{"id": 2, "name": "2", "vip": false}
+{"id": 3, "name": "c", "vip": true}
+*/
+
+-- test: multiple matching rows update once
+UPDATE customers SET vip = NOT vip FROM orders WHERE customers.id = orders.customer_id;
+SELECT id, vip FROM customers;
+/* result:
+{"id": 1, "vip": false}
+{"id": 2, "vip": true}
+{"id": 3, "vip": false}
+*/
+
+-- test: no matching rows
+UPDATE orders SET status = 'none' FROM customers WHERE orders.customer_id = customers.id AND customers.id > 10;
+SELECT id, status FROM orders WHERE status = 'none';
+/* result:
+*/
+
+-- test: update from with join
+CREATE TABLE regions (customer_id int, region text);
+INSERT INTO regions (customer_id, region) VALUES (1, 'eu'), (2, 'us');
+UPDATE orders SET status = r.region FROM customers AS c JOIN regions AS r ON c.id = r.customer_id WHERE orders.customer_id = c.id;
+SELECT id, status FROM orders;
+/* result:
+{"id": 1, "status": "eu"}
+{"id": 2, "status": "us"}
+{"id": 3, "status": "new"}
+{"id": 4, "status": "eu"}
+*/
+
+-- test: update indexed column from
+UPDATE orders SET customer_id = customers.id + 10 FROM customers WHERE orders.customer_id = customers.id AND customers.id = 1;
+SELECT id FROM orders WHERE customer_id = 11;
+/* result:
+{"id": 1}
+{"id": 4}
+*/
+
+-- test: duplicate table name
+UPDATE orders SET status = 'x' FROM orders WHERE orders.id = 1;
+-- error: table name "orders" specified more than once
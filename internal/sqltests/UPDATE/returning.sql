@@ -0,0 +1,46 @@
+-- setup:
+CREATE TABLE test (id int PRIMARY KEY, name text, score int);
+CREATE INDEX on test(score);
+INSERT INTO test (id, name, score) VALUES (1, 'a', 10), (2, 'b', 20), (3, 'c', 30);
+
+-- test: returning *
+UPDATE test SET score = score + 1 WHERE id > 1 RETURNING *;
+/* result:
+{"id": 2, "name": "b", "score": 21}
+{"id": 3, "name": "c", "score": 31}
+*/
+
+-- test: returning expressions
+UPDATE test SET name = 'z' WHERE id = 1 RETURNING id, name AS new_name, score * 2;
+/* result:
+{"id": 1, "new_name": "z", "score * 2": 20}
+*/
+
+-- test: returning without matching rows
+UPDATE test SET name = 'z' WHERE id > 10 RETURNING id;
+/* result:
+*/
+
+-- test: returning with modified primary key
+UPDATE test SET id = id + 10 WHERE id = 3 RETURNING id, name;
+/* result:
+{"id": 13, "name": "c"}
+*/
+
+-- test: returning with from
+CREATE TABLE bonus (id int, amount int);
+INSERT INTO bonus (id, amount) VALUES (1, 5), (3, 7);
+UPDATE test SET score = score + bonus.amount FROM bonus WHERE test.id = bonus.id RETURNING test.id, score, bonus.amount;
+/* result:
+{"test.id": 1, "score": 15, "bonus.amount": 5}
+{"test.id": 3, "score": 37, "bonus.amount": 7}
+*/
+
+-- test: changes are applied
+UPDATE test SET score = 0 RETURNING id;
+SELECT id, score FROM test;
+/* result:
+{"id": 1, "score": 0}
+{"id": 2, "score": 0}
+{"id": 3, "score": 0}
+*/
@@ -4,4 +4,11 @@ CREATE TABLE test(a int UNIQUE);
 -- test: conflict
 INSERT INTO test VALUES (1), (2);
 UPDATE test SET a = 2 WHERE a = 1;
--- error: UNIQUE constraint error: [a]
\ No newline at end of file
+-- error: UNIQUE constraint error: [a]
+-- test: conflict with a union of indexes
+CREATE TABLE t(id int PRIMARY KEY, a int, b int, c int UNIQUE);
+CREATE INDEX t_a_idx ON t(a);
+CREATE INDEX t_b_idx ON t(b);
+INSERT INTO t VALUES (1, 1, 1, 1), (2, 2, 2, 2), (3, 1, 2, 3);
+UPDATE t SET c = 1 WHERE a = 1 OR b = 2;
+-- error: UNIQUE constraint error: [c]
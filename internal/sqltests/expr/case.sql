@@ -0,0 +1,56 @@
+-- test: searched
+> CASE WHEN 1 > 2 THEN 'a' WHEN 2 > 1 THEN 'b' END
+'b'
+
+-- test: searched with else
+> CASE WHEN 1 > 2 THEN 'a' ELSE 'c' END
+'c'
+
+-- test: searched without match
+> CASE WHEN 1 > 2 THEN 'a' END
+NULL
+
+-- test: first match wins
+> CASE WHEN true THEN 1 WHEN true THEN 2 END
+1
+
+-- test: NULL condition
+> CASE WHEN NULL THEN 1 ELSE 2 END
+2
+
+-- test: simple
+> CASE 2 WHEN 1 THEN 'one' WHEN 2 THEN 'two' ELSE 'many' END
+'two'
+
+-- test: simple with else
+> CASE 3 WHEN 1 THEN 'one' WHEN 2 THEN 'two' ELSE 'many' END
+'many'
+
+-- test: simple with NULL operand
+> CASE NULL WHEN NULL THEN 1 ELSE 2 END
+2
+
+-- test: simple with expressions
+> CASE 1 + 1 WHEN 4 / 2 THEN 10 * 2 END
+20
+
+-- test: nested
+> CASE WHEN 1 < 2 THEN CASE 'a' WHEN 'a' THEN 'nested' END ELSE 'outer' END
+'nested'
+
+-- test: syntax
+! CASE END
+'found END, expected WHEN'
+
+! CASE WHEN 1 END
+'found END, expected THEN'
+
+! CASE WHEN 1 THEN 2
+'found EOF, expected END'
+
+! CASE 1 ELSE 2 END
+'found ELSE, expected WHEN'
+
+-- test: unexisting field
+! CASE WHEN a THEN 1 END
+'field not found'
@@ -0,0 +1,42 @@
+-- test: match
+> 'Fast embedded databases' MATCH 'database'
+true
+
+-- test: match with @@
+> 'Fast embedded databases' @@ 'embedded database'
+true
+
+-- test: match every term
+> 'Fast embedded databases' MATCH 'embedded search'
+false
+
+-- test: match stop words
+> 'The database' MATCH 'the'
+false
+
+-- test: match with null
+> NULL MATCH 'database'
+NULL
+
+-- test: not match with null
+> NOT (NULL MATCH 'database')
+NULL
+
+-- test: match with non text
+> 10 MATCH 'database'
+NULL
+
+-- test: rank
+> rank('sql databases', 'database')
+0.7071067811865475
+
+-- test: rank without match
+> rank('sql databases', 'search')
+0.0
+
+-- test: rank with null
+> rank(NULL, 'database')
+NULL
+
+-- test: rank with wrong arity
+! rank('sql databases')
@@ -0,0 +1,25 @@
+-- test: not
+> NOT true
+false
+
+> NOT false
+true
+
+> NOT 0
+true
+
+> NOT 'a'
+false
+
+-- test: not null
+> NOT NULL
+NULL
+
+> NOT (NULL = 1)
+NULL
+
+> NOT NOT NULL
+NULL
+
+> NOT (NULL MATCH 'a')
+NULL
@@ -0,0 +1,43 @@
+-- setup:
+CREATE TABLE sales(id int PRIMARY KEY, country text, city text, amount int);
+CREATE INDEX sales_country_city_idx ON sales(country, city);
+
+-- test: composite index
+EXPLAIN SELECT country, city, COUNT(*) FROM sales GROUP BY country, city;
+/* result:
+{
+    "plan": 'index.Scan("sales_country_city_idx") | rows.GroupAggregate([country, city], COUNT(*)) | rows.Project(country, city, COUNT(*))'
+}
+*/
+
+-- test: prefix of a composite index
+EXPLAIN SELECT country, COUNT(*) FROM sales GROUP BY country;
+/* result:
+{
+    "plan": 'index.Scan("sales_country_city_idx") | rows.GroupAggregate(country, COUNT(*)) | rows.Project(country, COUNT(*))'
+}
+*/
+
+-- test: order not matching the index
+EXPLAIN SELECT country, city, COUNT(*) FROM sales GROUP BY city, country;
+/* result:
+{
+    "plan": 'table.Scan("sales") | rows.TempTreeSort(city, country) | rows.GroupAggregate([city, country], COUNT(*)) | rows.Project(country, city, COUNT(*))'
+}
+*/
+
+-- test: expression
+EXPLAIN SELECT country, COUNT(*) FROM sales GROUP BY country, amount % 2;
+/* result:
+{
+    "plan": 'table.Scan("sales") | rows.TempTreeSort(country, amount % 2) | rows.GroupAggregate([country, amount % 2], COUNT(*)) | rows.Project(country, COUNT(*))'
+}
+*/
+
+-- test: with order by
+EXPLAIN SELECT country, city FROM sales GROUP BY country, city ORDER BY country DESC, city DESC;
+/* result:
+{
+    "plan": 'index.ScanReverse("sales_country_city_idx") | rows.GroupAggregate([country, city]) | rows.Project(country, city)'
+}
+*/
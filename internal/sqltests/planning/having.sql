@@ -0,0 +1,19 @@
+-- setup:
+CREATE TABLE sales(id int PRIMARY KEY, country text, amount int);
+CREATE INDEX sales_amount_idx ON sales(amount);
+
+-- test: filter after aggregation
+EXPLAIN SELECT country, COUNT(*) FROM sales WHERE amount > 10 GROUP BY country HAVING COUNT(*) > 1;
+/* result:
+{
+    "plan": 'index.Scan("sales_amount_idx", [{"min": [10], "exclusive": true}]) | rows.TempTreeSort(country) | rows.GroupAggregate(country, COUNT(*)) | rows.Filter(COUNT(*) > 1) | rows.Project(country, COUNT(*))'
+}
+*/
+
+-- test: no index selection for the HAVING clause
+EXPLAIN SELECT amount FROM sales GROUP BY amount HAVING amount > 10;
+/* result:
+{
+    "plan": 'index.Scan("sales_amount_idx") | rows.GroupAggregate(amount) | rows.Filter(amount > 10) | rows.Project(amount)'
+}
+*/
@@ -0,0 +1,102 @@
+-- setup:
+CREATE TABLE customers(id int PRIMARY KEY, name text);
+CREATE TABLE orders(oid int PRIMARY KEY, customer_id int, amount int);
+CREATE INDEX orders_customer_id_idx ON orders(customer_id);
+INSERT INTO customers (id, name) VALUES (1, 'alice'), (2, 'bob'), (3, 'carol');
+INSERT INTO orders (oid, customer_id, amount) VALUES (10, 1, 100), (11, 1, 50), (12, 2, 70);
+
+-- test: no index
+EXPLAIN SELECT * FROM customers c JOIN orders o ON c.name = o.amount;
+/* result:
+{
+    "plan": 'table.Scan("customers") | join.Alias("c") | join.NestedLoop(table.Scan("orders") AS o, c.name = o.amount)'
+}
+*/
+
+-- test: index lookup
+EXPLAIN SELECT * FROM customers c JOIN orders o ON c.id = o.customer_id;
+/* result:
+{
+    "plan": 'table.Scan("customers") | join.Alias("c") | join.IndexLookup(index.Scan("orders_customer_id_idx", [{"min": [c.id], "exact": true}]) AS o, c.id = o.customer_id)'
+}
+*/
+
+-- test: index lookup, operands reversed
+EXPLAIN SELECT * FROM customers JOIN orders ON orders.customer_id = customers.id;
+/* result:
+{
+    "plan": 'table.Scan("customers") | join.IndexLookup(index.Scan("orders_customer_id_idx", [{"min": [customers.id], "exact": true}]), orders.customer_id = customers.id)'
+}
+*/
+
+-- test: primary key lookup
+EXPLAIN SELECT * FROM orders o LEFT JOIN customers c ON c.id = o.customer_id AND c.name != 'bob';
+/* result:
+{
+    "plan": 'table.Scan("orders") | join.Alias("o") | join.LeftIndexLookup(table.Scan("customers", [{"min": [o.customer_id], "exact": true}]) AS c, c.id = o.customer_id AND c.name != "bob")'
+}
+*/
+
+-- test: primary key lookup results
+SELECT o.oid, c.name FROM orders o LEFT JOIN customers c ON c.id = o.customer_id AND c.name != 'bob';
+/* result:
+{"o.oid": 10, "c.name": "alice"}
+{"o.oid": 11, "c.name": "alice"}
+{"o.oid": 12, "c.name": null}
+*/
+
+-- test: index lookup results
+SELECT c.name, o.oid FROM customers c LEFT JOIN orders o ON c.id = o.customer_id;
+/* result:
+{"c.name": "alice", "o.oid": 10}
+{"c.name": "alice", "o.oid": 11}
+{"c.name": "bob", "o.oid": 12}
+{"c.name": "carol", "o.oid": null}
+*/
+
+-- test: condition referencing the joined table
+EXPLAIN SELECT * FROM customers c JOIN orders o ON o.customer_id = o.amount;
+/* result:
+{
+    "plan": 'table.Scan("customers") | join.Alias("c") | join.NestedLoop(table.Scan("orders") AS o, o.customer_id = o.amount)'
+}
+*/
+
+-- test: unqualified column of the first table
+EXPLAIN SELECT * FROM customers c JOIN orders o ON c.id = o.customer_id WHERE id = 1;
+/* result:
+{
+    "plan": 'table.Scan("customers") | join.Alias("c") | join.IndexLookup(index.Scan("orders_customer_id_idx", [{"min": [c.id], "exact": true}]) AS o, c.id = o.customer_id) | rows.Filter(id = 1)'
+}
+*/
+
+-- test: primary key of the first table
+EXPLAIN SELECT * FROM customers c JOIN orders o ON c.id = o.customer_id WHERE c.id = 1;
+/* result:
+{
+    "plan": 'table.Scan("customers", [{"min": [1], "exact": true}]) | join.Alias("c") | join.IndexLookup(index.Scan("orders_customer_id_idx", [{"min": [c.id], "exact": true}]) AS o, c.id = o.customer_id)'
+}
+*/
+
+-- test: index of the first table
+EXPLAIN SELECT * FROM orders JOIN customers ON customers.id = orders.customer_id WHERE orders.customer_id > 1 AND customers.name = 'bob';
+/* result:
+{
+    "plan": 'index.Scan("orders_customer_id_idx", [{"min": [1], "exclusive": true}]) | join.IndexLookup(table.Scan("customers", [{"min": [orders.customer_id], "exact": true}]), customers.id = orders.customer_id) | rows.Filter(customers.name = "bob")'
+}
+*/
+
+-- test: filter on the joined table
+EXPLAIN SELECT * FROM customers c LEFT JOIN orders o ON c.id = o.customer_id WHERE o.oid = 10;
+/* result:
+{
+    "plan": 'table.Scan("customers") | join.Alias("c") | join.LeftIndexLookup(index.Scan("orders_customer_id_idx", [{"min": [c.id], "exact": true}]) AS o, c.id = o.customer_id) | rows.Filter(o.oid = 10)'
+}
+*/
+
+-- test: index of the first table results
+SELECT o.oid, c.name FROM orders o LEFT JOIN customers c ON c.id = o.customer_id WHERE o.customer_id >= 1 AND o.amount > 60;
+/* result:
+{"o.oid": 10, "c.name": "alice"}
+{"o.oid": 12, "c.name": "bob"}
+*/
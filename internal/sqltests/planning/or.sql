@@ -0,0 +1,138 @@
+-- setup:
+CREATE TABLE test(id int PRIMARY KEY, a int, b int, c int, d text);
+
+CREATE INDEX test_a ON test(a);
+
+CREATE INDEX test_b ON test(b);
+
+CREATE TABLE norowid(a int, b int);
+
+CREATE INDEX norowid_a ON norowid(a);
+
+CREATE INDEX norowid_b ON norowid(b);
+
+INSERT INTO
+    test (id, a, b, c, d)
+VALUES
+    (1, 1, 1, 1, 'foo'),
+    (2, 2, 2, 2, 'bar'),
+    (3, 3, 3, 3, 'baz'),
+    (4, 4, 4, 4, 'foo'),
+    (5, 5, 1, 5, 'bar');
+
+INSERT INTO norowid (a, b) VALUES (1, 1), (1, 1), (2, 3);
+
+-- test: explain
+EXPLAIN SELECT * FROM test WHERE a = 1 OR b > 3;
+/* result:
+{
+    "plan": 'unionByKey(index.Scan("test_a", [{"min": [1], "exact": true}]), index.Scan("test_b", [{"min": [3], "exclusive": true}]))'
+}
+*/
+
+-- test: results
+SELECT id FROM test WHERE a = 1 OR b > 3;
+/* result:
+{"id": 1}
+{"id": 4}
+*/
+
+-- test: duplicates
+SELECT id FROM test WHERE a = 5 OR b = 1;
+/* result:
+{"id": 1}
+{"id": 5}
+*/
+
+-- test: rows without primary key
+SELECT * FROM norowid WHERE a = 1 OR b = 1;
+/* result:
+{"a": 1, "b": 1}
+{"a": 1, "b": 1}
+*/
+
+-- test: explain primary key
+EXPLAIN SELECT * FROM test WHERE id = 1 OR a = 2 OR a > 2 AND c < 4 OR b < 2;
+/* result:
+{
+    "plan": 'unionByKey(table.Scan("test", [{"min": [1], "exact": true}]), index.Scan("test_a", [{"min": [2], "exact": true}]), index.Scan("test_a", [{"min": [2], "exclusive": true}]) | rows.Filter(c < 4), index.Scan("test_b", [{"max": [2], "exclusive": true}]))'
+}
+*/
+
+-- test: results primary key
+SELECT id FROM test WHERE id = 1 OR a = 2 OR a > 2 AND c < 4 OR b < 2;
+/* result:
+{"id": 1}
+{"id": 2}
+{"id": 3}
+{"id": 5}
+*/
+
+-- test: explain AND
+EXPLAIN SELECT * FROM test WHERE (a = 1 AND c > 0) OR (b = 2 AND d = 'bar');
+/* result:
+{
+    "plan": 'unionByKey(index.Scan("test_a", [{"min": [1], "exact": true}]) | rows.Filter(c > 0), index.Scan("test_b", [{"min": [2], "exact": true}]) | rows.Filter(d = "bar"))'
+}
+*/
+
+-- test: results AND
+SELECT id FROM test WHERE (a = 1 AND c > 0) OR (b = 2 AND d = 'foo');
+/* result:
+{"id": 1}
+*/
+
+-- test: explain other filters
+EXPLAIN SELECT id FROM test WHERE (a = 1 OR b = 2) AND c > 1 ORDER BY d;
+/* result:
+{
+    "plan": 'unionByKey(index.Scan("test_a", [{"min": [1], "exact": true}]), index.Scan("test_b", [{"min": [2], "exact": true}])) | rows.Filter(c > 1) | rows.Project(id) | rows.TempTreeSort(d)'
+}
+*/
+
+-- test: results other filters
+SELECT id FROM test WHERE (a = 1 OR b = 2) AND c > 1 ORDER BY d;
+/* result:
+{"id": 2}
+*/
+
+-- test: explain nested
+EXPLAIN SELECT * FROM test WHERE a = 1 OR (b = 2 AND (a = 3 OR id = 4));
+/* result:
+{
+    "plan": 'unionByKey(index.Scan("test_a", [{"min": [1], "exact": true}]), index.Scan("test_b", [{"min": [2], "exact": true}]) | rows.Filter((a = 3 OR id = 4)))'
+}
+*/
+
+-- test: explain not indexed
+EXPLAIN SELECT * FROM test WHERE a = 1 OR c = 2;
+/* result:
+{
+    "plan": 'table.Scan("test") | rows.Filter(a = 1 OR c = 2)'
+}
+*/
+
+-- test: explain other index
+EXPLAIN SELECT * FROM test WHERE (a = 1 OR b = 2) AND a = 3;
+/* result:
+{
+    "plan": 'index.Scan("test_a", [{"min": [3], "exact": true}]) | rows.Filter((a = 1 OR b = 2))'
+}
+*/
+
+-- test: delete
+DELETE FROM test WHERE a = 1 OR b = 1;
+SELECT id FROM test;
+/* result:
+{"id": 2}
+{"id": 3}
+{"id": 4}
+*/
+
+-- test: update
+UPDATE test SET c = 10 WHERE a = 1 OR b = 2;
+SELECT id, c FROM test WHERE c = 10;
+/* result:
+{"id": 1, "c": 10}
+{"id": 2, "c": 10}
+*/
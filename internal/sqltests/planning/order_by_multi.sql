@@ -0,0 +1,101 @@
+-- setup:
+CREATE TABLE test(a int, b int, c int, d int, PRIMARY KEY (c, d DESC));
+
+CREATE INDEX test_a_b ON test(a, b);
+
+INSERT INTO
+    test (a, b, c, d)
+VALUES
+    (1, 1, 1, 1),
+    (2, 2, 2, 2),
+    (3, 3, 3, 3),
+    (4, 4, 4, 4),
+    (5, 5, 5, 5);
+
+-- test: indexed paths, ASC
+EXPLAIN SELECT * FROM test ORDER BY a, b;
+/* result:
+{
+    "plan": 'index.Scan("test_a_b")'
+}
+*/
+
+-- test: indexed paths, DESC
+EXPLAIN SELECT * FROM test ORDER BY a DESC, b DESC;
+/* result:
+{
+    "plan": 'index.ScanReverse("test_a_b")'
+}
+*/
+
+-- test: indexed paths, mixed directions
+EXPLAIN SELECT * FROM test ORDER BY a, b DESC;
+/* result:
+{
+    "plan": 'table.Scan("test") | rows.TempTreeSort(a, b DESC)'
+}
+*/
+
+-- test: indexed paths, wrong order
+EXPLAIN SELECT * FROM test ORDER BY b, a;
+/* result:
+{
+    "plan": 'table.Scan("test") | rows.TempTreeSort(b, a)'
+}
+*/
+
+-- test: more paths than indexed
+EXPLAIN SELECT * FROM test ORDER BY a, b, c;
+/* result:
+{
+    "plan": 'table.Scan("test") | rows.TempTreeSort(a, b, c)'
+}
+*/
+
+-- test: expression
+EXPLAIN SELECT * FROM test ORDER BY a, b + 1;
+/* result:
+{
+    "plan": 'table.Scan("test") | rows.TempTreeSort(a, b + 1)'
+}
+*/
+
+-- test: primary key with mixed directions
+EXPLAIN SELECT * FROM test ORDER BY c, d DESC;
+/* result:
+{
+    "plan": 'table.Scan("test")'
+}
+*/
+
+-- test: primary key with mixed directions, reversed
+EXPLAIN SELECT * FROM test ORDER BY c DESC, d;
+/* result:
+{
+    "plan": 'table.ScanReverse("test")'
+}
+*/
+
+-- test: primary key with wrong directions
+EXPLAIN SELECT * FROM test ORDER BY c, d;
+/* result:
+{
+    "plan": 'table.Scan("test") | rows.TempTreeSort(c, d)'
+}
+*/
+
+-- test: filter on the first path
+EXPLAIN SELECT * FROM test WHERE a = 1 ORDER BY a DESC, b DESC;
+/* result:
+{
+    "plan": 'index.ScanReverse("test_a_b", [{"min": [1], "exact": true}])'
+}
+*/
+
+-- test: filter on the first path, sort on the second
+EXPLAIN SELECT * FROM test WHERE a = 1 ORDER BY b DESC;
+/* result:
+{
+    "plan": 'index.ScanReverse("test_a_b", [{"min": [1], "exact": true}])'
+}
+*/
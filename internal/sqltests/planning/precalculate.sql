@@ -24,3 +24,19 @@ EXPLAIN SELECT * FROM test WHERE 4 * 3 BETWEEN 3 + 6 AND 5 * 10;
     plan: "table.Scan(\"test\")"
 }
 */
+
+-- test: precalculate CASE
+EXPLAIN SELECT * FROM test WHERE a = CASE WHEN 1 > 2 THEN 10 ELSE 5 * 4 END;
+/* result:
+{
+    plan: "table.Scan(\"test\") | rows.Filter(a = 20)"
+}
+*/
+
+-- test: precalculate CASE branches
+EXPLAIN SELECT * FROM test WHERE a = CASE b WHEN 1 + 1 THEN 10 ELSE 5 * 4 END;
+/* result:
+{
+    plan: "table.Scan(\"test\") | rows.Filter(a = CASE b WHEN 2 THEN 10 ELSE 20 END)"
+}
+*/
@@ -0,0 +1,29 @@
+-- setup:
+CREATE TABLE customers(id int PRIMARY KEY, name text);
+CREATE TABLE orders(oid int PRIMARY KEY, customer_id int, amount int);
+CREATE INDEX orders_customer_id_idx ON orders(customer_id);
+INSERT INTO customers (id, name) VALUES (1, 'alice'), (2, 'bob'), (3, 'carol');
+INSERT INTO orders (oid, customer_id, amount) VALUES (10, 1, 100), (11, 1, 50), (12, 2, 70);
+
+-- test: uncorrelated scalar subquery
+EXPLAIN SELECT name FROM customers WHERE id = (SELECT customer_id FROM orders WHERE oid = 12);
+/* result:
+{
+    "plan": 'table.Scan("customers", [{"min": [(table.Scan("orders", [{"min": [12], "exact": true}]) | rows.Project(customer_id))], "exact": true}]) | rows.Project(name)'
+}
+*/
+
+-- test: correlated scalar subquery
+EXPLAIN SELECT name FROM customers c WHERE id = (SELECT MAX(customer_id) FROM orders o WHERE o.amount > c.id);
+/* result:
+{
+    "plan": 'table.Scan("customers") | join.Alias("c") | rows.Filter(id = (table.Scan("orders") | join.Alias("o") | rows.Filter(o.amount > c.id) | rows.GroupAggregate(NULL, MAX(customer_id)) | rows.Project(MAX(customer_id)))) | rows.Project(name)'
+}
+*/
+
+-- test: correlated subquery results
+SELECT name FROM customers WHERE EXISTS (SELECT 1 FROM orders WHERE customer_id = customers.id);
+/* result:
+{"name": "alice"}
+{"name": "bob"}
+*/
@@ -0,0 +1,29 @@
+-- setup:
+CREATE TABLE customers (id int PRIMARY KEY, name text, vip bool);
+CREATE TABLE orders (id int PRIMARY KEY, customer_id int, status text);
+CREATE INDEX on orders(customer_id);
+
+-- test: update from uses the primary key of the joined table
+EXPLAIN UPDATE orders SET status = 'x' FROM customers WHERE orders.customer_id = customers.id AND customers.vip;
+/* result:
+{
+    plan: "table.Scan(\"orders\") | join.IndexLookup(table.Scan(\"customers\", [{\"min\": [orders.customer_id], \"exact\": true}]), orders.customer_id = customers.id AND customers.vip) | join.Target(\"orders\") | paths.Set(status, \"x\") | table.Validate(\"orders\") | index.Delete(\"orders_customer_id_idx\") | table.Replace(\"orders\") | index.Insert(\"orders_customer_id_idx\") | discard()"
+}
+*/
+
+-- test: delete using uses the index of the joined table
+EXPLAIN DELETE FROM customers USING orders AS o WHERE o.customer_id = customers.id;
+/* result:
+{
+    plan: "table.Scan(\"customers\") | join.IndexLookup(index.Scan(\"orders_customer_id_idx\", [{\"min\": [customers.id], \"exact\": true}]) AS o, o.customer_id = customers.id) | join.Target(\"customers\") | table.Delete('customers') | discard()"
+}
+*/
+
+-- test: delete using with join
+EXPLAIN DELETE FROM orders USING customers AS c JOIN orders AS o ON o.customer_id = c.id WHERE orders.customer_id = c.id;
+/* result:
+{
+    plan: "table.Scan(\"orders\") | join.NestedLoop(table.Scan(\"customers\") AS c) | join.IndexLookup(index.Scan(\"orders_customer_id_idx\", [{\"min\": [c.id], \"exact\": true}]) AS o, o.customer_id = c.id) | rows.Filter(orders.customer_id = c.id) | join.Target(\"orders\") | index.Delete(\"orders_customer_id_idx\") | table.Delete('orders') | discard()"
+}
+*/
+
@@ -0,0 +1,78 @@
+-- setup:
+CREATE TABLE test(a INT PRIMARY KEY, b INT, c INT);
+CREATE INDEX test_b ON test(b);
+CREATE TABLE other(id INT PRIMARY KEY, name TEXT);
+INSERT INTO test (a, b, c) VALUES (1, 1, 1), (2, 2, 2), (3, 3, 3);
+CREATE VIEW v AS SELECT a, c FROM test WHERE b = 2;
+CREATE VIEW w AS SELECT a, b AS x, c + 1 AS y FROM test;
+CREATE VIEW g AS SELECT b, COUNT(*) AS n FROM test GROUP BY b;
+
+-- test: inlined view
+EXPLAIN SELECT * FROM v;
+/* result:
+{
+    "plan": 'index.Scan("test_b", [{"min": [2], "exact": true}]) | rows.Project(a, c) | join.Alias("v")'
+}
+*/
+
+-- test: filter
+EXPLAIN SELECT c FROM v WHERE a > 1;
+/* result:
+{
+    "plan": 'index.Scan("test_b", [{"min": [2], "exact": true}]) | rows.Filter(a > 1) | rows.Project(a, c) | join.Alias("v") | rows.Project(c)'
+}
+*/
+
+-- test: order by
+EXPLAIN SELECT * FROM v ORDER BY c;
+/* result:
+{
+    "plan": 'index.Scan("test_b", [{"min": [2], "exact": true}]) | rows.Project(a, c) | join.Alias("v") | rows.TempTreeSort(c)'
+}
+*/
+
+-- test: join
+EXPLAIN SELECT * FROM other JOIN v ON v.a = other.id;
+/* result:
+{
+    "plan": 'table.Scan("other") | join.NestedLoop(index.Scan("test_b", [{"min": [2], "exact": true}]) | rows.Project(a, c) AS v, v.a = other.id)'
+}
+*/
+
+-- test: filter on an indexed column
+EXPLAIN SELECT * FROM w WHERE x = 1;
+/* result:
+{
+    "plan": 'index.Scan("test_b", [{"min": [1], "exact": true}]) | rows.Project(a, b, c + 1) | join.Alias("w")'
+}
+*/
+
+-- test: qualified filter on the primary key
+EXPLAIN SELECT * FROM w WHERE w.a > 1 AND w.a < 3;
+/* result:
+{
+    "plan": 'table.Scan("test", [{"min": [1], "exclusive": true}]) | rows.Filter(a < 3) | rows.Project(a, b, c + 1) | join.Alias("w")'
+}
+*/
+
+-- test: filter on a computed column
+EXPLAIN SELECT * FROM w WHERE y = 2 AND x = 1;
+/* result:
+{
+    "plan": 'index.Scan("test_b", [{"min": [1], "exact": true}]) | rows.Project(a, b, c + 1) | join.Alias("w") | rows.Filter(y = 2)'
+}
+*/
+
+-- test: filter on a grouped view
+EXPLAIN SELECT * FROM g WHERE b = 1;
+/* result:
+{
+    "plan": 'index.Scan("test_b") | rows.GroupAggregate(b, COUNT(*)) | rows.Project(b, COUNT(*)) | join.Alias("g") | rows.Filter(b = 1)'
+}
+*/
+
+-- test: results
+SELECT * FROM w WHERE x >= 2 AND y < 4 ORDER BY a;
+/* result:
+{"a": 2, "x": 2, "y": 3}
+*/
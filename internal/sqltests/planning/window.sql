@@ -0,0 +1,51 @@
+-- setup:
+CREATE TABLE emp(id int PRIMARY KEY, dept text, salary int);
+CREATE INDEX emp_dept_salary_idx ON emp(dept, salary);
+
+-- test: partition and order
+EXPLAIN SELECT id, row_number() OVER (PARTITION BY salary ORDER BY id) FROM emp;
+/* result:
+{
+    "plan": 'table.Scan("emp") | rows.TempTreeSort(salary, id) | rows.Window(ROW_NUMBER() OVER (PARTITION BY salary ORDER BY id)) | rows.Project(id, ROW_NUMBER() OVER (PARTITION BY salary ORDER BY id))'
+}
+*/
+
+-- test: index
+EXPLAIN SELECT id, rank() OVER (PARTITION BY dept ORDER BY salary) FROM emp;
+/* result:
+{
+    "plan": 'index.Scan("emp_dept_salary_idx") | rows.Window(RANK() OVER (PARTITION BY dept ORDER BY salary)) | rows.Project(id, RANK() OVER (PARTITION BY dept ORDER BY salary))'
+}
+*/
+
+-- test: primary key
+EXPLAIN SELECT id, lag(id) OVER (ORDER BY id DESC) FROM emp;
+/* result:
+{
+    "plan": 'table.ScanReverse("emp") | rows.Window(LAG(id) OVER (ORDER BY id DESC)) | rows.Project(id, LAG(id) OVER (ORDER BY id DESC))'
+}
+*/
+
+-- test: empty window
+EXPLAIN SELECT id, COUNT(*) OVER () FROM emp;
+/* result:
+{
+    "plan": 'table.Scan("emp") | rows.Window(COUNT(*) OVER ()) | rows.Project(id, COUNT(*) OVER ())'
+}
+*/
+
+-- test: multiple windows
+EXPLAIN SELECT id, row_number() OVER (ORDER BY salary), SUM(salary) OVER (ORDER BY salary), row_number() OVER (ORDER BY id) FROM emp;
+/* result:
+{
+    "plan": 'table.Scan("emp") | rows.TempTreeSort(salary) | rows.Window(ROW_NUMBER() OVER (ORDER BY salary), SUM(salary) OVER (ORDER BY salary)) | rows.TempTreeSort(id) | rows.Window(ROW_NUMBER() OVER (ORDER BY id)) | rows.Project(id, ROW_NUMBER() OVER (ORDER BY salary), SUM(salary) OVER (ORDER BY salary), ROW_NUMBER() OVER (ORDER BY id))'
+}
+*/
+
+-- test: with order by
+EXPLAIN SELECT id, row_number() OVER (ORDER BY salary) FROM emp ORDER BY id;
+/* result:
+{
+    "plan": 'table.Scan("emp") | rows.TempTreeSort(salary) | rows.Window(ROW_NUMBER() OVER (ORDER BY salary)) | rows.Project(id, ROW_NUMBER() OVER (ORDER BY salary)) | rows.TempTreeSort(id)'
+}
+*/
@@ -0,0 +1,32 @@
+-- setup:
+CREATE TABLE categories(id int PRIMARY KEY, parent_id int, name text);
+CREATE INDEX categories_parent_id_idx ON categories(parent_id);
+
+-- test: simple
+EXPLAIN WITH c AS (SELECT id FROM categories WHERE parent_id = 1) SELECT * FROM c WHERE id > 2;
+/* result:
+{
+    "plan": 'rows.CommonTableScan("c", index.Scan("categories_parent_id_idx", [{"min": [1], "exact": true}]) | rows.Project(id)) | join.Alias("c") | rows.Filter(id > 2)'
+}
+*/
+
+-- test: join
+EXPLAIN WITH c AS (SELECT id FROM categories) SELECT c.id, t.name FROM c JOIN categories t ON t.id = c.id;
+/* result:
+{
+    "plan": 'rows.CommonTableScan("c", table.Scan("categories") | rows.Project(id)) | join.Alias("c") | join.IndexLookup(table.Scan("categories", [{"min": [c.id], "exact": true}]) AS t, t.id = c.id) | rows.Project(c.id, t.name)'
+}
+*/
+
+-- test: recursive
+EXPLAIN WITH RECURSIVE sub AS (
+    SELECT id FROM categories WHERE id = 1
+    UNION ALL
+    SELECT c.id FROM categories c JOIN sub ON c.parent_id = sub.id
+)
+SELECT * FROM sub;
+/* result:
+{
+    "plan": 'rows.CommonTableScan("sub", table.Scan("categories", [{"min": [1], "exact": true}]) | rows.Project(id) UNION ALL table.Scan("categories") | join.Alias("c") | join.NestedLoop(rows.WorkingTableScan("sub") AS sub, c.parent_id = sub.id) | rows.Project(c.id)) | join.Alias("sub")'
+}
+*/
@@ -0,0 +1,85 @@
+package stream
+
+import (
+	"strings"
+
+	"github.com/chaisql/chai/internal/environment"
+)
+
+// ExceptOperator is an operator that returns the rows of its first stream
+// that are not returned by any of the other streams.
+type ExceptOperator struct {
+	BaseOperator
+	Streams []*Stream
+	// All keeps duplicate rows: every time a row is returned by
+	// one of the other streams, one of its occurrences is discarded.
+	All bool
+}
+
+// Except returns a new ExceptOperator that discards duplicate rows.
+func Except(s ...*Stream) *ExceptOperator {
+	return &ExceptOperator{Streams: s}
+}
+
+// ExceptAll returns a new ExceptOperator that keeps duplicate rows.
+func ExceptAll(s ...*Stream) *ExceptOperator {
+	return &ExceptOperator{Streams: s, All: true}
+}
+
+// Iterate iterates over all the streams and returns the rows of the first stream
+// minus the rows of the other streams.
+func (it *ExceptOperator) Iterate(in *environment.Environment, fn func(out *environment.Environment) error) (err error) {
+	if len(it.Streams) == 0 {
+		return nil
+	}
+
+	rc, err := newRowCounter(in)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		e := rc.cleanup()
+		if err == nil {
+			err = e
+		}
+	}()
+
+	// count the rows of the first stream
+	err = it.Streams[0].Iterate(in, func(out *environment.Environment) error {
+		return rc.add(out, it.All)
+	})
+	if err != nil {
+		return err
+	}
+
+	// remove the rows returned by the other streams
+	for _, s := range it.Streams[1:] {
+		err = s.Iterate(in, func(out *environment.Environment) error {
+			return rc.remove(out, it.All)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return rc.iterate(in, fn)
+}
+
+func (it *ExceptOperator) String() string {
+	var s strings.Builder
+
+	if it.All {
+		s.WriteString("exceptAll(")
+	} else {
+		s.WriteString("except(")
+	}
+	for i, st := range it.Streams {
+		if i > 0 {
+			s.WriteString(", ")
+		}
+		s.WriteString(st.String())
+	}
+	s.WriteRune(')')
+
+	return s.String()
+}
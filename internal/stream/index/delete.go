@@ -5,11 +5,11 @@ import (
 
 	"github.com/chaisql/chai/internal/environment"
 	"github.com/chaisql/chai/internal/stream"
-	"github.com/chaisql/chai/internal/types"
 	"github.com/cockroachdb/errors"
 )
 
 // DeleteOperator reads the input stream and deletes the object from the specified index.
+// If the index is partial, the objects not matching its predicate are ignored.
 type DeleteOperator struct {
 	stream.BaseOperator
 
@@ -51,25 +51,31 @@ func (op *DeleteOperator) Iterate(in *environment.Environment, fn func(out *envi
 			return err
 		}
 
-		vs := make([]types.Value, 0, len(info.Paths))
-		for _, path := range info.Paths {
-			v, err := path.GetValueFromObject(old.Object())
-			if err != nil {
-				v = types.NewNullValue()
-			}
-			vs = append(vs, v)
+		ok, err = info.Includes(tx, old.Object())
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fn(out)
 		}
 
-		key, err := table.Info.EncodeKey(old.Key())
+		entries, err := info.Entries(tx, old.Object())
 		if err != nil {
 			return err
 		}
 
-		err = idx.Delete(vs, key)
+		key, err := table.Info.EncodeKey(old.Key())
 		if err != nil {
 			return err
 		}
 
+		for _, vs := range entries {
+			err = idx.Delete(vs, key)
+			if err != nil {
+				return err
+			}
+		}
+
 		return fn(out)
 	})
 }
@@ -0,0 +1,113 @@
+package index
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/chaisql/chai/internal/database"
+	"github.com/chaisql/chai/internal/encoding"
+	"github.com/chaisql/chai/internal/environment"
+	"github.com/chaisql/chai/internal/expr"
+	"github.com/chaisql/chai/internal/fulltext"
+	"github.com/chaisql/chai/internal/stream"
+	"github.com/chaisql/chai/internal/tree"
+	"github.com/chaisql/chai/internal/types"
+)
+
+// A FullTextScanOperator iterates over the rows containing all the terms
+// of a query, using a full-text index.
+type FullTextScanOperator struct {
+	stream.BaseOperator
+
+	// IndexName references the full-text index used to perform the scan.
+	IndexName string
+	// Query is the text searched in the indexed column.
+	Query expr.Expr
+}
+
+// FullTextScan creates an iterator that iterates over the rows
+// matching the query, in primary key order.
+func FullTextScan(name string, query expr.Expr) *FullTextScanOperator {
+	return &FullTextScanOperator{IndexName: name, Query: query}
+}
+
+// Iterate reads the keys associated with each term of the query
+// and returns the rows associated with all of them.
+func (it *FullTextScanOperator) Iterate(in *environment.Environment, fn func(out *environment.Environment) error) error {
+	tx := in.GetTx()
+
+	index, err := tx.Catalog.GetIndex(tx, it.IndexName)
+	if err != nil {
+		return err
+	}
+
+	info, err := tx.Catalog.GetIndexInfo(it.IndexName)
+	if err != nil {
+		return err
+	}
+
+	table, err := tx.Catalog.GetTable(tx, info.Owner.TableName)
+	if err != nil {
+		return err
+	}
+
+	q, err := it.Query.Eval(in)
+	if err != nil {
+		return err
+	}
+	if q.Type() != types.TypeText {
+		return nil
+	}
+
+	// intersect the keys of every term
+	var keys map[string]struct{}
+	for _, term := range fulltext.Terms(types.AsString(q)) {
+		found := make(map[string]struct{})
+		k := tree.NewKey(types.NewTextValue(term))
+		err = index.IterateOnRange(&tree.Range{Min: k, Max: k}, false, func(key *tree.Key) error {
+			if _, ok := keys[string(key.Encoded)]; keys == nil || ok {
+				found[string(key.Encoded)] = struct{}{}
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		keys = found
+		if len(keys) == 0 {
+			return nil
+		}
+	}
+
+	// sort the keys like the table
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return encoding.Compare([]byte(sorted[i]), []byte(sorted[j])) < 0
+	})
+
+	var newEnv environment.Environment
+	newEnv.SetOuter(in)
+
+	var ptr database.LazyRow
+	newEnv.SetRow(&ptr)
+
+	for _, k := range sorted {
+		ptr.ResetWith(table, tree.NewEncodedKey([]byte(k)))
+
+		err = fn(&newEnv)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (it *FullTextScanOperator) String() string {
+	return fmt.Sprintf("index.FullTextScan(%q, %s)", it.IndexName, it.Query)
+}
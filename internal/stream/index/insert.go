@@ -5,11 +5,11 @@ import (
 
 	"github.com/chaisql/chai/internal/environment"
 	"github.com/chaisql/chai/internal/stream"
-	"github.com/chaisql/chai/internal/types"
 	"github.com/cockroachdb/errors"
 )
 
 // InsertOperator reads the input stream and indexes each object.
+// If the index is partial, the objects not matching its predicate are ignored.
 type InsertOperator struct {
 	stream.BaseOperator
 
@@ -46,23 +46,30 @@ func (op *InsertOperator) Iterate(in *environment.Environment, fn func(out *envi
 			return errors.New("missing row")
 		}
 
-		vs := make([]types.Value, 0, len(info.Paths))
-		for _, path := range info.Paths {
-			v, err := path.GetValueFromObject(r.Object())
-			if err != nil {
-				v = types.NewNullValue()
-			}
-			vs = append(vs, v)
+		ok, err := info.Includes(tx, r.Object())
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fn(out)
 		}
 
-		encKey, err := tinfo.EncodeKey(r.Key())
+		entries, err := info.Entries(tx, r.Object())
 		if err != nil {
 			return err
 		}
 
-		err = idx.Set(vs, encKey)
+		encKey, err := tinfo.EncodeKey(r.Key())
 		if err != nil {
-			return fmt.Errorf("error while inserting index value: %w", err)
+			return err
+		}
+
+		included := info.IncludedValues(r.Object())
+		for _, vs := range entries {
+			err = idx.SetWithIncluded(vs, included, encKey)
+			if err != nil {
+				return fmt.Errorf("error while inserting index value: %w", err)
+			}
 		}
 
 		return fn(out)
@@ -7,9 +7,12 @@ import (
 	"github.com/cockroachdb/errors"
 
 	"github.com/chaisql/chai/internal/database"
+	"github.com/chaisql/chai/internal/encoding"
 	"github.com/chaisql/chai/internal/environment"
+	"github.com/chaisql/chai/internal/object"
 	"github.com/chaisql/chai/internal/stream"
 	"github.com/chaisql/chai/internal/tree"
+	"github.com/chaisql/chai/internal/types"
 )
 
 // A ScanOperator iterates over the objects of an index.
@@ -23,6 +26,10 @@ type ScanOperator struct {
 	Ranges stream.Ranges
 	// Reverse indicates the direction used to traverse the index.
 	Reverse bool
+	// Covering indicates that the following operators only use columns
+	// stored in the index, which are read from the index entries instead of
+	// fetching the rows from the table.
+	Covering bool
 }
 
 // Scan creates an iterator that iterates over each object of the given table.
@@ -58,18 +65,25 @@ func (it *ScanOperator) Iterate(in *environment.Environment, fn func(out *enviro
 	var newEnv environment.Environment
 	newEnv.SetOuter(in)
 
-	var ptr database.LazyRow
+	iterate := func(r *tree.Range) error {
+		if it.Covering {
+			return it.iterateCovering(&newEnv, index, info, table, r, fn)
+		}
 
-	newEnv.SetRow(&ptr)
+		var ptr database.LazyRow
+		newEnv.SetRow(&ptr)
 
-	if len(it.Ranges) == 0 {
-		return index.IterateOnRange(nil, it.Reverse, func(key *tree.Key) error {
+		return index.IterateOnRange(r, it.Reverse, func(key *tree.Key) error {
 			ptr.ResetWith(table, key)
 
 			return fn(&newEnv)
 		})
 	}
 
+	if len(it.Ranges) == 0 {
+		return iterate(nil)
+	}
+
 	ranges, err := it.Ranges.Eval(in)
 	if err != nil || len(ranges) != len(it.Ranges) {
 		return err
@@ -81,11 +95,7 @@ func (it *ScanOperator) Iterate(in *environment.Environment, fn func(out *enviro
 			return err
 		}
 
-		err = index.IterateOnRange(r, it.Reverse, func(key *tree.Key) error {
-			ptr.ResetWith(table, key)
-
-			return fn(&newEnv)
-		})
+		err = iterate(r)
 		if errors.Is(err, stream.ErrStreamClosed) {
 			err = nil
 		}
@@ -97,10 +107,84 @@ func (it *ScanOperator) Iterate(in *environment.Environment, fn func(out *enviro
 	return nil
 }
 
+// iterateCovering iterates over the entries of the index, building the rows
+// from the indexed values, the included values and the primary key.
+// Indexed expressions and nested paths are not part of the rows: the other columns
+// are read from the table, if requested.
+func (it *ScanOperator) iterateCovering(env *environment.Environment, index *database.Index, info *database.IndexInfo, table *database.Table, r *tree.Range, fn func(out *environment.Environment) error) error {
+	ti := table.Info
+	var row database.CoveredRow
+	var fb object.FieldBuffer
+	env.SetRow(&row)
+
+	// some types, like timestamps, are not preserved by the encoding
+	// and must be converted back to the type of their column
+	add := func(field string, v types.Value) error {
+		if fc, ok := ti.FieldConstraints.ByField[field]; ok {
+			var err error
+			v, err = encoding.ConvertFromStoreTo(v, fc.Type)
+			if err != nil {
+				return err
+			}
+		}
+
+		fb.Add(field, v)
+		return nil
+	}
+
+	return index.IterateEntriesOnRange(r, it.Reverse, func(values, included []types.Value, key *tree.Key) error {
+		fb.Reset()
+
+		for i, p := range info.Paths {
+			if len(p) != 1 {
+				continue
+			}
+
+			if err := add(p[0].FieldName, values[i]); err != nil {
+				return err
+			}
+		}
+
+		for i, p := range info.Include {
+			if err := add(p[0].FieldName, included[i]); err != nil {
+				return err
+			}
+		}
+
+		if ti.PrimaryKey != nil {
+			pk, err := key.Decode()
+			if err != nil {
+				return err
+			}
+
+			for i, p := range ti.PrimaryKey.Paths {
+				if len(p) != 1 {
+					continue
+				}
+				if _, err := fb.GetByField(p[0].FieldName); !errors.Is(err, types.ErrFieldNotFound) {
+					continue
+				}
+
+				if err := add(p[0].FieldName, pk[i]); err != nil {
+					return err
+				}
+			}
+		}
+
+		row.ResetWith(table, key, &fb)
+
+		return fn(env)
+	})
+}
+
 func (it *ScanOperator) String() string {
 	var s strings.Builder
 
-	s.WriteString("index.Scan")
+	if it.Covering {
+		s.WriteString("index.CoveringScan")
+	} else {
+		s.WriteString("index.Scan")
+	}
 	if it.Reverse {
 		s.WriteString("Reverse")
 	}
@@ -46,22 +46,29 @@ func (op *ValidateOperator) Iterate(in *environment.Environment, fn func(out *en
 			return errors.New("missing row")
 		}
 
-		vs := make([]types.Value, 0, len(info.Paths))
+		// rows not matching the predicate of a partial index
+		// are not indexed and can't conflict
+		ok, err := info.Includes(tx, r.Object())
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fn(out)
+		}
+
+		vs, err := info.Values(tx, r.Object())
+		if err != nil {
+			return err
+		}
 
 		// if the indexes values contain NULL somewhere,
 		// we don't check for unicity.
 		// cf: https://sqlite.org/lang_createindex.html#unique_indexes
 		var hasNull bool
-		for _, path := range info.Paths {
-			v, err := path.GetValueFromObject(r.Object())
-			if err != nil {
-				hasNull = true
-				v = types.NewNullValue()
-			} else if v.Type() == types.TypeNull {
+		for _, v := range vs {
+			if v.Type() == types.TypeNull {
 				hasNull = true
 			}
-
-			vs = append(vs, v)
 		}
 
 		if !hasNull {
@@ -73,6 +80,7 @@ func (op *ValidateOperator) Iterate(in *environment.Environment, fn func(out *en
 				return &database.ConstraintViolationError{
 					Constraint: "UNIQUE",
 					Paths:      info.Paths,
+					Exprs:      info.KeyExprs(),
 					Key:        key,
 				}
 			}
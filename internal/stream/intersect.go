@@ -0,0 +1,98 @@
+package stream
+
+import (
+	"strings"
+
+	"github.com/chaisql/chai/internal/environment"
+)
+
+// IntersectOperator is an operator that returns the rows
+// returned by all of its streams.
+type IntersectOperator struct {
+	BaseOperator
+	Streams []*Stream
+	// All keeps duplicate rows: a row is returned as many times
+	// as the minimum number of times it is returned by each stream.
+	All bool
+}
+
+// Intersect returns a new IntersectOperator that discards duplicate rows.
+func Intersect(s ...*Stream) *IntersectOperator {
+	return &IntersectOperator{Streams: s}
+}
+
+// IntersectAll returns a new IntersectOperator that keeps duplicate rows.
+func IntersectAll(s ...*Stream) *IntersectOperator {
+	return &IntersectOperator{Streams: s, All: true}
+}
+
+// Iterate iterates over all the streams and returns their intersection.
+func (it *IntersectOperator) Iterate(in *environment.Environment, fn func(out *environment.Environment) error) (err error) {
+	if len(it.Streams) == 0 {
+		return nil
+	}
+
+	rc, err := newRowCounter(in)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		e := rc.cleanup()
+		if err == nil {
+			err = e
+		}
+	}()
+
+	// count the rows of the first stream
+	err = it.Streams[0].Iterate(in, func(out *environment.Environment) error {
+		return rc.add(out, it.All)
+	})
+	if err != nil {
+		return err
+	}
+
+	// for each of the other streams, only keep the rows that it also returns,
+	// up to the number of times it returns them
+	for _, s := range it.Streams[1:] {
+		next, err := newRowCounter(in)
+		if err != nil {
+			return err
+		}
+
+		err = s.Iterate(in, func(out *environment.Environment) error {
+			return next.addFrom(rc, out, it.All)
+		})
+		if err != nil {
+			_ = next.cleanup()
+			return err
+		}
+
+		err = rc.cleanup()
+		if err != nil {
+			_ = next.cleanup()
+			return err
+		}
+		rc = next
+	}
+
+	return rc.iterate(in, fn)
+}
+
+func (it *IntersectOperator) String() string {
+	var s strings.Builder
+
+	if it.All {
+		s.WriteString("intersectAll(")
+	} else {
+		s.WriteString("intersect(")
+	}
+	for i, st := range it.Streams {
+		if i > 0 {
+			s.WriteString(", ")
+		}
+		s.WriteString(st.String())
+	}
+	s.WriteRune(')')
+
+	return s.String()
+}
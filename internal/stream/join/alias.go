@@ -0,0 +1,71 @@
+package join
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/chaisql/chai/internal/database"
+	"github.com/chaisql/chai/internal/environment"
+	"github.com/chaisql/chai/internal/object"
+	"github.com/chaisql/chai/internal/stream"
+	"github.com/chaisql/chai/internal/types"
+	"github.com/cockroachdb/errors"
+)
+
+// An AliasOperator references every incoming row by a name.
+type AliasOperator struct {
+	stream.BaseOperator
+	Name string
+}
+
+// Alias creates an AliasOperator. Columns of the rows
+// it outputs can be referenced using the name as a qualifier.
+// The columns of joined rows are merged into a single row,
+// they must have different names.
+func Alias(name string) *AliasOperator {
+	return &AliasOperator{Name: name}
+}
+
+// Iterate implements the Operator interface.
+func (op *AliasOperator) Iterate(in *environment.Environment, fn func(out *environment.Environment) error) error {
+	var row Row
+	var merged database.BasicRow
+	var fb object.FieldBuffer
+	var newEnv environment.Environment
+
+	return op.Prev.Iterate(in, func(out *environment.Environment) error {
+		r, ok := out.GetRow()
+		if !ok {
+			return errors.New("missing row")
+		}
+
+		row.reset(r)
+		if len(row.names) != 1 {
+			fb.Reset()
+			err := r.Iterate(func(column string, v types.Value) error {
+				if _, err := fb.GetByField(column); err == nil {
+					return fmt.Errorf("cannot alias a joined row as %q: duplicate column %q", op.Name, column)
+				}
+
+				fb.Add(column, v)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			merged.ResetWith("", nil, &fb)
+			row.reset(&merged)
+		}
+		row.names[0] = op.Name
+
+		newEnv.SetOuter(out)
+		newEnv.SetRow(&row)
+
+		return fn(&newEnv)
+	})
+}
+
+func (op *AliasOperator) String() string {
+	return fmt.Sprintf("join.Alias(%s)", strconv.Quote(op.Name))
+}
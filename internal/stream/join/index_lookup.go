@@ -0,0 +1,179 @@
+package join
+
+import (
+	"strings"
+
+	"github.com/chaisql/chai/internal/database"
+	"github.com/chaisql/chai/internal/environment"
+	"github.com/chaisql/chai/internal/expr"
+	"github.com/chaisql/chai/internal/object"
+	"github.com/chaisql/chai/internal/stream"
+	"github.com/chaisql/chai/internal/stream/index"
+	"github.com/chaisql/chai/internal/stream/table"
+	"github.com/chaisql/chai/internal/tree"
+	"github.com/chaisql/chai/internal/types"
+	"github.com/cockroachdb/errors"
+)
+
+// An IndexLookupOperator joins every incoming row with the rows of a table
+// whose indexed values are equal to the evaluation of Values.
+// Instead of scanning the whole table for every incoming row, it only reads
+// the matching entries of an index or of the primary key of the table.
+type IndexLookupOperator struct {
+	stream.BaseOperator
+	TableName string
+	// IndexName is the name of the index used to lookup rows.
+	// If empty, the primary key of the table is used.
+	IndexName string
+	// Values are evaluated against each incoming row and compared to
+	// the leftmost indexed paths.
+	Values expr.LiteralExprList
+	// Alias references the rows of the table.
+	// If empty, the table name is used.
+	Alias string
+	// On is the full join condition, evaluated on each matching row.
+	On expr.Expr
+	// Outer indicates a LEFT JOIN.
+	Outer bool
+}
+
+// IndexLookup creates an IndexLookupOperator that performs an inner join.
+func IndexLookup(tableName, indexName string, values expr.LiteralExprList, alias string, on expr.Expr) *IndexLookupOperator {
+	return &IndexLookupOperator{TableName: tableName, IndexName: indexName, Values: values, Alias: alias, On: on}
+}
+
+// LeftIndexLookup creates an IndexLookupOperator that performs a left outer join.
+func LeftIndexLookup(tableName, indexName string, values expr.LiteralExprList, alias string, on expr.Expr) *IndexLookupOperator {
+	return &IndexLookupOperator{TableName: tableName, IndexName: indexName, Values: values, Alias: alias, On: on, Outer: true}
+}
+
+// Iterate implements the Operator interface.
+func (op *IndexLookupOperator) Iterate(in *environment.Environment, fn func(out *environment.Environment) error) error {
+	tx := in.GetTx()
+
+	tb, err := tx.Catalog.GetTable(tx, op.TableName)
+	if err != nil {
+		return err
+	}
+
+	var idx *database.Index
+	var paths []object.Path
+	if op.IndexName != "" {
+		idx, err = tx.Catalog.GetIndex(tx, op.IndexName)
+		if err != nil {
+			return err
+		}
+		info, err := tx.Catalog.GetIndexInfo(op.IndexName)
+		if err != nil {
+			return err
+		}
+		paths = info.Paths
+	}
+
+	rng := stream.Range{
+		Min:   op.Values,
+		Exact: true,
+	}
+	name := sourceName(op.Alias, op.TableName)
+
+	var row Row
+	var newEnv environment.Environment
+	var lazyRow database.LazyRow
+
+	return op.Prev.Iterate(in, func(out *environment.Environment) error {
+		left, ok := out.GetRow()
+		if !ok {
+			return errors.New("missing row")
+		}
+
+		newEnv.SetOuter(out)
+
+		var matched bool
+		visit := func(right database.Row) error {
+			row.reset(left)
+			row.add(name, right)
+			newEnv.SetRow(&row)
+
+			ok, err := evalCondition(&newEnv, op.On)
+			if err != nil || !ok {
+				return err
+			}
+
+			matched = true
+			return fn(&newEnv)
+		}
+
+		// evaluate the lookup values against the incoming row
+		row.reset(left)
+		row.add(name, nil)
+		newEnv.SetRow(&row)
+		r, err := rng.Eval(&newEnv)
+		if err != nil {
+			return err
+		}
+
+		// NULL never matches any value
+		var hasNull bool
+		for _, v := range r.Min {
+			if types.IsNull(v) {
+				hasNull = true
+				break
+			}
+		}
+
+		switch {
+		case hasNull:
+		case idx != nil:
+			tr, err := r.ToTreeRange(&tb.Info.FieldConstraints, paths)
+			if err != nil {
+				return err
+			}
+
+			err = idx.IterateOnRange(tr, false, func(key *tree.Key) error {
+				lazyRow.ResetWith(tb, key)
+				return visit(&lazyRow)
+			})
+			if err != nil {
+				return err
+			}
+		default:
+			err = tb.IterateOnRange(r, false, func(key *tree.Key, r database.Row) error {
+				return visit(r)
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if matched || !op.Outer {
+			return nil
+		}
+
+		row.reset(left)
+		row.add(name, nil)
+		newEnv.SetRow(&row)
+		return fn(&newEnv)
+	})
+}
+
+func (op *IndexLookupOperator) String() string {
+	var sb strings.Builder
+
+	if op.Outer {
+		sb.WriteString("join.LeftIndexLookup(")
+	} else {
+		sb.WriteString("join.IndexLookup(")
+	}
+
+	rng := stream.Range{Min: op.Values, Exact: true}
+	if op.IndexName != "" {
+		sb.WriteString(index.Scan(op.IndexName, rng).String())
+	} else {
+		sb.WriteString(table.Scan(op.TableName, rng).String())
+	}
+
+	writeAliasAndCondition(&sb, op.Alias, op.On)
+	sb.WriteString(")")
+
+	return sb.String()
+}
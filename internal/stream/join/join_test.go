@@ -0,0 +1,181 @@
+package join_test
+
+import (
+	"testing"
+
+	"github.com/chaisql/chai/internal/environment"
+	"github.com/chaisql/chai/internal/expr"
+	"github.com/chaisql/chai/internal/object"
+	"github.com/chaisql/chai/internal/sql/parser"
+	"github.com/chaisql/chai/internal/stream"
+	"github.com/chaisql/chai/internal/stream/join"
+	"github.com/chaisql/chai/internal/stream/table"
+	"github.com/chaisql/chai/internal/testutil"
+	"github.com/chaisql/chai/internal/testutil/assert"
+	"github.com/chaisql/chai/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJoin(t *testing.T) {
+	tests := []struct {
+		name string
+		op   stream.Operator
+		want []string
+	}{
+		{
+			"NestedLoop",
+			join.NestedLoop(stream.New(table.Scan("bar")), "b", parser.MustParseExpr("foo.a = b.a")),
+			[]string{
+				`{"a": 1, "b": 10, "a": 1, "c": 100}`,
+				`{"a": 1, "b": 10, "a": 1, "c": 101}`,
+				`{"a": 2, "b": 20, "a": 2, "c": 200}`,
+			},
+		},
+		{
+			"LeftNestedLoop",
+			join.LeftNestedLoop(stream.New(table.Scan("bar")), "b", parser.MustParseExpr("foo.a = b.a")),
+			[]string{
+				`{"a": 1, "b": 10, "a": 1, "c": 100}`,
+				`{"a": 1, "b": 10, "a": 1, "c": 101}`,
+				`{"a": 2, "b": 20, "a": 2, "c": 200}`,
+				`{"a": 3, "b": 30}`,
+			},
+		},
+		{
+			"IndexLookup",
+			join.IndexLookup("bar", "bar_a_idx", expr.LiteralExprList{parser.MustParseExpr("foo.a")}, "b", parser.MustParseExpr("foo.a = b.a")),
+			[]string{
+				`{"a": 1, "b": 10, "a": 1, "c": 100}`,
+				`{"a": 1, "b": 10, "a": 1, "c": 101}`,
+				`{"a": 2, "b": 20, "a": 2, "c": 200}`,
+			},
+		},
+		{
+			"LeftIndexLookup",
+			join.LeftIndexLookup("bar", "bar_a_idx", expr.LiteralExprList{parser.MustParseExpr("foo.a")}, "b", parser.MustParseExpr("foo.a = b.a AND b.c > 100")),
+			[]string{
+				`{"a": 1, "b": 10, "a": 1, "c": 101}`,
+				`{"a": 2, "b": 20, "a": 2, "c": 200}`,
+				`{"a": 3, "b": 30}`,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, tx, cleanup := testutil.NewTestTx(t)
+			defer cleanup()
+
+			testutil.MustExec(t, db, tx, `
+				CREATE TABLE foo(a int, b int);
+				CREATE TABLE bar(a int, c int);
+				CREATE INDEX bar_a_idx ON bar(a);
+				INSERT INTO foo (a, b) VALUES (1, 10), (2, 20), (3, 30);
+				INSERT INTO bar (a, c) VALUES (1, 100), (1, 101), (2, 200), (4, 400);
+			`)
+
+			var env environment.Environment
+			env.DB = db
+			env.Tx = tx
+
+			s := stream.New(table.Scan("foo")).Pipe(test.op)
+
+			var got []string
+			err := s.Iterate(&env, func(out *environment.Environment) error {
+				r, ok := out.GetRow()
+				require.True(t, ok)
+
+				b, err := object.MarshalJSON(r.Object())
+				if err != nil {
+					return err
+				}
+				got = append(got, string(b))
+				return nil
+			})
+			assert.NoError(t, err)
+			require.Equal(t, test.want, got)
+		})
+	}
+
+	t.Run("QualifiedColumns", func(t *testing.T) {
+		db, tx, cleanup := testutil.NewTestTx(t)
+		defer cleanup()
+
+		testutil.MustExec(t, db, tx, `
+			CREATE TABLE foo(a int, b int);
+			CREATE TABLE bar(a int, c int);
+			INSERT INTO foo (a, b) VALUES (1, 10);
+			INSERT INTO bar (a, c) VALUES (1, 100);
+		`)
+
+		var env environment.Environment
+		env.DB = db
+		env.Tx = tx
+
+		s := stream.New(table.Scan("foo")).
+			Pipe(join.Alias("f")).
+			Pipe(join.NestedLoop(stream.New(table.Scan("bar")), "", parser.MustParseExpr("f.a = bar.a")))
+
+		err := s.Iterate(&env, func(out *environment.Environment) error {
+			testutil.TestExpr(t, "f.b", out, types.NewIntegerValue(10), false)
+			testutil.TestExpr(t, "bar.c", out, types.NewIntegerValue(100), false)
+			testutil.TestExpr(t, "c", out, types.NewIntegerValue(100), false)
+			testutil.TestExpr(t, "foo.b", out, types.NewNullValue(), false)
+			testutil.TestExpr(t, "a", out, nil, true)
+			return nil
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("Target", func(t *testing.T) {
+		db, tx, cleanup := testutil.NewTestTx(t)
+		defer cleanup()
+
+		testutil.MustExec(t, db, tx, `
+			CREATE TABLE foo(a int, b int);
+			CREATE TABLE bar(a int, c int);
+			INSERT INTO foo (a, b) VALUES (1, 10), (2, 20);
+			INSERT INTO bar (a, c) VALUES (1, 100), (1, 101), (2, 200);
+		`)
+
+		var env environment.Environment
+		env.DB = db
+		env.Tx = tx
+
+		s := stream.New(table.Scan("foo")).
+			Pipe(join.NestedLoop(stream.New(table.Scan("bar")), "", parser.MustParseExpr("foo.a = bar.a"))).
+			Pipe(join.Target("foo"))
+
+		var got []string
+		err := s.Iterate(&env, func(out *environment.Environment) error {
+			r, ok := out.GetRow()
+			require.True(t, ok)
+			require.Equal(t, "foo", r.TableName())
+
+			b, err := object.MarshalJSON(r.Object())
+			if err != nil {
+				return err
+			}
+			got = append(got, string(b))
+
+			// the other rows are exposed to expressions,
+			// only the first row joined with each target row is returned
+			c := int64(len(got)) * 100
+			testutil.TestExpr(t, "bar.c", out, types.NewIntegerValue(c), false)
+			testutil.TestExpr(t, "c", out, types.NewIntegerValue(c), false)
+			testutil.TestExpr(t, "a", out, types.NewIntegerValue(int64(len(got))), false)
+			return nil
+		})
+		assert.NoError(t, err)
+		require.Equal(t, []string{`{"a": 1, "b": 10}`, `{"a": 2, "b": 20}`}, got)
+	})
+
+	t.Run("String", func(t *testing.T) {
+		require.Equal(t, `join.Alias("f")`, join.Alias("f").String())
+		require.Equal(t, `join.Target("foo")`, join.Target("foo").String())
+		require.Equal(t, `join.NestedLoop(table.Scan("bar") AS b, a = b.a)`, join.NestedLoop(stream.New(table.Scan("bar")), "b", parser.MustParseExpr("a = b.a")).String())
+		require.Equal(t, `join.LeftNestedLoop(table.Scan("bar"), a = bar.a)`, join.LeftNestedLoop(stream.New(table.Scan("bar")), "", parser.MustParseExpr("a = bar.a")).String())
+		require.Equal(t, `join.IndexLookup(index.Scan("idx", [{"min": [foo.a], "exact": true}]) AS b, foo.a = b.a)`, join.IndexLookup("bar", "idx", expr.LiteralExprList{parser.MustParseExpr("foo.a")}, "b", parser.MustParseExpr("foo.a = b.a")).String())
+		require.Equal(t, `join.LeftIndexLookup(table.Scan("bar", [{"min": [foo.a], "exact": true}]), foo.a = bar.a)`, join.LeftIndexLookup("bar", "", expr.LiteralExprList{parser.MustParseExpr("foo.a")}, "", parser.MustParseExpr("foo.a = bar.a")).String())
+	})
+}
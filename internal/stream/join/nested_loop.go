@@ -0,0 +1,134 @@
+package join
+
+import (
+	"strings"
+
+	"github.com/chaisql/chai/internal/environment"
+	"github.com/chaisql/chai/internal/expr"
+	"github.com/chaisql/chai/internal/stream"
+	"github.com/chaisql/chai/internal/stream/table"
+	"github.com/chaisql/chai/internal/types"
+	"github.com/cockroachdb/errors"
+)
+
+// A NestedLoopOperator joins every incoming row with every row of
+// the Right stream for which the On condition is truthy.
+type NestedLoopOperator struct {
+	stream.BaseOperator
+	// Right is iterated once per incoming row.
+	Right *stream.Stream
+	// Alias references the rows of the Right stream.
+	// If empty, the table name is used.
+	Alias string
+	// On is the join condition. If nil, all combinations are returned.
+	On expr.Expr
+	// Outer indicates a LEFT JOIN: incoming rows without any match
+	// are returned without the columns of the Right stream.
+	Outer bool
+}
+
+// NestedLoop creates a NestedLoopOperator that performs an inner join.
+func NestedLoop(right *stream.Stream, alias string, on expr.Expr) *NestedLoopOperator {
+	return &NestedLoopOperator{Right: right, Alias: alias, On: on}
+}
+
+// LeftNestedLoop creates a NestedLoopOperator that performs a left outer join.
+func LeftNestedLoop(right *stream.Stream, alias string, on expr.Expr) *NestedLoopOperator {
+	return &NestedLoopOperator{Right: right, Alias: alias, On: on, Outer: true}
+}
+
+// Iterate implements the Operator interface.
+func (op *NestedLoopOperator) Iterate(in *environment.Environment, fn func(out *environment.Environment) error) error {
+	var row Row
+	var newEnv environment.Environment
+
+	var tableName string
+	if scan, ok := op.Right.First().(*table.ScanOperator); ok {
+		tableName = scan.TableName
+	}
+
+	return op.Prev.Iterate(in, func(out *environment.Environment) error {
+		left, ok := out.GetRow()
+		if !ok {
+			return errors.New("missing row")
+		}
+
+		newEnv.SetOuter(out)
+
+		var matched bool
+		err := op.Right.Iterate(out, func(rout *environment.Environment) error {
+			right, ok := rout.GetRow()
+			if !ok {
+				return errors.New("missing row")
+			}
+
+			row.reset(left)
+			row.add(sourceName(op.Alias, right.TableName()), right)
+			newEnv.SetRow(&row)
+
+			ok, err := evalCondition(&newEnv, op.On)
+			if err != nil || !ok {
+				return err
+			}
+
+			matched = true
+			return fn(&newEnv)
+		})
+		if err != nil || matched || !op.Outer {
+			return err
+		}
+
+		row.reset(left)
+		row.add(sourceName(op.Alias, tableName), nil)
+		newEnv.SetRow(&row)
+		return fn(&newEnv)
+	})
+}
+
+func (op *NestedLoopOperator) String() string {
+	var sb strings.Builder
+
+	if op.Outer {
+		sb.WriteString("join.LeftNestedLoop(")
+	} else {
+		sb.WriteString("join.NestedLoop(")
+	}
+	sb.WriteString(op.Right.String())
+	writeAliasAndCondition(&sb, op.Alias, op.On)
+	sb.WriteString(")")
+
+	return sb.String()
+}
+
+func sourceName(alias, tableName string) string {
+	if alias != "" {
+		return alias
+	}
+
+	return tableName
+}
+
+func evalCondition(env *environment.Environment, e expr.Expr) (bool, error) {
+	if e == nil {
+		return true, nil
+	}
+
+	v, err := e.Eval(env)
+	if err != nil {
+		return false, err
+	}
+
+	return types.IsTruthy(v)
+}
+
+func writeAliasAndCondition(sb *strings.Builder, alias string, on expr.Expr) {
+	if alias != "" {
+		sb.WriteString(" AS ")
+		sb.WriteString(alias)
+	}
+
+	if on != nil {
+		sb.WriteString(", ")
+		sb.WriteString(on.String())
+	}
+}
@@ -0,0 +1,162 @@
+package join
+
+import (
+	"fmt"
+
+	"github.com/chaisql/chai/internal/database"
+	"github.com/chaisql/chai/internal/object"
+	"github.com/chaisql/chai/internal/tree"
+	"github.com/chaisql/chai/internal/types"
+	"github.com/cockroachdb/errors"
+)
+
+var _ database.Row = (*Row)(nil)
+
+// A Row combines the rows of multiple tables.
+// Each source row is referenced by a name, which is either the
+// alias of the table or its name.
+// Unqualified columns are looked up in every source row,
+// while a name matching a source returns the whole source row as an object.
+// A nil source row represents the missing side of a LEFT JOIN:
+// it doesn't have any column and its name evaluates to NULL.
+type Row struct {
+	names []string
+	rows  []database.Row
+}
+
+// NewRow returns a row combining the given source rows,
+// each referenced by the name at the same position.
+func NewRow(names []string, rows []database.Row) *Row {
+	return &Row{names: names, rows: rows}
+}
+
+// Sources returns the names and the source rows of the row, in order.
+// The returned slices must not be modified.
+func (r *Row) Sources() ([]string, []database.Row) {
+	return r.names, r.rows
+}
+
+// reset the row and add the sources of r.
+// If r is not a joined row, it is referenced by its table name.
+func (r *Row) reset(left database.Row) {
+	r.names = r.names[:0]
+	r.rows = r.rows[:0]
+
+	if jr, ok := left.(*Row); ok {
+		r.names = append(r.names, jr.names...)
+		r.rows = append(r.rows, jr.rows...)
+		return
+	}
+
+	r.add(left.TableName(), left)
+}
+
+// add a source to the row.
+func (r *Row) add(name string, row database.Row) {
+	r.names = append(r.names, name)
+	r.rows = append(r.rows, row)
+}
+
+// Source returns the source row referenced by name.
+// The returned row is nil for the missing side of a LEFT JOIN.
+func (r *Row) Source(name string) (database.Row, bool) {
+	for i, n := range r.names {
+		if n == name {
+			return r.rows[i], true
+		}
+	}
+
+	return nil, false
+}
+
+// Iterate over the columns of every source row, in order.
+func (r *Row) Iterate(fn func(column string, value types.Value) error) error {
+	for _, row := range r.rows {
+		if row == nil {
+			continue
+		}
+
+		err := row.Iterate(fn)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Get returns the value of the given column.
+func (r *Row) Get(name string) (types.Value, error) {
+	return r.GetByField(name)
+}
+
+// GetByField returns the source row referenced by name, if any,
+// otherwise it looks up the column in every source row.
+// It returns an error if the column exists in more than one source row.
+func (r *Row) GetByField(name string) (types.Value, error) {
+	for i, n := range r.names {
+		if n != name {
+			continue
+		}
+
+		if r.rows[i] == nil {
+			return types.NewNullValue(), nil
+		}
+
+		return types.NewObjectValue(r.rows[i].Object()), nil
+	}
+
+	var found types.Value
+	for _, row := range r.rows {
+		if row == nil {
+			continue
+		}
+
+		v, err := row.Get(name)
+		if errors.Is(err, types.ErrFieldNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if found != nil {
+			return nil, fmt.Errorf("column reference %q is ambiguous", name)
+		}
+		found = v
+	}
+
+	if found == nil {
+		return nil, errors.WithStack(types.ErrFieldNotFound)
+	}
+
+	return found, nil
+}
+
+// TableName returns the table name of the source row if there is only one,
+// otherwise it returns an empty string.
+func (r *Row) TableName() string {
+	if len(r.rows) == 1 && r.rows[0] != nil {
+		return r.rows[0].TableName()
+	}
+
+	return ""
+}
+
+// Key returns the key of the source row if there is only one,
+// otherwise it returns nil.
+func (r *Row) Key() *tree.Key {
+	if len(r.rows) == 1 && r.rows[0] != nil {
+		return r.rows[0].Key()
+	}
+
+	return nil
+}
+
+func (r *Row) Object() types.Object {
+	return r
+}
+
+func (r *Row) MarshalJSON() ([]byte, error) {
+	return object.MarshalJSON(r)
+}
@@ -0,0 +1,114 @@
+package join
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	"github.com/chaisql/chai/internal/environment"
+	"github.com/chaisql/chai/internal/object"
+	"github.com/chaisql/chai/internal/stream"
+	"github.com/chaisql/chai/internal/types"
+	"github.com/cockroachdb/errors"
+)
+
+// A TargetOperator replaces every incoming joined row by its source row
+// referenced by Name, i.e. the row of the table modified by
+// UPDATE ... FROM or DELETE ... USING.
+// The other source rows remain available to the following operators
+// as variables of the environment: each source row can be referenced by its name,
+// and its columns can be referenced without qualifier unless the target row
+// or a previous source row has a column with the same name.
+// If a target row is joined with multiple rows, only the first one is returned.
+type TargetOperator struct {
+	stream.BaseOperator
+	Name string
+}
+
+// Target creates a TargetOperator.
+func Target(name string) *TargetOperator {
+	return &TargetOperator{Name: name}
+}
+
+// Iterate implements the Operator interface.
+func (op *TargetOperator) Iterate(in *environment.Environment, fn func(out *environment.Environment) error) error {
+	var newEnv environment.Environment
+	vars := object.NewFieldBuffer()
+	var lastKey []byte
+
+	return op.Prev.Iterate(in, func(out *environment.Environment) error {
+		r, ok := out.GetRow()
+		if !ok {
+			return errors.New("missing row")
+		}
+
+		jr, ok := r.(*Row)
+		if !ok {
+			return fmt.Errorf("expected a joined row, got %T", r)
+		}
+
+		target, ok := jr.Source(op.Name)
+		if !ok || target == nil {
+			return fmt.Errorf("missing source row %q", op.Name)
+		}
+
+		// the rows joined with the same target row are returned consecutively
+		if k := target.Key(); k != nil && k.Encoded != nil {
+			if lastKey != nil && bytes.Equal(lastKey, k.Encoded) {
+				return nil
+			}
+			lastKey = append(lastKey[:0], k.Encoded...)
+		}
+
+		// add is used to expose a value unless the name
+		// is already used by the target row or by another source
+		add := func(name string, v types.Value) error {
+			if _, err := target.Get(name); !errors.Is(err, types.ErrFieldNotFound) {
+				return err
+			}
+			if _, err := vars.GetByField(name); err == nil {
+				return nil
+			}
+
+			vars.Add(name, v)
+			return nil
+		}
+
+		vars.Reset()
+		for i, name := range jr.names {
+			if name == op.Name {
+				continue
+			}
+
+			var err error
+			if jr.rows[i] == nil {
+				err = add(name, types.NewNullValue())
+			} else {
+				err = add(name, types.NewObjectValue(jr.rows[i].Object()))
+			}
+			if err != nil {
+				return err
+			}
+		}
+		for i, name := range jr.names {
+			if name == op.Name || jr.rows[i] == nil {
+				continue
+			}
+
+			err := jr.rows[i].Iterate(add)
+			if err != nil {
+				return err
+			}
+		}
+
+		newEnv.SetOuter(out)
+		newEnv.Vars = vars
+		newEnv.SetRow(target)
+
+		return fn(&newEnv)
+	})
+}
+
+func (op *TargetOperator) String() string {
+	return fmt.Sprintf("join.Target(%s)", strconv.Quote(op.Name))
+}
@@ -5,13 +5,23 @@ import (
 
 	"github.com/chaisql/chai/internal/database"
 	"github.com/chaisql/chai/internal/environment"
+	"github.com/chaisql/chai/internal/object"
+	"github.com/chaisql/chai/internal/types"
 )
 
 // OnConflictOperator handles any conflicts that occur during the iteration.
+// The OnConflict stream is run with the conflicting row, i.e. the proposed row
+// with the key of the existing row. The proposed row is also available as the
+// "excluded" variable.
 type OnConflictOperator struct {
 	BaseOperator
 
 	OnConflict *Stream
+
+	// Target restricts the handled conflicts to the ones on
+	// the primary key or the unique index covering these paths.
+	// Other conflicts are returned as errors.
+	Target []object.Path
 }
 
 func OnConflict(onConflict *Stream) *OnConflictOperator {
@@ -22,11 +32,16 @@ func OnConflict(onConflict *Stream) *OnConflictOperator {
 
 func (op *OnConflictOperator) Iterate(in *environment.Environment, fn func(out *environment.Environment) error) error {
 	var newEnv environment.Environment
+	vars := object.NewFieldBuffer()
 
 	return op.Prev.Iterate(in, func(out *environment.Environment) error {
 		err := fn(out)
 		if err != nil {
 			if cerr, ok := err.(*database.ConstraintViolationError); ok {
+				if !op.handles(cerr) {
+					return err
+				}
+
 				if op.OnConflict == nil {
 					return nil
 				}
@@ -37,6 +52,10 @@ func (op *OnConflictOperator) Iterate(in *environment.Environment, fn func(out *
 					return fmt.Errorf("missing row")
 				}
 
+				vars.Reset()
+				vars.Add("excluded", types.NewObjectValue(r.Object()))
+				newEnv.Vars = vars
+
 				var br database.BasicRow
 				br.ResetWith(r.TableName(), cerr.Key, r.Object())
 				newEnv.SetRow(&br)
@@ -48,6 +67,32 @@ func (op *OnConflictOperator) Iterate(in *environment.Environment, fn func(out *
 	})
 }
 
+// handles returns whether the conflict matches the target, if any.
+func (op *OnConflictOperator) handles(cerr *database.ConstraintViolationError) bool {
+	if len(op.Target) == 0 {
+		return true
+	}
+
+	if len(op.Target) != len(cerr.Paths) {
+		return false
+	}
+
+	for _, t := range op.Target {
+		var found bool
+		for _, p := range cerr.Paths {
+			if t.IsEqual(p) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
 func (op *OnConflictOperator) String() string {
 	if op.OnConflict == nil {
 		return "stream.OnConflict(NULL)"
@@ -52,8 +52,9 @@ func (op *UnsetOperator) Iterate(in *environment.Environment, f func(out *enviro
 			return err
 		}
 
+		// NULL values may not be part of the object
 		err = fb.Delete(object.NewPath(op.Column))
-		if err != nil {
+		if err != nil && !errors.Is(err, types.ErrFieldNotFound) {
 			return err
 		}
 
@@ -0,0 +1,371 @@
+package rows
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/chaisql/chai/internal/database"
+	"github.com/chaisql/chai/internal/environment"
+	"github.com/chaisql/chai/internal/object"
+	"github.com/chaisql/chai/internal/stream"
+	"github.com/chaisql/chai/internal/tree"
+	"github.com/chaisql/chai/internal/types"
+	"github.com/cockroachdb/errors"
+)
+
+// A CommonTable is a temporary table defined by a common table expression,
+// i.e. "WITH name AS (SELECT ...)".
+type CommonTable struct {
+	Name string
+	// Columns renames the columns returned by the query, in order.
+	// If empty, the columns keep their name.
+	Columns []string
+	// Stream returns the rows of the table.
+	// For recursive tables, it is the non-recursive term.
+	Stream *stream.Stream
+	// Recursive is the recursive term of the table, nil if the table is not recursive.
+	// It is evaluated against the rows returned by the previous iteration,
+	// which are bound to the environment under the name of the table,
+	// until it doesn't return any new row.
+	Recursive *stream.Stream
+	// Distinct discards the rows that were already returned (UNION),
+	// otherwise every row is kept (UNION ALL).
+	Distinct bool
+	// Correlated is true if the query references the enclosing statement.
+	// The rows of the table are then computed every time the table is scanned,
+	// otherwise they are computed once per execution of the statement.
+	Correlated bool
+}
+
+func (t *CommonTable) String() string {
+	if t.Recursive == nil {
+		return t.Stream.String()
+	}
+
+	if t.Distinct {
+		return fmt.Sprintf("%s UNION %s", t.Stream, t.Recursive)
+	}
+
+	return fmt.Sprintf("%s UNION ALL %s", t.Stream, t.Recursive)
+}
+
+// A CommonTableScanOperator iterates over the rows of a common table.
+type CommonTableScanOperator struct {
+	stream.BaseOperator
+	Table *CommonTable
+}
+
+// CommonTableScan creates an operator that computes the rows of the given table and iterates over them.
+// The rows are materialized in a temporary tree before being returned. Unless the table is correlated,
+// the tree is kept until the end of the execution of the statement and is scanned again
+// by the following scans of the table.
+// Recursive tables are computed one iteration at a time, the rows of each iteration being
+// returned before computing the next one.
+func CommonTableScan(t *CommonTable) *CommonTableScanOperator {
+	return &CommonTableScanOperator{Table: t}
+}
+
+// Iterate implements the Operator interface.
+func (op *CommonTableScanOperator) Iterate(in *environment.Environment, fn func(out *environment.Environment) error) error {
+	if !op.Table.Correlated {
+		if t, ok := in.GetMaterialized(op.Table); ok {
+			return iterateTemporaryTable(in, t, fn)
+		}
+	}
+
+	it := commonTableIterator{
+		table:   op.Table,
+		columns: op.Table.Columns,
+	}
+
+	if op.Table.Distinct {
+		var cleanup func() error
+		var err error
+		it.seen, cleanup, err = newTransientTree(in)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+	}
+
+	if op.Table.Recursive == nil {
+		t, cleanup, _, err := it.materialize(in, op.Table.Stream)
+		if err != nil {
+			return err
+		}
+
+		if op.Table.Correlated {
+			defer cleanup()
+		} else {
+			in.SetMaterialized(op.Table, t, cleanup)
+		}
+
+		return iterateTemporaryTable(in, t, fn)
+	}
+
+	// the rows of every iteration are accumulated to be stored once the table is complete.
+	// They are not stored if the scan stops before, as the table may be infinite.
+	var all *tree.Tree
+	var cleanup func() error
+	if !op.Table.Correlated {
+		var err error
+		all, cleanup, err = newTransientTree(in)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := it.iterateRecursive(in, all, fn)
+	if all == nil {
+		return err
+	}
+	if err != nil {
+		_ = cleanup()
+		return err
+	}
+
+	// the table may have been stored by another scan run by fn
+	if _, ok := in.GetMaterialized(op.Table); ok {
+		return cleanup()
+	}
+
+	in.SetMaterialized(op.Table, all, cleanup)
+	return nil
+}
+
+// commonTableIterator holds the state of the computation of a common table.
+type commonTableIterator struct {
+	table *CommonTable
+	// names of the columns of the table. If the table doesn't rename its columns,
+	// they are named after the columns of the first row, for
+	// the rows of the recursive term to use the same names.
+	columns []string
+	// rows already returned, when discarding duplicates
+	seen *tree.Tree
+}
+
+// iterateRecursive computes the rows of a recursive table one iteration at a time
+// and calls fn for each of them. If all is not nil, the rows are also copied to it.
+func (it *commonTableIterator) iterateRecursive(in *environment.Environment, all *tree.Tree, fn func(out *environment.Environment) error) error {
+	// the recursive term is evaluated with the working table
+	// bound to this environment
+	var newEnv environment.Environment
+	newEnv.SetOuter(in)
+
+	var cleanup func() error
+	defer func() {
+		if cleanup != nil {
+			_ = cleanup()
+		}
+	}()
+
+	var count int64
+	s := it.table.Stream
+	for s != nil {
+		working, clean, n, err := it.materialize(&newEnv, s)
+		if err != nil {
+			return err
+		}
+
+		// the previous working table is not needed anymore
+		if cleanup != nil {
+			err = cleanup()
+			if err != nil {
+				_ = clean()
+				return err
+			}
+		}
+		cleanup = clean
+
+		if n == 0 {
+			return nil
+		}
+
+		if all != nil {
+			count, err = appendTemporaryTable(all, working, count)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = iterateTemporaryTable(in, working, fn)
+		if err != nil {
+			return err
+		}
+
+		newEnv.SetTable(it.table.Name, working)
+		s = it.table.Recursive
+	}
+
+	return nil
+}
+
+// materialize stores the rows returned by s in a new temporary table
+// and returns the number of rows stored.
+func (it *commonTableIterator) materialize(in *environment.Environment, s *stream.Stream) (*tree.Tree, func() error, int64, error) {
+	tr, cleanup, err := newTransientTree(in)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	var counter int64
+	var buf []byte
+	err = s.Iterate(in, func(out *environment.Environment) error {
+		r, ok := out.GetRow()
+		if !ok {
+			return errors.New("missing row")
+		}
+
+		obj, err := it.renameColumns(r.Object())
+		if err != nil {
+			return err
+		}
+
+		if it.seen != nil {
+			key := tree.NewKey(types.NewObjectValue(obj))
+			ok, err := it.seen.Exists(key)
+			if err != nil || ok {
+				return err
+			}
+
+			err = it.seen.Put(key, nil)
+			if err != nil {
+				return err
+			}
+		}
+
+		buf, err = encodeProjectedObject(buf[:0], obj)
+		if err != nil {
+			return err
+		}
+
+		err = tr.Put(tree.NewKey(types.NewIntegerValue(counter)), buf)
+		if err != nil {
+			return err
+		}
+
+		counter++
+		return nil
+	})
+	if errors.Is(err, stream.ErrStreamClosed) {
+		err = nil
+	}
+	if err != nil {
+		_ = cleanup()
+		return nil, nil, 0, err
+	}
+
+	return tr, cleanup, counter, nil
+}
+
+// renameColumns returns the object with its columns renamed
+// after the columns of the table.
+func (it *commonTableIterator) renameColumns(o types.Object) (types.Object, error) {
+	if it.columns == nil {
+		if it.table.Recursive == nil {
+			return o, nil
+		}
+
+		err := o.Iterate(func(field string, value types.Value) error {
+			it.columns = append(it.columns, field)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return o, nil
+	}
+
+	var n int
+	fb := object.NewFieldBuffer()
+	err := o.Iterate(func(field string, value types.Value) error {
+		if n < len(it.columns) {
+			fb.Add(it.columns[n], value)
+		}
+		n++
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if n != len(it.columns) {
+		if len(it.table.Columns) > 0 {
+			return nil, fmt.Errorf("table %q has %d columns available but %d columns specified", it.table.Name, n, len(it.columns))
+		}
+
+		return nil, fmt.Errorf("each term of the recursive query %q must return the same number of columns", it.table.Name)
+	}
+
+	return fb, nil
+}
+
+func (op *CommonTableScanOperator) String() string {
+	return fmt.Sprintf("rows.CommonTableScan(%s, %s)", strconv.Quote(op.Table.Name), op.Table)
+}
+
+// A WorkingTableScanOperator iterates over the rows returned by the previous
+// iteration of a recursive common table.
+type WorkingTableScanOperator struct {
+	stream.BaseOperator
+	Name string
+}
+
+// WorkingTableScan creates an operator that iterates over the working table bound
+// to the environment under the given name.
+// It is used by the recursive term of a recursive common table to reference the table itself.
+func WorkingTableScan(name string) *WorkingTableScanOperator {
+	return &WorkingTableScanOperator{Name: name}
+}
+
+// Iterate implements the Operator interface.
+func (op *WorkingTableScanOperator) Iterate(in *environment.Environment, fn func(out *environment.Environment) error) error {
+	t, ok := in.GetTable(op.Name)
+	if !ok {
+		return fmt.Errorf("working table %q not found", op.Name)
+	}
+
+	return iterateTemporaryTable(in, t, fn)
+}
+
+func (op *WorkingTableScanOperator) String() string {
+	return fmt.Sprintf("rows.WorkingTableScan(%s)", strconv.Quote(op.Name))
+}
+
+// newTransientTree creates a temporary tree, removed by calling the returned function.
+func newTransientTree(in *environment.Environment) (*tree.Tree, func() error, error) {
+	tns := in.GetTx().Catalog.GetFreeTransientNamespace()
+	return tree.NewTransient(in.GetDB().Engine.NewTransientSession(), tns, 0)
+}
+
+// appendTemporaryTable copies the rows of src at the end of dst, whose last key is n - 1,
+// and returns the number of rows of dst.
+func appendTemporaryTable(dst, src *tree.Tree, n int64) (int64, error) {
+	err := src.IterateOnRange(nil, false, func(k *tree.Key, data []byte) error {
+		err := dst.Put(tree.NewKey(types.NewIntegerValue(n)), data)
+		n++
+		return err
+	})
+
+	return n, err
+}
+
+// iterateTemporaryTable iterates over the rows stored in a temporary table by a CommonTableScanOperator.
+func iterateTemporaryTable(in *environment.Environment, t *tree.Tree, fn func(out *environment.Environment) error) error {
+	var newEnv environment.Environment
+	newEnv.SetOuter(in)
+
+	var br database.BasicRow
+	return t.IterateOnRange(nil, false, func(k *tree.Key, data []byte) error {
+		obj, _, err := decodeProjectedObject(data)
+		if err != nil {
+			return err
+		}
+
+		br.ResetWith("", nil, obj)
+		newEnv.SetRow(&br)
+
+		return fn(&newEnv)
+	})
+}
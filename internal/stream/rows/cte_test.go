@@ -0,0 +1,122 @@
+package rows_test
+
+import (
+	"testing"
+
+	"github.com/chaisql/chai/internal/environment"
+	"github.com/chaisql/chai/internal/object"
+	"github.com/chaisql/chai/internal/sql/parser"
+	"github.com/chaisql/chai/internal/stream"
+	"github.com/chaisql/chai/internal/stream/rows"
+	"github.com/chaisql/chai/internal/testutil"
+	"github.com/chaisql/chai/internal/testutil/assert"
+	"github.com/chaisql/chai/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommonTableScan(t *testing.T) {
+	tests := []struct {
+		name  string
+		table *rows.CommonTable
+		want  []string
+		fails bool
+	}{
+		{
+			"simple",
+			&rows.CommonTable{
+				Name:   "foo",
+				Stream: stream.New(rows.Emit(parser.MustParseExpr(`{a: 1}`), parser.MustParseExpr(`{a: 2}`))),
+			},
+			[]string{`{"a": 1}`, `{"a": 2}`},
+			false,
+		},
+		{
+			"columns",
+			&rows.CommonTable{
+				Name:    "foo",
+				Columns: []string{"b"},
+				Stream:  stream.New(rows.Emit(parser.MustParseExpr(`{a: 1}`))),
+			},
+			[]string{`{"b": 1}`},
+			false,
+		},
+		{
+			"too many columns",
+			&rows.CommonTable{
+				Name:    "foo",
+				Columns: []string{"a", "b"},
+				Stream:  stream.New(rows.Emit(parser.MustParseExpr(`{a: 1}`))),
+			},
+			nil,
+			true,
+		},
+		{
+			"recursive",
+			&rows.CommonTable{
+				Name:   "foo",
+				Stream: stream.New(rows.Emit(parser.MustParseExpr(`{a: 1}`))),
+				Recursive: stream.New(rows.WorkingTableScan("foo")).
+					Pipe(rows.Filter(parser.MustParseExpr("a < 3"))).
+					Pipe(rows.Project(testutil.ParseNamedExpr(t, "a + 1"))),
+			},
+			[]string{`{"a": 1}`, `{"a": 2}`, `{"a": 3}`},
+			false,
+		},
+		{
+			"recursive/distinct",
+			&rows.CommonTable{
+				Name:   "foo",
+				Stream: stream.New(rows.Emit(parser.MustParseExpr(`{a: 0}`), parser.MustParseExpr(`{a: 0}`))),
+				Recursive: stream.New(rows.WorkingTableScan("foo")).
+					Pipe(rows.Project(testutil.ParseNamedExpr(t, "(a + 1) % 2"))),
+				Distinct: true,
+			},
+			[]string{`{"a": 0}`, `{"a": 1}`},
+			false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, tx, cleanup := testutil.NewTestTx(t)
+			defer cleanup()
+
+			var env environment.Environment
+			env.DB = db
+			env.Tx = tx
+
+			var got []types.Object
+			err := stream.New(rows.CommonTableScan(test.table)).Iterate(&env, func(env *environment.Environment) error {
+				r, ok := env.GetRow()
+				require.True(t, ok)
+				var fb object.FieldBuffer
+				err := fb.Copy(r.Object())
+				if err != nil {
+					return err
+				}
+				got = append(got, &fb)
+				return nil
+			})
+			if test.fails {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			want := testutil.MakeObjects(t, test.want...)
+			require.Equal(t, len(want), len(got))
+			for i, doc := range want {
+				testutil.RequireObjEqual(t, doc, got[i])
+			}
+		})
+	}
+
+	t.Run("String", func(t *testing.T) {
+		table := rows.CommonTable{
+			Name:      "foo",
+			Stream:    stream.New(rows.Emit(parser.MustParseExpr(`{a: 1}`))),
+			Recursive: stream.New(rows.WorkingTableScan("foo")),
+		}
+		require.Equal(t, `rows.CommonTableScan("foo", rows.Emit({a: 1}) UNION ALL rows.WorkingTableScan("foo"))`, rows.CommonTableScan(&table).String())
+	})
+}
@@ -14,49 +14,65 @@ import (
 type GroupAggregateOperator struct {
 	stream.BaseOperator
 	Builders []expr.AggregatorBuilder
-	E        expr.Expr
+	Exprs    []expr.Expr
 }
 
 // GroupAggregate consumes the incoming stream and outputs one value per group.
 // It assumes the stream is sorted by groupBy.
 func GroupAggregate(groupBy expr.Expr, builders ...expr.AggregatorBuilder) *GroupAggregateOperator {
-	return &GroupAggregateOperator{E: groupBy, Builders: builders}
+	var exprs []expr.Expr
+	if groupBy != nil {
+		exprs = []expr.Expr{groupBy}
+	}
+
+	return &GroupAggregateOperator{Exprs: exprs, Builders: builders}
+}
+
+// GroupAggregateBy does the same as GroupAggregate but groups the stream
+// by multiple expressions. It assumes the stream is sorted by all of them.
+func GroupAggregateBy(groupBy []expr.Expr, builders ...expr.AggregatorBuilder) *GroupAggregateOperator {
+	return &GroupAggregateOperator{Exprs: groupBy, Builders: builders}
 }
 
 func (op *GroupAggregateOperator) Iterate(in *environment.Environment, f func(out *environment.Environment) error) error {
-	var lastGroup types.Value
+	var lastGroup []types.Value
 	var ga *groupAggregator
 
-	var groupExpr string
-	if op.E != nil {
-		groupExpr = op.E.String()
+	groupExprs := make([]string, len(op.Exprs))
+	for i, e := range op.Exprs {
+		groupExprs[i] = e.String()
 	}
 
+	group := make([]types.Value, len(op.Exprs))
+
 	err := op.Prev.Iterate(in, func(out *environment.Environment) error {
-		if op.E == nil {
+		if len(op.Exprs) == 0 {
 			if ga == nil {
-				ga = newGroupAggregator(nil, groupExpr, op.Builders)
+				ga = newGroupAggregator(nil, nil, op.Builders)
 			}
 
 			return ga.Aggregate(out)
 		}
 
-		group, err := op.E.Eval(out)
-		if err != nil {
-			return err
+		var err error
+		for i, e := range op.Exprs {
+			group[i], err = e.Eval(out)
+			if err != nil {
+				return err
+			}
 		}
 
 		// handle the first object of the stream
 		if lastGroup == nil {
-			lastGroup, err = object.CloneValue(group)
+			lastGroup, err = cloneGroup(group)
 			if err != nil {
 				return err
 			}
-			ga = newGroupAggregator(lastGroup, groupExpr, op.Builders)
+			ga = newGroupAggregator(lastGroup, groupExprs, op.Builders)
 			return ga.Aggregate(out)
 		}
 
-		ok, err := lastGroup.EQ(group)
+		ok, err := groupEqual(lastGroup, group)
 		if err != nil {
 			return err
 		}
@@ -74,12 +90,12 @@ func (op *GroupAggregateOperator) Iterate(in *environment.Environment, f func(ou
 			return err
 		}
 
-		lastGroup, err = object.CloneValue(group)
+		lastGroup, err = cloneGroup(group)
 		if err != nil {
 			return err
 		}
 
-		ga = newGroupAggregator(lastGroup, groupExpr, op.Builders)
+		ga = newGroupAggregator(lastGroup, groupExprs, op.Builders)
 		return ga.Aggregate(out)
 	})
 	if err != nil {
@@ -92,7 +108,7 @@ func (op *GroupAggregateOperator) Iterate(in *environment.Environment, f func(ou
 	// we want the following result:
 	// {"COUNT(*)": 0}
 	if ga == nil {
-		ga = newGroupAggregator(nil, "", op.Builders)
+		ga = newGroupAggregator(nil, nil, op.Builders)
 	}
 
 	e, err := ga.Flush(in)
@@ -102,14 +118,44 @@ func (op *GroupAggregateOperator) Iterate(in *environment.Environment, f func(ou
 	return f(e)
 }
 
+// cloneGroup returns a copy of the values of a group.
+func cloneGroup(group []types.Value) ([]types.Value, error) {
+	cp := make([]types.Value, len(group))
+
+	var err error
+	for i, v := range group {
+		cp[i], err = object.CloneValue(v)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return cp, nil
+}
+
+// groupEqual returns true if both groups have the same values.
+func groupEqual(a, b []types.Value) (bool, error) {
+	for i := range a {
+		ok, err := a[i].EQ(b[i])
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
 func (op *GroupAggregateOperator) String() string {
 	var sb strings.Builder
 
 	sb.WriteString("rows.GroupAggregate(")
-	if op.E != nil {
-		sb.WriteString(op.E.String())
-	} else {
+	switch len(op.Exprs) {
+	case 0:
 		sb.WriteString("NULL")
+	case 1:
+		sb.WriteString(op.Exprs[0].String())
+	default:
+		sb.WriteString(expr.LiteralExprList(op.Exprs).String())
 	}
 
 	for _, agg := range op.Builders {
@@ -125,12 +171,12 @@ func (op *GroupAggregateOperator) String() string {
 // It applies all the aggregators for each objects and returns a new object with the
 // result of the aggregation.
 type groupAggregator struct {
-	group       types.Value
-	groupExpr   string
+	group       []types.Value
+	groupExprs  []string
 	aggregators []expr.Aggregator
 }
 
-func newGroupAggregator(group types.Value, groupExpr string, builders []expr.AggregatorBuilder) *groupAggregator {
+func newGroupAggregator(group []types.Value, groupExprs []string, builders []expr.AggregatorBuilder) *groupAggregator {
 	newAggregators := make([]expr.Aggregator, len(builders))
 	for i, b := range builders {
 		newAggregators[i] = b.Aggregator()
@@ -139,7 +185,7 @@ func newGroupAggregator(group types.Value, groupExpr string, builders []expr.Agg
 	return &groupAggregator{
 		aggregators: newAggregators,
 		group:       group,
-		groupExpr:   groupExpr,
+		groupExprs:  groupExprs,
 	}
 }
 
@@ -157,9 +203,9 @@ func (g *groupAggregator) Aggregate(env *environment.Environment) error {
 func (g *groupAggregator) Flush(env *environment.Environment) (*environment.Environment, error) {
 	fb := object.NewFieldBuffer()
 
-	// add the current group to the object
-	if g.groupExpr != "" {
-		fb.Add(g.groupExpr, g.group)
+	// add the values of the current group to the object
+	for i, v := range g.group {
+		fb.Add(g.groupExprs[i], v)
 	}
 
 	for _, agg := range g.aggregators {
@@ -113,7 +113,50 @@ func TestAggregate(t *testing.T) {
 		})
 	}
 
+	t.Run("multiple groupBy", func(t *testing.T) {
+		db, tx, cleanup := testutil.NewTestTx(t)
+		defer cleanup()
+
+		testutil.MustExec(t, db, tx, "CREATE TABLE test(a int)")
+
+		for _, doc := range generateSeqDocs(t, 6) {
+			testutil.MustExec(t, db, tx, "INSERT INTO test VALUES ?", environment.Param{Value: doc})
+		}
+
+		var env environment.Environment
+		env.DB = db
+		env.Tx = tx
+
+		groupBy := []expr.Expr{parser.MustParseExpr("a % 2"), parser.MustParseExpr("a < 3")}
+		s := stream.New(table.Scan("test")).
+			Pipe(rows.TempTreeSort(groupBy...)).
+			Pipe(rows.GroupAggregateBy(groupBy, functions.NewCount(expr.Wildcard{})))
+
+		var got []types.Object
+		err := s.Iterate(&env, func(env *environment.Environment) error {
+			r, ok := env.GetRow()
+			require.True(t, ok)
+			var fb object.FieldBuffer
+			fb.Copy(r.Object())
+			got = append(got, &fb)
+			return nil
+		})
+		assert.NoError(t, err)
+
+		want := testutil.MakeObjects(t,
+			`{"a % 2": 0, "a < 3": false, "COUNT(*)": 1}`,
+			`{"a % 2": 0, "a < 3": true, "COUNT(*)": 2}`,
+			`{"a % 2": 1, "a < 3": false, "COUNT(*)": 2}`,
+			`{"a % 2": 1, "a < 3": true, "COUNT(*)": 1}`,
+		)
+		require.Equal(t, len(want), len(got))
+		for i, doc := range want {
+			testutil.RequireObjEqual(t, doc, got[i])
+		}
+	})
+
 	t.Run("String", func(t *testing.T) {
+		require.Equal(t, `rows.GroupAggregate([a, b], a())`, rows.GroupAggregateBy([]expr.Expr{parser.MustParseExpr("a"), parser.MustParseExpr("b")}, makeAggregatorBuilders("a()")...).String())
 		require.Equal(t, `rows.GroupAggregate(a % 2, a(), b())`, rows.GroupAggregate(parser.MustParseExpr("a % 2"), makeAggregatorBuilders("a()", "b()")...).String())
 		require.Equal(t, `rows.GroupAggregate(NULL, a(), b())`, rows.GroupAggregate(nil, makeAggregatorBuilders("a()", "b()")...).String())
 		require.Equal(t, `rows.GroupAggregate(a % 2)`, rows.GroupAggregate(parser.MustParseExpr("a % 2")).String())
@@ -40,14 +40,24 @@ func (op *TakeOperator) Iterate(in *environment.Environment, f func(out *environ
 
 	n := types.AsInt64(v)
 	var count int64
-	return op.Prev.Iterate(in, func(out *environment.Environment) error {
+	// closed is true if the stream was closed by this operator,
+	// in which case the error must not be propagated to the
+	// operators this one is piped into.
+	var closed bool
+	err = op.Prev.Iterate(in, func(out *environment.Environment) error {
 		if count < n {
 			count++
 			return f(out)
 		}
 
+		closed = true
 		return errors.WithStack(stream.ErrStreamClosed)
 	})
+	if closed && errors.Is(err, stream.ErrStreamClosed) {
+		return nil
+	}
+
+	return err
 }
 
 func (op *TakeOperator) String() string {
@@ -1,13 +1,15 @@
 package rows
 
 import (
-	"fmt"
+	"strings"
 
 	"github.com/chaisql/chai/internal/database"
 	"github.com/chaisql/chai/internal/encoding"
 	"github.com/chaisql/chai/internal/environment"
 	"github.com/chaisql/chai/internal/expr"
+	"github.com/chaisql/chai/internal/object"
 	"github.com/chaisql/chai/internal/stream"
+	"github.com/chaisql/chai/internal/stream/join"
 	"github.com/chaisql/chai/internal/tree"
 	"github.com/chaisql/chai/internal/types"
 	"github.com/cockroachdb/errors"
@@ -16,19 +18,36 @@ import (
 // A TempTreeSortOperator consumes every value of the stream and outputs them in order.
 type TempTreeSortOperator struct {
 	stream.BaseOperator
-	Expr expr.Expr
-	Desc bool
+	Exprs []expr.Expr
+	// Order holds the direction of each expression.
+	Order tree.SortOrder
 }
 
-// TempTreeSort consumes every value of the stream, sorts them by the given expr and outputs them in order.
+// MaxSortExprs is the maximum number of expressions a TempTreeSortOperator can sort by:
+// the direction of each value of the keys of the temporary tree is stored in a tree.SortOrder,
+// which supports 64 values, and the keys end with 4 values identifying the row.
+const MaxSortExprs = 60
+
+// TempTreeSort consumes every value of the stream, sorts them by the given exprs and outputs them in order.
 // It creates a temporary index and uses it to sort the stream.
-func TempTreeSort(e expr.Expr) *TempTreeSortOperator {
-	return &TempTreeSortOperator{Expr: e}
+func TempTreeSort(exprs ...expr.Expr) *TempTreeSortOperator {
+	return &TempTreeSortOperator{Exprs: exprs}
 }
 
 // TempTreeSortReverse does the same as TempTreeSort but in descending order.
-func TempTreeSortReverse(e expr.Expr) *TempTreeSortOperator {
-	return &TempTreeSortOperator{Expr: e, Desc: true}
+func TempTreeSortReverse(exprs ...expr.Expr) *TempTreeSortOperator {
+	var order tree.SortOrder
+	for i := range exprs {
+		order = order.SetDesc(i)
+	}
+
+	return &TempTreeSortOperator{Exprs: exprs, Order: order}
+}
+
+// TempTreeSortWithOrder does the same as TempTreeSort but sorts each expression
+// in the direction specified by order.
+func TempTreeSortWithOrder(exprs []expr.Expr, order tree.SortOrder) *TempTreeSortOperator {
+	return &TempTreeSortOperator{Exprs: exprs, Order: order}
 }
 
 func (op *TempTreeSortOperator) Iterate(in *environment.Environment, fn func(out *environment.Environment) error) error {
@@ -36,7 +55,7 @@ func (op *TempTreeSortOperator) Iterate(in *environment.Environment, fn func(out
 
 	catalog := in.GetTx().Catalog
 	tns := catalog.GetFreeTransientNamespace()
-	tr, cleanup, err := tree.NewTransient(db.Engine.NewTransientSession(), tns, 0)
+	tr, cleanup, err := tree.NewTransient(db.Engine.NewTransientSession(), tns, op.Order)
 	if err != nil {
 		return err
 	}
@@ -45,23 +64,30 @@ func (op *TempTreeSortOperator) Iterate(in *environment.Environment, fn func(out
 	var counter int64
 
 	var buf []byte
+	// the key is composed of the sort values,
+	// followed by the table name, the row key, the encoding of the row and a counter
+	values := make([]types.Value, len(op.Exprs)+4)
 	err = op.Prev.Iterate(in, func(out *environment.Environment) error {
 		buf = buf[:0]
-		// evaluate the sort expression
-		v, err := op.Expr.Eval(out)
-		if err != nil {
-			return err
-		}
-
-		if types.IsNull(v) {
-			// the expression might be pointing to the original row.
-			v, err = op.Expr.Eval(out.Outer)
+		// evaluate the sort expressions
+		for i, e := range op.Exprs {
+			v, err := e.Eval(out)
 			if err != nil {
-				// the only valid error here is a missing field.
-				if !errors.Is(err, types.ErrFieldNotFound) {
-					return err
+				return err
+			}
+
+			if types.IsNull(v) {
+				// the expression might be pointing to the original row.
+				v, err = e.Eval(out.Outer)
+				if err != nil {
+					// the only valid error here is a missing field.
+					if !errors.Is(err, types.ErrFieldNotFound) {
+						return err
+					}
 				}
 			}
+
+			values[i] = v
 		}
 
 		row, ok := out.GetRow()
@@ -75,28 +101,50 @@ func (op *TempTreeSortOperator) Iterate(in *environment.Environment, fn func(out
 			if err != nil {
 				return err
 			}
+		}
 
-			buf, err = info.EncodeObject(in.GetTx(), buf, row.Object())
-			if err != nil {
-				return err
-			}
-		} else {
-			buf, err = encoding.EncodeObject(buf, row.Object())
-			if err != nil {
-				return err
+		// projected rows and rows extended by window functions may contain columns
+		// that are not part of the table, they must not be encoded using the table schema.
+		// The same goes for rows that don't belong to any table.
+		// Joined rows are encoded source by source to preserve their qualifiers.
+		kind := sortedTableRow
+		switch row.(type) {
+		case *RowMask, *windowRow:
+			kind = sortedProjectedRow
+		case *join.Row:
+			kind = sortedJoinedRow
+		default:
+			if info == nil {
+				kind = sortedProjectedRow
 			}
 		}
+		switch kind {
+		case sortedTableRow:
+			buf, err = info.EncodeObject(in.GetTx(), buf, row.Object())
+		case sortedProjectedRow:
+			buf, err = encodeProjectedObject(buf, row.Object())
+		case sortedJoinedRow:
+			buf, err = encodeJoinedRow(buf, catalog, row.(*join.Row))
+		}
+		if err != nil {
+			return err
+		}
 
 		var encKey []byte
 		key := row.Key()
-		if key != nil {
+		if key != nil && info != nil {
 			encKey, err = info.EncodeKey(key)
 			if err != nil {
 				return err
 			}
 		}
 
-		tk := tree.NewKey(v, types.NewTextValue(row.TableName()), types.NewBlobValue(encKey), types.NewIntegerValue(counter))
+		n := len(op.Exprs)
+		values[n] = types.NewTextValue(row.TableName())
+		values[n+1] = types.NewBlobValue(encKey)
+		values[n+2] = types.NewIntegerValue(kind)
+		values[n+3] = types.NewIntegerValue(counter)
+		tk := tree.NewKey(values...)
 
 		counter++
 
@@ -109,48 +157,212 @@ func (op *TempTreeSortOperator) Iterate(in *environment.Environment, fn func(out
 	var newEnv environment.Environment
 	newEnv.SetOuter(in)
 	var br database.BasicRow
-	return tr.IterateOnRange(nil, op.Desc, func(k *tree.Key, data []byte) error {
+	return tr.IterateOnRange(nil, false, func(k *tree.Key, data []byte) error {
 		kv, err := k.Decode()
 		if err != nil {
 			return err
 		}
 
+		n := len(op.Exprs)
+
 		var tableName string
-		tf := kv[1]
+		tf := kv[n]
 		if tf.Type() != types.TypeNull {
 			tableName = types.AsString(tf)
 		}
 
 		var key *tree.Key
-		kf := kv[2]
+		kf := kv[n+1]
 		if kf.Type() != types.TypeNull {
 			key = tree.NewEncodedKey(types.AsByteSlice(kf))
 		}
 
-		var obj types.Object
-
-		if tableName != "" {
+		switch types.AsInt64(kv[n+2]) {
+		case sortedTableRow:
 			info, err := catalog.GetTableInfo(tableName)
 			if err != nil {
 				return err
 			}
-			obj = database.NewEncodedObject(&info.FieldConstraints, data)
-		} else {
-			obj = encoding.DecodeObject(data, false /* intAsDouble */)
+			br.ResetWith(tableName, key, database.NewEncodedObject(&info.FieldConstraints, data))
+			newEnv.SetRow(&br)
+		case sortedProjectedRow:
+			obj, _, err := decodeProjectedObject(data)
+			if err != nil {
+				return err
+			}
+			br.ResetWith(tableName, key, obj)
+			newEnv.SetRow(&br)
+		case sortedJoinedRow:
+			r, err := decodeJoinedRow(data)
+			if err != nil {
+				return err
+			}
+			newEnv.SetRow(r)
+		}
+
+		return fn(&newEnv)
+	})
+}
+
+// the way a row is encoded in the temporary tree of a TempTreeSortOperator.
+const (
+	sortedTableRow int64 = iota
+	sortedProjectedRow
+	sortedJoinedRow
+)
+
+// encodeJoinedRow encodes each source of a joined row: its name,
+// followed by either NULL for the missing side of a LEFT JOIN,
+// or the table name, the encoded key and the columns of the row.
+func encodeJoinedRow(dst []byte, catalog *database.Catalog, r *join.Row) ([]byte, error) {
+	names, rows := r.Sources()
+	for i, row := range rows {
+		dst = encoding.EncodeText(dst, names[i])
+		if row == nil {
+			dst = encoding.EncodeNull(dst)
+			continue
+		}
+
+		var encKey []byte
+		if row.Key() != nil && row.TableName() != "" {
+			info, err := catalog.GetTableInfo(row.TableName())
+			if err != nil {
+				return nil, err
+			}
+			encKey, err = info.EncodeKey(row.Key())
+			if err != nil {
+				return nil, err
+			}
 		}
 
+		var err error
+		dst = encoding.EncodeText(dst, row.TableName())
+		dst = encoding.EncodeBlob(dst, encKey)
+		dst, err = encodeProjectedObject(dst, row.Object())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return dst, nil
+}
+
+// decodeJoinedRow decodes a row encoded by encodeJoinedRow.
+func decodeJoinedRow(data []byte) (*join.Row, error) {
+	var names []string
+	var rows []database.Row
+
+	for len(data) > 0 {
+		name, n := encoding.DecodeText(data)
+		data = data[n:]
+		names = append(names, name)
+
+		if data[0] == encoding.NullValue {
+			data = data[1:]
+			rows = append(rows, nil)
+			continue
+		}
+
+		tableName, n := encoding.DecodeText(data)
+		data = data[n:]
+
+		encKey, n := encoding.DecodeBlob(data)
+		data = data[n:]
+		var key *tree.Key
+		if len(encKey) > 0 {
+			key = tree.NewEncodedKey(encKey)
+		}
+
+		obj, n, err := decodeProjectedObject(data)
+		if err != nil {
+			return nil, err
+		}
+		data = data[n:]
+
+		var br database.BasicRow
 		br.ResetWith(tableName, key, obj)
+		rows = append(rows, &br)
+	}
 
-		newEnv.SetRow(&br)
+	return join.NewRow(names, rows), nil
+}
 
-		return fn(&newEnv)
+// encodeProjectedObject encodes the object followed by the type of each of its values,
+// to be able to restore the types that are not preserved by the encoding, i.e. timestamps.
+func encodeProjectedObject(dst []byte, o types.Object) ([]byte, error) {
+	dst, err := encoding.EncodeObject(dst, o)
+	if err != nil {
+		return nil, err
+	}
+
+	var tps []byte
+	err = o.Iterate(func(field string, value types.Value) error {
+		tps = append(tps, byte(value.Type()))
+		return nil
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	return encoding.EncodeBlob(dst, tps), nil
+}
+
+// decodeProjectedObject decodes an object encoded by encodeProjectedObject
+// and returns the number of bytes read.
+func decodeProjectedObject(data []byte) (types.Object, int, error) {
+	n := encoding.SkipObject(data[1:]) + 1
+	tps, m := encoding.DecodeBlob(data[n:])
+
+	fb := object.NewFieldBuffer()
+	var i int
+	err := encoding.DecodeObject(data[:n], false /* intAsDouble */).Iterate(func(field string, value types.Value) error {
+		v, err := encoding.ConvertFromStoreTo(value, types.Type(tps[i]))
+		if err != nil {
+			return err
+		}
+		i++
+
+		fb.Add(field, v)
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return fb, n + m, nil
+}
+
+// IsDesc returns true if all the expressions are sorted in descending order.
+func (op *TempTreeSortOperator) IsDesc() bool {
+	for i := range op.Exprs {
+		if !op.Order.IsDesc(i) {
+			return false
+		}
+	}
+
+	return len(op.Exprs) > 0
 }
 
 func (op *TempTreeSortOperator) String() string {
-	if op.Desc {
-		return fmt.Sprintf("rows.TempTreeSortReverse(%s)", op.Expr)
+	var sb strings.Builder
+
+	desc := op.IsDesc()
+	if desc {
+		sb.WriteString("rows.TempTreeSortReverse(")
+	} else {
+		sb.WriteString("rows.TempTreeSort(")
+	}
+
+	for i, e := range op.Exprs {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(e.String())
+		if !desc && op.Order.IsDesc(i) {
+			sb.WriteString(" DESC")
+		}
 	}
 
-	return fmt.Sprintf("rows.TempTreeSort(%s)", op.Expr)
+	sb.WriteString(")")
+	return sb.String()
 }
@@ -12,6 +12,7 @@ import (
 	"github.com/chaisql/chai/internal/stream/table"
 	"github.com/chaisql/chai/internal/testutil"
 	"github.com/chaisql/chai/internal/testutil/assert"
+	"github.com/chaisql/chai/internal/tree"
 	"github.com/chaisql/chai/internal/types"
 	"github.com/stretchr/testify/require"
 )
@@ -104,7 +105,50 @@ func TestTempTreeSort(t *testing.T) {
 		})
 	}
 
+	t.Run("Multiple", func(t *testing.T) {
+		db, tx, cleanup := testutil.NewTestTx(t)
+		defer cleanup()
+
+		testutil.MustExec(t, db, tx, `
+			CREATE TABLE test(a int, b int);
+			INSERT INTO test (a, b) VALUES (1, 1), (2, 1), (1, 2), (2, 2), (1, 3);
+		`)
+
+		var env environment.Environment
+		env.DB = db
+		env.Tx = tx
+
+		s := stream.New(table.Scan("test")).
+			Pipe(rows.TempTreeSortWithOrder(
+				[]expr.Expr{parser.MustParseExpr("a"), parser.MustParseExpr("b * 10")},
+				tree.SortOrder(0).SetDesc(1),
+			))
+
+		var got []string
+		err := s.Iterate(&env, func(env *environment.Environment) error {
+			r, ok := env.GetRow()
+			require.True(t, ok)
+
+			b, err := object.MarshalJSON(r.Object())
+			if err != nil {
+				return err
+			}
+			got = append(got, string(b))
+			return nil
+		})
+		assert.NoError(t, err)
+		require.Equal(t, []string{
+			`{"a": 1, "b": 3}`,
+			`{"a": 1, "b": 2}`,
+			`{"a": 1, "b": 1}`,
+			`{"a": 2, "b": 2}`,
+			`{"a": 2, "b": 1}`,
+		}, got)
+	})
+
 	t.Run("String", func(t *testing.T) {
 		require.Equal(t, `rows.TempTreeSort(a)`, rows.TempTreeSort(parser.MustParseExpr("a")).String())
+		require.Equal(t, `rows.TempTreeSortReverse(a, b)`, rows.TempTreeSortReverse(parser.MustParseExpr("a"), parser.MustParseExpr("b")).String())
+		require.Equal(t, `rows.TempTreeSort(a, b + 1 DESC)`, rows.TempTreeSortWithOrder([]expr.Expr{parser.MustParseExpr("a"), parser.MustParseExpr("b + 1")}, tree.SortOrder(0).SetDesc(1)).String())
 	})
 }
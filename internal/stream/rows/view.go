@@ -0,0 +1,32 @@
+package rows
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/chaisql/chai/internal/environment"
+	"github.com/chaisql/chai/internal/stream"
+)
+
+// A ViewOperator iterates over the rows of a view.
+type ViewOperator struct {
+	stream.BaseOperator
+	Name string
+	// Stream is the prepared query of the view.
+	Stream *stream.Stream
+}
+
+// View creates an operator that iterates over the rows returned by the query of the given view.
+// The planner replaces it by the operators of the query when possible.
+func View(name string, s *stream.Stream) *ViewOperator {
+	return &ViewOperator{Name: name, Stream: s}
+}
+
+// Iterate implements the Operator interface.
+func (op *ViewOperator) Iterate(in *environment.Environment, fn func(out *environment.Environment) error) error {
+	return op.Stream.Iterate(in, fn)
+}
+
+func (op *ViewOperator) String() string {
+	return fmt.Sprintf("rows.View(%s)", strconv.Quote(op.Name))
+}
@@ -0,0 +1,317 @@
+package rows
+
+import (
+	"strings"
+
+	"github.com/chaisql/chai/internal/database"
+	"github.com/chaisql/chai/internal/environment"
+	"github.com/chaisql/chai/internal/expr"
+	"github.com/chaisql/chai/internal/object"
+	"github.com/chaisql/chai/internal/stream"
+	"github.com/chaisql/chai/internal/stream/join"
+	"github.com/chaisql/chai/internal/tree"
+	"github.com/chaisql/chai/internal/types"
+	"github.com/cockroachdb/errors"
+)
+
+// A WindowOperator computes the value of window functions
+// sharing the same window definition.
+type WindowOperator struct {
+	stream.BaseOperator
+	Window *expr.WindowDefinition
+	Exprs  []*expr.Over
+}
+
+// Window consumes the incoming stream, one partition at a time, and outputs every row
+// along with the value of each window function, stored under its name.
+// It assumes the stream is sorted by the PARTITION BY expressions, then by the ORDER BY expressions
+// of the window definition.
+func Window(w *expr.WindowDefinition, exprs ...*expr.Over) *WindowOperator {
+	return &WindowOperator{Window: w, Exprs: exprs}
+}
+
+// Iterate implements the Operator interface.
+func (op *WindowOperator) Iterate(in *environment.Environment, fn func(out *environment.Environment) error) error {
+	var p partition
+	var lastPartition []types.Value
+
+	values := make([]types.Value, len(op.Window.PartitionBy))
+
+	err := op.Prev.Iterate(in, func(out *environment.Environment) error {
+		var err error
+		for i, e := range op.Window.PartitionBy {
+			values[i], err = e.Eval(out)
+			if err != nil {
+				return err
+			}
+		}
+
+		if lastPartition != nil {
+			ok, err := groupEqual(lastPartition, values)
+			if err != nil {
+				return err
+			}
+
+			// if the row is from a different partition, we emit the rows of the previous one
+			if !ok {
+				err = op.flush(&p, fn)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		lastPartition, err = cloneGroup(values)
+		if err != nil {
+			return err
+		}
+
+		return p.add(in, out, op.Window.OrderBy)
+	})
+	if err != nil {
+		return err
+	}
+
+	return op.flush(&p, fn)
+}
+
+// flush computes the window functions for every row of the partition,
+// emits them and resets the partition.
+func (op *WindowOperator) flush(p *partition, fn func(out *environment.Environment) error) error {
+	defer p.reset()
+
+	aggregators := make([]expr.Aggregator, len(op.Exprs))
+	// number of rows aggregated by each aggregator, when the frame
+	// starts at the beginning of the partition.
+	aggregated := make([]int, len(op.Exprs))
+
+	w := expr.Window{
+		Rows: p.rows,
+	}
+
+	peerEnd := 0
+	for i := range p.rows {
+		// determine the peers of the current row
+		if i == peerEnd {
+			if i > 0 {
+				w.PeerGroup++
+			}
+			w.PeerStart = i
+			peerEnd = i + 1
+			for peerEnd < len(p.rows) {
+				ok, err := groupEqual(p.orderValues[i], p.orderValues[peerEnd])
+				if err != nil {
+					return err
+				}
+				if !ok {
+					break
+				}
+				peerEnd++
+			}
+		}
+		w.Current = i
+
+		lo, hi := op.frame(i, peerEnd, len(p.rows))
+
+		row := p.rows[i].Row.(*windowRow)
+		for j, o := range op.Exprs {
+			var v types.Value
+			var err error
+
+			switch t := o.Fn.(type) {
+			case expr.WindowFunction:
+				v, err = t.EvalWindow(&w)
+			case expr.AggregatorBuilder:
+				// if the frame always starts at the beginning of the partition,
+				// the rows are aggregated incrementally
+				if lo > 0 || aggregators[j] == nil {
+					aggregators[j] = t.Aggregator()
+					aggregated[j] = lo
+				}
+				for ; aggregated[j] < hi; aggregated[j]++ {
+					err = aggregators[j].Aggregate(p.rows[aggregated[j]])
+					if err != nil {
+						return err
+					}
+				}
+				v, err = aggregators[j].Eval(p.rows[i])
+			default:
+				err = errors.Errorf("%s is not a window function", o.Fn)
+			}
+			if err != nil {
+				return err
+			}
+
+			row.values.Add(o.String(), v)
+		}
+
+		err := fn(p.rows[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// frame returns the boundaries of the frame of the current row.
+func (op *WindowOperator) frame(cur, peerEnd, n int) (int, int) {
+	if op.Window.Frame != nil {
+		return op.Window.Frame.Bounds(cur, n)
+	}
+
+	// by default, the frame goes from the beginning of the partition
+	// to the last peer of the current row
+	if len(op.Window.OrderBy) > 0 {
+		return 0, peerEnd
+	}
+
+	return 0, n
+}
+
+func (op *WindowOperator) String() string {
+	var sb strings.Builder
+
+	sb.WriteString("rows.Window(")
+	for i, e := range op.Exprs {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(e.String())
+	}
+	sb.WriteString(")")
+
+	return sb.String()
+}
+
+// a partition holds a copy of all the rows of a partition
+// along with the values of their ORDER BY expressions.
+type partition struct {
+	rows        []*environment.Environment
+	orderValues [][]types.Value
+}
+
+func (p *partition) add(in, out *environment.Environment, orderBy []expr.Expr) error {
+	r, ok := out.GetRow()
+	if !ok {
+		return errors.New("missing row")
+	}
+
+	// rows are reused by the previous operators, copy them
+	cr, err := cloneRow(r)
+	if err != nil {
+		return err
+	}
+
+	row := windowRow{
+		Row:    cr,
+		values: object.NewFieldBuffer(),
+	}
+
+	var env environment.Environment
+	env.SetOuter(in)
+	env.SetRow(&row)
+
+	values := make([]types.Value, len(orderBy))
+	for i, e := range orderBy {
+		v, err := e.Eval(&env)
+		if err != nil {
+			return err
+		}
+
+		values[i], err = object.CloneValue(v)
+		if err != nil {
+			return err
+		}
+	}
+
+	p.rows = append(p.rows, &env)
+	p.orderValues = append(p.orderValues, values)
+	return nil
+}
+
+func (p *partition) reset() {
+	p.rows = p.rows[:0]
+	p.orderValues = p.orderValues[:0]
+}
+
+// cloneRow returns a copy of the row that can be used
+// after the iteration of the previous operator moves on.
+// The sources of joined rows are copied separately.
+func cloneRow(r database.Row) (database.Row, error) {
+	if jr, ok := r.(*join.Row); ok {
+		names, rows := jr.Sources()
+		cloned := make([]database.Row, len(rows))
+		for i, row := range rows {
+			if row == nil {
+				continue
+			}
+
+			var err error
+			cloned[i], err = cloneRow(row)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return join.NewRow(append([]string{}, names...), cloned), nil
+	}
+
+	fb := object.NewFieldBuffer()
+	err := fb.Copy(r.Object())
+	if err != nil {
+		return nil, err
+	}
+
+	var br database.BasicRow
+	br.ResetWith(r.TableName(), cloneKey(r.Key()), fb)
+	return &br, nil
+}
+
+// cloneKey returns a copy of the key that can be used
+// after the iteration of the previous operator moves on.
+func cloneKey(k *tree.Key) *tree.Key {
+	if k == nil || k.Encoded == nil {
+		return k
+	}
+
+	return tree.NewEncodedKey(append([]byte{}, k.Encoded...))
+}
+
+// a windowRow is a row of the source stream extended
+// with the values computed by window functions.
+type windowRow struct {
+	database.Row
+
+	values *object.FieldBuffer
+}
+
+func (r *windowRow) Iterate(fn func(column string, value types.Value) error) error {
+	err := r.Row.Iterate(fn)
+	if err != nil {
+		return err
+	}
+
+	return r.values.Iterate(fn)
+}
+
+func (r *windowRow) Get(column string) (types.Value, error) {
+	v, err := r.values.GetByField(column)
+	if err == nil || !errors.Is(err, types.ErrFieldNotFound) {
+		return v, err
+	}
+
+	return r.Row.Get(column)
+}
+
+func (r *windowRow) MarshalJSON() ([]byte, error) {
+	return object.MarshalJSON(r.Object())
+}
+
+func (r *windowRow) Object() types.Object {
+	return r
+}
+
+func (r *windowRow) GetByField(field string) (types.Value, error) {
+	return r.Get(field)
+}
@@ -0,0 +1,124 @@
+package rows_test
+
+import (
+	"testing"
+
+	"github.com/chaisql/chai/internal/environment"
+	"github.com/chaisql/chai/internal/expr"
+	"github.com/chaisql/chai/internal/object"
+	"github.com/chaisql/chai/internal/sql/parser"
+	"github.com/chaisql/chai/internal/stream"
+	"github.com/chaisql/chai/internal/stream/rows"
+	"github.com/chaisql/chai/internal/stream/table"
+	"github.com/chaisql/chai/internal/testutil"
+	"github.com/chaisql/chai/internal/testutil/assert"
+	"github.com/chaisql/chai/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWindow(t *testing.T) {
+	tests := []struct {
+		name string
+		over string
+		want []string
+	}{
+		{
+			"row_number",
+			"row_number() OVER (PARTITION BY a % 2 ORDER BY a)",
+			[]string{
+				`{"a": 0, "ROW_NUMBER() OVER (PARTITION BY a % 2 ORDER BY a)": 1}`,
+				`{"a": 2, "ROW_NUMBER() OVER (PARTITION BY a % 2 ORDER BY a)": 2}`,
+				`{"a": 1, "ROW_NUMBER() OVER (PARTITION BY a % 2 ORDER BY a)": 1}`,
+				`{"a": 3, "ROW_NUMBER() OVER (PARTITION BY a % 2 ORDER BY a)": 2}`,
+			},
+		},
+		{
+			"rank",
+			"rank() OVER (ORDER BY a / 2)",
+			[]string{
+				`{"a": 0, "RANK() OVER (ORDER BY a / 2)": 1}`,
+				`{"a": 1, "RANK() OVER (ORDER BY a / 2)": 1}`,
+				`{"a": 2, "RANK() OVER (ORDER BY a / 2)": 3}`,
+				`{"a": 3, "RANK() OVER (ORDER BY a / 2)": 3}`,
+			},
+		},
+		{
+			"lead",
+			"lead(a) OVER (ORDER BY a)",
+			[]string{
+				`{"a": 0, "LEAD(a) OVER (ORDER BY a)": 1}`,
+				`{"a": 1, "LEAD(a) OVER (ORDER BY a)": 2}`,
+				`{"a": 2, "LEAD(a) OVER (ORDER BY a)": 3}`,
+				`{"a": 3, "LEAD(a) OVER (ORDER BY a)": null}`,
+			},
+		},
+		{
+			"running sum",
+			"sum(a) OVER (ORDER BY a)",
+			[]string{
+				`{"a": 0, "SUM(a) OVER (ORDER BY a)": 0}`,
+				`{"a": 1, "SUM(a) OVER (ORDER BY a)": 1}`,
+				`{"a": 2, "SUM(a) OVER (ORDER BY a)": 3}`,
+				`{"a": 3, "SUM(a) OVER (ORDER BY a)": 6}`,
+			},
+		},
+		{
+			"sliding frame",
+			"sum(a) OVER (ORDER BY a ROWS BETWEEN CURRENT ROW AND 1 FOLLOWING)",
+			[]string{
+				`{"a": 0, "SUM(a) OVER (ORDER BY a ROWS BETWEEN CURRENT ROW AND 1 FOLLOWING)": 1}`,
+				`{"a": 1, "SUM(a) OVER (ORDER BY a ROWS BETWEEN CURRENT ROW AND 1 FOLLOWING)": 3}`,
+				`{"a": 2, "SUM(a) OVER (ORDER BY a ROWS BETWEEN CURRENT ROW AND 1 FOLLOWING)": 5}`,
+				`{"a": 3, "SUM(a) OVER (ORDER BY a ROWS BETWEEN CURRENT ROW AND 1 FOLLOWING)": 3}`,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, tx, cleanup := testutil.NewTestTx(t)
+			defer cleanup()
+
+			testutil.MustExec(t, db, tx, "CREATE TABLE test(a int)")
+
+			for _, doc := range generateSeqDocs(t, 4) {
+				testutil.MustExec(t, db, tx, "INSERT INTO test VALUES ?", environment.Param{Value: doc})
+			}
+
+			var env environment.Environment
+			env.DB = db
+			env.Tx = tx
+
+			o := parser.MustParseExpr(test.over).(*expr.Over)
+			sortExprs := append(append([]expr.Expr{}, o.Window.PartitionBy...), o.Window.OrderBy...)
+			s := stream.New(table.Scan("test")).
+				Pipe(rows.TempTreeSort(sortExprs...)).
+				Pipe(rows.Window(o.Window, o))
+
+			var got []types.Object
+			err := s.Iterate(&env, func(env *environment.Environment) error {
+				r, ok := env.GetRow()
+				require.True(t, ok)
+				var fb object.FieldBuffer
+				err := fb.Copy(r.Object())
+				if err != nil {
+					return err
+				}
+				got = append(got, &fb)
+				return nil
+			})
+			assert.NoError(t, err)
+
+			want := testutil.MakeObjects(t, test.want...)
+			require.Equal(t, len(want), len(got))
+			for i, doc := range want {
+				testutil.RequireObjEqual(t, doc, got[i])
+			}
+		})
+	}
+
+	t.Run("String", func(t *testing.T) {
+		o := parser.MustParseExpr("sum(a) OVER (PARTITION BY b ORDER BY c DESC)").(*expr.Over)
+		require.Equal(t, `rows.Window(SUM(a) OVER (PARTITION BY b ORDER BY c DESC))`, rows.Window(o.Window, o).String())
+	})
+}
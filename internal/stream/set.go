@@ -0,0 +1,266 @@
+package stream
+
+import (
+	"errors"
+	"math"
+
+	"github.com/chaisql/chai/internal/database"
+	"github.com/chaisql/chai/internal/encoding"
+	"github.com/chaisql/chai/internal/engine"
+	"github.com/chaisql/chai/internal/environment"
+	"github.com/chaisql/chai/internal/object"
+	"github.com/chaisql/chai/internal/tree"
+	"github.com/chaisql/chai/internal/types"
+)
+
+// a rowCounter counts the occurrences of rows using a temporary tree.
+// The values of each row are used as the key of the tree, which deduplicates them.
+// Rows are compared by position rather than by column name, like in SQL,
+// and numbers are compared by value regardless of their type.
+// The value holds the number of occurrences of the row, the first occurrence
+// of the row and, if it has one, its key and table name.
+type rowCounter struct {
+	tree    *tree.Tree
+	cleanup func() error
+	buf     []byte
+}
+
+// a rowCount is the value associated with a row in a rowCounter.
+type rowCount struct {
+	count int64
+	// first occurrence of the row
+	row types.Object
+	// encoded key and table name of the row, if any
+	key   []byte
+	table string
+}
+
+func newRowCounter(in *environment.Environment) (*rowCounter, error) {
+	db := in.GetDB()
+	tns := in.GetTx().Catalog.GetFreeTransientNamespace()
+	t, cleanup, err := tree.NewTransient(db.Engine.NewTransientSession(), tns, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rowCounter{tree: t, cleanup: cleanup}, nil
+}
+
+// add increments the number of occurrences of the current row.
+// If all is false, rows are counted only once.
+func (rc *rowCounter) add(out *environment.Environment, all bool) error {
+	row, ok := out.GetRow()
+	if !ok {
+		return errors.New("missing row")
+	}
+
+	values, err := rowValues(row)
+	if err != nil {
+		return err
+	}
+
+	key := tree.NewKey(values...)
+	c, err := rc.get(key)
+	if err != nil {
+		return err
+	}
+
+	if c == nil {
+		c = &rowCount{row: row.Object()}
+		if row.Key() != nil {
+			info, err := out.GetTx().Catalog.GetTableInfo(row.TableName())
+			if err != nil {
+				return err
+			}
+			c.key, err = info.EncodeKey(row.Key())
+			if err != nil {
+				return err
+			}
+			c.table = row.TableName()
+		}
+	} else if !all {
+		return nil
+	}
+
+	c.count++
+	return rc.put(key, c)
+}
+
+// addFrom increments the number of occurrences of the current row,
+// up to the number of occurrences of the same row in prev.
+// Rows absent from prev are ignored.
+func (rc *rowCounter) addFrom(prev *rowCounter, out *environment.Environment, all bool) error {
+	row, ok := out.GetRow()
+	if !ok {
+		return errors.New("missing row")
+	}
+
+	// keys cache their encoding, which depends on the tree
+	// they are used with, so each tree needs its own key
+	values, err := rowValues(row)
+	if err != nil {
+		return err
+	}
+
+	p, err := prev.get(tree.NewKey(values...))
+	if err != nil || p == nil {
+		return err
+	}
+
+	key := tree.NewKey(values...)
+	c, err := rc.get(key)
+	if err != nil {
+		return err
+	}
+	if c == nil {
+		c = &rowCount{row: p.row, key: p.key, table: p.table}
+	}
+
+	if c.count >= p.count || (!all && c.count > 0) {
+		return nil
+	}
+
+	c.count++
+	return rc.put(key, c)
+}
+
+// remove decrements the number of occurrences of the current row.
+// If all is false, the row is removed entirely.
+func (rc *rowCounter) remove(out *environment.Environment, all bool) error {
+	row, ok := out.GetRow()
+	if !ok {
+		return errors.New("missing row")
+	}
+
+	values, err := rowValues(row)
+	if err != nil {
+		return err
+	}
+
+	key := tree.NewKey(values...)
+	c, err := rc.get(key)
+	if err != nil || c == nil {
+		return err
+	}
+
+	if !all || c.count <= 1 {
+		return rc.tree.Delete(key)
+	}
+
+	c.count--
+	return rc.put(key, c)
+}
+
+// get returns the count associated with the given row, or nil if the row is not found.
+func (rc *rowCounter) get(key *tree.Key) (*rowCount, error) {
+	v, err := rc.tree.Get(key)
+	if errors.Is(err, engine.ErrKeyNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeRowCount(v)
+}
+
+func (rc *rowCounter) put(key *tree.Key, c *rowCount) error {
+	fb := object.NewFieldBuffer()
+	fb.Add("count", types.NewIntegerValue(c.count))
+	fb.Add("row", types.NewObjectValue(c.row))
+	if c.key != nil {
+		fb.Add("key", types.NewBlobValue(c.key))
+		fb.Add("table", types.NewTextValue(c.table))
+	}
+
+	var err error
+	rc.buf, err = encoding.EncodeObject(rc.buf[:0], fb)
+	if err != nil {
+		return err
+	}
+
+	return rc.tree.Put(key, rc.buf)
+}
+
+// iterate returns each row as many times as it was counted.
+func (rc *rowCounter) iterate(in *environment.Environment, fn func(out *environment.Environment) error) error {
+	var newEnv environment.Environment
+	newEnv.SetOuter(in)
+
+	var basicRow database.BasicRow
+	return rc.tree.IterateOnRange(nil, false, func(_ *tree.Key, value []byte) error {
+		c, err := decodeRowCount(value)
+		if err != nil {
+			return err
+		}
+
+		var pk *tree.Key
+		if c.key != nil {
+			pk = tree.NewEncodedKey(c.key)
+		}
+
+		basicRow.ResetWith(c.table, pk, c.row)
+		newEnv.SetRow(&basicRow)
+
+		for i := int64(0); i < c.count; i++ {
+			err = fn(&newEnv)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func decodeRowCount(value []byte) (*rowCount, error) {
+	obj := encoding.DecodeObject(value, false)
+
+	v, err := obj.GetByField("count")
+	if err != nil {
+		return nil, err
+	}
+	c := rowCount{count: types.AsInt64(v)}
+
+	v, err = obj.GetByField("row")
+	if err != nil {
+		return nil, err
+	}
+	c.row = types.AsObject(v)
+
+	v, err = obj.GetByField("key")
+	if errors.Is(err, types.ErrFieldNotFound) {
+		return &c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.key = types.AsByteSlice(v)
+
+	v, err = obj.GetByField("table")
+	if err != nil {
+		return nil, err
+	}
+	c.table = types.AsString(v)
+
+	return &c, nil
+}
+
+// rowValues returns the values of the row, in order.
+// Integral doubles are converted to integers, so that
+// numbers are compared by value.
+func rowValues(row database.Row) ([]types.Value, error) {
+	var values []types.Value
+	err := row.Iterate(func(_ string, v types.Value) error {
+		if v.Type() == types.TypeDouble {
+			f := types.AsFloat64(v)
+			if f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64 {
+				v = types.NewIntegerValue(int64(f))
+			}
+		}
+
+		values = append(values, v)
+		return nil
+	})
+	return values, err
+}
@@ -166,3 +166,85 @@ func TestConcatOperator(t *testing.T) {
 		testutil.RequireObjEqual(t, d, got[i])
 	}
 }
+
+func TestIntersectExcept(t *testing.T) {
+	first := testutil.ParseExprs(t, `{"a": 1}`, `{"a": 1}`, `{"a": 1}`, `{"a": 2}`, `{"a": 3}`)
+	second := testutil.ParseExprs(t, `{"a": 1}`, `{"a": 1}`, `{"a": 3}`, `{"a": 4}`)
+	third := testutil.ParseExprs(t, `{"a": 1}`, `{"a": 2}`, `{"a": 1}`)
+
+	tests := []struct {
+		name     string
+		op       func(s ...*stream.Stream) stream.Operator
+		streams  [][]expr.Expr
+		expected testutil.Objs
+	}{
+		{"intersect", func(s ...*stream.Stream) stream.Operator { return stream.Intersect(s...) },
+			[][]expr.Expr{first, second},
+			testutil.MakeObjects(t, `{"a": 1}`, `{"a": 3}`)},
+		{"intersect all", func(s ...*stream.Stream) stream.Operator { return stream.IntersectAll(s...) },
+			[][]expr.Expr{first, second},
+			testutil.MakeObjects(t, `{"a": 1}`, `{"a": 1}`, `{"a": 3}`)},
+		{"intersect three", func(s ...*stream.Stream) stream.Operator { return stream.Intersect(s...) },
+			[][]expr.Expr{first, second, third},
+			testutil.MakeObjects(t, `{"a": 1}`)},
+		{"intersect only one", func(s ...*stream.Stream) stream.Operator { return stream.Intersect(s...) },
+			[][]expr.Expr{first},
+			testutil.MakeObjects(t, `{"a": 1}`, `{"a": 2}`, `{"a": 3}`)},
+		{"except", func(s ...*stream.Stream) stream.Operator { return stream.Except(s...) },
+			[][]expr.Expr{first, second},
+			testutil.MakeObjects(t, `{"a": 2}`)},
+		{"except all", func(s ...*stream.Stream) stream.Operator { return stream.ExceptAll(s...) },
+			[][]expr.Expr{first, second},
+			testutil.MakeObjects(t, `{"a": 1}`, `{"a": 2}`)},
+		{"except all three", func(s ...*stream.Stream) stream.Operator { return stream.ExceptAll(s...) },
+			[][]expr.Expr{first, second, third},
+			nil},
+		{"except from empty", func(s ...*stream.Stream) stream.Operator { return stream.Except(s...) },
+			[][]expr.Expr{nil, second},
+			nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, tx, cleanup := testutil.NewTestTx(t)
+			defer cleanup()
+
+			var streams []*stream.Stream
+			for _, exprs := range test.streams {
+				streams = append(streams, stream.New(rows.Emit(exprs...)))
+			}
+
+			st := stream.New(test.op(streams...))
+			var env environment.Environment
+			env.Tx = tx
+			env.DB = db
+
+			var got testutil.Objs
+			err := st.Iterate(&env, func(env *environment.Environment) error {
+				r, ok := env.GetRow()
+				require.True(t, ok)
+
+				clone, err := object.CloneValue(types.NewObjectValue(r.Object()))
+				if err != nil {
+					return err
+				}
+
+				got = append(got, types.AsObject(clone))
+				return nil
+			})
+			assert.NoError(t, err)
+			require.Equal(t, len(test.expected), len(got))
+			test.expected.RequireEqual(t, got)
+		})
+	}
+
+	t.Run("String", func(t *testing.T) {
+		s1 := stream.New(rows.Emit(testutil.ParseExprs(t, `{"a": 1}`)...))
+		s2 := stream.New(rows.Emit(testutil.ParseExprs(t, `{"a": 2}`)...))
+
+		require.Equal(t, `intersect(rows.Emit({a: 1}), rows.Emit({a: 2}))`, stream.New(stream.Intersect(s1, s2)).String())
+		require.Equal(t, `intersectAll(rows.Emit({a: 1}), rows.Emit({a: 2}))`, stream.New(stream.IntersectAll(s1, s2)).String())
+		require.Equal(t, `except(rows.Emit({a: 1}), rows.Emit({a: 2}))`, stream.New(stream.Except(s1, s2)).String())
+		require.Equal(t, `exceptAll(rows.Emit({a: 1}), rows.Emit({a: 2}))`, stream.New(stream.ExceptAll(s1, s2)).String())
+	})
+}
@@ -0,0 +1,62 @@
+package table
+
+import (
+	"fmt"
+
+	"github.com/chaisql/chai/internal/database"
+	"github.com/chaisql/chai/internal/environment"
+	"github.com/chaisql/chai/internal/stream"
+	"github.com/cockroachdb/errors"
+)
+
+// A LookupOperator replaces each row by the row of the table with the same key.
+type LookupOperator struct {
+	stream.BaseOperator
+	Name string
+}
+
+// Lookup fetches the rows of the table with the same keys as the incoming rows.
+func Lookup(tableName string) *LookupOperator {
+	return &LookupOperator{Name: tableName}
+}
+
+// Iterate implements the Operator interface.
+func (op *LookupOperator) Iterate(in *environment.Environment, f func(out *environment.Environment) error) error {
+	var table *database.Table
+	var newEnv environment.Environment
+
+	it := func(out *environment.Environment) error {
+		r, ok := out.GetRow()
+		if !ok {
+			return errors.New("missing row")
+		}
+
+		if table == nil {
+			var err error
+			table, err = out.GetTx().Catalog.GetTable(out.GetTx(), op.Name)
+			if err != nil {
+				return err
+			}
+		}
+
+		row, err := table.GetRow(r.Key())
+		if err != nil {
+			return err
+		}
+
+		newEnv.SetOuter(out)
+		newEnv.SetRow(row)
+
+		return f(&newEnv)
+	}
+
+	if op.Prev == nil {
+		return it(in)
+	}
+
+	return op.Prev.Iterate(in, it)
+}
+
+func (op *LookupOperator) String() string {
+	return fmt.Sprintf("table.Lookup(%q)", op.Name)
+}
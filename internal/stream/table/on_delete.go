@@ -0,0 +1,69 @@
+package table
+
+import (
+	"fmt"
+
+	"github.com/chaisql/chai/internal/database"
+	"github.com/chaisql/chai/internal/environment"
+	errs "github.com/chaisql/chai/internal/errors"
+	"github.com/chaisql/chai/internal/stream"
+	"github.com/cockroachdb/errors"
+)
+
+// An OnDeleteOperator performs the ON DELETE actions of the foreign keys
+// referencing the incoming rows, once the following operators have deleted them.
+// Rows that were already deleted by a previous action are skipped.
+type OnDeleteOperator struct {
+	stream.BaseOperator
+	Name string
+}
+
+// OnDelete creates an OnDeleteOperator.
+func OnDelete(tableName string) *OnDeleteOperator {
+	return &OnDeleteOperator{Name: tableName}
+}
+
+// Iterate implements the Operator interface.
+func (op *OnDeleteOperator) Iterate(in *environment.Environment, f func(out *environment.Environment) error) error {
+	var table *database.Table
+
+	it := func(out *environment.Environment) error {
+		r, ok := out.GetRow()
+		if !ok {
+			return errors.New("missing row")
+		}
+
+		if table == nil {
+			var err error
+			table, err = out.GetTx().Catalog.GetTable(out.GetTx(), op.Name)
+			if err != nil {
+				return err
+			}
+		}
+
+		old, err := table.GetRow(r.Key())
+		if errs.IsNotFoundError(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		err = f(out)
+		if err != nil {
+			return err
+		}
+
+		return table.ApplyOnDelete(old)
+	}
+
+	if op.Prev == nil {
+		return it(in)
+	}
+
+	return op.Prev.Iterate(in, it)
+}
+
+func (op *OnDeleteOperator) String() string {
+	return fmt.Sprintf("table.OnDelete(%q)", op.Name)
+}
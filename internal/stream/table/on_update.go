@@ -0,0 +1,70 @@
+package table
+
+import (
+	"fmt"
+
+	"github.com/chaisql/chai/internal/database"
+	"github.com/chaisql/chai/internal/environment"
+	errs "github.com/chaisql/chai/internal/errors"
+	"github.com/chaisql/chai/internal/stream"
+	"github.com/cockroachdb/errors"
+)
+
+// An OnUpdateOperator performs the ON UPDATE actions of the foreign keys
+// referencing the incoming rows, once the following operators have replaced
+// the stored rows by them. The incoming rows must have the key of the stored rows.
+// Rows that were deleted by a previous action are skipped.
+type OnUpdateOperator struct {
+	stream.BaseOperator
+	Name string
+}
+
+// OnUpdate creates an OnUpdateOperator.
+func OnUpdate(tableName string) *OnUpdateOperator {
+	return &OnUpdateOperator{Name: tableName}
+}
+
+// Iterate implements the Operator interface.
+func (op *OnUpdateOperator) Iterate(in *environment.Environment, f func(out *environment.Environment) error) error {
+	var table *database.Table
+
+	it := func(out *environment.Environment) error {
+		r, ok := out.GetRow()
+		if !ok {
+			return errors.New("missing row")
+		}
+
+		if table == nil {
+			var err error
+			table, err = out.GetTx().Catalog.GetTable(out.GetTx(), op.Name)
+			if err != nil {
+				return err
+			}
+		}
+
+		old, err := table.GetRow(r.Key())
+		if errs.IsNotFoundError(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		err = f(out)
+		if err != nil {
+			return err
+		}
+
+		return table.ApplyOnUpdate(old, r.Object())
+	}
+
+	if op.Prev == nil {
+		return it(in)
+	}
+
+	return op.Prev.Iterate(in, it)
+}
+
+func (op *OnUpdateOperator) String() string {
+	return fmt.Sprintf("table.OnUpdate(%q)", op.Name)
+}
@@ -0,0 +1,181 @@
+package table
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/chaisql/chai/internal/database"
+	"github.com/chaisql/chai/internal/environment"
+	errs "github.com/chaisql/chai/internal/errors"
+	"github.com/chaisql/chai/internal/expr"
+	"github.com/chaisql/chai/internal/object"
+	"github.com/chaisql/chai/internal/stream"
+	"github.com/chaisql/chai/internal/types"
+	"github.com/cockroachdb/errors"
+)
+
+// A PreparedTrigger is a trigger whose statements are ready to be executed.
+type PreparedTrigger struct {
+	Name   string
+	Timing database.TriggerTiming
+	// When is evaluated before executing the statements, if set.
+	When       expr.Expr
+	Statements []*stream.Stream
+}
+
+// A TriggerOperator executes triggers for every incoming row, before and after
+// the following operators modify the table.
+// The statements of the triggers are executed in the same transaction with
+// the NEW and OLD variables referencing the new and the old version of the row.
+// OLD is NULL for inserted rows and NEW is NULL for deleted rows.
+// For updated rows, the incoming rows must have the key of the stored rows.
+// Rows deleted or updated by the actions of foreign keys, like ON DELETE CASCADE,
+// don't execute the triggers of their table.
+type TriggerOperator struct {
+	stream.BaseOperator
+	Name     string
+	Event    database.TriggerEvent
+	Triggers []*PreparedTrigger
+}
+
+// Trigger creates a TriggerOperator.
+func Trigger(tableName string, event database.TriggerEvent, triggers ...*PreparedTrigger) *TriggerOperator {
+	return &TriggerOperator{Name: tableName, Event: event, Triggers: triggers}
+}
+
+// Iterate implements the Operator interface.
+func (op *TriggerOperator) Iterate(in *environment.Environment, f func(out *environment.Environment) error) error {
+	var table *database.Table
+
+	it := func(out *environment.Environment) error {
+		r, ok := out.GetRow()
+		if !ok {
+			return errors.New("missing row")
+		}
+
+		var err error
+		var newRow, oldRow types.Value = types.NewNullValue(), types.NewNullValue()
+		switch op.Event {
+		case database.TriggerInsert:
+			newRow, err = copyRow(r)
+		case database.TriggerUpdate:
+			newRow, err = copyRow(r)
+			if err != nil {
+				return err
+			}
+
+			if table == nil {
+				table, err = out.GetTx().Catalog.GetTable(out.GetTx(), op.Name)
+				if err != nil {
+					return err
+				}
+			}
+
+			var old database.Row
+			old, err = table.GetRow(r.Key())
+			if errs.IsNotFoundError(err) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			oldRow, err = copyRow(old)
+		case database.TriggerDelete:
+			oldRow, err = copyRow(r)
+		}
+		if err != nil {
+			return err
+		}
+
+		var newEnv environment.Environment
+		newEnv.DB = out.GetDB()
+		newEnv.Tx = out.GetTx()
+		newEnv.Vars = object.NewFieldBuffer().Add("NEW", newRow).Add("OLD", oldRow)
+		// the statements don't have access to the columns of the row
+		// without using NEW or OLD
+		newEnv.SetRowFromObject(object.NewFieldBuffer())
+		defer newEnv.Close()
+
+		err = op.execute(&newEnv, database.TriggerBefore)
+		if err != nil {
+			return err
+		}
+
+		err = f(out)
+		if err != nil {
+			return err
+		}
+
+		return op.execute(&newEnv, database.TriggerAfter)
+	}
+
+	if op.Prev == nil {
+		return it(in)
+	}
+
+	return op.Prev.Iterate(in, it)
+}
+
+// execute the triggers with the given timing.
+func (op *TriggerOperator) execute(env *environment.Environment, timing database.TriggerTiming) error {
+	for _, t := range op.Triggers {
+		if t.Timing != timing {
+			continue
+		}
+
+		if t.When != nil {
+			v, err := t.When.Eval(env)
+			if err != nil {
+				return err
+			}
+
+			ok, err := types.IsTruthy(v)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+		}
+
+		for _, s := range t.Statements {
+			err := s.Iterate(env, func(out *environment.Environment) error {
+				return nil
+			})
+			if errors.Is(err, stream.ErrStreamClosed) {
+				err = nil
+			}
+			if err != nil {
+				return errors.Wrapf(err, "trigger %q", t.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// copyRow returns a copy of the row, as it may be modified
+// by the following operators.
+func copyRow(r database.Row) (types.Value, error) {
+	var fb object.FieldBuffer
+	err := fb.Copy(r.Object())
+	if err != nil {
+		return nil, err
+	}
+
+	return types.NewObjectValue(&fb), nil
+}
+
+func (op *TriggerOperator) String() string {
+	var s strings.Builder
+
+	s.WriteString("table.Trigger(")
+	s.WriteString(strconv.Quote(op.Name))
+	for _, t := range op.Triggers {
+		s.WriteString(", ")
+		s.WriteString(strconv.Quote(t.Name))
+	}
+	s.WriteRune(')')
+
+	return s.String()
+}
@@ -66,6 +66,12 @@ func (op *ValidateOperator) Iterate(in *environment.Environment, fn func(out *en
 			return err
 		}
 
+		// ensure the rows referenced by foreign keys exist
+		err = info.ValidateForeignKeys(tx, &eo)
+		if err != nil {
+			return err
+		}
+
 		return fn(&newEnv)
 	})
 }
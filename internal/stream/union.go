@@ -5,9 +5,7 @@ import (
 	"strings"
 
 	"github.com/chaisql/chai/internal/database"
-	"github.com/chaisql/chai/internal/encoding"
 	"github.com/chaisql/chai/internal/environment"
-	"github.com/chaisql/chai/internal/object"
 	"github.com/chaisql/chai/internal/tree"
 	"github.com/chaisql/chai/internal/types"
 )
@@ -16,6 +14,11 @@ import (
 type UnionOperator struct {
 	BaseOperator
 	Streams []*Stream
+	// ByKey deduplicates the rows using their table and primary key
+	// instead of their content. The streams must return rows read from tables.
+	// Only the keys are stored and the rows are fetched once the union is computed,
+	// in key order.
+	ByKey bool
 }
 
 // Union returns a new UnionOperator.
@@ -23,71 +26,87 @@ func Union(s ...*Stream) *UnionOperator {
 	return &UnionOperator{Streams: s}
 }
 
+// UnionByKey returns a new UnionOperator that deduplicates rows by primary key.
+func UnionByKey(s ...*Stream) *UnionOperator {
+	return &UnionOperator{Streams: s, ByKey: true}
+}
+
 // Iterate iterates over all the streams and returns their union.
 func (it *UnionOperator) Iterate(in *environment.Environment, fn func(out *environment.Environment) error) (err error) {
+	if it.ByKey {
+		return it.iterateByKey(in, fn)
+	}
+
+	rc, err := newRowCounter(in)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		e := rc.cleanup()
+		if err == nil {
+			err = e
+		}
+	}()
+
+	// count each row once to deduplicate them
+	for _, s := range it.Streams {
+		err = s.Iterate(in, func(out *environment.Environment) error {
+			return rc.add(out, false)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return rc.iterate(in, fn)
+}
+
+// iterateByKey stores the table name and the key of each row in a temporary tree
+// to deduplicate them, then reads the rows from their table.
+func (it *UnionOperator) iterateByKey(in *environment.Environment, fn func(out *environment.Environment) error) (err error) {
 	var temp *tree.Tree
 	var cleanup func() error
 
 	defer func() {
 		if cleanup != nil {
 			e := cleanup()
-			if err != nil {
+			if err == nil {
 				err = e
 			}
 		}
 	}()
 
-	// iterate over all the streams and insert each key in the temporary table
-	// to deduplicate them
-	fb := object.NewFieldBuffer()
-	var buf []byte
+	tx := in.GetTx()
 
 	for _, s := range it.Streams {
 		err := s.Iterate(in, func(out *environment.Environment) error {
-			fb.Reset()
-			buf = buf[:0]
-
 			row, ok := out.GetRow()
 			if !ok {
 				return errors.New("missing row")
 			}
+			if row.Key() == nil {
+				return errors.New("missing row key")
+			}
 
 			if temp == nil {
 				// create a temporary tree
 				db := in.GetDB()
-				tns := in.GetTx().Catalog.GetFreeTransientNamespace()
+				tns := tx.Catalog.GetFreeTransientNamespace()
 				temp, cleanup, err = tree.NewTransient(db.Engine.NewTransientSession(), tns, 0)
 				if err != nil {
 					return err
 				}
 			}
 
-			key := tree.NewKey(types.NewObjectValue(row.Object()))
-
-			if row.Key() != nil {
-				// encode the row key and table name as the value
-				info, err := in.GetTx().Catalog.GetTableInfo(row.TableName())
-				if err != nil {
-					return err
-				}
-				encKey, err := info.EncodeKey(row.Key())
-				if err != nil {
-					return err
-				}
-
-				fb.Add("key", types.NewBlobValue(encKey))
-				fb.Add("table", types.NewTextValue(row.TableName()))
-				buf, err = encoding.EncodeObject(buf, fb)
-				if err != nil {
-					return err
-				}
+			// store the values of the key rather than the encoded key
+			// so that the rows are sorted like in the table
+			pk, err := row.Key().Decode()
+			if err != nil {
+				return err
 			}
 
-			err = temp.Put(key, buf)
-			if err == nil || errors.Is(err, database.ErrIndexDuplicateValue) {
-				return nil
-			}
-			return err
+			key := tree.NewKey(append([]types.Value{types.NewTextValue(row.TableName())}, pk...)...)
+			return temp.Put(key, nil)
 		})
 		if err != nil {
 			return err
@@ -102,38 +121,25 @@ func (it *UnionOperator) Iterate(in *environment.Environment, fn func(out *envir
 	var newEnv environment.Environment
 	newEnv.SetOuter(in)
 
-	var vb object.ValueBuffer
-	var basicRow database.BasicRow
-	// iterate over the temporary index
-	return temp.IterateOnRange(nil, false, func(key *tree.Key, value []byte) error {
-		vb.Reset()
+	var ptr database.LazyRow
+	newEnv.SetRow(&ptr)
+
+	var table *database.Table
+	return temp.IterateOnRange(nil, false, func(key *tree.Key, _ []byte) error {
 		kv, err := key.Decode()
 		if err != nil {
 			return err
 		}
 
-		var tableName string
-		var pk *tree.Key
-
-		obj := types.AsObject(kv[0])
-
-		if len(value) > 1 {
-			ser := encoding.DecodeObject(value, false)
-			pkf, err := ser.GetByField("key")
+		tableName := types.AsString(kv[0])
+		if table == nil || table.Info.TableName != tableName {
+			table, err = tx.Catalog.GetTable(tx, tableName)
 			if err != nil {
 				return err
 			}
-			pk = tree.NewEncodedKey(types.AsByteSlice(pkf))
-			tf, err := ser.GetByField("table")
-			if err != nil {
-				return err
-			}
-			tableName = types.AsString(tf)
 		}
 
-		basicRow.ResetWith(tableName, pk, obj)
-
-		newEnv.SetRow(&basicRow)
+		ptr.ResetWith(table, tree.NewKey(kv[1:]...))
 		return fn(&newEnv)
 	})
 }
@@ -141,7 +147,11 @@ func (it *UnionOperator) Iterate(in *environment.Environment, fn func(out *envir
 func (it *UnionOperator) String() string {
 	var s strings.Builder
 
-	s.WriteString("union(")
+	if it.ByKey {
+		s.WriteString("unionByKey(")
+	} else {
+		s.WriteString("union(")
+	}
 	for i, st := range it.Streams {
 		if i > 0 {
 			s.WriteString(", ")